@@ -67,7 +67,7 @@ func TestE2EBasic() error {
 	composeFiles := []string{
 		"./docker-compose.e2e-basic.yml",
 	}
-	runner, cleanup := e2e.NewRunner(composeFiles, "node_e2e_basic_test", "openvpn,noop,wireguard,hermes2")
+	runner, cleanup := e2e.NewRunner(composeFiles, "node_e2e_basic_test", "openvpn,noop,wireguard,hermes2,gossip")
 	defer cleanup()
 	if err := runner.Init(); err != nil {
 		return err
@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"io"
 	stdlog "log"
+	"math/big"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/config/urfavecli/clicontext"
 	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/discovery/query"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/metadata"
@@ -163,6 +166,7 @@ func (c *cliApp) handleActions(line string) {
 		{"nat", c.natStatus},
 		{"location", c.location},
 		{"disconnect", c.disconnect},
+		{"speedtest", c.speedtest},
 		{"stop", c.stopClient},
 	}
 
@@ -324,7 +328,7 @@ func (c *cliApp) serviceGet(id string) {
 func (c *cliApp) connect(argsString string) {
 	args := strings.Fields(argsString)
 
-	helpMsg := "Please type in the provider identity. connect <consumer-identity> <provider-identity> <service-type> [dns=auto|provider|system|1.1.1.1] [disable-kill-switch]"
+	helpMsg := "Please type in the provider identity. connect <consumer-identity> <provider-identity> <service-type> [dns=auto|provider|system|1.1.1.1] [disable-kill-switch] [include=10.0.0.0/8,example.com] [exclude=192.168.0.0/16,example.org] [quota-bytes=2147483648] [quota-time=30m] [quota-myst=0.5]"
 	if len(args) < 3 {
 		info(helpMsg)
 		return
@@ -334,8 +338,26 @@ func (c *cliApp) connect(argsString string) {
 
 	var disableKillSwitch bool
 	var dns connection.DNSOption
+	var splitTunnel connection.SplitTunnel
+	var quota contract.QuotaOptions
 	var err error
 	for _, arg := range args[3:] {
+		if strings.HasPrefix(arg, "quota-") {
+			if err := parseQuotaArg(arg, &quota); err != nil {
+				warn("Invalid value: ", err)
+				info(helpMsg)
+				return
+			}
+			continue
+		}
+		if strings.HasPrefix(arg, "include=") {
+			splitTunnel.Include = strings.Split(strings.TrimPrefix(arg, "include="), ",")
+			continue
+		}
+		if strings.HasPrefix(arg, "exclude=") {
+			splitTunnel.Exclude = strings.Split(strings.TrimPrefix(arg, "exclude="), ",")
+			continue
+		}
 		if strings.HasPrefix(arg, "dns=") {
 			kv := strings.Split(arg, "=")
 			dns, err = connection.NewDNSOption(kv[1])
@@ -356,9 +378,20 @@ func (c *cliApp) connect(argsString string) {
 		}
 	}
 
+	if err := splitTunnel.Validate(); err != nil {
+		warn("Invalid value: ", err)
+		info(helpMsg)
+		return
+	}
+
 	connectOptions := contract.ConnectOptions{
-		DNS:               dns,
-		DisableKillSwitch: disableKillSwitch,
+		DNS:                dns,
+		DisableKillSwitch:  disableKillSwitch,
+		SplitTunnelInclude: splitTunnel.Include,
+		SplitTunnelExclude: splitTunnel.Exclude,
+	}
+	if quota.ToQuota().Enabled() {
+		connectOptions.Quota = &quota
 	}
 
 	if consumerID == "new" {
@@ -385,6 +418,37 @@ func (c *cliApp) connect(argsString string) {
 	success("Connected.")
 }
 
+func parseQuotaArg(arg string, quota *contract.QuotaOptions) error {
+	kv := strings.SplitN(arg, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("missing value of %s", arg)
+	}
+
+	switch kv[0] {
+	case "quota-bytes":
+		bytes, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		quota.Bytes = bytes
+	case "quota-time":
+		duration, err := time.ParseDuration(kv[1])
+		if err != nil {
+			return err
+		}
+		quota.Duration = uint64(duration.Seconds())
+	case "quota-myst":
+		myst, ok := new(big.Float).SetString(kv[1])
+		if !ok || myst.Sign() <= 0 {
+			return fmt.Errorf("invalid amount of MYST %q", kv[1])
+		}
+		quota.Tokens, _ = myst.Mul(myst, new(big.Float).SetInt(money.MystSize)).Int(nil)
+	default:
+		return fmt.Errorf("unknown quota %s", kv[0])
+	}
+	return nil
+}
+
 func (c *cliApp) payout(argsString string) {
 	args := strings.Fields(argsString)
 
@@ -460,6 +524,19 @@ func (c *cliApp) disconnect() {
 	success("Disconnected.")
 }
 
+func (c *cliApp) speedtest() {
+	info("Measuring connection quality, it may take a while...")
+	probe, err := c.tequilapi.ConnectionProbe(true)
+	if err != nil {
+		warn(err)
+		return
+	}
+	info(fmt.Sprintf("Latency: %s", time.Duration(probe.Latency)*time.Millisecond))
+	info(fmt.Sprintf("Jitter: %s", time.Duration(probe.Jitter)*time.Millisecond))
+	info(fmt.Sprintf("Packet loss: %.0f%%", probe.PacketLoss*100))
+	info(fmt.Sprintf("Throughput: %s/%s", datasize.BitSpeed(probe.ThroughputDownload), datasize.BitSpeed(probe.ThroughputUpload)))
+}
+
 func (c *cliApp) status() {
 	status, err := c.tequilapi.ConnectionStatus()
 	if err != nil {
@@ -524,9 +601,35 @@ func (c *cliApp) natStatus() {
 	} else {
 		infof("NAT traversal status: %q (error: %q)\n", status.Status, status.Error)
 	}
+	if status.Protocol != "" {
+		infof("Port mapping protocol: %q\n", status.Protocol)
+	}
+
+	natType, err := c.tequilapi.NATType()
+	if err != nil {
+		warn("Failed to retrieve NAT type:", err)
+		return
+	}
+
+	if natType.Error == "" {
+		infof("NAT type: %q\n", natType.Type)
+	} else {
+		infof("NAT type: %q (error: %q)\n", natType.Type, natType.Error)
+	}
 }
 
-func (c *cliApp) proposals(filter string) {
+func (c *cliApp) proposals(argsString string) {
+	filter, sort, offset, limit, err := parseProposalsOptions(argsString)
+	if err != nil {
+		warn(err)
+		info("Usage: proposals [<query>|<filter>] [sort=-quality,price_gib] [offset=0] [limit=10]")
+		return
+	}
+	if _, err := query.Parse(filter, query.NewFields(nil)); err == nil || sort != "" || offset > 0 || limit > 0 {
+		c.proposalsByQuery(filter, sort, offset, limit)
+		return
+	}
+
 	proposals := c.fetchProposals()
 	c.fetchedProposals = proposals
 
@@ -560,6 +663,63 @@ func (c *cliApp) proposals(filter string) {
 	}
 }
 
+// parseProposalsOptions cuts trailing sort, offset and limit options off the proposals arguments,
+// what is left is the query or the plain text filter.
+func parseProposalsOptions(argsString string) (filter, sort string, offset, limit int, err error) {
+	filter = strings.TrimSpace(argsString)
+	for {
+		arg := filter[strings.LastIndexAny(filter, " \t")+1:]
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			return filter, sort, offset, limit, nil
+		}
+
+		switch kv[0] {
+		case "sort":
+			sort = kv[1]
+		case "offset", "limit":
+			count, err := strconv.Atoi(kv[1])
+			if err != nil || count < 0 {
+				return "", "", 0, 0, fmt.Errorf("%s must be a non-negative integer", kv[0])
+			}
+			if kv[0] == "offset" {
+				offset = count
+			} else {
+				limit = count
+			}
+		default:
+			return filter, sort, offset, limit, nil
+		}
+		filter = strings.TrimSpace(strings.TrimSuffix(filter, arg))
+	}
+}
+
+func (c *cliApp) proposalsByQuery(filter, sort string, offset, limit int) {
+	proposals, err := c.tequilapi.ProposalsByQuery(tequilapi_client.ProposalsQuery{
+		Query:               filter,
+		Sort:                sort,
+		Offset:              offset,
+		Limit:               limit,
+		LowerTimePriceBound: config.GetBigInt(config.FlagPaymentsConsumerPricePerMinuteLowerBound),
+		UpperTimePriceBound: config.GetBigInt(config.FlagPaymentsConsumerPricePerMinuteUpperBound),
+		LowerGBPriceBound:   config.GetBigInt(config.FlagPaymentsConsumerPricePerGBLowerBound),
+		UpperGBPriceBound:   config.GetBigInt(config.FlagPaymentsConsumerPricePerGBUpperBound),
+	})
+	if err != nil {
+		warn(err)
+		return
+	}
+
+	info(fmt.Sprintf("Found %v proposals (query: '%s')", len(proposals), filter))
+	for _, proposal := range proposals {
+		country := proposal.ServiceDefinition.LocationOriginate.Country
+		if country == "" {
+			country = "Unknown"
+		}
+		info(fmt.Sprintf("- provider id: %v\ttype: %v\tcountry: %v", proposal.ProviderID, proposal.ServiceType, country))
+	}
+}
+
 func (c *cliApp) fetchProposals() []contract.ProposalDTO {
 	upperTimeBound := config.GetBigInt(config.FlagPaymentsConsumerPricePerMinuteUpperBound)
 	lowerTimeBound := config.GetBigInt(config.FlagPaymentsConsumerPricePerMinuteLowerBound)
@@ -648,6 +808,11 @@ func newAutocompleter(tequilapi *tequilapi_client.Client, proposals []contract.P
 		readline.PcItem("dns=provider"),
 		readline.PcItem("dns=system"),
 		readline.PcItem("dns=1.1.1.1"),
+		readline.PcItem("include="),
+		readline.PcItem("exclude="),
+		readline.PcItem("quota-bytes="),
+		readline.PcItem("quota-time="),
+		readline.PcItem("quota-myst="),
 	}
 	return readline.NewPrefixCompleter(
 		readline.PcItem(
@@ -696,6 +861,7 @@ func newAutocompleter(tequilapi *tequilapi_client.Client, proposals []contract.P
 		readline.PcItem("proposals"),
 		readline.PcItem("location"),
 		readline.PcItem("disconnect"),
+		readline.PcItem("speedtest"),
 		readline.PcItem("mmn"),
 		readline.PcItem("help"),
 		readline.PcItem("quit"),
@@ -36,9 +36,12 @@ import (
 	"github.com/mysteriumnetwork/node/core/auth"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/discovery/brokerdiscovery"
+	"github.com/mysteriumnetwork/node/core/discovery/gossipdiscovery"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/discovery/scoring"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
+	"github.com/mysteriumnetwork/node/core/metrics"
 	"github.com/mysteriumnetwork/node/core/node"
 	nodevent "github.com/mysteriumnetwork/node/core/node/event"
 	"github.com/mysteriumnetwork/node/core/policy"
@@ -63,9 +66,11 @@ import (
 	"github.com/mysteriumnetwork/node/nat"
 	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/mysteriumnetwork/node/nat/mapping"
+	"github.com/mysteriumnetwork/node/nat/stun"
 	"github.com/mysteriumnetwork/node/nat/traversal"
 	"github.com/mysteriumnetwork/node/nat/upnp"
 	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/mysteriumnetwork/node/services"
 	service_noop "github.com/mysteriumnetwork/node/services/noop"
@@ -113,13 +118,18 @@ type Dependencies struct {
 	DiscoveryFactory   service.DiscoveryFactory
 	ProposalRepository proposal.Repository
 	DiscoveryWorker    brokerdiscovery.Worker
+	GossipNode         *gossipdiscovery.Node
 
 	QualityClient *quality.MysteriumMORQA
 
+	ConnectionHistory *scoring.History
+	ProposalScorer    *scoring.Scorer
+
 	IPResolver       ip.Resolver
 	LocationResolver *location.Cache
 
-	PolicyOracle *policy.Oracle
+	PolicyOracle  *policy.Oracle
+	LocalPolicies *policy.LocalPolicies
 
 	StatisticsReporter               *statistics.SessionStatisticsReporter
 	SessionStorage                   *consumer_session.Storage
@@ -128,22 +138,28 @@ type Dependencies struct {
 	EventBus eventbus.EventBus
 
 	ConnectionManager  connection.Manager
+	ConnectionPool     *connection.Pool
 	ConnectionRegistry *connection.Registry
 
 	ServicesManager *service.Manager
 	ServiceRegistry *service.Registry
 	ServiceSessions *service.SessionPool
 	ServiceFirewall firewall.IncomingTrafficFirewall
+	ServiceLoad     *service.SystemLoadMonitor
+
+	NATPinger       traversal.NATPinger
+	NATTracker      *event.Tracker
+	NATTypeDetector *stun.Detector
+	PortPool        *port.Pool
+	PortMapper      mapping.PortMapper
 
-	NATPinger  traversal.NATPinger
-	NATTracker *event.Tracker
-	PortPool   *port.Pool
-	PortMapper mapping.PortMapper
+	MetricsExporter *metrics.Exporter
 
 	StateKeeper *state.Keeper
 
 	P2PDialer   p2p.Dialer
 	P2PListener p2p.Listener
+	P2PRelay    *relay.Server
 
 	Authenticator     *auth.Authenticator
 	JWTAuthenticator  *auth.JWTAuthenticator
@@ -198,6 +214,10 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 
 	di.bootstrapEventBus()
 
+	if err := di.bootstrapMetrics(); err != nil {
+		return err
+	}
+
 	if err := di.bootstrapStorage(nodeOptions.Directories.Storage); err != nil {
 		return err
 	}
@@ -230,13 +250,15 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 
 	di.PortPool = port.NewPool()
 	if config.GetBool(config.FlagPortMapping) {
-		portmapConfig := mapping.DefaultConfig()
+		portmapConfig := mapping.DefaultConfig(nodeOptions.PortMappingProtocols)
 		di.PortMapper = mapping.NewPortMapper(portmapConfig, di.EventBus)
 	} else {
 		di.PortMapper = mapping.NewNoopPortMapper(di.EventBus)
 	}
 
-	di.bootstrapP2P(nodeOptions.P2PPorts)
+	if err := di.bootstrapP2P(nodeOptions.P2PPorts, nodeOptions.P2PRelay); err != nil {
+		return err
+	}
 	di.SessionConnectivityStatusStorage = connectivity.NewStatusStorage()
 
 	if err := di.bootstrapServices(nodeOptions); err != nil {
@@ -265,7 +287,7 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 	return nil
 }
 
-func (di *Dependencies) bootstrapP2P(p2pPorts *port.Range) {
+func (di *Dependencies) bootstrapP2P(p2pPorts *port.Range, relayOptions node.OptionsP2PRelay) error {
 	portPool := di.PortPool
 	natPinger := di.NATPinger
 	identityVerifier := identity.NewVerifierSigned()
@@ -275,8 +297,16 @@ func (di *Dependencies) bootstrapP2P(p2pPorts *port.Range) {
 		natPinger = traversal.NewNoopPinger()
 	}
 
-	di.P2PListener = p2p.NewListener(di.BrokerConnection, di.SignerFactory, identityVerifier, di.IPResolver, natPinger, portPool, di.PortMapper)
+	if relayOptions.Port > 0 {
+		di.P2PRelay = relay.NewServer(fmt.Sprintf(":%d", relayOptions.Port), relay.DefaultIdleTimeout, relay.DefaultMaxSessions)
+		if err := di.P2PRelay.Start(); err != nil {
+			return errors.Wrap(err, "could not start UDP relay")
+		}
+	}
+
+	di.P2PListener = p2p.NewListener(di.BrokerConnection, di.SignerFactory, identityVerifier, di.IPResolver, natPinger, portPool, di.PortMapper, di.NATTypeDetector, relayOptions.Addresses)
 	di.P2PDialer = p2p.NewDialer(di.BrokerConnector, di.SignerFactory, identityVerifier, di.IPResolver, natPinger, portPool)
+	return nil
 }
 
 func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Listener, error) {
@@ -291,6 +321,15 @@ func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Lis
 	return tequilaListener, nil
 }
 
+func (di *Dependencies) bootstrapMetrics() error {
+	if !config.GetBool(config.FlagMetricsEnable) {
+		return nil
+	}
+
+	di.MetricsExporter = metrics.NewExporter()
+	return di.MetricsExporter.Subscribe(di.EventBus)
+}
+
 func (di *Dependencies) bootstrapStateKeeper(options node.Options) error {
 	var lastStageName string
 	if options.ExperimentNATPunching {
@@ -352,16 +391,36 @@ func (di *Dependencies) Shutdown() (err error) {
 		}
 	}
 
+	if di.ConnectionPool != nil {
+		di.ConnectionPool.Close()
+	}
+
 	if di.ServicesManager != nil {
 		if err := di.ServicesManager.Kill(); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	if di.ServiceLoad != nil {
+		di.ServiceLoad.Stop()
+	}
+
 	if di.PolicyOracle != nil {
 		di.PolicyOracle.Stop()
 	}
 
+	if di.LocalPolicies != nil {
+		di.LocalPolicies.Stop()
+	}
+
+	if di.P2PRelay != nil {
+		di.P2PRelay.Stop()
+	}
+
+	if di.NATTypeDetector != nil {
+		di.NATTypeDetector.Stop()
+	}
+
 	if di.NATService != nil {
 		if err := di.NATService.Disable(); err != nil {
 			errs = append(errs, err)
@@ -370,6 +429,9 @@ func (di *Dependencies) Shutdown() (err error) {
 	if di.DiscoveryWorker != nil {
 		di.DiscoveryWorker.Stop()
 	}
+	if di.GossipNode != nil {
+		di.GossipNode.Stop()
+	}
 	if di.BrokerConnection != nil {
 		di.BrokerConnection.Close()
 	}
@@ -496,28 +558,38 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	}
 
 	di.ConnectionRegistry = connection.NewRegistry()
-	di.ConnectionManager = connection.NewManager(
-		pingpong.ExchangeFactoryFunc(
-			di.Keystore,
-			di.SignerFactory,
-			di.ConsumerTotalsStorage,
-			nodeOptions.Transactor.ChannelImplementation,
-			nodeOptions.Transactor.RegistryAddress,
+	connectionConfig := connection.DefaultConfig()
+	connectionConfig.Probe.Interval = config.GetDuration(config.FlagConnectionProbeInterval)
+	di.ConnectionPool = connection.NewPool(func(id string) connection.Manager {
+		return connection.NewManager(
+			pingpong.ExchangeFactoryFunc(
+				di.Keystore,
+				di.SignerFactory,
+				di.ConsumerTotalsStorage,
+				nodeOptions.Transactor.ChannelImplementation,
+				nodeOptions.Transactor.RegistryAddress,
+				di.EventBus,
+				nodeOptions.Payments.ConsumerDataLeewayMegabytes,
+			),
+			di.ConnectionRegistry.CreateConnection,
 			di.EventBus,
-			nodeOptions.Payments.ConsumerDataLeewayMegabytes,
-		),
-		di.ConnectionRegistry.CreateConnection,
-		di.EventBus,
-		di.IPResolver,
-		di.LocationResolver,
-		connection.DefaultConfig(),
-		connection.DefaultStatsReportInterval,
-		connection.NewValidator(
-			di.ConsumerBalanceTracker,
-			di.IdentityManager,
-		),
-		di.P2PDialer,
-	)
+			di.IPResolver,
+			di.LocationResolver,
+			connectionConfig,
+			connection.DefaultStatsReportInterval,
+			connection.NewValidator(
+				di.ConsumerBalanceTracker,
+				di.IdentityManager,
+			),
+			di.P2PDialer,
+			di.ProposalRepository,
+			id,
+		)
+	})
+	if err := di.ConnectionPool.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	di.ConnectionManager = di.ConnectionPool.Default()
 
 	di.LogCollector = logconfig.NewCollector(&logconfig.CurrentLogOptions)
 	reporter, err := feedback.NewReporter(di.LogCollector, di.IdentityManager, nodeOptions.FeedbackURL)
@@ -551,14 +623,15 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 	tequilapi_endpoints.AddRouteForStop(router, utils.SoftKiller(di.Shutdown))
 	tequilapi_endpoints.AddRoutesForAuthentication(router, di.Authenticator, di.JWTAuthenticator)
 	tequilapi_endpoints.AddRoutesForIdentities(router, di.IdentityManager, di.IdentitySelector, di.IdentityRegistry, di.ConsumerBalanceTracker, di.ChannelAddressCalculator, di.HermesPromiseSettler, di.BCHelper)
-	tequilapi_endpoints.AddRoutesForConnection(router, di.ConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry)
+	tequilapi_endpoints.AddRoutesForConnection(router, di.ConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.ProposalScorer)
+	tequilapi_endpoints.AddRoutesForConnections(router, di.ConnectionPool, di.ProposalRepository, di.IdentityRegistry)
 	tequilapi_endpoints.AddRoutesForSessions(router, di.SessionStorage)
 	tequilapi_endpoints.AddRoutesForConnectionLocation(router, di.IPResolver, di.LocationResolver, di.LocationResolver)
 	tequilapi_endpoints.AddRoutesForProposals(router, di.ProposalRepository, di.QualityClient)
 	tequilapi_endpoints.AddRoutesForService(router, di.ServicesManager, services.JSONParsersByType)
 	tequilapi_endpoints.AddRoutesForPayout(router, di.IdentityManager, di.SignerFactory, di.MysteriumAPI)
-	tequilapi_endpoints.AddRoutesForAccessPolicies(di.HTTPClient, router, config.GetString(config.FlagAccessPolicyAddress))
-	tequilapi_endpoints.AddRoutesForNAT(router, di.StateKeeper)
+	tequilapi_endpoints.AddRoutesForAccessPolicies(di.HTTPClient, router, config.GetString(config.FlagAccessPolicyAddress), di.LocalPolicies)
+	tequilapi_endpoints.AddRoutesForNAT(router, di.StateKeeper, di.NATTypeDetector)
 	tequilapi_endpoints.AddRoutesForTransactor(router, di.Transactor, di.HermesPromiseSettler, di.SettlementHistoryStorage, common.HexToAddress(nodeOptions.Hermes.HermesID))
 	tequilapi_endpoints.AddRoutesForConfig(router)
 	tequilapi_endpoints.AddRoutesForMMN(router, di.MMN)
@@ -572,6 +645,10 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 		tequilapi_endpoints.AddRoutesForPProf(router)
 	}
 
+	if di.MetricsExporter != nil {
+		tequilapi_endpoints.AddRoutesForMetrics(router, di.MetricsExporter.Registry())
+	}
+
 	corsPolicy := tequilapi.NewMysteriumCorsPolicy()
 	return tequilapi.NewServer(listener, router, corsPolicy), nil
 }
@@ -712,12 +789,29 @@ func (di *Dependencies) bootstrapQualityComponents(bindAddress string, options n
 		return err
 	}
 
+	if options.OTLPAddress != "" {
+		if _, err := firewall.AllowURLAccess(options.OTLPAddress); err != nil {
+			return err
+		}
+		if _, err := di.ServiceFirewall.AllowURLAccess(options.OTLPAddress); err != nil {
+			return err
+		}
+		transport = quality.NewMultiTransport(transport, quality.NewOTLPTransport(di.HTTPClient, options.OTLPAddress))
+	}
+
 	// Quality metrics
 	qualitySender := quality.NewSender(transport, metadata.VersionAsString())
 	if err := qualitySender.Subscribe(di.EventBus); err != nil {
 		return err
 	}
 
+	// Proposal scoring
+	di.ConnectionHistory = scoring.NewHistory(di.Storage)
+	if err := di.ConnectionHistory.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	di.ProposalScorer = scoring.NewScorer(di.QualityClient, di.ConnectionHistory, scoring.DefaultWeights)
+
 	// warm up the loader as the load takes up to a couple of secs
 	loader := &upnp.GatewayLoader{}
 	go loader.Get()
@@ -803,6 +897,9 @@ func (di *Dependencies) bootstrapNATComponents(options node.Options) error {
 	} else {
 		di.NATPinger = &traversal.NoopPinger{}
 	}
+
+	di.NATTypeDetector = stun.NewDetector(options.STUNServers, stun.DefaultDetectInterval)
+	go di.NATTypeDetector.Start()
 	return nil
 }
 
@@ -842,6 +939,10 @@ func (di *Dependencies) handleConnStateChange() error {
 		if e.State != connectionstate.Connected && e.State != connectionstate.NotConnected {
 			return
 		}
+		// Additional connections are detached, they do not change host networking.
+		if !e.SessionInfo.IsDefault() {
+			return
+		}
 
 		isDisconnected := latestState == connectionstate.Connected && e.State == connectionstate.NotConnected
 		isConnected := latestState == connectionstate.NotConnected && e.State == connectionstate.Connected
@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -218,6 +219,12 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 	)
 	go di.PolicyOracle.Start()
 
+	di.LocalPolicies = policy.NewLocalPolicies(
+		filepath.Join(config.GetString(config.FlagConfigDir), "access-policies"),
+		config.GetDuration(config.FlagAccessPolicyLocalReloadInterval),
+	)
+	go di.LocalPolicies.Start()
+
 	newP2PSessionHandler := func(serviceInstance *service.Instance, channel p2p.Channel) *service.SessionManager {
 		paymentEngineFactory := pingpong.InvoiceFactoryCreator(
 			channel, nodeOptions.Payments.ProviderInvoiceFrequency,
@@ -244,14 +251,31 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 		)
 	}
 
+	admissionConfig := service.AdmissionConfig{
+		MaxSessions:            config.GetInt(config.FlagServiceMaxSessions),
+		MaxSessionsPerConsumer: config.GetInt(config.FlagServiceMaxSessionsPerConsumer),
+		MaxSessionsPerMinute:   config.GetInt(config.FlagServiceMaxSessionsPerMinute),
+		MaxCPUPercent:          config.GetFloat64(config.FlagServiceCPUWatermark),
+		MaxBandwidthMbps:       config.GetFloat64(config.FlagServiceBandwidthWatermark),
+	}
+	var loadMonitor service.LoadMonitor
+	if admissionConfig.MaxCPUPercent > 0 || admissionConfig.MaxBandwidthMbps > 0 {
+		di.ServiceLoad = service.NewSystemLoadMonitor(5 * time.Second)
+		di.ServiceLoad.Start()
+		loadMonitor = di.ServiceLoad
+	}
+
 	di.ServicesManager = service.NewManager(
 		di.ServiceRegistry,
 		di.DiscoveryFactory,
 		di.EventBus,
 		di.PolicyOracle,
+		di.LocalPolicies,
 		di.P2PListener,
 		newP2PSessionHandler,
 		di.SessionConnectivityStatusStorage,
+		admissionConfig,
+		loadMonitor,
 	)
 
 	serviceCleaner := service.Cleaner{SessionStorage: di.ServiceSessions}
@@ -272,7 +296,7 @@ func (di *Dependencies) registerWireguardConnection(nodeOptions node.Options) {
 	wireguard.Bootstrap()
 	handshakeWaiter := wireguard_connection.NewHandshakeWaiter()
 	endpointFactory := func() (wireguard.ConnectionEndpoint, error) {
-		resourceAllocator := resources.NewAllocator(nil, wireguard_service.DefaultOptions.Subnet)
+		resourceAllocator := resources.NewAllocator(nil, wireguard_service.DefaultOptions.Subnet, wireguard_service.DefaultOptions.Subnet6)
 		return endpoint.NewConnectionEndpoint(resourceAllocator)
 	}
 	connFactory := func() (connection.Connection, error) {
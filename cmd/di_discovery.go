@@ -23,8 +23,10 @@ import (
 	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/apidiscovery"
 	"github.com/mysteriumnetwork/node/core/discovery/brokerdiscovery"
+	"github.com/mysteriumnetwork/node/core/discovery/gossipdiscovery"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/pkg/errors"
 )
 
@@ -40,7 +42,14 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 			discoveryRegistry.AddRegistry(brokerdiscovery.NewRegistry(di.BrokerConnection))
 
 			storage := brokerdiscovery.NewStorage(di.EventBus)
-			brokerRepository := brokerdiscovery.NewRepository(di.BrokerConnection, storage, options.PingInterval+time.Second, 1*time.Second)
+			brokerRepository := brokerdiscovery.NewRepository(
+				di.BrokerConnection,
+				storage,
+				di.EventBus,
+				identity.VerifierIdentityFactory,
+				options.PingInterval+time.Second,
+				1*time.Second,
+			)
 			if options.FetchEnabled {
 				di.DiscoveryWorker = brokerRepository
 				if err := di.DiscoveryWorker.Start(); err != nil {
@@ -48,12 +57,33 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 				}
 			}
 			proposalRepository.Add(brokerRepository)
+		case node.DiscoveryTypeGossip:
+			config := gossipdiscovery.DefaultConfig()
+			config.Address = options.GossipAddress
+			config.Bootstrap = options.GossipBootstrap
+
+			di.GossipNode = gossipdiscovery.NewNode(
+				config,
+				di.EventBus,
+				identity.VerifierIdentityFactory,
+			)
+			if err := di.GossipNode.Start(); err != nil {
+				return errors.Wrap(err, "failed to enable gossip discovery")
+			}
+			// Proposals are relayed through several nodes, so they must outlive a few missed pings.
+			discoveryRegistry.AddRegistry(gossipdiscovery.NewRegistry(di.GossipNode, 3*options.PingInterval))
+			proposalRepository.Add(gossipdiscovery.NewRepository(di.GossipNode))
 		default:
 			return errors.Errorf("unknown discovery adapter: %s", discoveryType)
 		}
 	}
 
 	di.ProposalRepository = proposalRepository
+	if options.CacheMaxAge > 0 {
+		cacheConfig := discovery.DefaultCacheConfig()
+		cacheConfig.MaxAge = options.CacheMaxAge
+		di.ProposalRepository = discovery.NewCache(proposalRepository, di.Storage, cacheConfig)
+	}
 	di.DiscoveryFactory = func() service.Discovery {
 		return discovery.NewService(di.IdentityRegistry, discoveryRegistry, options.PingInterval, di.SignerFactory, di.EventBus)
 	}
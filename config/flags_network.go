@@ -68,6 +68,18 @@ var (
 		Usage: "Enables NAT port mapping",
 		Value: true,
 	}
+	// FlagPortMappingProtocols sets NAT port mapping protocols and the order they are tried in.
+	FlagPortMappingProtocols = cli.StringSliceFlag{
+		Name:  "nat-port-mapping-protocols",
+		Usage: "NAT port mapping protocols tried in the given order: upnp, natpmp, pcp",
+		Value: cli.NewStringSlice("upnp", "natpmp", "pcp"),
+	}
+	// FlagSTUNServers sets STUN servers used to detect NAT type.
+	FlagSTUNServers = cli.StringSliceFlag{
+		Name:  "stun-servers",
+		Usage: "STUN servers supporting NAT behavior discovery (RFC 5780) used to detect NAT type, empty value disables detection",
+		Value: cli.NewStringSlice("stun.stunprotocol.org:3478"),
+	}
 	// FlagIncomingFirewall enables incoming traffic filtering.
 	FlagIncomingFirewall = cli.BoolFlag{
 		Name:  "incoming-firewall",
@@ -89,7 +101,9 @@ func RegisterFlagsNetwork(flags *[]cli.Flag) {
 		&FlagTestnet,
 		&FlagLocalnet,
 		&FlagPortMapping,
+		&FlagPortMappingProtocols,
 		&FlagNATPunching,
+		&FlagSTUNServers,
 		&FlagAPIAddress,
 		&FlagBrokerAddress,
 		&FlagEtherRPC,
@@ -108,7 +122,9 @@ func ParseFlagsNetwork(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagBrokerAddress)
 	Current.ParseStringFlag(ctx, FlagEtherRPC)
 	Current.ParseBoolFlag(ctx, FlagPortMapping)
+	Current.ParseStringSliceFlag(ctx, FlagPortMappingProtocols)
 	Current.ParseBoolFlag(ctx, FlagNATPunching)
+	Current.ParseStringSliceFlag(ctx, FlagSTUNServers)
 	Current.ParseBoolFlag(ctx, FlagIncomingFirewall)
 	Current.ParseBoolFlag(ctx, FlagOutgoingFirewall)
 }
@@ -36,7 +36,7 @@ var (
 	// FlagDiscoveryType proposal discovery adapter.
 	FlagDiscoveryType = cli.StringSliceFlag{
 		Name:  "discovery.type",
-		Usage: `Proposal discovery adapter(s) separated by comma Options: { "api", "broker", "api,broker" }`,
+		Usage: `Proposal discovery adapter(s) separated by comma Options: { "api", "broker", "gossip", "api,broker" }`,
 		Value: cli.NewStringSlice("api", "broker"),
 	}
 	// FlagDiscoveryPingInterval proposal ping interval in seconds.
@@ -51,6 +51,30 @@ var (
 		Usage: `Proposal fetch interval { "30s", "3m", "1h20m30s" }`,
 		Value: 180 * time.Second,
 	}
+	// FlagDiscoveryCacheMaxAge how long proposals are cached since last seen.
+	FlagDiscoveryCacheMaxAge = cli.DurationFlag{
+		Name:  "discovery.cache.max-age",
+		Usage: `How long to keep discovered proposals on disk since they were last seen, 0 disables the cache { "30m", "24h" }`,
+		Value: 24 * time.Hour,
+	}
+	// FlagDiscoveryGossipAddress address to listen for proposal gossip.
+	FlagDiscoveryGossipAddress = cli.StringFlag{
+		Name:  "discovery.gossip.address",
+		Usage: "UDP address to exchange proposals with other nodes when gossip discovery is enabled",
+		Value: ":4070",
+	}
+	// FlagDiscoveryGossipBootstrap peers to join proposal gossip through.
+	FlagDiscoveryGossipBootstrap = cli.StringSliceFlag{
+		Name:  "discovery.gossip.bootstrap",
+		Usage: "Addresses of gossip discovery peers (e.g. 1.2.3.4:4070) separated by comma",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagConnectionProbeInterval consumer connection quality probe interval.
+	FlagConnectionProbeInterval = cli.DurationFlag{
+		Name:  "connection.probe-interval",
+		Usage: `Interval of latency probes over an established connection, 0 disables periodic probes { "30s", "3m", "1h20m30s" }`,
+		Value: 0,
+	}
 	// FlagBindAddress IP address to bind to.
 	FlagBindAddress = cli.StringFlag{
 		Name:  "bind.address",
@@ -79,6 +103,30 @@ var (
 		Name:  "shaper.enabled",
 		Usage: "Limit service bandwidth",
 	}
+	// FlagShaperUplink limits bandwidth of traffic sent through the service interface.
+	FlagShaperUplink = cli.IntFlag{
+		Name:  "shaper.uplink",
+		Usage: "Bandwidth limit of traffic sent through the service interface in Kbps, 0 means unlimited",
+		Value: 5000,
+	}
+	// FlagShaperDownlink limits bandwidth of traffic received on the service interface.
+	FlagShaperDownlink = cli.IntFlag{
+		Name:  "shaper.downlink",
+		Usage: "Bandwidth limit of traffic received on the service interface in Kbps, 0 means unlimited",
+		Value: 5000,
+	}
+	// FlagShaperSessionUplink limits bandwidth of traffic sent to a single consumer.
+	FlagShaperSessionUplink = cli.IntFlag{
+		Name:  "shaper.session.uplink",
+		Usage: "Bandwidth limit of traffic sent to a single consumer in Kbps, 0 means unlimited",
+		Value: 0,
+	}
+	// FlagShaperSessionDownlink limits bandwidth of traffic received from a single consumer.
+	FlagShaperSessionDownlink = cli.IntFlag{
+		Name:  "shaper.session.downlink",
+		Usage: "Bandwidth limit of traffic received from a single consumer in Kbps, 0 means unlimited",
+		Value: 0,
+	}
 	// FlagKeystoreLightweight determines the scrypt memory complexity.
 	FlagKeystoreLightweight = cli.BoolFlag{
 		Name:  "keystore.lightweight",
@@ -134,6 +182,15 @@ var (
 		),
 		Value: "https://quality.mysterium.network/api/v1",
 	}
+	// FlagQualityOTLPAddress OpenTelemetry collector OTLP/HTTP URL.
+	FlagQualityOTLPAddress = cli.StringFlag{
+		Name: "quality.otlp-address",
+		Usage: fmt.Sprintf(
+			"OpenTelemetry collector OTLP/HTTP address (e.g. http://localhost:4318), quality events are exported to it in addition to '--%s' adapter",
+			FlagQualityType.Name,
+		),
+		Value: "",
+	}
 	// FlagTequilapiAddress IP address of interface to listen for incoming connections.
 	FlagTequilapiAddress = cli.StringFlag{
 		Name:  "tequilapi.address",
@@ -152,6 +209,12 @@ var (
 		Usage: "Enables pprof",
 		Value: false,
 	}
+	// FlagMetricsEnable enables Prometheus metrics via TequilAPI.
+	FlagMetricsEnable = cli.BoolFlag{
+		Name:  "metrics.enable",
+		Usage: "Enables Prometheus metrics endpoint /metrics",
+		Value: false,
+	}
 	// FlagUIEnable enables built-in web UI for node.
 	FlagUIEnable = cli.BoolFlag{
 		Name:  "ui.enable",
@@ -188,6 +251,18 @@ var (
 		Usage: "Range of P2P listen ports (e.g. 51820:52075), value of 0:0 means disabled",
 		Value: "0:0",
 	}
+	// FlagP2PRelayAddresses sets UDP relays which are advertised to consumers and used when NAT hole punching fails.
+	FlagP2PRelayAddresses = cli.StringSliceFlag{
+		Name:  "p2p.relay.addresses",
+		Usage: "UDP relay addresses (e.g. 1.2.3.4:4060) separated by comma, first one is used when NAT hole punching fails",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagP2PRelayPort runs UDP relay for other peers.
+	FlagP2PRelayPort = cli.IntFlag{
+		Name:  "p2p.relay.port",
+		Usage: "Port of UDP relay offered to other peers, value of 0 means disabled",
+		Value: 0,
+	}
 
 	//FlagConsumer sets to run as consumer only which allows to skip bootstrap for some of the dependencies.
 	FlagConsumer = cli.BoolFlag{
@@ -215,10 +290,18 @@ func RegisterFlagsNode(flags *[]cli.Flag) error {
 		&FlagDiscoveryType,
 		&FlagDiscoveryPingInterval,
 		&FlagDiscoveryFetchInterval,
+		&FlagDiscoveryCacheMaxAge,
+		&FlagDiscoveryGossipAddress,
+		&FlagDiscoveryGossipBootstrap,
+		&FlagConnectionProbeInterval,
 		&FlagFeedbackURL,
 		&FlagFirewallKillSwitch,
 		&FlagFirewallProtectedNetworks,
 		&FlagShaperEnabled,
+		&FlagShaperUplink,
+		&FlagShaperDownlink,
+		&FlagShaperSessionUplink,
+		&FlagShaperSessionDownlink,
 		&FlagKeystoreLightweight,
 		&FlagLogHTTP,
 		&FlagLogLevel,
@@ -226,15 +309,19 @@ func RegisterFlagsNode(flags *[]cli.Flag) error {
 		&FlagOpenvpnBinary,
 		&FlagQualityType,
 		&FlagQualityAddress,
+		&FlagQualityOTLPAddress,
 		&FlagTequilapiAddress,
 		&FlagTequilapiPort,
 		&FlagPProfEnable,
+		&FlagMetricsEnable,
 		&FlagUIEnable,
 		&FlagUIAddress,
 		&FlagUIPort,
 		&FlagUserMode,
 		&FlagVendorID,
 		&FlagP2PListenPorts,
+		&FlagP2PRelayAddresses,
+		&FlagP2PRelayPort,
 		&FlagConsumer,
 	)
 
@@ -256,10 +343,18 @@ func ParseFlagsNode(ctx *cli.Context) {
 	Current.ParseStringSliceFlag(ctx, FlagDiscoveryType)
 	Current.ParseDurationFlag(ctx, FlagDiscoveryPingInterval)
 	Current.ParseDurationFlag(ctx, FlagDiscoveryFetchInterval)
+	Current.ParseDurationFlag(ctx, FlagDiscoveryCacheMaxAge)
+	Current.ParseStringFlag(ctx, FlagDiscoveryGossipAddress)
+	Current.ParseStringSliceFlag(ctx, FlagDiscoveryGossipBootstrap)
+	Current.ParseDurationFlag(ctx, FlagConnectionProbeInterval)
 	Current.ParseStringFlag(ctx, FlagFeedbackURL)
 	Current.ParseBoolFlag(ctx, FlagFirewallKillSwitch)
 	Current.ParseStringFlag(ctx, FlagFirewallProtectedNetworks)
 	Current.ParseBoolFlag(ctx, FlagShaperEnabled)
+	Current.ParseIntFlag(ctx, FlagShaperUplink)
+	Current.ParseIntFlag(ctx, FlagShaperDownlink)
+	Current.ParseIntFlag(ctx, FlagShaperSessionUplink)
+	Current.ParseIntFlag(ctx, FlagShaperSessionDownlink)
 	Current.ParseBoolFlag(ctx, FlagKeystoreLightweight)
 	Current.ParseBoolFlag(ctx, FlagLogHTTP)
 	Current.ParseStringFlag(ctx, FlagLogLevel)
@@ -267,15 +362,19 @@ func ParseFlagsNode(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagOpenvpnBinary)
 	Current.ParseStringFlag(ctx, FlagQualityAddress)
 	Current.ParseStringFlag(ctx, FlagQualityType)
+	Current.ParseStringFlag(ctx, FlagQualityOTLPAddress)
 	Current.ParseStringFlag(ctx, FlagTequilapiAddress)
 	Current.ParseIntFlag(ctx, FlagTequilapiPort)
 	Current.ParseBoolFlag(ctx, FlagPProfEnable)
+	Current.ParseBoolFlag(ctx, FlagMetricsEnable)
 	Current.ParseBoolFlag(ctx, FlagUIEnable)
 	Current.ParseStringFlag(ctx, FlagUIAddress)
 	Current.ParseIntFlag(ctx, FlagUIPort)
 	Current.ParseBoolFlag(ctx, FlagUserMode)
 	Current.ParseStringFlag(ctx, FlagVendorID)
 	Current.ParseStringFlag(ctx, FlagP2PListenPorts)
+	Current.ParseStringSliceFlag(ctx, FlagP2PRelayAddresses)
+	Current.ParseIntFlag(ctx, FlagP2PRelayPort)
 	Current.ParseBoolFlag(ctx, FlagConsumer)
 
 	ValidateAddressFlags(FlagTequilapiAddress)
@@ -37,6 +37,12 @@ var (
 		Usage: `Proposal fetch interval { "30s", "3m", "1h20m30s" }`,
 		Value: 10 * time.Minute,
 	}
+	// FlagAccessPolicyLocalReloadInterval local policy files reload interval.
+	FlagAccessPolicyLocalReloadInterval = cli.DurationFlag{
+		Name:  "access-policy.local-reload",
+		Usage: `Interval of checking local access policy files for changes { "10s", "1m" }`,
+		Value: 10 * time.Second,
+	}
 )
 
 // RegisterFlagsPolicy function registers Policy Oracle flags to flag list.
@@ -44,6 +50,7 @@ func RegisterFlagsPolicy(flags *[]cli.Flag) {
 	*flags = append(*flags,
 		&FlagAccessPolicyAddress,
 		&FlagAccessPolicyFetchInterval,
+		&FlagAccessPolicyLocalReloadInterval,
 	)
 }
 
@@ -51,4 +58,5 @@ func RegisterFlagsPolicy(flags *[]cli.Flag) {
 func ParseFlagsPolicy(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagAccessPolicyAddress)
 	Current.ParseDurationFlag(ctx, FlagAccessPolicyFetchInterval)
+	Current.ParseDurationFlag(ctx, FlagAccessPolicyLocalReloadInterval)
 }
@@ -59,6 +59,45 @@ var (
 		Usage: "Sets the price per minute applied to provider service.",
 		Value: 0.0001,
 	}
+
+	// FlagServiceMaxSessions limits concurrent sessions of a single service.
+	FlagServiceMaxSessions = cli.IntFlag{
+		Name:  "service.max-sessions",
+		Usage: "Maximum number of concurrent sessions per service, 0 means unlimited",
+		Value: 0,
+	}
+	// FlagServiceMaxSessionsPerConsumer limits concurrent sessions of a single consumer identity.
+	FlagServiceMaxSessionsPerConsumer = cli.IntFlag{
+		Name:  "service.max-sessions-per-consumer",
+		Usage: "Maximum number of concurrent sessions per consumer identity, 0 means unlimited",
+		Value: 0,
+	}
+	// FlagServiceMaxSessionsPerMinute limits new sessions started by a single service per minute.
+	FlagServiceMaxSessionsPerMinute = cli.IntFlag{
+		Name:  "service.max-sessions-per-minute",
+		Usage: "Maximum number of new sessions per minute per service, 0 means unlimited",
+		Value: 0,
+	}
+	// FlagServiceCPUWatermark rejects new sessions when CPU usage is above the watermark.
+	FlagServiceCPUWatermark = cli.Float64Flag{
+		Name:  "service.cpu-watermark",
+		Usage: "CPU usage percentage above which new sessions are rejected, 0 means unlimited",
+		Value: 0,
+	}
+	// FlagServiceBandwidthWatermark rejects new sessions when network throughput is above the watermark.
+	FlagServiceBandwidthWatermark = cli.Float64Flag{
+		Name:  "service.bandwidth-watermark",
+		Usage: "Network throughput in Mbps above which new sessions are rejected, 0 means unlimited",
+		Value: 0,
+	}
+
+	// FlagDNSUpstreams sets DNS servers consumer DNS queries are forwarded to by the provider DNS proxy.
+	FlagDNSUpstreams = cli.StringSliceFlag{
+		Name: "dns.upstreams",
+		Usage: `DNS servers used to resolve consumer DNS queries, tried in the given order. ` +
+			`Options: { "system", "udp://1.1.1.1:53", "tcp://1.1.1.1:53", "tls://1.1.1.1:853", "https://cloudflare-dns.com/dns-query" }`,
+		Value: cli.NewStringSlice("system"),
+	}
 )
 
 // RegisterFlagsServiceStart registers CLI flags used to start a service.
@@ -70,6 +109,12 @@ func RegisterFlagsServiceStart(flags *[]cli.Flag) {
 		&FlagPaymentPricePerGB,
 		&FlagPaymentPricePerMinute,
 		&FlagAccessPolicyList,
+		&FlagServiceMaxSessions,
+		&FlagServiceMaxSessionsPerConsumer,
+		&FlagServiceMaxSessionsPerMinute,
+		&FlagServiceCPUWatermark,
+		&FlagServiceBandwidthWatermark,
+		&FlagDNSUpstreams,
 	)
 }
 
@@ -81,4 +126,10 @@ func ParseFlagsServiceStart(ctx *cli.Context) {
 	Current.ParseFloat64Flag(ctx, FlagPaymentPricePerGB)
 	Current.ParseFloat64Flag(ctx, FlagPaymentPricePerMinute)
 	Current.ParseStringFlag(ctx, FlagAccessPolicyList)
+	Current.ParseIntFlag(ctx, FlagServiceMaxSessions)
+	Current.ParseIntFlag(ctx, FlagServiceMaxSessionsPerConsumer)
+	Current.ParseIntFlag(ctx, FlagServiceMaxSessionsPerMinute)
+	Current.ParseFloat64Flag(ctx, FlagServiceCPUWatermark)
+	Current.ParseFloat64Flag(ctx, FlagServiceBandwidthWatermark)
+	Current.ParseStringSliceFlag(ctx, FlagDNSUpstreams)
 }
@@ -58,6 +58,10 @@ const consumeCooldown = 500 * time.Millisecond
 
 // consumeStatisticsEvent handles the connection statistics changes
 func (t *Tracker) consumeStatisticsEvent(evt connectionstate.AppEventConnectionStatistics) {
+	if !evt.SessionInfo.IsDefault() {
+		return
+	}
+
 	t.lock.Lock()
 	defer func() {
 		t.lock.Unlock()
@@ -90,6 +94,10 @@ func (t *Tracker) consumeStatisticsEvent(evt connectionstate.AppEventConnectionS
 
 // consumeSessionEvent handles the session state changes
 func (t *Tracker) consumeSessionEvent(sessionEvent connectionstate.AppEventConnectionSession) {
+	if !sessionEvent.SessionInfo.IsDefault() {
+		return
+	}
+
 	t.lock.Lock()
 	defer t.lock.Unlock()
 	switch sessionEvent.Status {
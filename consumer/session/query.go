@@ -18,6 +18,7 @@
 package session
 
 import (
+	"strings"
 	"time"
 
 	"github.com/asdine/storm/v3"
@@ -60,8 +61,13 @@ func (qr *Query) FilterTo(to time.Time) *Query {
 	return qr
 }
 
-// FilterDirection filters fetched sessions by direction.
+// FilterDirection filters fetched sessions by direction, which is matched case insensitively e.g. "provided".
 func (qr *Query) FilterDirection(direction string) *Query {
+	for _, known := range []string{DirectionConsumed, DirectionProvided} {
+		if strings.EqualFold(direction, known) {
+			direction = known
+		}
+	}
 	qr.filterDirection = &direction
 	return qr
 }
@@ -134,13 +140,13 @@ func (qr *Query) run(node storm.Node) error {
 		where = append(where, q.Lte("Started", qr.filterTo))
 	}
 	if qr.filterDirection != nil {
-		where = append(where, q.Eq("Direction", qr.filterDirection))
+		where = append(where, q.Eq("Direction", *qr.filterDirection))
 	}
 	if qr.filterServiceType != nil {
-		where = append(where, q.Eq("ServiceType", qr.filterServiceType))
+		where = append(where, q.Eq("ServiceType", *qr.filterServiceType))
 	}
 	if qr.filterStatus != nil {
-		where = append(where, q.Eq("Status", qr.filterStatus))
+		where = append(where, q.Eq("Status", *qr.filterStatus))
 	}
 
 	sq := node.
@@ -70,6 +70,13 @@ func TestSessionQuery_FilterDirection(t *testing.T) {
 	// then
 	assert.Nil(t, err)
 	assert.Equal(t, []History{}, query.Sessions)
+
+	// when
+	query = NewQuery().FetchSessions().FilterDirection("provided")
+	err = storage.Query(query)
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{sessionExpected}, query.Sessions)
 }
 
 func TestSessionQuery_FetchStats(t *testing.T) {
@@ -129,6 +129,8 @@ func (repo *Storage) consumeServiceSessionStatisticsEvent(e session_event.AppEve
 	repo.sessionsActive[sessionID] = row
 }
 
+// consumeServiceSessionEarningsEvent persists the agreement total of the provided session, so that earnings
+// are not lost if node stops before the session ends. Promises may arrive after the session was ended too.
 func (repo *Storage) consumeServiceSessionEarningsEvent(e session_event.AppEventTokensEarned) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -136,12 +138,38 @@ func (repo *Storage) consumeServiceSessionEarningsEvent(e session_event.AppEvent
 	sessionID := session_node.ID(e.SessionID)
 	row, ok := repo.sessionsActive[sessionID]
 	if !ok {
-		log.Warn().Msg("Received a unknown session update")
+		repo.updateEndedSessionEarnings(sessionID, e.Total)
 		return
 	}
-
+	row.Updated = repo.timeGetter().UTC()
 	row.Tokens = e.Total
+
+	err := repo.storage.Update(sessionStorageBucketName, &row)
+	if err != nil {
+		log.Error().Err(err).Msgf("Session %v update failed", sessionID)
+		return
+	}
+
 	repo.sessionsActive[sessionID] = row
+	log.Debug().Msgf("Session %v updated", sessionID)
+}
+
+func (repo *Storage) updateEndedSessionEarnings(sessionID session_node.ID, total *big.Int) {
+	var row History
+	if err := repo.storage.GetOneByField(sessionStorageBucketName, "SessionID", sessionID, &row); err != nil {
+		log.Warn().Err(err).Msgf("Received a unknown session %v update", sessionID)
+		return
+	}
+	if row.Tokens != nil && row.Tokens.Cmp(total) >= 0 {
+		return
+	}
+	row.Tokens = total
+
+	if err := repo.storage.Update(sessionStorageBucketName, &row); err != nil {
+		log.Error().Err(err).Msgf("Session %v update failed", sessionID)
+		return
+	}
+	log.Debug().Msgf("Ended session %v earnings updated", sessionID)
 }
 
 // consumeConnectionSessionEvent consumes the session state change events
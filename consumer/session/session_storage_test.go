@@ -161,6 +161,60 @@ func TestSessionStorage_consumeServiceSessionsEvent(t *testing.T) {
 	)
 }
 
+func TestSessionStorage_consumeServiceSessionEarningsEvent(t *testing.T) {
+	// given
+	storage, storageCleanup := newStorage()
+	storage.timeGetter = func() time.Time {
+		return time.Date(2020, 6, 17, 10, 21, 12, 0, time.UTC)
+	}
+	defer storageCleanup()
+
+	storage.consumeServiceSessionEvent(session_event.AppEventSession{
+		Status:  session_event.CreatedStatus,
+		Session: serviceSessionMock,
+	})
+	storage.consumeServiceSessionStatisticsEvent(session_event.AppEventDataTransferred{
+		ID:   serviceSessionMock.ID,
+		Up:   123,
+		Down: 1234,
+	})
+
+	// when
+	storage.consumeServiceSessionEarningsEvent(session_event.AppEventTokensEarned{
+		SessionID: serviceSessionMock.ID,
+		Total:     big.NewInt(12),
+	})
+	// then
+	sessions, err := storage.GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "New", sessions[0].Status)
+	assert.Equal(t, big.NewInt(12), sessions[0].Tokens)
+	assert.Equal(t, uint64(1234), sessions[0].DataSent)
+	assert.Equal(t, uint64(123), sessions[0].DataReceived)
+	assert.Equal(t, 10*time.Minute, sessions[0].GetDuration())
+
+	// when
+	storage.consumeServiceSessionEvent(session_event.AppEventSession{
+		Status:  session_event.RemovedStatus,
+		Session: serviceSessionMock,
+	})
+	storage.consumeServiceSessionEarningsEvent(session_event.AppEventTokensEarned{
+		SessionID: serviceSessionMock.ID,
+		Total:     big.NewInt(20),
+	})
+	storage.consumeServiceSessionEarningsEvent(session_event.AppEventTokensEarned{
+		SessionID: serviceSessionMock.ID,
+		Total:     big.NewInt(15),
+	})
+	// then
+	sessions, err = storage.GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "Completed", sessions[0].Status)
+	assert.Equal(t, big.NewInt(20), sessions[0].Tokens)
+}
+
 func TestSessionStorage_consumeEventEndedOK(t *testing.T) {
 	// given
 	storage, storageCleanup := newStorage()
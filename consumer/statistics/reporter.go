@@ -148,6 +148,11 @@ func (sr *SessionStatisticsReporter) send(session connectionstate.Status, signer
 
 // consumeSessionEvent handles the session state changes
 func (sr *SessionStatisticsReporter) consumeSessionEvent(sessionEvent connectionstate.AppEventConnectionSession) {
+	// Only the default connection is reported.
+	if !sessionEvent.SessionInfo.IsDefault() {
+		return
+	}
+
 	switch sessionEvent.Status {
 	case connectionstate.SessionEndedStatus:
 		sr.stop()
@@ -158,6 +163,10 @@ func (sr *SessionStatisticsReporter) consumeSessionEvent(sessionEvent connection
 }
 
 func (sr *SessionStatisticsReporter) consumeSessionStatisticsEvent(e connectionstate.AppEventConnectionStatistics) {
+	if !e.SessionInfo.IsDefault() {
+		return
+	}
+
 	sr.statisticsMu.Lock()
 	sr.statistics = e.Stats
 	sr.statisticsMu.Unlock()
@@ -33,6 +33,14 @@ type ConnectParams struct {
 	DisableKillSwitch bool
 	// DNS servers to use
 	DNS DNSOption
+	// Failover defines where to reconnect when the active session dies
+	Failover FailoverStrategy
+	// SplitTunnel selects destinations routed through the tunnel
+	SplitTunnel SplitTunnel
+	// Detached connection keeps its traffic on its own interface, leaving host routes, DNS and kill switch untouched
+	Detached bool
+	// Quota limits usage of the connection
+	Quota Quota
 }
 
 // ConnectOptions represents the params we need to ensure a successful connection
@@ -45,5 +53,10 @@ type ConnectOptions struct {
 	SessionConfig   []byte
 	ProviderNATConn *net.UDPConn
 	ChannelConn     *net.UDPConn
-	HermesID        common.Address
+	// Relayed is set when ProviderNATConn is connected to the relay instead of the provider.
+	Relayed  bool
+	HermesID common.Address
+	// Chained is set for every hop of a multi-hop connection except the entry one,
+	// meaning that connection is carried by the tunnel of a previous hop.
+	Chained bool
 }
@@ -18,6 +18,7 @@
 package connectionstate
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -35,6 +36,12 @@ const (
 	AppTopicConnectionStatistics = "Statistics"
 	// AppTopicConnectionSession represents the session lifetime changes
 	AppTopicConnectionSession = "Session"
+	// AppTopicConnectionFailover represents switches of a dead session to a failover proposal
+	AppTopicConnectionFailover = "Failover"
+	// AppTopicConnectionQuota represents quota warnings and quota exhaustion of the connection
+	AppTopicConnectionQuota = "Quota"
+	// AppTopicConnectionProbe represents connection quality measured by active probes
+	AppTopicConnectionProbe = "Probe"
 )
 
 // AppEventConnectionState is the struct we'll emit on a AppEventConnectionState topic event
@@ -67,8 +74,13 @@ const (
 	StateConnectionFailed = State("ConnectionFailed")
 )
 
+// DefaultConnectionID addresses the default consumer connection, the one which routes host traffic
+const DefaultConnectionID = "default"
+
 // Status holds connection state, session id and proposal of the connection
 type Status struct {
+	// ConnectionID addresses the connection among simultaneous consumer connections
+	ConnectionID     string
 	StartedAt        time.Time
 	ConsumerID       identity.Identity
 	ConsumerLocation locationstate.Location
@@ -76,6 +88,67 @@ type Status struct {
 	State            State
 	SessionID        session.ID
 	Proposal         market.ServiceProposal
+	// Hops lists every hop of a multi-hop connection starting with the entry one,
+	// it is empty for a single hop connection.
+	Hops []HopStatus
+	// Interface is the network interface which carries connection traffic, if it has a dedicated one
+	Interface string
+	// Quota holds usage limits of the connection, it is nil for an unlimited connection
+	Quota *QuotaStatus
+}
+
+// QuotaStatus holds usage limits of the connection together with the current usage, zero limit means unlimited
+type QuotaStatus struct {
+	BytesLimit    uint64
+	BytesUsed     uint64
+	DurationLimit time.Duration
+	DurationUsed  time.Duration
+	TokensLimit   *big.Int
+	TokensUsed    *big.Int
+}
+
+// RemainingBytes returns bytes left until the quota is exhausted.
+func (q QuotaStatus) RemainingBytes() uint64 {
+	if q.BytesUsed >= q.BytesLimit {
+		return 0
+	}
+	return q.BytesLimit - q.BytesUsed
+}
+
+// RemainingDuration returns time left until the quota is exhausted.
+func (q QuotaStatus) RemainingDuration() time.Duration {
+	if q.DurationUsed >= q.DurationLimit {
+		return 0
+	}
+	return q.DurationLimit - q.DurationUsed
+}
+
+// RemainingTokens returns tokens left until the quota is exhausted.
+func (q QuotaStatus) RemainingTokens() *big.Int {
+	if q.TokensLimit == nil {
+		return new(big.Int)
+	}
+	used := q.TokensUsed
+	if used == nil {
+		used = new(big.Int)
+	}
+	remaining := new(big.Int).Sub(q.TokensLimit, used)
+	if remaining.Sign() < 0 {
+		return new(big.Int)
+	}
+	return remaining
+}
+
+// HopStatus holds session, proposal and statistics of a single multi-hop connection hop
+type HopStatus struct {
+	SessionID  session.ID
+	Proposal   market.ServiceProposal
+	Statistics Statistics
+}
+
+// IsDefault returns true if status belongs to the default consumer connection
+func (s *Status) IsDefault() bool {
+	return s.ConnectionID == "" || s.ConnectionID == DefaultConnectionID
 }
 
 // Duration returns elapsed time from marked session start
@@ -104,3 +177,48 @@ type AppEventConnectionStatistics struct {
 	Stats       Statistics
 	SessionInfo Status
 }
+
+// SessionStatistics returns statistics of the given session, which is either the connection session
+// or a session of one of multi-hop connection hops.
+func (e AppEventConnectionStatistics) SessionStatistics(sessionID session.ID) (Statistics, bool) {
+	if e.SessionInfo.SessionID == sessionID {
+		return e.Stats, true
+	}
+	for _, hop := range e.SessionInfo.Hops {
+		if hop.SessionID == sessionID {
+			return hop.Statistics, true
+		}
+	}
+	return Statistics{}, false
+}
+
+// QuotaKind names the kind of connection quota
+type QuotaKind string
+
+const (
+	// QuotaBytes limits bytes sent and received through the connection
+	QuotaBytes = QuotaKind("bytes")
+	// QuotaDuration limits the connection time
+	QuotaDuration = QuotaKind("duration")
+	// QuotaTokens limits tokens spent on the connection
+	QuotaTokens = QuotaKind("tokens")
+)
+
+// AppEventConnectionQuota represents a quota warning threshold being crossed, or the quota being exhausted
+type AppEventConnectionQuota struct {
+	Kind QuotaKind
+	// Threshold is the used fraction of quota which was crossed, it is 1 once quota is exhausted
+	Threshold   float64
+	Exhausted   bool
+	Quota       QuotaStatus
+	SessionInfo Status
+}
+
+// AppEventConnectionFailover represents a switch from a dead session to a failover proposal
+type AppEventConnectionFailover struct {
+	From        market.ServiceProposal
+	To          market.ServiceProposal
+	Reason      string
+	Error       string
+	SessionInfo Status
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connectionstate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/datasize"
+)
+
+// ProbeResult represents connection quality measured by an active probe.
+type ProbeResult struct {
+	At time.Time
+	// Latency is the average round trip time of probe pings.
+	Latency time.Duration
+	// Jitter is the average difference between round trip times of consecutive probe pings.
+	Jitter time.Duration
+	// PacketLoss is the fraction of probe pings left without a response.
+	PacketLoss float64
+	// Download and Upload are set only when throughput test was requested.
+	Download datasize.BitSpeed
+	Upload   datasize.BitSpeed
+}
+
+func (r ProbeResult) String() string {
+	return fmt.Sprintf(
+		"latency: %s, jitter: %s, loss: %.0f%%, throughput: %s/%s",
+		r.Latency,
+		r.Jitter,
+		r.PacketLoss*100,
+		r.Download,
+		r.Upload,
+	)
+}
+
+// AppEventConnectionProbe represents a finished connection probe.
+type AppEventConnectionProbe struct {
+	Result      ProbeResult
+	SessionInfo Status
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// FailoverStrategy defines how a replacement proposal is picked when the active session dies,
+// it is supported for single hop connections only
+type FailoverStrategy struct {
+	// Proposals are tried in the given order
+	Proposals []market.ServiceProposal
+	// Filter picks replacement proposals from discovery once Proposals are exhausted
+	Filter *proposal.Filter
+}
+
+// Enabled returns true if strategy has any source of replacement proposals
+func (fs FailoverStrategy) Enabled() bool {
+	return len(fs.Proposals) > 0 || fs.Filter != nil
+}
+
+// connectionLost is called when the session of the given context dies. It switches connection
+// to a failover proposal when strategy is configured, or disconnects otherwise.
+func (m *connectionManager) connectionLost(ctx context.Context, reason string) {
+	m.failoverLock.Lock()
+	defer m.failoverLock.Unlock()
+
+	// Connection is already being torn down by whoever cancelled it.
+	if ctx.Err() != nil {
+		return
+	}
+
+	state := m.Status().State
+	failover := m.connectOptions.Params.Failover
+	if !failover.Enabled() || (state != connectionstate.Connected && state != connectionstate.Reconnecting) {
+		logDisconnectError(m.Disconnect())
+		return
+	}
+
+	m.failover(failover, reason)
+}
+
+// failover tears down the current session and connects to the next failover candidate with the same connect params,
+// while keeping kill switch rules in place until a new session takes them over.
+func (m *connectionManager) failover(failover FailoverStrategy, reason string) {
+	options := m.connectOptions
+	from := m.Status().Proposal
+	quota := m.quotaTracker()
+	defer m.setResumeQuota(nil)
+	tried := []market.ServiceProposal{from}
+
+	m.setKeepTrafficBlock(true)
+	defer m.releaseTrafficBlock()
+
+	log.Warn().Msgf("Session with %s died (%s), failing over", from.ProviderID, reason)
+	logDisconnectError(m.Disconnect())
+
+	for {
+		next, ok := m.nextFailoverProposal(failover, tried)
+		if !ok {
+			log.Error().Msg("No failover proposals left, staying disconnected")
+			return
+		}
+		tried = append(tried, next)
+
+		m.setResumeQuota(quota)
+		err := m.Connect(options.ConsumerID, options.HermesID, next, options.Params)
+		m.publishFailover(from, next, reason, err)
+		if err == nil {
+			log.Info().Msgf("Failed over from %s to %s", from.ProviderID, next.ProviderID)
+			return
+		}
+
+		log.Warn().Err(err).Msgf("Failover to %s failed", next.ProviderID)
+		from = next
+		reason = err.Error()
+	}
+}
+
+// nextFailoverProposal returns the highest ranked candidate which was not tried yet.
+func (m *connectionManager) nextFailoverProposal(failover FailoverStrategy, tried []market.ServiceProposal) (market.ServiceProposal, bool) {
+	isTried := func(candidate market.ServiceProposal) bool {
+		for _, p := range tried {
+			if p.ProviderID == candidate.ProviderID && p.ServiceType == candidate.ServiceType {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, candidate := range failover.Proposals {
+		if !isTried(candidate) {
+			return candidate, true
+		}
+	}
+
+	if failover.Filter == nil || m.proposalRepository == nil {
+		return market.ServiceProposal{}, false
+	}
+
+	candidates, err := m.proposalRepository.Proposals(failover.Filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Could not fetch failover proposals")
+		return market.ServiceProposal{}, false
+	}
+	for _, candidate := range candidates {
+		if !isTried(candidate) {
+			return candidate, true
+		}
+	}
+
+	return market.ServiceProposal{}, false
+}
+
+func (m *connectionManager) publishFailover(from, to market.ServiceProposal, reason string, err error) {
+	event := connectionstate.AppEventConnectionFailover{
+		From:        from,
+		To:          to,
+		Reason:      reason,
+		SessionInfo: m.Status(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	m.eventBus.Publish(connectionstate.AppTopicConnectionFailover, event)
+}
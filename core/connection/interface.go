@@ -39,6 +39,11 @@ type Connection interface {
 	Statistics() (connectionstate.Statistics, error)
 }
 
+// InterfaceNamer is implemented by connections which carry traffic through a dedicated network interface
+type InterfaceNamer interface {
+	InterfaceName() string
+}
+
 // StateChannel is the channel we receive state change events on
 type StateChannel chan connectionstate.State
 
@@ -46,12 +51,17 @@ type StateChannel chan connectionstate.State
 type Manager interface {
 	// Connect creates new connection from given consumer to provider, reports error if connection already exists
 	Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, params ConnectParams) error
+	// ConnectMultiHop creates new connection chained through the given ordered list of proposals, the first one being the entry
+	// and the last one being the exit hop, reports error if connection already exists
+	ConnectMultiHop(consumerID identity.Identity, hermesID common.Address, proposals []market.ServiceProposal, params ConnectParams) error
 	// Status queries current status of connection
 	Status() connectionstate.Status
 	// Disconnect closes established connection, reports error if no connection
 	Disconnect() error
 	// CheckChannel checks if current session channel is alive, returns error on failed keep-alive ping
 	CheckChannel(context.Context) error
+	// Probe measures latency, jitter and optionally throughput of the established connection
+	Probe(ctx context.Context, throughput bool) (connectionstate.ProbeResult, error)
 	// Reconnect reconnects current session
 	Reconnect()
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/location"
 
 	"github.com/mysteriumnetwork/node/core/ip"
@@ -62,8 +63,31 @@ var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	// ErrUnlockRequired indicates that the consumer identity has not been unlocked yet
 	ErrUnlockRequired = errors.New("unlock required")
+	// ErrChainingNotSupported indicates that connection can not be used as a non-entry hop of multi-hop connection
+	ErrChainingNotSupported = errors.New("connection does not support chaining")
+	// ErrNoHops indicates that multi-hop connection was requested without any proposals
+	ErrNoHops = errors.New("at least one proposal is required")
+	// ErrSplitTunnelMultiHop indicates that split tunneling was requested for a multi-hop connection.
+	ErrSplitTunnelMultiHop = errors.New("split tunneling is not supported for multi-hop connections")
+	// ErrFailoverMultiHop indicates that failover was requested for a multi-hop connection.
+	ErrFailoverMultiHop = errors.New("failover is not supported for multi-hop connections")
+	// ErrSplitTunnelNotSupported indicates that connection can not route only a part of the traffic through the tunnel
+	ErrSplitTunnelNotSupported = errors.New("connection does not support split tunneling")
+	// ErrDetachingNotSupported indicates that connection can not be kept on its own interface
+	ErrDetachingNotSupported = errors.New("connection does not support detaching")
 )
 
+// SessionRejectedError is returned when provider did not admit the session, e.g. because of its capacity limits.
+// Consumer may pick another proposal in such case.
+type SessionRejectedError struct {
+	Reason  pb.RejectionReason
+	Message string
+}
+
+func (e *SessionRejectedError) Error() string {
+	return fmt.Sprintf("session rejected by provider: %s: %s", e.Reason, e.Message)
+}
+
 // IPCheckConfig contains common params for connection ip check.
 type IPCheckConfig struct {
 	MaxAttempts             int
@@ -81,6 +105,7 @@ type KeepAliveConfig struct {
 type Config struct {
 	IPCheck   IPCheckConfig
 	KeepAlive KeepAliveConfig
+	Probe     ProbeConfig
 }
 
 // DefaultConfig returns default params.
@@ -95,6 +120,14 @@ func DefaultConfig() Config {
 			SendTimeout:     5 * time.Second,
 			MaxSendErrCount: 5,
 		},
+		Probe: ProbeConfig{
+			PingCount:           10,
+			PingInterval:        200 * time.Millisecond,
+			PingTimeout:         2 * time.Second,
+			ThroughputDuration:  5 * time.Second,
+			ThroughputChunkSize: 64 * 1024,
+			ThroughputStreams:   4,
+		},
 	}
 }
 
@@ -120,6 +153,7 @@ type PaymentEngineFactory func(channel p2p.Channel, consumer, provider identity.
 
 type connectionManager struct {
 	// These are passed on creation.
+	id                   string
 	paymentEngineFactory PaymentEngineFactory
 	newConnection        Creator
 	eventBus             eventbus.EventBus
@@ -129,6 +163,7 @@ type connectionManager struct {
 	statsReportInterval  time.Duration
 	validator            validator
 	p2pDialer            p2p.Dialer
+	proposalRepository   proposal.Repository
 	timeGetter           TimeGetter
 
 	// These are populated by Connect at runtime.
@@ -144,9 +179,24 @@ type connectionManager struct {
 	acknowledge            func()
 	cancel                 func()
 	channel                p2p.Channel
+	proposals              []market.ServiceProposal
+	hopsPending            int
+	failoverLock           sync.Mutex
+
+	quotaLock      sync.Mutex
+	quota          *quotaTracker
+	resumeQuota    *quotaTracker
+	quotaSubscribe sync.Once
+
+	trafficBlockLock   sync.Mutex
+	removeTrafficBlock func()
+	trafficBlockOwned  bool
+	keepTrafficBlock   bool
 
 	discoLock      sync.Mutex
 	connectOptions ConnectOptions
+
+	probeLock sync.Mutex
 }
 
 // NewManager creates connection manager with given dependencies
@@ -160,8 +210,11 @@ func NewManager(
 	statsReportInterval time.Duration,
 	validator validator,
 	p2pDialer p2p.Dialer,
+	proposalRepository proposal.Repository,
+	id string,
 ) *connectionManager {
 	return &connectionManager{
+		id:                   id,
 		newConnection:        connectionCreator,
 		status:               connectionstate.Status{State: connectionstate.NotConnected},
 		eventBus:             eventBus,
@@ -174,13 +227,38 @@ func NewManager(
 		statsReportInterval:  statsReportInterval,
 		validator:            validator,
 		p2pDialer:            p2pDialer,
+		proposalRepository:   proposalRepository,
 		timeGetter:           time.Now,
 	}
 }
 
-func (m *connectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, params ConnectParams) (err error) {
+func (m *connectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, params ConnectParams) error {
+	return m.ConnectMultiHop(consumerID, hermesID, []market.ServiceProposal{proposal}, params)
+}
+
+func (m *connectionManager) ConnectMultiHop(consumerID identity.Identity, hermesID common.Address, proposals []market.ServiceProposal, params ConnectParams) (err error) {
 	var sessionID session.ID
 
+	if len(proposals) == 0 {
+		return ErrNoHops
+	}
+	if len(proposals) > 1 && params.SplitTunnel.Enabled() {
+		return ErrSplitTunnelMultiHop
+	}
+	if len(proposals) > 1 && params.Failover.Enabled() {
+		return ErrFailoverMultiHop
+	}
+	if len(proposals) > 1 && params.Detached {
+		return ErrDetachingNotSupported
+	}
+	if err := params.SplitTunnel.Validate(); err != nil {
+		return err
+	}
+	if err := params.Quota.Validate(); err != nil {
+		return err
+	}
+	proposal := proposals[0]
+
 	tracer := trace.NewTracer("Consumer whole Connect")
 	defer func() {
 		traceResult := tracer.Finish(m.eventBus, string(sessionID))
@@ -198,16 +276,20 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		return ErrAlreadyExists
 	}
 
-	err = m.validator.Validate(consumerID, proposal)
-	if err != nil {
-		return err
+	for _, hop := range proposals {
+		err = m.validator.Validate(consumerID, hop)
+		if err != nil {
+			return err
+		}
 	}
+	m.proposals = proposals
 
 	m.ctxLock.Lock()
 	m.ctx, m.cancel = context.WithCancel(context.Background())
 	m.ctxLock.Unlock()
 
-	m.statusConnecting(consumerID, hermesID, proposal)
+	m.statusConnecting(consumerID, hermesID, proposals)
+	m.startQuota(params.Quota)
 	defer func() {
 		if err != nil {
 			log.Err(err).Msg("Connect failed, disconnecting")
@@ -217,7 +299,7 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 
 	providerID := identity.FromAddress(proposal.ProviderID)
 
-	err = m.createP2PChannel(m.currentCtx(), consumerID, providerID, proposal, tracer)
+	m.channel, err = m.createP2PChannel(m.currentCtx(), consumerID, providerID, proposal, tracer)
 	if err != nil {
 		return fmt.Errorf("could not create p2p channel during connect: %w", err)
 	}
@@ -244,6 +326,9 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 	m.setStatus(func(status *connectionstate.Status) {
 		status.SessionID = sessionID
 	})
+	m.setHopStatus(0, func(hop *connectionstate.HopStatus) {
+		hop.SessionID = sessionID
+	})
 	m.publishSessionCreate(sessionID)
 	paymentSession.SetSessionID(string(sessionID))
 	tracer.EndStage(traceStart)
@@ -258,6 +343,7 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		Proposal:        proposal,
 		ProviderNATConn: m.channel.ServiceConn(),
 		ChannelConn:     m.channel.Conn(),
+		Relayed:         m.channel.Relayed(),
 		HermesID:        hermesID,
 	}
 	err = m.startConnection(m.currentCtx(), connection, m.connectOptions, tracer)
@@ -275,9 +361,129 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		return err
 	}
 
+	if len(proposals) > 1 {
+		go m.hopStatsLoop(0, connection)
+	}
+
+	for i := 1; i < len(proposals); i++ {
+		err = m.connectHop(consumerID, hermesID, i, proposals[i], params)
+		if err != nil {
+			if err == context.Canceled {
+				return ErrConnectionCancelled
+			}
+			m.publishStateEvent(connectionstate.StateConnectionFailed)
+
+			log.Info().Err(err).Msgf("Cancelling multi-hop connection initiation on hop %d: ", i)
+			m.Cancel()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// connectHop establishes chained connection to the given proposal through the tunnel of a previous hop.
+func (m *connectionManager) connectHop(consumerID identity.Identity, hermesID common.Address, index int, proposal market.ServiceProposal, params ConnectParams) (err error) {
+	var sessionID session.ID
+
+	tracer := trace.NewTracer(fmt.Sprintf("Consumer hop %d Connect", index))
+	defer func() {
+		traceResult := tracer.Finish(m.eventBus, string(sessionID))
+		log.Debug().Msgf("Consumer hop %d connection trace: %s", index, traceResult)
+	}()
+
+	providerID := identity.FromAddress(proposal.ProviderID)
+
+	channel, err := m.createP2PChannel(m.currentCtx(), consumerID, providerID, proposal, tracer)
+	if err != nil {
+		return fmt.Errorf("could not create p2p channel for hop %d: %w", index, err)
+	}
+
+	connection, err := m.newConnection(proposal.ServiceType)
+	if err != nil {
+		return err
+	}
+
+	paymentSession, err := m.paymentLoop(channel, consumerID, providerID, hermesID, proposal)
+	if err != nil {
+		return err
+	}
+
+	sessionDTO, err := m.createP2PSession(m.currentCtx(), connection, channel, consumerID, hermesID, proposal, tracer)
+	sessionID = session.ID(sessionDTO.GetID())
+	if err != nil {
+		m.sendSessionStatus(channel, consumerID, sessionID, connectivity.StatusSessionEstablishmentFailed, err)
+		return err
+	}
+
+	go m.keepAliveLoop(channel, sessionID)
+	m.setHopStatus(index, func(hop *connectionstate.HopStatus) {
+		hop.SessionID = sessionID
+	})
+	paymentSession.SetSessionID(string(sessionID))
+
+	trace := tracer.StartStage("Consumer start connection")
+	defer tracer.EndStage(trace)
+
+	err = connection.Start(m.currentCtx(), ConnectOptions{
+		SessionID:       sessionID,
+		SessionConfig:   sessionDTO.GetConfig(),
+		Params:          params,
+		ConsumerID:      consumerID,
+		ProviderID:      providerID,
+		Proposal:        proposal,
+		ProviderNATConn: channel.ServiceConn(),
+		ChannelConn:     channel.Conn(),
+		Relayed:         channel.Relayed(),
+		HermesID:        hermesID,
+		Chained:         true,
+	})
+	if err != nil {
+		m.addCleanupAfterDisconnect(func() error {
+			return m.sendSessionStatus(channel, consumerID, sessionID, connectivity.StatusConnectionFailed, err)
+		})
+		return err
+	}
+	m.addCleanup(func() error {
+		log.Trace().Msgf("Cleaning: stopping hop %d connection", index)
+		defer log.Trace().Msgf("Cleaning: stopping hop %d connection DONE", index)
+		connection.Stop()
+		return nil
+	})
+
+	err = m.waitForConnectedState(connection.State())
+	if err != nil {
+		return err
+	}
+	m.statusHopConnected()
+
+	go m.hopStatsLoop(index, connection)
+	go m.consumeConnectionStates(m.currentCtx(), connection.State())
+	go m.connectionWaiter(m.currentCtx(), connection)
+
 	return nil
 }
 
+// hopStatsLoop periodically updates statistics of the given multi-hop connection hop.
+func (m *connectionManager) hopStatsLoop(index int, conn Connection) {
+	ctx := m.currentCtx()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.statsReportInterval):
+			stats, err := conn.Statistics()
+			if err != nil {
+				log.Warn().Err(err).Msgf("Could not get hop %d connection statistics", index)
+				continue
+			}
+			m.setHopStatus(index, func(hop *connectionstate.HopStatus) {
+				hop.Statistics = stats
+			})
+		}
+	}
+}
+
 func (m *connectionManager) clearIPCache() {
 	if cr, ok := m.ipResolver.(*ip.CachedResolver); ok {
 		cr.ClearCache()
@@ -324,11 +530,7 @@ func (m *connectionManager) sendSessionStatus(channel p2p.ChannelSender, consume
 		Message:    errDetailsMsg,
 	}
 
-	log.Debug().Msgf("Sending session status P2P message to %q: %s", p2p.TopicSessionStatus, sessionStatus.String())
-
-	ctx, cancel := context.WithTimeout(m.currentCtx(), 20*time.Second)
-	defer cancel()
-	_, err := channel.Send(ctx, p2p.TopicSessionStatus, p2p.ProtoMessage(sessionStatus))
+	err := p2p.NewSessionClient(channel).Status(m.currentCtx(), sessionStatus)
 	if err != nil {
 		return fmt.Errorf("could not send p2p session status message: %w", err)
 	}
@@ -398,22 +600,26 @@ func (m *connectionManager) cleanAfterDisconnect() {
 	m.cleanupAfterDisconnect = nil
 }
 
-func (m *connectionManager) createP2PChannel(ctx context.Context, consumerID, providerID identity.Identity, proposal market.ServiceProposal, tracer *trace.Tracer) error {
+func (m *connectionManager) createP2PChannel(ctx context.Context, consumerID, providerID identity.Identity, proposal market.ServiceProposal, tracer *trace.Tracer) (p2p.Channel, error) {
 	trace := tracer.StartStage("Consumer P2P channel creation")
 	defer tracer.EndStage(trace)
 
 	contactDef, err := p2p.ParseContact(proposal.ProviderContacts)
 	if err != nil {
-		return fmt.Errorf("provider does not support p2p communication: %w", err)
+		return nil, fmt.Errorf("provider does not support p2p communication: %w", err)
+	}
+	relayDef, err := p2p.ParseRelayContact(proposal.ProviderContacts)
+	if err != nil && err != p2p.ErrContactNotFound {
+		log.Warn().Err(err).Msg("Ignoring invalid provider relay contact")
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, p2pDialTimeout)
 	defer cancel()
 
 	// TODO register all handlers before channel read/write loops
-	channel, err := m.p2pDialer.Dial(timeoutCtx, consumerID, providerID, proposal.ServiceType, contactDef, tracer)
+	channel, err := m.p2pDialer.Dial(timeoutCtx, consumerID, providerID, proposal.ServiceType, contactDef, relayDef, tracer)
 	if err != nil {
-		return fmt.Errorf("p2p dialer failed: %w", err)
+		return nil, fmt.Errorf("p2p dialer failed: %w", err)
 	}
 	m.addCleanupAfterDisconnect(func() error {
 		log.Trace().Msg("Cleaning: closing P2P communication channel")
@@ -422,8 +628,7 @@ func (m *connectionManager) createP2PChannel(ctx context.Context, consumerID, pr
 		return channel.Close()
 	})
 
-	m.channel = channel
-	return nil
+	return channel, nil
 }
 
 func (m *connectionManager) addCleanupAfterDisconnect(fn func() error) {
@@ -464,18 +669,13 @@ func (m *connectionManager) createP2PSession(ctx context.Context, c Connection,
 		ProposalID: int64(proposal.ID),
 		Config:     config,
 	}
-	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionCreate, sessionRequest.String())
-	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
-	res, err := p2pChannel.Send(ctx, p2p.TopicSessionCreate, p2p.ProtoMessage(sessionRequest))
+	sessionClient := p2p.NewSessionClient(p2pChannel)
+	sessionResponse, err := sessionClient.Create(ctx, sessionRequest)
 	if err != nil {
 		return nil, fmt.Errorf("could not send p2p session create request: %w", err)
 	}
-
-	var sessionResponse pb.SessionResponse
-	err = res.UnmarshalProto(&sessionResponse)
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal session reply to proto: %w", err)
+	if rejection := sessionResponse.GetRejection(); rejection.GetReason() != pb.RejectionReason_NOT_REJECTED {
+		return nil, &SessionRejectedError{Reason: rejection.GetReason(), Message: rejection.GetMessage()}
 	}
 	log.Info().Msgf("Provider's session config: %s", string(sessionResponse.Config))
 
@@ -484,10 +684,7 @@ func (m *connectionManager) createP2PSession(ctx context.Context, c Connection,
 			ConsumerID: consumerID.Address,
 			SessionID:  sessionResponse.GetID(),
 		}
-		log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionAcknowledge, pc.String())
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
-		_, err := p2pChannel.Send(ctx, p2p.TopicSessionAcknowledge, p2p.ProtoMessage(pc))
+		err := sessionClient.Acknowledge(context.Background(), pc)
 		if err != nil {
 			log.Warn().Err(err).Msg("Acknowledge failed")
 		}
@@ -501,10 +698,7 @@ func (m *connectionManager) createP2PSession(ctx context.Context, c Connection,
 			SessionID:  sessionResponse.GetID(),
 		}
 
-		log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionDestroy, sessionDestroy.String())
-		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-		defer cancel()
-		_, err := p2pChannel.Send(ctx, p2p.TopicSessionDestroy, p2p.ProtoMessage(sessionDestroy))
+		err := sessionClient.Destroy(context.Background(), sessionDestroy)
 		if err != nil {
 			return fmt.Errorf("could not send session destroy request: %w", err)
 		}
@@ -512,7 +706,7 @@ func (m *connectionManager) createP2PSession(ctx context.Context, c Connection,
 		return nil
 	})
 
-	return &sessionResponse, nil
+	return sessionResponse, nil
 }
 
 func (m *connectionManager) publishSessionCreate(sessionID session.ID) {
@@ -548,7 +742,15 @@ func (m *connectionManager) startConnection(ctx context.Context, conn Connection
 		return nil
 	})
 
-	err = m.setupTrafficBlock(connectOptions.Params.DisableKillSwitch)
+	if namer, ok := conn.(InterfaceNamer); ok {
+		m.setStatus(func(status *connectionstate.Status) {
+			status.Interface = namer.InterfaceName()
+		})
+	}
+
+	// Kill switch would block everything outside the tunnel, which is not included on purpose.
+	params := connectOptions.Params
+	err = m.setupTrafficBlock(params.DisableKillSwitch || params.Detached || !params.SplitTunnel.FullTunnel())
 	if err != nil {
 		return err
 	}
@@ -567,14 +769,18 @@ func (m *connectionManager) startConnection(ctx context.Context, conn Connection
 		return nil
 	})
 
-	go m.consumeConnectionStates(conn.State())
-	go m.connectionWaiter(conn)
+	go m.consumeConnectionStates(m.currentCtx(), conn.State())
+	go m.connectionWaiter(m.currentCtx(), conn)
 
 	// Clear IP cache so session IP check can report that IP has really changed.
 	m.clearIPCache()
 
 	go m.checkSessionIP(m.channel, connectOptions.ConsumerID, connectOptions.SessionID, originalPublicIP)
 
+	if m.config.Probe.Interval > 0 {
+		go m.probeLoop(m.currentCtx())
+	}
+
 	return nil
 }
 
@@ -582,7 +788,9 @@ func (m *connectionManager) Status() connectionstate.Status {
 	m.statusLock.RLock()
 	defer m.statusLock.RUnlock()
 
-	return m.status
+	status := m.status
+	status.ConnectionID = m.id
+	return status
 }
 
 func (m *connectionManager) setStatus(delta func(status *connectionstate.Status)) {
@@ -600,25 +808,61 @@ func (m *connectionManager) setStatus(delta func(status *connectionstate.Status)
 	}
 }
 
-func (m *connectionManager) statusConnecting(consumerID identity.Identity, accountantID common.Address, proposal market.ServiceProposal) {
+func (m *connectionManager) setHopStatus(index int, delta func(hop *connectionstate.HopStatus)) {
+	m.setStatus(func(status *connectionstate.Status) {
+		if index >= len(status.Hops) {
+			return
+		}
+
+		// Hops are copied, so that statuses already handed out are never modified.
+		hops := make([]connectionstate.HopStatus, len(status.Hops))
+		copy(hops, status.Hops)
+		delta(&hops[index])
+		status.Hops = hops
+	})
+}
+
+func (m *connectionManager) statusConnecting(consumerID identity.Identity, accountantID common.Address, proposals []market.ServiceProposal) {
+	var hops []connectionstate.HopStatus
+	if len(proposals) > 1 {
+		for _, proposal := range proposals {
+			hops = append(hops, connectionstate.HopStatus{Proposal: proposal})
+		}
+	}
+
 	m.setStatus(func(status *connectionstate.Status) {
+		m.hopsPending = len(proposals) - 1
 		*status = connectionstate.Status{
 			StartedAt:        m.timeGetter(),
 			ConsumerID:       consumerID,
 			ConsumerLocation: m.locationResolver.GetOrigin(),
 			HermesID:         accountantID,
-			Proposal:         proposal,
+			Proposal:         proposals[0],
 			State:            connectionstate.Connecting,
+			Hops:             hops,
 		}
 	})
 }
 
 func (m *connectionManager) statusConnected() {
 	m.setStatus(func(status *connectionstate.Status) {
+		// Multi-hop connection is not connected until its last hop is.
+		if m.hopsPending > 0 {
+			return
+		}
 		status.State = connectionstate.Connected
 	})
 }
 
+func (m *connectionManager) statusHopConnected() {
+	m.setStatus(func(status *connectionstate.Status) {
+		m.hopsPending--
+		if m.hopsPending == 0 {
+			status.State = connectionstate.Connected
+		}
+	})
+}
+
 func (m *connectionManager) statusReconnecting() {
 	m.setStatus(func(status *connectionstate.Status) {
 		status.State = connectionstate.Reconnecting
@@ -685,7 +929,7 @@ func (m *connectionManager) disconnect() {
 	m.cleanAfterDisconnect()
 }
 
-func (m *connectionManager) connectionWaiter(connection Connection) {
+func (m *connectionManager) connectionWaiter(ctx context.Context, connection Connection) {
 	err := connection.Wait()
 	if err != nil {
 		log.Warn().Err(err).Msg("Connection exited with error")
@@ -693,7 +937,7 @@ func (m *connectionManager) connectionWaiter(connection Connection) {
 		log.Info().Msg("Connection exited")
 	}
 
-	logDisconnectError(m.Disconnect())
+	m.connectionLost(ctx, "connection exited")
 }
 
 func (m *connectionManager) waitForConnectedState(stateChannel <-chan connectionstate.State) error {
@@ -722,13 +966,13 @@ func (m *connectionManager) waitForConnectedState(stateChannel <-chan connection
 	}
 }
 
-func (m *connectionManager) consumeConnectionStates(stateChannel <-chan connectionstate.State) {
+func (m *connectionManager) consumeConnectionStates(ctx context.Context, stateChannel <-chan connectionstate.State) {
 	for state := range stateChannel {
 		m.onStateChanged(state)
 	}
 
 	log.Debug().Msg("State updater stopCalled")
-	logDisconnectError(m.Disconnect())
+	m.connectionLost(ctx, "connection state channel closed")
 }
 
 func (m *connectionManager) onStateChanged(state connectionstate.State) {
@@ -748,24 +992,63 @@ func (m *connectionManager) setupTrafficBlock(disableKillSwitch bool) error {
 		return nil
 	}
 
-	outboundIP, err := m.ipResolver.GetOutboundIP()
-	if err != nil {
-		return err
-	}
+	m.trafficBlockLock.Lock()
+	// Rule may still be in place, if it was kept during failover.
+	if m.removeTrafficBlock == nil {
+		outboundIP, err := m.ipResolver.GetOutboundIP()
+		if err != nil {
+			m.trafficBlockLock.Unlock()
+			return err
+		}
 
-	removeRule, err := firewall.BlockNonTunnelTraffic(firewall.Session, outboundIP)
-	if err != nil {
-		return err
+		removeRule, err := firewall.BlockNonTunnelTraffic(firewall.Session, outboundIP)
+		if err != nil {
+			m.trafficBlockLock.Unlock()
+			return err
+		}
+		m.removeTrafficBlock = removeRule
 	}
+	m.trafficBlockOwned = true
+	m.trafficBlockLock.Unlock()
+
 	m.addCleanup(func() error {
 		log.Trace().Msg("Cleaning: traffic block rule")
 		defer log.Trace().Msg("Cleaning: traffic block rule DONE")
-		removeRule()
+
+		m.trafficBlockLock.Lock()
+		defer m.trafficBlockLock.Unlock()
+
+		m.trafficBlockOwned = false
+		if m.keepTrafficBlock {
+			log.Info().Msg("Keeping traffic block rule for failover")
+			return nil
+		}
+		m.removeTrafficBlock()
+		m.removeTrafficBlock = nil
 		return nil
 	})
 	return nil
 }
 
+func (m *connectionManager) setKeepTrafficBlock(keep bool) {
+	m.trafficBlockLock.Lock()
+	defer m.trafficBlockLock.Unlock()
+
+	m.keepTrafficBlock = keep
+}
+
+// releaseTrafficBlock removes traffic block rule which was kept during failover, unless a new session took it over.
+func (m *connectionManager) releaseTrafficBlock() {
+	m.trafficBlockLock.Lock()
+	defer m.trafficBlockLock.Unlock()
+
+	m.keepTrafficBlock = false
+	if m.removeTrafficBlock != nil && !m.trafficBlockOwned {
+		m.removeTrafficBlock()
+		m.removeTrafficBlock = nil
+	}
+}
+
 func (m *connectionManager) publishStateEvent(state connectionstate.State) {
 	m.eventBus.Publish(connectionstate.AppTopicConnectionState, connectionstate.AppEventConnectionState{
 		State:       state,
@@ -778,34 +1061,35 @@ func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session
 	if channel == nil {
 		return
 	}
+	ctx := m.currentCtx()
 
 	// Register handler for handling p2p keep alive pings from provider.
-	channel.Handle(p2p.TopicKeepAlive, func(c p2p.Context) error {
-		var ping pb.P2PKeepAlivePing
-		if err := c.Request().UnmarshalProto(&ping); err != nil {
-			return err
-		}
-
+	p2p.HandleKeepAlivePing(channel, func(_ context.Context, ping *pb.P2PKeepAlivePing) error {
 		log.Debug().Msgf("Received p2p keepalive ping with SessionID=%s", ping.SessionID)
-		return c.OK()
+		return nil
 	})
 
 	// Send pings to provider.
 	var errCount int
 	for {
 		select {
-		case <-m.currentCtx().Done():
-			log.Debug().Msgf("Stopping p2p keepalive: %v", m.currentCtx().Err())
+		case <-ctx.Done():
+			log.Debug().Msgf("Stopping p2p keepalive: %v", ctx.Err())
 			return
 		case <-time.After(m.config.KeepAlive.SendInterval):
-			ctx, cancel := context.WithTimeout(context.Background(), m.config.KeepAlive.SendTimeout)
-			if err := m.sendKeepAlivePing(ctx, channel, sessionID); err != nil {
+			pingCtx, cancel := context.WithTimeout(context.Background(), m.config.KeepAlive.SendTimeout)
+			if err := m.sendKeepAlivePing(pingCtx, channel, sessionID); err != nil {
 				log.Err(err).Msgf("Failed to send p2p keepalive ping. SessionID=%s", sessionID)
+				m.eventBus.Publish(p2p.AppTopicKeepAliveFailed, p2p.AppEventKeepAliveFailed{
+					Role:      p2p.RoleConsumer,
+					SessionID: string(sessionID),
+					Error:     err.Error(),
+				})
 				errCount++
 				if errCount == m.config.KeepAlive.MaxSendErrCount {
 					log.Error().Msgf("Max p2p keepalive err count reached, disconnecting. SessionID=%s", sessionID)
-					m.Disconnect()
 					cancel()
+					m.connectionLost(ctx, "max p2p keepalive err count reached")
 					return
 				}
 			} else {
@@ -820,8 +1104,7 @@ func (m *connectionManager) sendKeepAlivePing(ctx context.Context, channel p2p.C
 	msg := &pb.P2PKeepAlivePing{
 		SessionID: string(sessionID),
 	}
-	_, err := channel.Send(ctx, p2p.TopicKeepAlive, p2p.ProtoMessage(msg))
-	return err
+	return p2p.NewKeepAliveClient(channel).Ping(ctx, msg)
 }
 
 func (m *connectionManager) currentCtx() context.Context {
@@ -832,6 +1115,11 @@ func (m *connectionManager) currentCtx() context.Context {
 }
 
 func (m *connectionManager) Reconnect() {
+	if m.connectOptions.Params.Failover.Enabled() {
+		m.connectionLost(m.currentCtx(), "reconnect requested")
+		return
+	}
+
 	err := m.Disconnect()
 	if err != nil {
 		log.Error().Msgf("Failed to disconnect stale session: %v", err)
@@ -841,7 +1129,7 @@ func (m *connectionManager) Reconnect() {
 	m.cleanupFinishedLock.Lock()
 	defer m.cleanupFinishedLock.Unlock()
 	<-m.cleanupFinished
-	err = m.Connect(m.connectOptions.ConsumerID, m.connectOptions.HermesID, m.connectOptions.Proposal, m.connectOptions.Params)
+	err = m.ConnectMultiHop(m.connectOptions.ConsumerID, m.connectOptions.HermesID, m.proposals, m.connectOptions.Params)
 	if err != nil {
 		log.Error().Msgf("Failed to Reconnect: %v", err)
 	}
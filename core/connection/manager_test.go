@@ -28,6 +28,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/location"
 	"github.com/mysteriumnetwork/node/core/location/locationstate"
 	"github.com/mysteriumnetwork/node/trace"
@@ -38,6 +39,7 @@ import (
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
@@ -152,6 +154,8 @@ func (tc *testContext) SetupTest() {
 		tc.statsReportInterval,
 		&mockValidator{},
 		tc.mockP2P,
+		&mockProposalRepository{},
+		"",
 	)
 	tc.connManager.timeGetter = func() time.Time {
 		return tc.mockTime
@@ -368,6 +372,268 @@ func (tc *testContext) TestTwoConnectDisconnectCyclesReturnNoError() {
 	assert.Equal(tc.T(), connectionstate.NotConnected, tc.connManager.Status().State)
 }
 
+func (tc *testContext) TestMultiHopConnectReportsEveryHop() {
+	exitProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-2",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+
+	err := tc.connManager.ConnectMultiHop(consumerID, hermesID, []market.ServiceProposal{activeProposal, exitProposal}, ConnectParams{})
+	assert.NoError(tc.T(), err)
+
+	waitABit()
+	status := tc.connManager.Status()
+	assert.Equal(tc.T(), connectionstate.Connected, status.State)
+	assert.Equal(tc.T(), activeProposal, status.Proposal)
+	assert.Equal(
+		tc.T(),
+		[]connectionstate.HopStatus{
+			{SessionID: establishedSessionID, Proposal: activeProposal, Statistics: tc.mockStatistics},
+			{SessionID: establishedSessionID, Proposal: exitProposal, Statistics: tc.mockStatistics},
+		},
+		status.Hops,
+	)
+
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+	assert.Equal(tc.T(), connectionstate.NotConnected, tc.connManager.Status().State)
+}
+
+func (tc *testContext) TestMultiHopConnectRequiresProposals() {
+	assert.Equal(tc.T(), ErrNoHops, tc.connManager.ConnectMultiHop(consumerID, hermesID, nil, ConnectParams{}))
+}
+
+func (tc *testContext) TestFailoverSwitchesToNextRankedProposal() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+	tc.stubPublisher.Clear()
+
+	backupProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-2",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	params := ConnectParams{
+		Failover: FailoverStrategy{Proposals: []market.ServiceProposal{activeProposal, backupProposal}},
+	}
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, params))
+
+	tc.connManager.Reconnect()
+	waitABit()
+
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+	assert.Equal(tc.T(), backupProposal, tc.connManager.Status().Proposal)
+
+	var failovers []connectionstate.AppEventConnectionFailover
+	for _, v := range tc.stubPublisher.GetEventHistory() {
+		if v.Topic == connectionstate.AppTopicConnectionFailover {
+			failovers = append(failovers, v.Event.(connectionstate.AppEventConnectionFailover))
+		}
+	}
+	assert.Len(tc.T(), failovers, 1)
+	assert.Equal(tc.T(), activeProposal, failovers[0].From)
+	assert.Equal(tc.T(), backupProposal, failovers[0].To)
+	assert.Empty(tc.T(), failovers[0].Error)
+
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+}
+
+func (tc *testContext) TestFailoverKeepsTrafficBlockWhileCandidatesFail() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+	outgoingFirewall := &outgoingFirewallMock{}
+	defaultFirewall := firewall.DefaultOutgoingFirewall
+	firewall.DefaultOutgoingFirewall = outgoingFirewall
+	defer func() { firewall.DefaultOutgoingFirewall = defaultFirewall }()
+
+	// Connection to the first failover candidate dies before it is established.
+	var created int
+	newConnection := tc.connManager.newConnection
+	tc.connManager.newConnection = func(serviceType string) (Connection, error) {
+		conn, err := newConnection(serviceType)
+		created++
+		if created == 2 {
+			mock := conn.(*connectionMock)
+			mock.onStartReportStates = []fakeState{processStarted, processExited}
+			mock.onStopReportStates = nil
+		}
+		return conn, err
+	}
+
+	failingProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-2",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	backupProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-3",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	params := ConnectParams{
+		Failover: FailoverStrategy{Proposals: []market.ServiceProposal{activeProposal, failingProposal, backupProposal}},
+	}
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, params))
+
+	tc.connManager.Reconnect()
+	waitABit()
+
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+	assert.Equal(tc.T(), backupProposal, tc.connManager.Status().Proposal)
+	assert.Equal(tc.T(), 1, outgoingFirewall.blocks())
+	assert.Equal(tc.T(), 0, outgoingFirewall.removals())
+
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+	waitABit()
+	assert.Equal(tc.T(), 1, outgoingFirewall.removals())
+}
+
+func (tc *testContext) TestMultiHopConnectRefusesFailover() {
+	exitProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-2",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	params := ConnectParams{
+		Failover: FailoverStrategy{Proposals: []market.ServiceProposal{exitProposal}},
+	}
+
+	err := tc.connManager.ConnectMultiHop(consumerID, hermesID, []market.ServiceProposal{activeProposal, exitProposal}, params)
+	assert.Equal(tc.T(), ErrFailoverMultiHop, err)
+}
+
+func (tc *testContext) TestFailoverPicksProposalFromDiscovery() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+
+	discoveredProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-3",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	repository := &mockProposalRepository{proposals: []market.ServiceProposal{activeProposal, discoveredProposal}}
+	tc.connManager.proposalRepository = repository
+	filter := &proposal.Filter{ServiceType: activeServiceType}
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{Failover: FailoverStrategy{Filter: filter}}))
+
+	tc.connManager.Reconnect()
+	waitABit()
+
+	assert.Equal(tc.T(), filter, repository.recordedFilter)
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+	assert.Equal(tc.T(), discoveredProposal, tc.connManager.Status().Proposal)
+
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+}
+
+func (tc *testContext) TestFailoverDisconnectsWhenNoCandidatesLeft() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+
+	params := ConnectParams{
+		Failover: FailoverStrategy{Proposals: []market.ServiceProposal{activeProposal}},
+	}
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, params))
+
+	tc.connManager.Reconnect()
+	waitABit()
+
+	assert.Equal(tc.T(), connectionstate.NotConnected, tc.connManager.Status().State)
+}
+
+func (tc *testContext) TestKeepAliveFailuresDisconnect() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Millisecond
+	tc.connManager.config.KeepAlive.MaxSendErrCount = 3
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{}))
+
+	assert.Eventually(tc.T(), func() bool {
+		return tc.connManager.Status().State == connectionstate.NotConnected
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (tc *testContext) TestKeepAliveFailuresFailover() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Millisecond
+	tc.connManager.config.KeepAlive.MaxSendErrCount = 3
+	tc.stubPublisher.Clear()
+
+	backupProposal := market.ServiceProposal{
+		ProviderID:        "fake-node-2",
+		ProviderContacts:  []market.Contact{activeProviderContact},
+		ServiceType:       activeServiceType,
+		ServiceDefinition: &fakeServiceDefinition{},
+	}
+	params := ConnectParams{
+		Failover: FailoverStrategy{Proposals: []market.ServiceProposal{activeProposal, backupProposal}},
+	}
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, params))
+
+	assert.Eventually(tc.T(), func() bool {
+		for _, v := range tc.stubPublisher.GetEventHistory() {
+			if v.Topic != connectionstate.AppTopicConnectionFailover {
+				continue
+			}
+			failover := v.Event.(connectionstate.AppEventConnectionFailover)
+			return failover.From.ProviderID == activeProposal.ProviderID &&
+				failover.To.ProviderID == backupProposal.ProviderID &&
+				failover.Reason == "max p2p keepalive err count reached"
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	tc.connManager.Disconnect()
+}
+
+func (tc *testContext) TestQuotaWarningIsPublished() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+	tc.stubPublisher.Clear()
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{Quota: Quota{Bytes: 35}}))
+	waitABit()
+
+	status := tc.connManager.Status()
+	assert.Equal(tc.T(), connectionstate.Connected, status.State)
+	assert.NotNil(tc.T(), status.Quota)
+	assert.Equal(tc.T(), uint64(30), status.Quota.BytesUsed)
+	assert.Equal(tc.T(), uint64(5), status.Quota.RemainingBytes())
+
+	var warnings []connectionstate.AppEventConnectionQuota
+	for _, v := range tc.stubPublisher.GetEventHistory() {
+		if v.Topic == connectionstate.AppTopicConnectionQuota {
+			warnings = append(warnings, v.Event.(connectionstate.AppEventConnectionQuota))
+		}
+	}
+	assert.Len(tc.T(), warnings, 1)
+	assert.Equal(tc.T(), connectionstate.QuotaBytes, warnings[0].Kind)
+	assert.Equal(tc.T(), 0.8, warnings[0].Threshold)
+	assert.False(tc.T(), warnings[0].Exhausted)
+
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+}
+
+func (tc *testContext) TestExhaustedQuotaDisconnects() {
+	tc.connManager.config.KeepAlive.SendInterval = time.Hour
+	tc.stubPublisher.Clear()
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{Quota: Quota{Bytes: 30}}))
+	waitABit()
+
+	assert.Equal(tc.T(), connectionstate.NotConnected, tc.connManager.Status().State)
+
+	var exhausted []connectionstate.AppEventConnectionQuota
+	for _, v := range tc.stubPublisher.GetEventHistory() {
+		if v.Topic == connectionstate.AppTopicConnectionQuota {
+			exhausted = append(exhausted, v.Event.(connectionstate.AppEventConnectionQuota))
+		}
+	}
+	assert.Len(tc.T(), exhausted, 1)
+	assert.True(tc.T(), exhausted[0].Exhausted)
+}
+
 func (tc *testContext) TestConnectFailsIfConnectionFactoryReturnsError() {
 	tc.fakeConnectionFactory.mockError = errors.New("failed to create connection instance")
 	assert.Error(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{}))
@@ -442,6 +708,20 @@ func (tc *testContext) Test_PaymentManager_OnConnectErrorIsStopped() {
 	assert.True(tc.T(), tc.MockPaymentIssuer.StopCalled())
 }
 
+func (tc *testContext) Test_Connect_ReturnsSessionRejectedError() {
+	tc.mockP2P.ch.rejection = &pb.SessionRejection{
+		Reason:  pb.RejectionReason_MAX_SESSIONS,
+		Message: "service reached max sessions limit: 1",
+	}
+
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposal, ConnectParams{})
+
+	rejectedErr, ok := err.(*SessionRejectedError)
+	assert.True(tc.T(), ok)
+	assert.Equal(tc.T(), pb.RejectionReason_MAX_SESSIONS, rejectedErr.Reason)
+	assert.Equal(tc.T(), connectionstate.NotConnected, tc.connManager.Status().State)
+}
+
 func (tc *testContext) Test_SessionEndPublished_OnConnectError() {
 	tc.stubPublisher.Clear()
 
@@ -681,13 +961,14 @@ type mockP2PDialer struct {
 	ch *mockP2PChannel
 }
 
-func (m mockP2PDialer) Dial(ctx context.Context, consumerID identity.Identity, providerID identity.Identity, serviceType string, contactDef p2p.ContactDefinition, tracer *trace.Tracer) (p2p.Channel, error) {
+func (m mockP2PDialer) Dial(ctx context.Context, consumerID identity.Identity, providerID identity.Identity, serviceType string, contactDef p2p.ContactDefinition, relayDef p2p.RelayContactDefinition, tracer *trace.Tracer) (p2p.Channel, error) {
 	return m.ch, nil
 }
 
 type mockP2PChannel struct {
-	status proto.Message
-	lock   sync.Mutex
+	status    proto.Message
+	rejection *pb.SessionRejection
+	lock      sync.Mutex
 }
 
 func (m *mockP2PChannel) Conn() *net.UDPConn {
@@ -703,14 +984,15 @@ func (m *mockP2PChannel) getSentMsg() proto.Message {
 func (m *mockP2PChannel) Send(_ context.Context, topic string, msg *p2p.Message) (*p2p.Message, error) {
 	switch topic {
 	case p2p.TopicSessionCreate:
-		res := &pb.SessionResponse{
-			ID: string(establishedSessionID),
-		}
-		return p2p.ProtoMessage(res), nil
+		res, err := proto.Marshal(&pb.SessionResponse{
+			ID:        string(establishedSessionID),
+			Rejection: m.rejection,
+		})
+		return &p2p.Message{Data: res}, err
 	case p2p.TopicSessionStatus:
 		m.lock.Lock()
 		m.status = &pb.SessionStatus{}
-		msg.UnmarshalProto(m.status)
+		proto.Unmarshal(msg.Data, m.status)
 		m.lock.Unlock()
 
 		return nil, nil
@@ -734,10 +1016,48 @@ func (m *mockP2PChannel) ServiceConn() *net.UDPConn {
 	return conn
 }
 
+func (m *mockP2PChannel) Relayed() bool {
+	return false
+}
+
 func (m *mockP2PChannel) Close() error {
 	return nil
 }
 
+type outgoingFirewallMock struct {
+	firewall.OutgoingTrafficFirewall
+	lock          sync.Mutex
+	blockCount    int
+	removalsCount int
+}
+
+func (f *outgoingFirewallMock) BlockOutgoingTraffic(firewall.Scope, string) (firewall.OutgoingRuleRemove, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.blockCount++
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		f.removalsCount++
+	}, nil
+}
+
+func (f *outgoingFirewallMock) blocks() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.blockCount
+}
+
+func (f *outgoingFirewallMock) removals() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.removalsCount
+}
+
 type mockValidator struct {
 	errorToReturn error
 }
@@ -751,3 +1071,22 @@ type mockLocationResolver struct{}
 func (mlr *mockLocationResolver) GetOrigin() locationstate.Location {
 	return consumerLocation
 }
+
+type mockProposalRepository struct {
+	proposals      []market.ServiceProposal
+	recordedFilter *proposal.Filter
+}
+
+func (m *mockProposalRepository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	for _, p := range m.proposals {
+		if p.ProviderID == id.ProviderID && p.ServiceType == id.ServiceType {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockProposalRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	m.recordedFilter = filter
+	return m.proposals, nil
+}
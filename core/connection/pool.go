@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// DefaultConnectionID addresses the default consumer connection, the one which routes host traffic.
+const DefaultConnectionID = connectionstate.DefaultConnectionID
+
+// ManagerFactory creates connection manager for the connection with the given ID
+type ManagerFactory func(id string) Manager
+
+// Pool holds several simultaneous consumer connections addressable by ID.
+// The default connection routes host traffic, while every additional one is detached
+// and carries traffic only through its own network interface.
+type Pool struct {
+	lock       sync.Mutex
+	newManager ManagerFactory
+	managers   map[string]Manager
+}
+
+// NewPool creates connection pool with the default connection manager.
+func NewPool(newManager ManagerFactory) *Pool {
+	return &Pool{
+		newManager: newManager,
+		managers: map[string]Manager{
+			DefaultConnectionID: newManager(DefaultConnectionID),
+		},
+	}
+}
+
+// Subscribe removes additional connections from the pool once they get closed.
+func (p *Pool) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(connectionstate.AppTopicConnectionState, p.consumeConnectionStateEvent)
+}
+
+// Default returns manager of the default connection.
+func (p *Pool) Default() Manager {
+	manager, _ := p.Get(DefaultConnectionID)
+	return manager
+}
+
+// Get returns manager of the connection with the given ID.
+func (p *Pool) Get(id string) (Manager, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	manager, ok := p.managers[id]
+	return manager, ok
+}
+
+// Connect creates an additional detached connection and returns its ID.
+func (p *Pool) Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, params ConnectParams) (string, error) {
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	id := uid.String()
+
+	manager := p.newManager(id)
+	p.lock.Lock()
+	p.managers[id] = manager
+	p.lock.Unlock()
+
+	params.Detached = true
+	if err := manager.Connect(consumerID, hermesID, proposal, params); err != nil {
+		p.remove(id)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// List returns statuses of all connections, starting with the default one.
+func (p *Pool) List() []connectionstate.Status {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	statuses := make([]connectionstate.Status, 0, len(p.managers))
+	for _, manager := range p.managers {
+		statuses = append(statuses, manager.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ConnectionID == DefaultConnectionID {
+			return true
+		}
+		if statuses[j].ConnectionID == DefaultConnectionID {
+			return false
+		}
+		return statuses[i].StartedAt.Before(statuses[j].StartedAt)
+	})
+	return statuses
+}
+
+// Disconnect closes the connection with the given ID.
+func (p *Pool) Disconnect(id string) error {
+	manager, ok := p.Get(id)
+	if !ok {
+		return ErrNoConnection
+	}
+
+	err := manager.Disconnect()
+	if id != DefaultConnectionID {
+		p.remove(id)
+	}
+	return err
+}
+
+// Close closes every additional connection, leaving the default one untouched.
+func (p *Pool) Close() {
+	p.lock.Lock()
+	ids := make([]string, 0, len(p.managers))
+	for id := range p.managers {
+		if id != DefaultConnectionID {
+			ids = append(ids, id)
+		}
+	}
+	p.lock.Unlock()
+
+	for _, id := range ids {
+		if err := p.Disconnect(id); err != nil && err != ErrNoConnection {
+			log.Warn().Err(err).Msgf("Failed to disconnect connection %s", id)
+		}
+	}
+}
+
+func (p *Pool) consumeConnectionStateEvent(e connectionstate.AppEventConnectionState) {
+	id := e.SessionInfo.ConnectionID
+	if e.State != connectionstate.NotConnected || id == DefaultConnectionID {
+		return
+	}
+
+	// Connection might have been brought up again by failover in the meantime.
+	manager, ok := p.Get(id)
+	if !ok || manager.Status().State != connectionstate.NotConnected {
+		return
+	}
+	p.remove(id)
+}
+
+func (p *Pool) remove(id string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.managers, id)
+}
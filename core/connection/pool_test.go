@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+type poolTestManager struct {
+	status     connectionstate.Status
+	params     ConnectParams
+	connectErr error
+}
+
+func (m *poolTestManager) Connect(_ identity.Identity, _ common.Address, _ market.ServiceProposal, params ConnectParams) error {
+	m.params = params
+	if m.connectErr != nil {
+		return m.connectErr
+	}
+	m.status.State = connectionstate.Connected
+	return nil
+}
+
+func (m *poolTestManager) ConnectMultiHop(identity.Identity, common.Address, []market.ServiceProposal, ConnectParams) error {
+	return nil
+}
+
+func (m *poolTestManager) Status() connectionstate.Status {
+	return m.status
+}
+
+func (m *poolTestManager) Disconnect() error {
+	m.status.State = connectionstate.NotConnected
+	return nil
+}
+
+func (m *poolTestManager) CheckChannel(context.Context) error {
+	return nil
+}
+
+func (m *poolTestManager) Probe(context.Context, bool) (connectionstate.ProbeResult, error) {
+	return connectionstate.ProbeResult{}, nil
+}
+
+func (m *poolTestManager) Reconnect() {}
+
+func newTestPool(connectErr error) (*Pool, map[string]*poolTestManager) {
+	managers := make(map[string]*poolTestManager)
+	pool := NewPool(func(id string) Manager {
+		manager := &poolTestManager{
+			status:     connectionstate.Status{ConnectionID: id, State: connectionstate.NotConnected},
+			connectErr: connectErr,
+		}
+		managers[id] = manager
+		return manager
+	})
+	return pool, managers
+}
+
+func TestPool_ConnectAddsDetachedConnection(t *testing.T) {
+	pool, managers := newTestPool(nil)
+
+	id, err := pool.Connect(identity.FromAddress("me"), common.Address{}, market.ServiceProposal{}, ConnectParams{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, DefaultConnectionID, id)
+	assert.True(t, managers[id].params.Detached)
+	assert.False(t, managers[DefaultConnectionID].params.Detached)
+
+	statuses := pool.List()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, DefaultConnectionID, statuses[0].ConnectionID)
+	assert.Equal(t, id, statuses[1].ConnectionID)
+
+	assert.NoError(t, pool.Disconnect(id))
+	_, ok := pool.Get(id)
+	assert.False(t, ok)
+}
+
+func TestPool_FailedConnectIsNotKept(t *testing.T) {
+	pool, _ := newTestPool(errors.New("boom"))
+
+	_, err := pool.Connect(identity.FromAddress("me"), common.Address{}, market.ServiceProposal{}, ConnectParams{})
+	assert.Error(t, err)
+	assert.Len(t, pool.List(), 1)
+}
+
+func TestPool_ClosedConnectionIsRemoved(t *testing.T) {
+	pool, managers := newTestPool(nil)
+	id, err := pool.Connect(identity.FromAddress("me"), common.Address{}, market.ServiceProposal{}, ConnectParams{})
+	assert.NoError(t, err)
+
+	managers[id].status.State = connectionstate.NotConnected
+	pool.consumeConnectionStateEvent(connectionstate.AppEventConnectionState{
+		State:       connectionstate.NotConnected,
+		SessionInfo: managers[id].status,
+	})
+	_, ok := pool.Get(id)
+	assert.False(t, ok)
+
+	pool.consumeConnectionStateEvent(connectionstate.AppEventConnectionState{
+		State:       connectionstate.NotConnected,
+		SessionInfo: managers[DefaultConnectionID].status,
+	})
+	assert.NotNil(t, pool.Default())
+}
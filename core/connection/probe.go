@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// ErrProbeNotSupported indicates that provider of the connection does not serve connection probes.
+var ErrProbeNotSupported = errors.New("provider does not support connection probes")
+
+// ProbeConfig contains connection quality probe options.
+type ProbeConfig struct {
+	// Interval of periodic latency probes, zero disables them.
+	Interval time.Duration
+	// PingCount is the number of pings sent by a single latency probe.
+	PingCount    int
+	PingInterval time.Duration
+	PingTimeout  time.Duration
+	// ThroughputDuration limits each direction of the throughput test.
+	ThroughputDuration  time.Duration
+	ThroughputChunkSize int
+	ThroughputStreams   int
+}
+
+// Probe measures quality of the established connection by exchanging probe messages with the provider
+// over p2p channel, the entry hop provider is probed for multi-hop connections. Throughput is measured only
+// when asked, as it transfers a considerable amount of data.
+func (m *connectionManager) Probe(ctx context.Context, throughput bool) (connectionstate.ProbeResult, error) {
+	m.probeLock.Lock()
+	defer m.probeLock.Unlock()
+
+	if m.Status().State != connectionstate.Connected || m.channel == nil {
+		return connectionstate.ProbeResult{}, ErrNoConnection
+	}
+
+	p := newProber(m.channel, m.config.Probe)
+	result, err := p.latency(ctx)
+	if err != nil {
+		return connectionstate.ProbeResult{}, err
+	}
+	if throughput {
+		if result.Download, err = p.download(ctx); err != nil {
+			return connectionstate.ProbeResult{}, err
+		}
+		if result.Upload, err = p.upload(ctx); err != nil {
+			return connectionstate.ProbeResult{}, err
+		}
+	}
+	result.At = m.timeGetter()
+
+	log.Debug().Msgf("Connection probe: %s", result)
+	m.eventBus.Publish(connectionstate.AppTopicConnectionProbe, connectionstate.AppEventConnectionProbe{
+		Result:      result,
+		SessionInfo: m.Status(),
+	})
+	return result, nil
+}
+
+func (m *connectionManager) probeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.config.Probe.Interval):
+			_, err := m.Probe(ctx, false)
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrProbeNotSupported):
+				log.Info().Msg("Provider does not support connection probes, stopping periodic probes")
+				return
+			default:
+				log.Warn().Err(err).Msg("Connection probe failed")
+			}
+		}
+	}
+}
+
+type prober struct {
+	client *p2p.ProbeClient
+	config ProbeConfig
+}
+
+func newProber(sender p2p.ChannelSender, config ProbeConfig) *prober {
+	return &prober{
+		client: p2p.NewProbeClient(sender),
+		config: config,
+	}
+}
+
+// latency sends pings one after another and measures their round trip times.
+func (p *prober) latency(ctx context.Context) (connectionstate.ProbeResult, error) {
+	rtts := make([]time.Duration, 0, p.config.PingCount)
+	for seq := 0; seq < p.config.PingCount; seq++ {
+		if seq > 0 {
+			select {
+			case <-ctx.Done():
+				return connectionstate.ProbeResult{}, ctx.Err()
+			case <-time.After(p.config.PingInterval):
+			}
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, p.config.PingTimeout)
+		start := time.Now()
+		pong, err := p.client.Ping(pingCtx, &pb.ProbePing{Seq: uint32(seq)})
+		rtt := time.Since(start)
+		cancel()
+		switch {
+		case errors.Is(err, p2p.ErrHandlerNotFound):
+			return connectionstate.ProbeResult{}, ErrProbeNotSupported
+		case ctx.Err() != nil:
+			return connectionstate.ProbeResult{}, ctx.Err()
+		case err != nil:
+			log.Debug().Err(err).Msgf("Probe ping %d lost", seq)
+		case pong.Seq != uint32(seq):
+			log.Debug().Msgf("Probe ping %d answered with %d", seq, pong.Seq)
+		default:
+			rtts = append(rtts, rtt)
+		}
+	}
+	if len(rtts) == 0 {
+		return connectionstate.ProbeResult{}, errors.New("no probe pings were answered")
+	}
+	return latencyResult(rtts, p.config.PingCount), nil
+}
+
+// latencyResult calculates average latency, jitter as the average difference of consecutive
+// round trip times and the fraction of lost pings.
+func latencyResult(rtts []time.Duration, sent int) connectionstate.ProbeResult {
+	var total, variation time.Duration
+	for i, rtt := range rtts {
+		total += rtt
+		if i > 0 {
+			diff := rtt - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			variation += diff
+		}
+	}
+
+	result := connectionstate.ProbeResult{
+		Latency:    total / time.Duration(len(rtts)),
+		PacketLoss: float64(sent-len(rtts)) / float64(sent),
+	}
+	if len(rtts) > 1 {
+		result.Jitter = variation / time.Duration(len(rtts)-1)
+	}
+	return result
+}
+
+func (p *prober) download(ctx context.Context) (datasize.BitSpeed, error) {
+	req := &pb.ProbeDownloadRequest{Size: uint32(p.config.ThroughputChunkSize)}
+	return p.throughput(ctx, "download", func(ctx context.Context) (int, error) {
+		res, err := p.client.Download(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		return len(res.Data), nil
+	})
+}
+
+func (p *prober) upload(ctx context.Context) (datasize.BitSpeed, error) {
+	req := &pb.ProbeData{Data: make([]byte, p.config.ThroughputChunkSize)}
+	return p.throughput(ctx, "upload", func(ctx context.Context) (int, error) {
+		if err := p.client.Upload(ctx, req); err != nil {
+			return 0, err
+		}
+		return len(req.Data), nil
+	})
+}
+
+// throughput repeats transfer in parallel streams for the configured duration
+// and calculates speed of the transfers completed in time.
+func (p *prober) throughput(ctx context.Context, name string, transfer func(context.Context) (int, error)) (datasize.BitSpeed, error) {
+	testCtx, cancel := context.WithTimeout(ctx, p.config.ThroughputDuration)
+	defer cancel()
+
+	var (
+		lock     sync.Mutex
+		wg       sync.WaitGroup
+		total    uint64
+		last     time.Time
+		firstErr error
+	)
+	start := time.Now()
+	for i := 0; i < p.config.ThroughputStreams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for testCtx.Err() == nil {
+				n, err := transfer(testCtx)
+
+				lock.Lock()
+				// Provider limits probe traffic of the session, the test ends once the limit is reached.
+				if err != nil && p2p.ErrorCode(err) == pb.ErrorCode_UNAVAILABLE {
+					lock.Unlock()
+					cancel()
+					return
+				}
+				if err != nil {
+					if testCtx.Err() == nil && firstErr == nil {
+						firstErr = err
+					}
+					lock.Unlock()
+					cancel()
+					return
+				}
+				total += uint64(n)
+				last = time.Now()
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case ctx.Err() != nil:
+		return 0, ctx.Err()
+	case errors.Is(firstErr, p2p.ErrHandlerNotFound):
+		return 0, ErrProbeNotSupported
+	case firstErr != nil:
+		return 0, fmt.Errorf("%s test failed: %w", name, firstErr)
+	case total == 0:
+		return 0, fmt.Errorf("%s test failed: no data was transferred in time", name)
+	}
+	return datasize.BitSpeed(float64(datasize.FromBytes(total)) / last.Sub(start).Seconds()), nil
+}
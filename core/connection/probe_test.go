@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+type probeSenderMock struct {
+	lost map[uint32]bool
+	err  error
+
+	// downloadLimit limits total size of downloads when set.
+	downloadLimit int
+	downloaded    int
+	lock          sync.Mutex
+}
+
+func (s *probeSenderMock) Send(_ context.Context, topic string, msg *p2p.Message) (*p2p.Message, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var reply proto.Message
+	switch topic {
+	case "p2p-probe-ping":
+		ping := new(pb.ProbePing)
+		if err := proto.Unmarshal(msg.Data, ping); err != nil {
+			return nil, err
+		}
+		if s.lost[ping.Seq] {
+			return nil, p2p.ErrSendTimeout
+		}
+		reply = ping
+	case "p2p-probe-download":
+		req := new(pb.ProbeDownloadRequest)
+		if err := proto.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		if !s.takeDownload(int(req.Size)) {
+			return nil, p2p.Errorf(pb.ErrorCode_UNAVAILABLE, "probe traffic budget exceeded")
+		}
+		reply = &pb.ProbeData{Data: make([]byte, req.Size)}
+	case "p2p-probe-upload":
+		reply = &pb.ProbeData{}
+	default:
+		return nil, p2p.ErrHandlerNotFound
+	}
+	time.Sleep(time.Millisecond)
+
+	data, err := proto.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &p2p.Message{Data: data}, nil
+}
+
+func (s *probeSenderMock) takeDownload(size int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.downloadLimit > 0 && s.downloaded+size > s.downloadLimit {
+		return false
+	}
+	s.downloaded += size
+	return true
+}
+
+var probeTestConfig = ProbeConfig{
+	PingCount:           4,
+	PingInterval:        time.Millisecond,
+	PingTimeout:         time.Second,
+	ThroughputDuration:  50 * time.Millisecond,
+	ThroughputChunkSize: 1024,
+	ThroughputStreams:   2,
+}
+
+func Test_latencyResult(t *testing.T) {
+	result := latencyResult([]time.Duration{
+		10 * time.Millisecond,
+		30 * time.Millisecond,
+		20 * time.Millisecond,
+	}, 4)
+
+	assert.Equal(t, 20*time.Millisecond, result.Latency)
+	assert.Equal(t, 15*time.Millisecond, result.Jitter)
+	assert.Equal(t, 0.25, result.PacketLoss)
+}
+
+func Test_latencyResult_WithSingleAnswer(t *testing.T) {
+	result := latencyResult([]time.Duration{10 * time.Millisecond}, 1)
+
+	assert.Equal(t, 10*time.Millisecond, result.Latency)
+	assert.Zero(t, result.Jitter)
+	assert.Zero(t, result.PacketLoss)
+}
+
+func TestProber_Latency(t *testing.T) {
+	p := newProber(&probeSenderMock{lost: map[uint32]bool{1: true}}, probeTestConfig)
+
+	result, err := p.latency(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, result.Latency > 0)
+	assert.Equal(t, 0.25, result.PacketLoss)
+}
+
+func TestProber_Latency_WhenAllPingsAreLost(t *testing.T) {
+	p := newProber(&probeSenderMock{err: p2p.ErrSendTimeout}, probeTestConfig)
+
+	_, err := p.latency(context.Background())
+
+	assert.EqualError(t, err, "no probe pings were answered")
+}
+
+func TestProber_Latency_WhenProviderDoesNotSupportProbes(t *testing.T) {
+	p := newProber(&probeSenderMock{err: errors.New("unknown topic: " + p2p.ErrHandlerNotFound.Error())}, probeTestConfig)
+	_, err := p.latency(context.Background())
+	assert.Error(t, err)
+	assert.NotEqual(t, ErrProbeNotSupported, err)
+
+	p = newProber(&probeSenderMock{err: p2p.ErrHandlerNotFound}, probeTestConfig)
+	_, err = p.latency(context.Background())
+	assert.Equal(t, ErrProbeNotSupported, err)
+}
+
+func TestProber_Throughput(t *testing.T) {
+	p := newProber(&probeSenderMock{}, probeTestConfig)
+
+	download, err := p.download(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, download > 0)
+
+	upload, err := p.upload(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, upload > 0)
+}
+
+func TestProber_Throughput_StopsWhenProviderLimitIsReached(t *testing.T) {
+	p := newProber(&probeSenderMock{downloadLimit: 3 * probeTestConfig.ThroughputChunkSize}, probeTestConfig)
+
+	download, err := p.download(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, download > 0)
+}
+
+func TestProber_Throughput_WhenTransferFails(t *testing.T) {
+	p := newProber(&probeSenderMock{err: errors.New("boom")}, probeTestConfig)
+
+	_, err := p.download(context.Background())
+
+	assert.EqualError(t, err, "download test failed: could not call Probe.Download: boom")
+}
+
+func TestConnectionManager_Probe_WithoutConnection(t *testing.T) {
+	m := &connectionManager{status: connectionstate.Status{State: connectionstate.NotConnected}}
+
+	_, err := m.Probe(context.Background(), false)
+
+	assert.Equal(t, ErrNoConnection, err)
+}
@@ -0,0 +1,302 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/session"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+// DefaultQuotaWarningThresholds are used fractions of quota at which warnings are published, unless given otherwise.
+var DefaultQuotaWarningThresholds = []float64{0.8, 0.9}
+
+// ErrInvalidQuota indicates that quota warning thresholds are out of range.
+var ErrInvalidQuota = errors.New("quota warning thresholds must be between 0 and 1")
+
+// Quota limits usage of the connection, connection is disconnected once any of the limits is reached.
+// Zero values mean unlimited.
+type Quota struct {
+	// Bytes limits bytes sent and received through the connection
+	Bytes uint64
+	// Duration limits the connection time
+	Duration time.Duration
+	// Tokens limits tokens spent on the connection
+	Tokens *big.Int
+	// WarningThresholds are used fractions of quota at which warnings are published
+	WarningThresholds []float64
+}
+
+// Enabled returns true if any limit is set.
+func (q Quota) Enabled() bool {
+	return q.Bytes > 0 || q.Duration > 0 || (q.Tokens != nil && q.Tokens.Sign() > 0)
+}
+
+// Validate checks that warning thresholds are proper fractions.
+func (q Quota) Validate() error {
+	for _, threshold := range q.WarningThresholds {
+		if threshold <= 0 || threshold >= 1 {
+			return ErrInvalidQuota
+		}
+	}
+	return nil
+}
+
+func (q Quota) warningThresholds() []float64 {
+	thresholds := q.WarningThresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultQuotaWarningThresholds
+	}
+	thresholds = append([]float64{}, thresholds...)
+	sort.Float64s(thresholds)
+	return thresholds
+}
+
+// quotaTracker sums up usage of every session the connection went through, so that quota
+// is kept when connection fails over to another provider.
+type quotaTracker struct {
+	lock       sync.Mutex
+	quota      Quota
+	thresholds []float64
+	startedAt  time.Time
+	bytes      map[session.ID]uint64
+	tokens     map[session.ID]*big.Int
+	crossed    map[connectionstate.QuotaKind]int
+	exhausted  bool
+}
+
+func newQuotaTracker(quota Quota, startedAt time.Time) *quotaTracker {
+	return &quotaTracker{
+		quota:      quota,
+		thresholds: quota.warningThresholds(),
+		startedAt:  startedAt,
+		bytes:      make(map[session.ID]uint64),
+		tokens:     make(map[session.ID]*big.Int),
+		crossed:    make(map[connectionstate.QuotaKind]int),
+	}
+}
+
+func (t *quotaTracker) updateStatistics(sessionID session.ID, stats connectionstate.Statistics) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.bytes[sessionID] = stats.BytesSent + stats.BytesReceived
+}
+
+func (t *quotaTracker) updateSpending(sessionID session.ID, agreementTotal *big.Int) {
+	if agreementTotal == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.tokens[sessionID] = new(big.Int).Set(agreementTotal)
+}
+
+func (t *quotaTracker) status(now time.Time) connectionstate.QuotaStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.statusLocked(now)
+}
+
+func (t *quotaTracker) statusLocked(now time.Time) connectionstate.QuotaStatus {
+	status := connectionstate.QuotaStatus{
+		BytesLimit:    t.quota.Bytes,
+		DurationLimit: t.quota.Duration,
+		DurationUsed:  now.Sub(t.startedAt),
+		TokensUsed:    new(big.Int),
+	}
+	if t.quota.Tokens != nil {
+		status.TokensLimit = new(big.Int).Set(t.quota.Tokens)
+	}
+	for _, bytes := range t.bytes {
+		status.BytesUsed += bytes
+	}
+	for _, tokens := range t.tokens {
+		status.TokensUsed.Add(status.TokensUsed, tokens)
+	}
+	return status
+}
+
+// check returns events of newly crossed warning thresholds and of quota exhaustion, which is reported only once.
+func (t *quotaTracker) check(now time.Time) []connectionstate.AppEventConnectionQuota {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.exhausted {
+		return nil
+	}
+
+	status := t.statusLocked(now)
+	usage := make(map[connectionstate.QuotaKind]float64)
+	if status.BytesLimit > 0 {
+		usage[connectionstate.QuotaBytes] = float64(status.BytesUsed) / float64(status.BytesLimit)
+	}
+	if status.DurationLimit > 0 {
+		usage[connectionstate.QuotaDuration] = float64(status.DurationUsed) / float64(status.DurationLimit)
+	}
+	if status.TokensLimit != nil && status.TokensLimit.Sign() > 0 {
+		used, _ := new(big.Float).Quo(new(big.Float).SetInt(status.TokensUsed), new(big.Float).SetInt(status.TokensLimit)).Float64()
+		usage[connectionstate.QuotaTokens] = used
+	}
+
+	var events []connectionstate.AppEventConnectionQuota
+	for _, kind := range []connectionstate.QuotaKind{connectionstate.QuotaBytes, connectionstate.QuotaDuration, connectionstate.QuotaTokens} {
+		used, ok := usage[kind]
+		if !ok {
+			continue
+		}
+
+		if used >= 1 {
+			t.exhausted = true
+			return append(events, connectionstate.AppEventConnectionQuota{
+				Kind:      kind,
+				Threshold: 1,
+				Exhausted: true,
+				Quota:     status,
+			})
+		}
+
+		// Only the highest crossed threshold is reported when usage jumps over several of them.
+		crossed := t.crossed[kind]
+		for crossed < len(t.thresholds) && used >= t.thresholds[crossed] {
+			crossed++
+		}
+		if crossed > t.crossed[kind] {
+			t.crossed[kind] = crossed
+			events = append(events, connectionstate.AppEventConnectionQuota{
+				Kind:      kind,
+				Threshold: t.thresholds[crossed-1],
+				Quota:     status,
+			})
+		}
+	}
+	return events
+}
+
+// startQuota starts tracking quota of a new connection, or keeps tracking the quota of a connection which fails over.
+func (m *connectionManager) startQuota(quota Quota) {
+	m.quotaLock.Lock()
+	tracker := m.resumeQuota
+	m.resumeQuota = nil
+	if tracker == nil && quota.Enabled() {
+		tracker = newQuotaTracker(quota, m.timeGetter())
+	}
+	m.quota = tracker
+	m.quotaLock.Unlock()
+
+	if tracker == nil {
+		return
+	}
+
+	m.quotaSubscribe.Do(func() {
+		err := m.eventBus.SubscribeAsync(pingpongEvent.AppTopicInvoicePaid, m.consumeInvoicePaidEvent)
+		if err != nil {
+			log.Error().Err(err).Msg("Could not subscribe to invoices, tokens quota will not be enforced")
+		}
+	})
+	status := tracker.status(m.timeGetter())
+	m.setStatus(func(s *connectionstate.Status) {
+		s.Quota = &status
+	})
+}
+
+func (m *connectionManager) setResumeQuota(tracker *quotaTracker) {
+	m.quotaLock.Lock()
+	defer m.quotaLock.Unlock()
+
+	m.resumeQuota = tracker
+}
+
+func (m *connectionManager) quotaTracker() *quotaTracker {
+	m.quotaLock.Lock()
+	defer m.quotaLock.Unlock()
+
+	return m.quota
+}
+
+// updateQuota accounts traffic of the current session.
+func (m *connectionManager) updateQuota(stats connectionstate.Statistics) {
+	tracker := m.quotaTracker()
+	if tracker == nil {
+		return
+	}
+
+	tracker.updateStatistics(m.Status().SessionID, stats)
+	m.checkQuota(tracker)
+}
+
+func (m *connectionManager) consumeInvoicePaidEvent(e pingpongEvent.AppEventInvoicePaid) {
+	tracker := m.quotaTracker()
+	if tracker == nil || !m.ownsSession(session.ID(e.SessionID)) {
+		return
+	}
+
+	tracker.updateSpending(session.ID(e.SessionID), e.Invoice.AgreementTotal)
+	m.checkQuota(tracker)
+}
+
+func (m *connectionManager) ownsSession(sessionID session.ID) bool {
+	status := m.Status()
+	if sessionID == "" || status.State == connectionstate.NotConnected {
+		return false
+	}
+	if status.SessionID == sessionID {
+		return true
+	}
+	for _, hop := range status.Hops {
+		if hop.SessionID == sessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuota publishes quota warnings and disconnects gracefully once quota is exhausted.
+func (m *connectionManager) checkQuota(tracker *quotaTracker) {
+	now := m.timeGetter()
+	status := tracker.status(now)
+	m.setStatus(func(s *connectionstate.Status) {
+		s.Quota = &status
+	})
+
+	for _, e := range tracker.check(now) {
+		e.SessionInfo = m.Status()
+		m.eventBus.Publish(connectionstate.AppTopicConnectionQuota, e)
+		if !e.Exhausted {
+			log.Info().Msgf("Connection %s quota is %.0f%% used", e.Kind, e.Threshold*100)
+			continue
+		}
+
+		log.Warn().Msgf("Connection %s quota exhausted, disconnecting", e.Kind)
+		// Disconnect stops statistics publisher, which might be the caller.
+		go func() {
+			logDisconnectError(m.Disconnect())
+		}()
+	}
+}
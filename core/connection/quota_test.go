@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+func TestQuota_Validate(t *testing.T) {
+	assert.NoError(t, Quota{WarningThresholds: []float64{0.5, 0.9}}.Validate())
+	assert.Equal(t, ErrInvalidQuota, Quota{WarningThresholds: []float64{1}}.Validate())
+	assert.Equal(t, ErrInvalidQuota, Quota{WarningThresholds: []float64{0}}.Validate())
+}
+
+func TestQuotaTracker_SumsUsageOfEverySession(t *testing.T) {
+	start := time.Now()
+	tracker := newQuotaTracker(Quota{Bytes: 100, Tokens: big.NewInt(1000)}, start)
+
+	tracker.updateStatistics("s1", connectionstate.Statistics{BytesSent: 10, BytesReceived: 20})
+	tracker.updateStatistics("s1", connectionstate.Statistics{BytesSent: 20, BytesReceived: 20})
+	tracker.updateStatistics("s2", connectionstate.Statistics{BytesSent: 5, BytesReceived: 5})
+	tracker.updateSpending("s1", big.NewInt(300))
+	tracker.updateSpending("s2", big.NewInt(200))
+
+	status := tracker.status(start.Add(time.Minute))
+	assert.Equal(t, uint64(50), status.BytesUsed)
+	assert.Equal(t, uint64(50), status.RemainingBytes())
+	assert.Equal(t, big.NewInt(500), status.TokensUsed)
+	assert.Equal(t, big.NewInt(500), status.RemainingTokens())
+	assert.Equal(t, time.Minute, status.DurationUsed)
+}
+
+func TestQuotaTracker_Check(t *testing.T) {
+	start := time.Now()
+	tracker := newQuotaTracker(Quota{Duration: 10 * time.Minute, WarningThresholds: []float64{0.9, 0.5}}, start)
+
+	assert.Empty(t, tracker.check(start.Add(time.Minute)))
+
+	events := tracker.check(start.Add(6 * time.Minute))
+	assert.Len(t, events, 1)
+	assert.Equal(t, connectionstate.QuotaDuration, events[0].Kind)
+	assert.Equal(t, 0.5, events[0].Threshold)
+
+	// Same threshold is not reported again.
+	assert.Empty(t, tracker.check(start.Add(7*time.Minute)))
+
+	events = tracker.check(start.Add(10 * time.Minute))
+	assert.Len(t, events, 1)
+	assert.True(t, events[0].Exhausted)
+	assert.Equal(t, time.Duration(0), events[0].Quota.RemainingDuration())
+
+	// Exhaustion is reported once.
+	assert.Empty(t, tracker.check(start.Add(11*time.Minute)))
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var domainRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// SplitTunnel selects which destinations are routed through the tunnel.
+// Entries are CIDRs, single IPs or DNS names.
+type SplitTunnel struct {
+	// Include routes only the given destinations through the tunnel, everything else goes directly.
+	Include []string
+	// Exclude routes the given destinations outside of the tunnel.
+	Exclude []string
+}
+
+// Enabled returns true if any split tunneling rules are given.
+func (st SplitTunnel) Enabled() bool {
+	return len(st.Include) > 0 || len(st.Exclude) > 0
+}
+
+// FullTunnel returns true if everything except excluded destinations is routed through the tunnel.
+func (st SplitTunnel) FullTunnel() bool {
+	return len(st.Include) == 0
+}
+
+// Validate checks that every entry is either a CIDR, an IP or a DNS name.
+func (st SplitTunnel) Validate() error {
+	for _, entry := range append(append([]string{}, st.Include...), st.Exclude...) {
+		if _, _, err := ParseSplitTunnelEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncludedNetworks returns included entries which are networks, in CIDR notation.
+func (st SplitTunnel) IncludedNetworks() []string {
+	networks, _ := splitEntries(st.Include)
+	return networks
+}
+
+// IncludedDomains returns included entries which are DNS names.
+func (st SplitTunnel) IncludedDomains() []string {
+	_, domains := splitEntries(st.Include)
+	return domains
+}
+
+// ExcludedNetworks returns excluded entries which are networks, in CIDR notation.
+func (st SplitTunnel) ExcludedNetworks() []string {
+	networks, _ := splitEntries(st.Exclude)
+	return networks
+}
+
+// ExcludedDomains returns excluded entries which are DNS names.
+func (st SplitTunnel) ExcludedDomains() []string {
+	_, domains := splitEntries(st.Exclude)
+	return domains
+}
+
+// ParseSplitTunnelEntry parses a split tunnel entry into either a network or a DNS name.
+func ParseSplitTunnelEntry(entry string) (network *net.IPNet, domain string, err error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid split tunnel CIDR %q: %w", entry, err)
+		}
+		return network, "", nil
+	}
+
+	if ip := net.ParseIP(entry); ip != nil {
+		return HostNetwork(ip), "", nil
+	}
+
+	domain = strings.ToLower(strings.TrimSuffix(entry, "."))
+	if domain == "" || len(domain) > 253 || !domainRegex.MatchString(domain) {
+		return nil, "", fmt.Errorf("invalid split tunnel entry %q: must be CIDR, IP or DNS name", entry)
+	}
+	return nil, domain, nil
+}
+
+// HostNetwork returns a network which holds the single given IP.
+func HostNetwork(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+func splitEntries(entries []string) (networks []string, domains []string) {
+	for _, entry := range entries {
+		network, domain, err := ParseSplitTunnelEntry(entry)
+		if err != nil {
+			continue
+		}
+		if network != nil {
+			networks = append(networks, network.String())
+		} else {
+			domains = append(domains, domain)
+		}
+	}
+	return networks, domains
+}
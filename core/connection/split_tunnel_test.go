@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSplitTunnelEntry(t *testing.T) {
+	tests := []struct {
+		entry   string
+		network string
+		domain  string
+		wantErr bool
+	}{
+		{entry: "10.0.0.0/8", network: "10.0.0.0/8"},
+		{entry: "10.1.2.3/8", network: "10.0.0.0/8"},
+		{entry: "1.1.1.1", network: "1.1.1.1/32"},
+		{entry: "2001:db8::1", network: "2001:db8::1/128"},
+		{entry: "Example.COM.", domain: "example.com"},
+		{entry: "10.0.0.0/33", wantErr: true},
+		{entry: "bad_domain!", wantErr: true},
+		{entry: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.entry, func(t *testing.T) {
+			network, domain, err := ParseSplitTunnelEntry(test.entry)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if test.network != "" {
+				assert.Equal(t, test.network, network.String())
+			}
+			assert.Equal(t, test.domain, domain)
+		})
+	}
+}
+
+func TestSplitTunnelEntries(t *testing.T) {
+	splitTunnel := SplitTunnel{
+		Include: []string{"10.0.0.0/8", "example.com"},
+		Exclude: []string{"1.1.1.1", "local.example.com"},
+	}
+
+	assert.NoError(t, splitTunnel.Validate())
+	assert.False(t, splitTunnel.FullTunnel())
+	assert.Equal(t, []string{"10.0.0.0/8"}, splitTunnel.IncludedNetworks())
+	assert.Equal(t, []string{"example.com"}, splitTunnel.IncludedDomains())
+	assert.Equal(t, []string{"1.1.1.1/32"}, splitTunnel.ExcludedNetworks())
+	assert.Equal(t, []string{"local.example.com"}, splitTunnel.ExcludedDomains())
+
+	splitTunnel.Exclude = append(splitTunnel.Exclude, "not a domain")
+	assert.Error(t, splitTunnel.Validate())
+}
@@ -55,6 +55,7 @@ func (s statsPublisher) start(sessionSupplier *connectionManager, statsSupplier
 				log.Warn().Err(err).Msg("Could not get connection statistics")
 				continue
 			}
+			sessionSupplier.updateQuota(stats)
 			s.bus.Publish(connectionstate.AppTopicConnectionStatistics, connectionstate.AppEventConnectionStatistics{
 				Stats:       stats,
 				SessionInfo: sessionSupplier.Status(),
@@ -50,21 +50,23 @@ func (c *connectionFactoryFake) CreateConnection(serviceType string) (Connection
 		return nil, c.mockError
 	}
 
-	c.mockConnection.stateChannel = make(chan connectionstate.State, 100)
+	// every connection gets its own state channel, so that several of them can be chained
+	stateChannel := make(chan connectionstate.State, 100)
+	c.mockConnection.stateChannel = stateChannel
 
 	stateCallback := func(state fakeState) {
 		if state == connectedState {
-			c.mockConnection.stateChannel <- connectionstate.Connected
+			stateChannel <- connectionstate.Connected
 		}
 		if state == exitingState {
-			c.mockConnection.stateChannel <- connectionstate.Disconnecting
+			stateChannel <- connectionstate.Disconnecting
 		}
 		if state == reconnectingState {
-			c.mockConnection.stateChannel <- connectionstate.Reconnecting
+			stateChannel <- connectionstate.Reconnecting
 		}
 		//this is the last state - close channel (according to best practices of go - channel writer controls channel)
 		if state == processExited {
-			close(c.mockConnection.stateChannel)
+			close(stateChannel)
 		}
 	}
 	c.mockConnection.StateCallback(stateCallback)
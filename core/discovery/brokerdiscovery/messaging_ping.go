@@ -19,12 +19,11 @@ package brokerdiscovery
 
 import (
 	"github.com/mysteriumnetwork/node/communication"
-	"github.com/mysteriumnetwork/node/market"
 )
 
 // pingMessage structure represents message that the Provider sends about healthy Proposal
 type pingMessage struct {
-	Proposal market.ServiceProposal `json:"proposal"`
+	signedProposal
 }
 
 const pingEndpoint = communication.MessageEndpoint("proposal-ping")
@@ -19,12 +19,11 @@ package brokerdiscovery
 
 import (
 	"github.com/mysteriumnetwork/node/communication"
-	"github.com/mysteriumnetwork/node/market"
 )
 
 // registerMessage structure represents message that the Provider sends about newly announced Proposal
 type registerMessage struct {
-	Proposal market.ServiceProposal `json:"proposal"`
+	signedProposal
 }
 
 const registerEndpoint = communication.MessageEndpoint("proposal-register")
@@ -19,12 +19,11 @@ package brokerdiscovery
 
 import (
 	"github.com/mysteriumnetwork/node/communication"
-	"github.com/mysteriumnetwork/node/market"
 )
 
 // unregisterMessage structure represents message that the Provider sends about de-announced Proposal
 type unregisterMessage struct {
-	Proposal market.ServiceProposal `json:"proposal"`
+	signedProposal
 }
 
 const unregisterEndpoint = communication.MessageEndpoint("proposal-unregister")
@@ -37,18 +37,27 @@ func NewRegistry(connection nats.Connection) *registryBroker {
 
 // RegisterProposal registers service proposal to discovery service
 func (rb *registryBroker) RegisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
-	message := &registerMessage{Proposal: proposal}
-	return rb.sender.Send(&registerProducer{message: message})
+	signed, err := newSignedProposal(registerEndpoint, proposal, signer)
+	if err != nil {
+		return err
+	}
+	return rb.sender.Send(&registerProducer{message: &registerMessage{signed}})
 }
 
 // UnregisterProposal unregisters a service proposal when client disconnects
 func (rb *registryBroker) UnregisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
-	message := &unregisterMessage{Proposal: proposal}
-	return rb.sender.Send(&unregisterProducer{message: message})
+	signed, err := newSignedProposal(unregisterEndpoint, proposal, signer)
+	if err != nil {
+		return err
+	}
+	return rb.sender.Send(&unregisterProducer{message: &unregisterMessage{signed}})
 }
 
 // PingProposal pings service proposal as being alive
 func (rb *registryBroker) PingProposal(proposal market.ServiceProposal, signer identity.Signer) error {
-	message := &pingMessage{Proposal: proposal}
-	return rb.sender.Send(&pingProducer{message: message})
+	signed, err := newSignedProposal(pingEndpoint, proposal, signer)
+	if err != nil {
+		return err
+	}
+	return rb.sender.Send(&pingProducer{message: &pingMessage{signed}})
 }
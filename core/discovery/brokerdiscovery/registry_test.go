@@ -19,6 +19,7 @@ package brokerdiscovery
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/mysteriumnetwork/node/communication"
@@ -54,13 +55,7 @@ func Test_Registry_RegisterProposal(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, "*.proposal-register", connection.GetLastMessageSubject())
-	assert.JSONEq(
-		t,
-		`{
-			"proposal": `+string(newProposalPayload)+`
-		}`,
-		string(connection.GetLastMessage()),
-	)
+	assertSignedProposal(t, registerEndpoint, connection.GetLastMessage())
 }
 
 func Test_Registry_UnregisterProposal(t *testing.T) {
@@ -72,13 +67,7 @@ func Test_Registry_UnregisterProposal(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, "*.proposal-unregister", connection.GetLastMessageSubject())
-	assert.JSONEq(
-		t,
-		`{
-			"proposal": `+string(newProposalPayload)+`
-		}`,
-		string(connection.GetLastMessage()),
-	)
+	assertSignedProposal(t, unregisterEndpoint, connection.GetLastMessage())
 }
 
 func Test_Registry_PingProposal(t *testing.T) {
@@ -90,11 +79,24 @@ func Test_Registry_PingProposal(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, "*.proposal-ping", connection.GetLastMessageSubject())
-	assert.JSONEq(
-		t,
-		`{
-			"proposal": `+string(newProposalPayload)+`
-		}`,
-		string(connection.GetLastMessage()),
-	)
+	assertSignedProposal(t, pingEndpoint, connection.GetLastMessage())
+}
+
+func Test_Registry_RegisterProposal_WhenSigningFails(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	registry := NewRegistry(connection)
+	err := registry.RegisterProposal(newProposal, &identity.SignerFake{ErrorMock: errors.New("locked")})
+	assert.EqualError(t, err, "could not sign proposal message: locked")
+}
+
+func assertSignedProposal(t *testing.T, endpoint communication.MessageEndpoint, data []byte) {
+	var message signedProposal
+	assert.NoError(t, json.Unmarshal(data, &message))
+	assert.JSONEq(t, string(newProposalPayload), string(message.Proposal))
+	assert.NotZero(t, message.Timestamp)
+
+	verifier := &identity.VerifierFake{}
+	assert.True(t, verifier.Verify(message.payload(endpoint), identity.SignatureBase64(message.Signature)))
 }
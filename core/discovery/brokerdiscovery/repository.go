@@ -23,7 +23,10 @@ import (
 
 	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/communication/nats"
+	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 )
 
@@ -31,6 +34,8 @@ import (
 type Repository struct {
 	storage         *ProposalStorage
 	receiver        communication.Receiver
+	publisher       eventbus.Publisher
+	newVerifier     identity.VerifierFactory
 	timeoutInterval time.Duration
 
 	stopOnce sync.Once
@@ -39,23 +44,30 @@ type Repository struct {
 	timeoutCheckStep  time.Duration
 	watchdogLock      sync.Mutex
 	timeoutCheckSeens map[market.ProposalID]time.Time
+	// signedAt holds timestamp of the last signed message accepted for the proposal, older ones are replays.
+	signedAt map[market.ProposalID]int64
 }
 
 // NewRepository constructs a new proposal repository (backed by the broker).
 func NewRepository(
 	connection nats.Connection,
 	storage *ProposalStorage,
+	publisher eventbus.Publisher,
+	verifierFactory identity.VerifierFactory,
 	proposalTimeoutInterval time.Duration,
 	proposalCheckInterval time.Duration,
 ) *Repository {
 	return &Repository{
 		storage:         storage,
 		receiver:        nats.NewReceiver(connection, communication.NewCodecJSON(), "*"),
+		publisher:       publisher,
+		newVerifier:     verifierFactory,
 		timeoutInterval: proposalTimeoutInterval,
 
 		stopChan:          make(chan struct{}),
 		timeoutCheckStep:  proposalCheckInterval,
 		timeoutCheckSeens: make(map[market.ProposalID]time.Time),
+		signedAt:          make(map[market.ProposalID]int64),
 	}
 }
 
@@ -103,43 +115,77 @@ func (r *Repository) Stop() {
 }
 
 func (r *Repository) proposalRegisterMessage(message registerMessage) error {
-	if !message.Proposal.IsSupported() {
+	proposal, ok := r.accept(registerEndpoint, message.signedProposal)
+	if !ok || !proposal.IsSupported() {
 		return nil
 	}
 
-	r.storage.AddProposal(message.Proposal)
+	r.storage.AddProposal(proposal)
 
 	r.watchdogLock.Lock()
 	defer r.watchdogLock.Unlock()
-	r.timeoutCheckSeens[message.Proposal.UniqueID()] = time.Now().UTC()
+	r.timeoutCheckSeens[proposal.UniqueID()] = time.Now().UTC()
 
 	return nil
 }
 
 func (r *Repository) proposalUnregisterMessage(message unregisterMessage) error {
-	r.storage.RemoveProposal(message.Proposal.UniqueID())
+	proposal, ok := r.accept(unregisterEndpoint, message.signedProposal)
+	if !ok {
+		return nil
+	}
+
+	r.storage.RemoveProposal(proposal.UniqueID())
 
 	r.watchdogLock.Lock()
 	defer r.watchdogLock.Unlock()
-	delete(r.timeoutCheckSeens, message.Proposal.UniqueID())
+	delete(r.timeoutCheckSeens, proposal.UniqueID())
 
 	return nil
 }
 
 func (r *Repository) proposalPingMessage(message pingMessage) error {
-	if !message.Proposal.IsSupported() {
+	proposal, ok := r.accept(pingEndpoint, message.signedProposal)
+	if !ok || !proposal.IsSupported() {
 		return nil
 	}
 
-	r.storage.AddProposal(message.Proposal)
+	r.storage.AddProposal(proposal)
 
 	r.watchdogLock.Lock()
 	defer r.watchdogLock.Unlock()
-	r.timeoutCheckSeens[message.Proposal.UniqueID()] = time.Now()
+	r.timeoutCheckSeens[proposal.UniqueID()] = time.Now()
 
 	return nil
 }
 
+// accept verifies proposal message, messages which failed verification are rejected.
+// Unsigned messages of older providers are accepted as long as they don't touch a verified proposal.
+func (r *Repository) accept(endpoint communication.MessageEndpoint, message signedProposal) (market.ServiceProposal, bool) {
+	proposal, err := message.decode(endpoint, r.newVerifier)
+	switch err {
+	case nil:
+		r.watchdogLock.Lock()
+		defer r.watchdogLock.Unlock()
+
+		if message.Timestamp <= r.signedAt[proposal.UniqueID()] {
+			discovery.PublishRejection(r.publisher, proposal, errStale)
+			return proposal, false
+		}
+		r.signedAt[proposal.UniqueID()] = message.Timestamp
+		return proposal, true
+	case discovery.ErrUnsigned:
+		if stored, err := r.storage.GetProposal(proposal.UniqueID()); err == nil && stored.Verified {
+			discovery.PublishRejection(r.publisher, proposal, discovery.ErrUnsigned)
+			return proposal, false
+		}
+		return proposal, true
+	default:
+		discovery.PublishRejection(r.publisher, proposal, err)
+		return proposal, false
+	}
+}
+
 func (r *Repository) timeoutCheckLoop() {
 	for {
 		select {
@@ -153,7 +199,18 @@ func (r *Repository) timeoutCheckLoop() {
 					delete(r.timeoutCheckSeens, proposalID)
 				}
 			}
+			r.pruneSignedAt()
 			r.watchdogLock.Unlock()
 		}
 	}
 }
+
+// pruneSignedAt forgets timestamps of the proposals which are gone for longer than the timeout interval.
+func (r *Repository) pruneSignedAt() {
+	expired := time.Now().Add(-r.timeoutInterval).UnixNano()
+	for proposalID, signedAt := range r.signedAt {
+		if _, seen := r.timeoutCheckSeens[proposalID]; !seen && signedAt < expired {
+			delete(r.signedAt, proposalID)
+		}
+	}
+}
@@ -19,11 +19,15 @@ package brokerdiscovery
 
 import (
 	"encoding/json"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/communication/nats"
+	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/money"
 	"github.com/stretchr/testify/assert"
@@ -76,7 +80,7 @@ func Test_Subscriber_StartSyncsNewProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -93,7 +97,7 @@ func Test_Subscriber_SkipUnsupportedProposal(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -111,7 +115,7 @@ func Test_Subscriber_StartSyncsIdleProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -126,7 +130,7 @@ func Test_Subscriber_StartSyncsHealthyProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -149,7 +153,7 @@ func Test_Subscriber_StartSyncsStoppedProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
 	repo.storage.AddProposal(proposalFirst(), proposalSecond())
 	err := repo.Start()
 	defer repo.Stop()
@@ -163,6 +167,164 @@ func Test_Subscriber_StartSyncsStoppedProposals(t *testing.T) {
 	assert.Exactly(t, []market.ServiceProposal{proposalSecond()}, repo.storage.Proposals())
 }
 
+func Test_Subscriber_AcceptsSignedProposals(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	bus := eventbus.New()
+	rejected := rejectedCounter(t, bus)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), bus, identity.VerifierIdentityFactory, 10*time.Second, 10*time.Millisecond)
+	err := repo.Start()
+	defer repo.Stop()
+	assert.NoError(t, err)
+
+	provider, signer := identity.NewMockSigner()
+	proposal := proposalFirst()
+	proposal.ProviderID = provider.Address
+
+	proposalRegister(connection, signedPayload(t, registerEndpoint, proposal, signer))
+
+	assert.Eventually(t, proposalCountEquals(repo, 1), 2*time.Second, 10*time.Millisecond)
+	proposal.Verified = true
+	assert.Exactly(t, []market.ServiceProposal{proposal}, repo.storage.Proposals())
+	assert.Zero(t, rejected())
+}
+
+func Test_Subscriber_RejectsProposalsSignedByOtherIdentity(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	bus := eventbus.New()
+	rejected := make(chan discovery.AppEventProposalRejected, 1)
+	err := bus.Subscribe(discovery.AppTopicProposalRejected, func(e discovery.AppEventProposalRejected) {
+		rejected <- e
+	})
+	assert.NoError(t, err)
+
+	repo := NewRepository(connection, NewStorage(eventbus.New()), bus, identity.VerifierIdentityFactory, 10*time.Second, 10*time.Millisecond)
+	err = repo.Start()
+	defer repo.Stop()
+	assert.NoError(t, err)
+
+	victim, _ := identity.NewMockSigner()
+	_, attackerSigner := identity.NewMockSigner()
+	proposal := proposalFirst()
+	proposal.ProviderID = victim.Address
+
+	proposalRegister(connection, signedPayload(t, registerEndpoint, proposal, attackerSigner))
+
+	select {
+	case e := <-rejected:
+		assert.Equal(t, discovery.AppEventProposalRejected{
+			ProviderID:  victim.Address,
+			ServiceType: "mock_service",
+			Reason:      "invalid_signature",
+		}, e)
+	case <-time.After(2 * time.Second):
+		t.Fatal("proposal was not rejected")
+	}
+	assert.Len(t, repo.storage.Proposals(), 0)
+}
+
+func Test_Subscriber_RejectsUnsignedMessagesOfVerifiedProposals(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	bus := eventbus.New()
+	rejected := rejectedCounter(t, bus)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), bus, identity.VerifierIdentityFactory, 10*time.Second, 10*time.Millisecond)
+	err := repo.Start()
+	defer repo.Stop()
+	assert.NoError(t, err)
+
+	provider, signer := identity.NewMockSigner()
+	proposal := proposalFirst()
+	proposal.ProviderID = provider.Address
+	proposalRegister(connection, signedPayload(t, registerEndpoint, proposal, signer))
+	assert.Eventually(t, proposalCountEquals(repo, 1), 2*time.Second, 10*time.Millisecond)
+
+	proposalUnregister(connection, `{
+		"proposal": {"provider_id": "`+provider.Address+`", "service_type": "mock_service"}
+	}`)
+
+	assert.Eventually(t, func() bool {
+		return rejected() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, repo.storage.Proposals(), 1)
+}
+
+func Test_Subscriber_RejectsReplayedMessages(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	bus := eventbus.New()
+	rejected := rejectedCounter(t, bus)
+	repo := NewRepository(connection, NewStorage(eventbus.New()), bus, identity.VerifierIdentityFactory, 10*time.Second, 10*time.Millisecond)
+	err := repo.Start()
+	defer repo.Stop()
+	assert.NoError(t, err)
+
+	provider, signer := identity.NewMockSigner()
+	proposal := proposalFirst()
+	proposal.ProviderID = provider.Address
+	unregister := signedPayload(t, unregisterEndpoint, proposal, signer)
+	register := signedPayload(t, registerEndpoint, proposal, signer)
+
+	proposalRegister(connection, register)
+	assert.Eventually(t, proposalCountEquals(repo, 1), 2*time.Second, 10*time.Millisecond)
+
+	proposalUnregister(connection, unregister)
+
+	assert.Eventually(t, func() bool {
+		return rejected() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, repo.storage.Proposals(), 1)
+}
+
+func Test_Subscriber_PrunesSignaturesOfGoneProposals(t *testing.T) {
+	connection := nats.StartConnectionMock()
+	defer connection.Close()
+
+	repo := NewRepository(connection, NewStorage(eventbus.New()), eventbus.New(), identity.VerifierIdentityFactory, 10*time.Millisecond, 10*time.Millisecond)
+	err := repo.Start()
+	defer repo.Stop()
+	assert.NoError(t, err)
+
+	provider, signer := identity.NewMockSigner()
+	proposal := proposalFirst()
+	proposal.ProviderID = provider.Address
+	proposalRegister(connection, signedPayload(t, registerEndpoint, proposal, signer))
+	assert.Eventually(t, proposalCountEquals(repo, 1), 2*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		repo.watchdogLock.Lock()
+		defer repo.watchdogLock.Unlock()
+		return len(repo.signedAt) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, repo.storage.Proposals(), 0)
+}
+
+// rejectedCounter returns the number of proposal rejections published to the bus so far.
+func rejectedCounter(t *testing.T, bus eventbus.EventBus) func() int {
+	var count int32
+	err := bus.Subscribe(discovery.AppTopicProposalRejected, func(_ discovery.AppEventProposalRejected) {
+		atomic.AddInt32(&count, 1)
+	})
+	assert.NoError(t, err)
+
+	return func() int {
+		return int(atomic.LoadInt32(&count))
+	}
+}
+
+func signedPayload(t *testing.T, endpoint communication.MessageEndpoint, proposal market.ServiceProposal, signer identity.Signer) string {
+	message, err := newSignedProposal(endpoint, proposal, signer)
+	assert.NoError(t, err)
+	data, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return string(data)
+}
+
 func proposalRegister(connection nats.Connection, payload string) {
 	err := connection.Publish("*.proposal-register", []byte(payload))
 	if err != nil {
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package brokerdiscovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/communication"
+	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var errStale = discovery.NewRejection("stale", "proposal message is older than the last one seen")
+
+// signedProposal is the content shared by all proposal messages. Proposal is kept raw, so that the signature is
+// verified against exactly the bytes provider signed. Messages of older providers carry no signature and timestamp.
+type signedProposal struct {
+	Proposal  json.RawMessage `json:"proposal"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+	Signature string          `json:"signature,omitempty"`
+}
+
+func newSignedProposal(endpoint communication.MessageEndpoint, proposal market.ServiceProposal, signer identity.Signer) (signedProposal, error) {
+	data, err := json.Marshal(proposal)
+	if err != nil {
+		return signedProposal{}, fmt.Errorf("could not marshal proposal: %w", err)
+	}
+
+	message := signedProposal{
+		Proposal:  data,
+		Timestamp: time.Now().UnixNano(),
+	}
+	message.Signature, err = discovery.SignProposalPayload(signer, message.payload(endpoint))
+	if err != nil {
+		return signedProposal{}, err
+	}
+	return message, nil
+}
+
+// payload returns bytes covered by the signature, endpoint is included so that a message can't be replayed as
+// a message of other kind, e.g. register as unregister.
+func (m signedProposal) payload(endpoint communication.MessageEndpoint) []byte {
+	return []byte(fmt.Sprintf("%s\n%d\n%s", endpoint, m.Timestamp, m.Proposal))
+}
+
+// decode unmarshals the proposal and checks that the message was signed by the proposal provider.
+// The proposal is returned even when verification fails, to let caller report the rejected provider.
+func (m signedProposal) decode(endpoint communication.MessageEndpoint, newVerifier identity.VerifierFactory) (market.ServiceProposal, error) {
+	var proposal market.ServiceProposal
+	if err := json.Unmarshal(m.Proposal, &proposal); err != nil {
+		return proposal, fmt.Errorf("could not unmarshal proposal: %w", err)
+	}
+
+	if err := discovery.VerifyProposalPayload(newVerifier, proposal.ProviderID, m.payload(endpoint), m.Signature); err != nil {
+		return proposal, err
+	}
+	proposal.Verified = true
+	return proposal, nil
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	proposalCacheBucket = "proposal-cache"
+	proposalCacheKey    = "proposals"
+)
+
+// CacheConfig describes proposal cache parameters.
+type CacheConfig struct {
+	// MaxAge is how long a proposal is kept after it was last seen by discovery.
+	MaxAge time.Duration
+	// Warmup is a period after start during which cached proposals are listed along with discovered ones.
+	Warmup time.Duration
+	// Timeout is how long listing waits for discovery during warmup before serving cached proposals only.
+	Timeout time.Duration
+	// PersistInterval limits how often the cache is written to disk.
+	PersistInterval time.Duration
+}
+
+// DefaultCacheConfig returns default proposal cache configuration.
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxAge:          24 * time.Hour,
+		Warmup:          3 * time.Minute,
+		Timeout:         2 * time.Second,
+		PersistInterval: time.Minute,
+	}
+}
+
+type cacheStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+// cachedProposal is a proposal persisted with the time it was last seen by discovery.
+type cachedProposal struct {
+	Proposal market.ServiceProposal
+	// Verified is persisted separately, as it is not a part of proposal JSON.
+	Verified bool
+	LastSeen time.Time
+}
+
+type cacheEntry struct {
+	proposal market.ServiceProposal
+	lastSeen time.Time
+	// refreshed tells whether discovery confirmed the proposal since start.
+	refreshed bool
+}
+
+// cache is a proposal repository which keeps proposals of a delegate repository on disk, so that they can be listed
+// right after the start, before discovery catches up, or when discovery is unavailable at all.
+type cache struct {
+	delegate  proposal.Repository
+	storage   cacheStorage
+	config    CacheConfig
+	startedAt time.Time
+
+	loadOnce    sync.Once
+	lock        sync.Mutex
+	entries     map[market.ProposalID]*cacheEntry
+	persistedAt time.Time
+}
+
+// NewCache wraps the repository with a persistent proposal cache.
+func NewCache(delegate proposal.Repository, storage cacheStorage, config CacheConfig) *cache {
+	return &cache{
+		delegate:  delegate,
+		storage:   storage,
+		config:    config,
+		startedAt: time.Now(),
+		entries:   make(map[market.ProposalID]*cacheEntry),
+	}
+}
+
+// Proposal returns a single proposal by its ID. Cached proposal is returned if discovery fails to find it during
+// warmup, or later if discovery itself fails.
+func (c *cache) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	c.load()
+
+	p, err := c.delegate.Proposal(id)
+	if err == nil {
+		c.update([]market.ServiceProposal{*p})
+		return p, nil
+	}
+
+	if !c.warmingUp() && !c.discoveryFails(id) {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if entry, ok := c.entries[id]; ok {
+		log.Debug().Err(err).Msgf("Serving cached proposal %v", id)
+		cached := entry.cached()
+		return &cached, nil
+	}
+	return nil, err
+}
+
+// discoveryFails tells whether discovery fails to list proposals of the provider, as opposed to the proposal being gone.
+func (c *cache) discoveryFails(id market.ProposalID) bool {
+	proposals, err := c.delegate.Proposals(&proposal.Filter{ProviderID: id.ProviderID, ServiceType: id.ServiceType})
+	return err != nil && len(proposals) == 0
+}
+
+// Proposals returns proposals matching the filter. During warmup cached proposals not yet discovered are listed as
+// stale along with discovered ones, they are also served instead of discovery results when discovery fails.
+func (c *cache) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	c.load()
+
+	type result struct {
+		proposals []market.ServiceProposal
+		err       error
+	}
+	done := make(chan result, 1)
+	go func() {
+		proposals, err := c.delegate.Proposals(filter)
+		c.update(proposals)
+		done <- result{proposals, err}
+	}()
+
+	warmingUp := c.warmingUp()
+	var res result
+	if warmingUp && len(c.cachedProposals(filter, false)) > 0 {
+		select {
+		case res = <-done:
+		case <-time.After(c.config.Timeout):
+			log.Debug().Msg("Discovery is slow, serving cached proposals")
+			return c.cachedProposals(filter, false), nil
+		}
+	} else {
+		res = <-done
+	}
+
+	if res.err != nil && len(res.proposals) == 0 {
+		if cached := c.cachedProposals(filter, false); len(cached) > 0 {
+			log.Warn().Err(res.err).Msgf("Discovery failed, serving %d cached proposals", len(cached))
+			return cached, nil
+		}
+		return nil, res.err
+	}
+	if warmingUp {
+		res.proposals = append(res.proposals, c.cachedProposals(filter, true)...)
+	}
+	return res.proposals, res.err
+}
+
+func (c *cache) warmingUp() bool {
+	return time.Since(c.startedAt) < c.config.Warmup
+}
+
+// cachedProposals returns cached proposals matching the filter, optionally only stale ones.
+func (c *cache) cachedProposals(filter *proposal.Filter, staleOnly bool) []market.ServiceProposal {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var proposals []market.ServiceProposal
+	for _, entry := range c.entries {
+		if staleOnly && entry.refreshed {
+			continue
+		}
+		if p := entry.cached(); filter.Matches(p) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals
+}
+
+func (e *cacheEntry) cached() market.ServiceProposal {
+	p := e.proposal
+	p.Stale = !e.refreshed
+	return p
+}
+
+// load restores the cache from disk, it is done lazily as proposals can't be unmarshalled
+// before services register their definitions.
+func (c *cache) load() {
+	c.loadOnce.Do(func() {
+		var proposals []cachedProposal
+		if err := c.storage.GetValue(proposalCacheBucket, proposalCacheKey, &proposals); err != nil {
+			if err != storage.ErrNotFound {
+				log.Warn().Err(err).Msg("Failed to load proposal cache")
+			}
+			return
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		for _, p := range proposals {
+			if _, ok := c.entries[p.Proposal.UniqueID()]; ok {
+				continue
+			}
+			p.Proposal.Verified = p.Verified
+			c.entries[p.Proposal.UniqueID()] = &cacheEntry{proposal: p.Proposal, lastSeen: p.LastSeen}
+		}
+		c.prune()
+		log.Debug().Msgf("Loaded %d cached proposals", len(c.entries))
+	})
+}
+
+func (c *cache) update(proposals []market.ServiceProposal) {
+	if len(proposals) == 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for _, p := range proposals {
+		c.entries[p.UniqueID()] = &cacheEntry{proposal: p, lastSeen: now, refreshed: true}
+	}
+	c.prune()
+
+	if now.Sub(c.persistedAt) < c.config.PersistInterval {
+		return
+	}
+	c.persistedAt = now
+	c.persist()
+}
+
+// prune removes proposals not seen for too long, lock must be held by caller.
+func (c *cache) prune() {
+	for id, entry := range c.entries {
+		if time.Since(entry.lastSeen) > c.config.MaxAge {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// persist writes the cache to disk, lock must be held by caller.
+func (c *cache) persist() {
+	proposals := make([]cachedProposal, 0, len(c.entries))
+	for _, entry := range c.entries {
+		proposals = append(proposals, cachedProposal{
+			Proposal: entry.proposal,
+			Verified: entry.proposal.Verified,
+			LastSeen: entry.lastSeen,
+		})
+	}
+	if err := c.storage.SetValue(proposalCacheBucket, proposalCacheKey, proposals); err != nil {
+		log.Warn().Err(err).Msg("Failed to persist proposal cache")
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cachedProposal1 = market.ServiceProposal{ProviderID: "0x1", ServiceType: "wireguard"}
+	cachedProposal2 = market.ServiceProposal{ProviderID: "0x2", ServiceType: "wireguard"}
+)
+
+func TestCache_ServesPersistedProposalsWhenDiscoveryFails(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal1}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	config.Warmup = 0
+	cache := NewCache(&mockRepository{err: errors.New("discovery is down")}, db, config)
+
+	proposals, err := cache.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1 stale"}, proposalStates(proposals))
+
+	p, err := cache.Proposal(cachedProposal1.UniqueID())
+	assert.NoError(t, err)
+	assert.True(t, p.Stale)
+
+	_, err = cache.Proposal(cachedProposal2.UniqueID())
+	assert.Error(t, err)
+}
+
+func TestCache_ServesGoneProposalOnlyDuringWarmup(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal1}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	repository := &mockRepository{proposals: []market.ServiceProposal{cachedProposal2}}
+	p, err := NewCache(repository, db, config).Proposal(cachedProposal1.UniqueID())
+	assert.NoError(t, err)
+	assert.True(t, p.Stale)
+
+	config.Warmup = 0
+	_, err = NewCache(repository, db, config).Proposal(cachedProposal1.UniqueID())
+	assert.Error(t, err)
+}
+
+func TestCache_PersistsVerifiedFlag(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	verified := cachedProposal1
+	verified.Verified = true
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{verified, cachedProposal2}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	cache := NewCache(&mockRepository{err: errors.New("discovery is down")}, db, config)
+	p, err := cache.Proposal(cachedProposal1.UniqueID())
+	assert.NoError(t, err)
+	assert.True(t, p.Verified)
+
+	p, err = cache.Proposal(cachedProposal2.UniqueID())
+	assert.NoError(t, err)
+	assert.False(t, p.Verified)
+}
+
+func TestCache_ListsStaleProposalsDuringWarmup(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal1}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	repository := &mockRepository{proposals: []market.ServiceProposal{cachedProposal2}}
+	cache := NewCache(repository, db, config)
+
+	proposals, err := cache.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1 stale", "0x2"}, proposalStates(proposals))
+
+	proposals, err = cache.Proposals(&proposal.Filter{ProviderID: "0x1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1 stale"}, proposalStates(proposals))
+
+	repository.proposals = []market.ServiceProposal{cachedProposal1, cachedProposal2}
+	proposals, err = cache.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2"}, proposalStates(proposals))
+}
+
+func TestCache_HidesStaleProposalsAfterWarmup(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal1}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	config.Warmup = 0
+	proposals, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal2}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x2"}, proposalStates(proposals))
+}
+
+func TestCache_ServesCachedProposalsWhenDiscoveryIsSlow(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	config := testCacheConfig()
+	_, err := NewCache(&mockRepository{proposals: []market.ServiceProposal{cachedProposal1}}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+
+	repository := &mockRepository{proposals: []market.ServiceProposal{cachedProposal1}, delay: time.Second}
+	started := time.Now()
+	proposals, err := NewCache(repository, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1 stale"}, proposalStates(proposals))
+	assert.True(t, time.Since(started) < repository.delay)
+}
+
+func TestCache_PrunesProposalsNotSeenForTooLong(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	err := db.SetValue(proposalCacheBucket, proposalCacheKey, []cachedProposal{
+		{Proposal: cachedProposal1, LastSeen: time.Now().Add(-2 * time.Hour)},
+		{Proposal: cachedProposal2, LastSeen: time.Now().Add(-time.Minute)},
+	})
+	assert.NoError(t, err)
+
+	config := testCacheConfig()
+	config.MaxAge = time.Hour
+	proposals, err := NewCache(&mockRepository{err: errors.New("discovery is down")}, db, config).Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x2 stale"}, proposalStates(proposals))
+}
+
+func TestCache_ReturnsDiscoveryErrorWithoutCachedProposals(t *testing.T) {
+	db, cleanup := newCacheStorage(t)
+	defer cleanup()
+
+	discoveryErr := errors.New("discovery is down")
+	proposals, err := NewCache(&mockRepository{err: discoveryErr}, db, testCacheConfig()).Proposals(&proposal.Filter{})
+	assert.Equal(t, discoveryErr, err)
+	assert.Len(t, proposals, 0)
+}
+
+func testCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxAge:  time.Hour,
+		Warmup:  time.Hour,
+		Timeout: 50 * time.Millisecond,
+	}
+}
+
+func newCacheStorage(t *testing.T) (*boltdb.Bolt, func()) {
+	dir, err := ioutil.TempDir("", "proposalCacheTest")
+	assert.NoError(t, err)
+	db, err := boltdb.NewStorage(dir)
+	assert.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func proposalStates(proposals []market.ServiceProposal) []string {
+	states := make([]string, 0, len(proposals))
+	for _, p := range proposals {
+		state := p.ProviderID
+		if p.Stale {
+			state += " stale"
+		}
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	return states
+}
+
+type mockRepository struct {
+	proposals []market.ServiceProposal
+	err       error
+	delay     time.Duration
+}
+
+func (m *mockRepository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	for _, p := range m.proposals {
+		if p.UniqueID() == id {
+			return &p, nil
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return nil, errors.New("proposal does not exist")
+}
+
+func (m *mockRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	time.Sleep(m.delay)
+
+	var proposals []market.ServiceProposal
+	for _, p := range m.proposals {
+		if filter.Matches(p) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals, m.err
+}
@@ -27,4 +27,14 @@ const (
 	AppTopicProposalRemoved = "ProposalRemoved"
 	// AppTopicProposalAnnounce represent proposal events topic.
 	AppTopicProposalAnnounce = "proposalEvent"
+	// AppTopicProposalRejected represents proposal message dropped because it failed verification
+	AppTopicProposalRejected = "ProposalRejected"
 )
+
+// AppEventProposalRejected represents proposal message dropped because it failed verification
+type AppEventProposalRejected struct {
+	ProviderID  string
+	ServiceType string
+	// Reason is the Rejection reason, e.g. "unsigned", "invalid_signature" or "malformed"
+	Reason string
+}
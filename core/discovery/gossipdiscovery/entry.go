@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var (
+	errInvalidTTL      = discovery.NewRejection("invalid_ttl", "proposal entry TTL is out of allowed range")
+	errFutureTimestamp = discovery.NewRejection("future_timestamp", "proposal entry is signed in the future")
+)
+
+// entry is a proposal record signed by its provider. Nodes relay entries as is, so any node can verify
+// an entry no matter how many hops it went through. Removed entries are kept until they expire,
+// to stop older copies of the proposal from being resurrected by slower peers.
+type entry struct {
+	Proposal  json.RawMessage `json:"proposal"`
+	Timestamp int64           `json:"timestamp"`
+	TTL       int64           `json:"ttl"`
+	Removed   bool            `json:"removed,omitempty"`
+	Signature string          `json:"signature"`
+
+	proposal market.ServiceProposal
+}
+
+func newEntry(proposal market.ServiceProposal, ttl time.Duration, removed bool, signer identity.Signer) (entry, error) {
+	data, err := json.Marshal(proposal)
+	if err != nil {
+		return entry{}, fmt.Errorf("could not marshal proposal: %w", err)
+	}
+
+	e := entry{
+		Proposal:  data,
+		Timestamp: time.Now().UnixNano(),
+		TTL:       int64(ttl / time.Second),
+		Removed:   removed,
+	}
+	e.Signature, err = discovery.SignProposalPayload(signer, e.payload())
+	if err != nil {
+		return entry{}, err
+	}
+	return e, nil
+}
+
+// payload returns bytes covered by the signature.
+func (e entry) payload() []byte {
+	return []byte(fmt.Sprintf("gossip\n%d\n%d\n%t\n%s", e.Timestamp, e.TTL, e.Removed, e.Proposal))
+}
+
+func (e entry) expiresAt() time.Time {
+	return time.Unix(0, e.Timestamp).Add(time.Duration(e.TTL) * time.Second)
+}
+
+// decode unmarshals the proposal of entry, signature is checked separately by verify.
+func (e *entry) decode() error {
+	if err := json.Unmarshal(e.Proposal, &e.proposal); err != nil {
+		return fmt.Errorf("could not unmarshal proposal: %w", err)
+	}
+	return nil
+}
+
+// verify checks that the entry was signed by the proposal provider and its lifetime is sane.
+func (e *entry) verify(newVerifier identity.VerifierFactory, maxTTL, maxClockSkew time.Duration) error {
+	if e.Signature == "" {
+		return discovery.ErrUnsigned
+	}
+	if e.TTL <= 0 || time.Duration(e.TTL)*time.Second > maxTTL {
+		return errInvalidTTL
+	}
+	if time.Unix(0, e.Timestamp).After(time.Now().Add(maxClockSkew)) {
+		return errFutureTimestamp
+	}
+	if err := discovery.VerifyProposalPayload(newVerifier, e.proposal.ProviderID, e.payload(), e.Signature); err != nil {
+		return err
+	}
+	e.proposal.Verified = true
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// protocolVersion is bumped on incompatible wire format changes, messages of other versions are ignored.
+const protocolVersion = 2
+
+type messageType string
+
+const (
+	// messagePush carries proposal entries, either new ones being spread or pulled ones.
+	messagePush = messageType("push")
+	// messageDigest lists entries known by sender, receiver pulls what it lacks.
+	// Digest which is not a reply is answered with receiver's own digest, so that both peers converge.
+	messageDigest = messageType("digest")
+	// messagePull requests entries from receiver.
+	messagePull = messageType("pull")
+	// messageHello asks receiver for a cookie, hello which is not a reply is answered with receiver's hello.
+	messageHello = messageType("hello")
+)
+
+// message is a single datagram of gossip protocol.
+// Every node issues a cookie to each peer address, peers echo it in their messages to prove that they receive
+// messages sent to the address. Messages without a valid cookie are dropped, except hellos asking for one.
+type message struct {
+	Version int         `json:"version"`
+	Type    messageType `json:"type"`
+	// Cookie is the cookie receiver issued to the sender.
+	Cookie string `json:"cookie,omitempty"`
+	// Issue is the cookie sender issues to the receiver.
+	Issue   string     `json:"issue,omitempty"`
+	Entries []entry    `json:"entries,omitempty"`
+	Digest  []digest   `json:"digest,omitempty"`
+	Reply   bool       `json:"reply,omitempty"`
+	Keys    []entryKey `json:"keys,omitempty"`
+	Peers   []string   `json:"peers,omitempty"`
+	// Padding makes hello larger than the reply to it.
+	Padding string `json:"padding,omitempty"`
+}
+
+type entryKey struct {
+	ProviderID  string `json:"provider_id"`
+	ServiceType string `json:"service_type"`
+}
+
+func newEntryKey(id market.ProposalID) entryKey {
+	return entryKey{ProviderID: id.ProviderID, ServiceType: id.ServiceType}
+}
+
+func (k entryKey) proposalID() market.ProposalID {
+	return market.ProposalID{ProviderID: k.ProviderID, ServiceType: k.ServiceType}
+}
+
+type digest struct {
+	entryKey
+	Timestamp int64 `json:"timestamp"`
+}
@@ -0,0 +1,611 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const (
+	maxDatagramSize = 64 * 1024
+	// maxMessageItems limits digest and pull messages to keep them within a datagram.
+	maxMessageItems = 256
+	// maxExchangedPeers is the number of known peers shared with each digest.
+	maxExchangedPeers = 8
+	maxClockSkew      = time.Minute
+	// maxResponseFactor limits the size of responses relative to the size of request.
+	maxResponseFactor = 8
+	cookieSize        = 16
+	// helloPaddingSize makes hello larger than the reply to it, so spoofed hellos don't amplify traffic.
+	helloPaddingSize = 96
+)
+
+// Config describes gossip node parameters.
+type Config struct {
+	// Address is UDP address the node listens on.
+	Address string
+	// Bootstrap is a list of peer addresses to join the network through, they are never forgotten.
+	Bootstrap []string
+	// Interval is a period of anti-entropy rounds.
+	Interval time.Duration
+	// Fanout is a number of peers new entries are pushed to and digests are sent to each round.
+	Fanout int
+	// MaxPeers limits the number of remembered peers.
+	MaxPeers int
+	// PeerTTL is a time after which silent peers are forgotten.
+	PeerTTL time.Duration
+	// MaxTTL is the longest accepted lifetime of an entry.
+	MaxTTL time.Duration
+}
+
+// DefaultConfig returns default gossip node configuration.
+func DefaultConfig() Config {
+	return Config{
+		Address:  ":4070",
+		Interval: 5 * time.Second,
+		Fanout:   3,
+		MaxPeers: 64,
+		PeerTTL:  5 * time.Minute,
+		MaxTTL:   time.Hour,
+	}
+}
+
+type peer struct {
+	addr      *net.UDPAddr
+	seen      time.Time
+	bootstrap bool
+	// cookie is issued by the peer, it is empty until the peer replied.
+	cookie string
+}
+
+// Node exchanges signed proposal entries with other nodes over UDP.
+// New entries are pushed to a few random peers, which forward them further, while periodic digest exchange
+// (anti-entropy) repairs whatever was lost on the way.
+type Node struct {
+	config      Config
+	publisher   eventbus.Publisher
+	newVerifier identity.VerifierFactory
+	secret      []byte
+
+	conn *net.UDPConn
+
+	lock    sync.Mutex
+	entries map[market.ProposalID]entry
+	peers   map[string]*peer
+
+	stopOnce sync.Once
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewNode creates a gossip node.
+func NewNode(config Config, publisher eventbus.Publisher, verifierFactory identity.VerifierFactory) *Node {
+	secret := make([]byte, sha256.Size)
+	if _, err := crand.Read(secret); err != nil {
+		panic(fmt.Sprintf("could not generate gossip cookie secret: %v", err))
+	}
+
+	return &Node{
+		config:      config,
+		publisher:   publisher,
+		newVerifier: verifierFactory,
+		secret:      secret,
+		entries:     make(map[market.ProposalID]entry),
+		peers:       make(map[string]*peer),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins listening for peers and gossiping with them.
+func (n *Node) Start() error {
+	addr, err := net.ResolveUDPAddr("udp", n.config.Address)
+	if err != nil {
+		return fmt.Errorf("could not resolve gossip address %q: %w", n.config.Address, err)
+	}
+	n.conn, err = net.ListenUDP("udp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen for gossip: %w", err)
+	}
+	log.Info().Msgf("Gossip discovery listening on %s", n.conn.LocalAddr())
+
+	n.wg.Add(2)
+	go n.readLoop()
+	go n.gossipLoop()
+	return nil
+}
+
+// Stop stops the node.
+func (n *Node) Stop() {
+	n.stopOnce.Do(func() {
+		close(n.stopChan)
+		if n.conn != nil {
+			n.conn.Close()
+		}
+		n.wg.Wait()
+	})
+}
+
+// Addr returns the address node listens on.
+func (n *Node) Addr() net.Addr {
+	return n.conn.LocalAddr()
+}
+
+// Peers returns the number of currently known peers.
+func (n *Node) Peers() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return len(n.peers)
+}
+
+// Publish signs the proposal entry and spreads it to peers.
+func (n *Node) Publish(proposal market.ServiceProposal, ttl time.Duration, removed bool, signer identity.Signer) error {
+	e, err := newEntry(proposal, ttl, removed, signer)
+	if err != nil {
+		return err
+	}
+	if err := e.decode(); err != nil {
+		return err
+	}
+	return n.accept(e, nil)
+}
+
+// proposal returns a live proposal by its ID.
+func (n *Node) proposal(id market.ProposalID) (market.ServiceProposal, bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	e, ok := n.entries[id]
+	if !ok || !n.visible(e) {
+		return market.ServiceProposal{}, false
+	}
+	return e.proposal, true
+}
+
+// proposals returns live proposals matched by given function.
+func (n *Node) proposals(match func(market.ServiceProposal) bool) []market.ServiceProposal {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	proposals := make([]market.ServiceProposal, 0)
+	for _, e := range n.entries {
+		if n.visible(e) && match(e.proposal) {
+			proposals = append(proposals, e.proposal)
+		}
+	}
+	return proposals
+}
+
+// visible tells whether the entry is a proposal consumers can use. Unsupported proposals are still relayed
+// to peers, which might support them.
+func (n *Node) visible(e entry) bool {
+	return !e.Removed && time.Now().Before(e.expiresAt()) && e.proposal.IsSupported()
+}
+
+func (n *Node) readLoop() {
+	defer n.wg.Done()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		size, from, err := n.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-n.stopChan:
+				return
+			default:
+			}
+			log.Warn().Err(err).Msg("Failed to read gossip message")
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(buf[:size], &msg); err != nil {
+			log.Debug().Err(err).Msgf("Dropped malformed gossip message from %s", from)
+			continue
+		}
+		if msg.Version != protocolVersion {
+			log.Trace().Msgf("Dropped gossip message of version %d from %s", msg.Version, from)
+			continue
+		}
+		n.handle(from, msg, size)
+	}
+}
+
+// handle processes the message received from the peer. Responses to the message are limited
+// by its size, so that the node can't be used to amplify traffic.
+func (n *Node) handle(from *net.UDPAddr, msg message, size int) {
+	budget := size
+	if msg.Type == messageHello && !msg.Reply {
+		n.reply(from, message{Type: messageHello, Reply: true, Cookie: msg.Issue}, &budget)
+		return
+	}
+	if !hmac.Equal([]byte(msg.Cookie), []byte(n.cookie(from))) {
+		log.Trace().Msgf("Dropped gossip message without valid cookie from %s", from)
+		return
+	}
+
+	n.addPeer(from, msg.Issue)
+	for _, address := range msg.Peers {
+		if addr, err := parsePeerAddress(address); err == nil {
+			n.addPeer(addr, "")
+		}
+	}
+
+	budget *= maxResponseFactor
+	switch msg.Type {
+	case messageHello:
+	case messagePush:
+		for _, e := range msg.Entries {
+			if err := e.decode(); err != nil {
+				discovery.PublishRejection(n.publisher, e.proposal, err)
+				continue
+			}
+			_ = n.accept(e, from)
+		}
+	case messageDigest:
+		n.handleDigest(from, msg, &budget)
+	case messagePull:
+		n.handlePull(from, msg, &budget)
+	default:
+		log.Debug().Msgf("Dropped gossip message of unknown type %q from %s", msg.Type, from)
+	}
+}
+
+func (n *Node) handleDigest(from *net.UDPAddr, msg message, budget *int) {
+	n.lock.Lock()
+	var missing []entryKey
+	for _, d := range msg.Digest {
+		if known, ok := n.entries[d.proposalID()]; !ok || known.Timestamp < d.Timestamp {
+			missing = append(missing, d.entryKey)
+		}
+	}
+	n.lock.Unlock()
+
+	if len(missing) > maxMessageItems {
+		missing = missing[:maxMessageItems]
+	}
+	if len(missing) > 0 {
+		n.reply(from, message{Type: messagePull, Cookie: msg.Issue, Keys: missing}, budget)
+	}
+	if !msg.Reply {
+		n.replyDigest(from, msg.Issue, budget)
+	}
+}
+
+func (n *Node) handlePull(from *net.UDPAddr, msg message, budget *int) {
+	keys := msg.Keys
+	if len(keys) > maxMessageItems {
+		keys = keys[:maxMessageItems]
+	}
+
+	n.lock.Lock()
+	var entries []entry
+	pulled := make(map[market.ProposalID]bool)
+	for _, key := range keys {
+		id := key.proposalID()
+		if e, ok := n.entries[id]; ok && !pulled[id] && time.Now().Before(e.expiresAt()) {
+			entries = append(entries, e)
+			pulled[id] = true
+		}
+	}
+	n.lock.Unlock()
+
+	// Entries which don't fit are pulled again in later rounds.
+	for _, e := range entries {
+		n.reply(from, message{Type: messagePush, Cookie: msg.Issue, Entries: []entry{e}}, budget)
+	}
+}
+
+// accept stores the entry if it is newer than the known one and forwards it to random peers.
+// Older and duplicate entries are ignored silently, as every entry reaches a node several times.
+func (n *Node) accept(e entry, from *net.UDPAddr) error {
+	id := e.proposal.UniqueID()
+	if !time.Now().Before(e.expiresAt()) {
+		return nil
+	}
+
+	n.lock.Lock()
+	known, ok := n.entries[id]
+	n.lock.Unlock()
+	if ok && known.Timestamp >= e.Timestamp {
+		return nil
+	}
+
+	if err := e.verify(n.newVerifier, n.config.MaxTTL, maxClockSkew); err != nil {
+		discovery.PublishRejection(n.publisher, e.proposal, err)
+		return err
+	}
+
+	n.lock.Lock()
+	if known, ok := n.entries[id]; ok && known.Timestamp >= e.Timestamp {
+		n.lock.Unlock()
+		return nil
+	}
+	n.entries[id] = e
+	targets := n.randomPeers(n.config.Fanout, from, n.replied)
+	n.lock.Unlock()
+
+	for _, addr := range targets {
+		n.send(addr, message{Type: messagePush, Entries: []entry{e}})
+	}
+	return nil
+}
+
+func (n *Node) gossipLoop() {
+	defer n.wg.Done()
+
+	for {
+		n.gossipRound()
+
+		select {
+		case <-n.stopChan:
+			return
+		case <-time.After(n.config.Interval):
+		}
+	}
+}
+
+// gossipRound exchanges digests with random peers to pull entries missed by push.
+// Peers which haven't replied recently are only greeted, until they reply with a cookie.
+func (n *Node) gossipRound() {
+	n.refreshBootstrap()
+	n.expire()
+
+	n.lock.Lock()
+	targets := n.randomPeers(n.config.Fanout, nil, n.replied)
+	strangers := n.randomPeers(n.config.Fanout, nil, func(p *peer) bool { return !n.replied(p) })
+	n.lock.Unlock()
+
+	for _, addr := range strangers {
+		n.send(addr, message{Type: messageHello, Padding: strings.Repeat("0", helloPaddingSize)})
+	}
+	for _, addr := range targets {
+		n.sendDigest(addr)
+	}
+}
+
+func (n *Node) sendDigest(to *net.UDPAddr) {
+	digests, peers := n.digest(to)
+
+	// Empty digest is still sent, so that receiver replies with its own.
+	for {
+		size := min(len(digests), maxMessageItems)
+		n.send(to, message{Type: messageDigest, Digest: digests[:size], Peers: peers})
+		digests = digests[size:]
+		if len(digests) == 0 {
+			return
+		}
+	}
+}
+
+// replyDigest answers the digest of the peer with a single datagram which fits into the budget.
+// Entries are listed in random order, so each reply covers a different part of them.
+func (n *Node) replyDigest(to *net.UDPAddr, cookie string, budget *int) {
+	digests, peers := n.digest(to)
+	build := func(size int) message {
+		return message{Type: messageDigest, Cookie: cookie, Digest: digests[:size], Reply: true, Peers: peers}
+	}
+
+	fits := sort.Search(min(len(digests), maxMessageItems)+1, func(size int) bool {
+		data, err := n.marshal(to, build(size))
+		return err != nil || len(data) > *budget
+	}) - 1
+	if fits >= 0 {
+		n.reply(to, build(fits), budget)
+	}
+}
+
+// digest lists known entries and a few peers which replied, to be shared with the given peer.
+func (n *Node) digest(to *net.UDPAddr) ([]digest, []string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	digests := make([]digest, 0, len(n.entries))
+	for id, e := range n.entries {
+		digests = append(digests, digest{entryKey: newEntryKey(id), Timestamp: e.Timestamp})
+	}
+	var peers []string
+	for _, p := range n.randomPeers(maxExchangedPeers, to, n.replied) {
+		peers = append(peers, p.String())
+	}
+	return digests, peers
+}
+
+func (n *Node) send(to *net.UDPAddr, msg message) {
+	data, err := n.marshal(to, msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal gossip message")
+		return
+	}
+	n.write(to, data)
+}
+
+// reply sends the response to the peer request, if it fits into the budget left for the request.
+func (n *Node) reply(to *net.UDPAddr, msg message, budget *int) bool {
+	data, err := n.marshal(to, msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal gossip message")
+		return false
+	}
+	if len(data) > *budget {
+		return false
+	}
+	*budget -= len(data)
+	n.write(to, data)
+	return true
+}
+
+// marshal adds cookies to the message, cookie of the receiver is taken from known peers unless it is set.
+func (n *Node) marshal(to *net.UDPAddr, msg message) ([]byte, error) {
+	msg.Version = protocolVersion
+	msg.Issue = n.cookie(to)
+	if msg.Cookie == "" {
+		n.lock.Lock()
+		if p, ok := n.peers[to.String()]; ok {
+			msg.Cookie = p.cookie
+		}
+		n.lock.Unlock()
+	}
+	return json.Marshal(msg)
+}
+
+func (n *Node) write(to *net.UDPAddr, data []byte) {
+	if _, err := n.conn.WriteToUDP(data, to); err != nil {
+		log.Debug().Err(err).Msgf("Failed to send gossip message to %s", to)
+	}
+}
+
+// cookie returns the cookie issued to the peer address. Only a peer receiving messages sent
+// to the address can echo it, so spoofed messages are never answered.
+func (n *Node) cookie(addr *net.UDPAddr) string {
+	mac := hmac.New(sha256.New, n.secret)
+	mac.Write([]byte(addr.String()))
+	return hex.EncodeToString(mac.Sum(nil)[:cookieSize])
+}
+
+func (n *Node) refreshBootstrap() {
+	for _, address := range n.config.Bootstrap {
+		addr, err := net.ResolveUDPAddr("udp", address)
+		if err != nil {
+			log.Debug().Err(err).Msgf("Could not resolve gossip bootstrap peer %q", address)
+			continue
+		}
+
+		n.lock.Lock()
+		if p, ok := n.peers[addr.String()]; ok {
+			p.bootstrap = true
+		} else {
+			n.peers[addr.String()] = &peer{addr: addr, seen: time.Now(), bootstrap: true}
+		}
+		n.lock.Unlock()
+	}
+}
+
+// addPeer remembers the peer. Peer which replied with its cookie is seen now, peers shared by other nodes
+// come without cookie and are only greeted until they reply.
+func (n *Node) addPeer(addr *net.UDPAddr, cookie string) {
+	if n.isSelf(addr) {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	p, ok := n.peers[addr.String()]
+	if !ok {
+		if len(n.peers) >= n.config.MaxPeers {
+			return
+		}
+		p = &peer{addr: addr, seen: time.Now()}
+		n.peers[addr.String()] = p
+	}
+	if cookie != "" {
+		p.cookie = cookie
+		p.seen = time.Now()
+	}
+}
+
+// replied tells whether the peer replied recently, only such peers are sent messages other than hello.
+// Peers which restarted issue new cookies, so silent peers are greeted again, lock must be held by caller.
+func (n *Node) replied(p *peer) bool {
+	return p.cookie != "" && time.Since(p.seen) < n.config.PeerTTL/2
+}
+
+func (n *Node) isSelf(addr *net.UDPAddr) bool {
+	local := n.conn.LocalAddr().(*net.UDPAddr)
+	if addr.Port != local.Port {
+		return false
+	}
+	return addr.IP.Equal(local.IP) || (local.IP.IsUnspecified() && addr.IP.IsLoopback())
+}
+
+func (n *Node) expire() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := time.Now()
+	for id, e := range n.entries {
+		if !now.Before(e.expiresAt()) {
+			delete(n.entries, id)
+		}
+	}
+	for key, p := range n.peers {
+		if !p.bootstrap && now.Sub(p.seen) > n.config.PeerTTL {
+			delete(n.peers, key)
+		}
+	}
+}
+
+// randomPeers picks up to count random matching peers except the given one, lock must be held by caller.
+func (n *Node) randomPeers(count int, except *net.UDPAddr, match func(*peer) bool) []*net.UDPAddr {
+	candidates := make([]*net.UDPAddr, 0, len(n.peers))
+	for _, p := range n.peers {
+		if except != nil && p.addr.String() == except.String() {
+			continue
+		}
+		if !match(p) {
+			continue
+		}
+		candidates = append(candidates, p.addr)
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	return candidates
+}
+
+// parsePeerAddress parses the peer address shared by other nodes, only IP addresses are accepted,
+// to avoid resolving host names supplied by strangers.
+func parsePeerAddress(address string) (*net.UDPAddr, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) == nil {
+		return nil, fmt.Errorf("peer address is not an IP: %q", address)
+	}
+	return net.ResolveUDPAddr("udp", net.JoinHostPort(host, port))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
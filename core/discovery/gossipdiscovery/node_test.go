@@ -0,0 +1,410 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	market.RegisterServiceDefinitionUnserializer(
+		"mock_service",
+		func(rawDefinition *json.RawMessage) (market.ServiceDefinition, error) {
+			return mockServiceDefinition{}, nil
+		},
+	)
+	market.RegisterPaymentMethodUnserializer(
+		"mock_payment",
+		func(rawDefinition *json.RawMessage) (market.PaymentMethod, error) {
+			return mockPaymentMethod{}, nil
+		},
+	)
+	market.RegisterContactUnserializer("mock_contact",
+		func(rawMessage *json.RawMessage) (market.ContactDefinition, error) {
+			return mockContact{}, nil
+		},
+	)
+}
+
+func Test_Nodes_SpreadProposalThroughRelay(t *testing.T) {
+	provider := startTestNode(t, eventbus.New())
+	defer provider.Stop()
+	relay := startTestNode(t, eventbus.New(), provider.Addr().String())
+	defer relay.Stop()
+	consumer := startTestNode(t, eventbus.New(), relay.Addr().String())
+	defer consumer.Stop()
+
+	id, signer := identity.NewMockSigner()
+	p := newTestProposal(id)
+	assert.NoError(t, NewRegistry(provider, time.Minute).RegisterProposal(p, signer))
+
+	repository := NewRepository(consumer)
+	assert.Eventually(t, proposalCountEquals(repository, 1), 5*time.Second, 10*time.Millisecond)
+
+	received, err := repository.Proposal(p.UniqueID())
+	assert.NoError(t, err)
+	assert.Equal(t, id.Address, received.ProviderID)
+	assert.True(t, received.Verified)
+	assert.Equal(t, 2, relay.Peers())
+}
+
+func Test_Nodes_SpreadUnregisteredProposal(t *testing.T) {
+	provider := startTestNode(t, eventbus.New())
+	defer provider.Stop()
+	consumer := startTestNode(t, eventbus.New(), provider.Addr().String())
+	defer consumer.Stop()
+
+	id, signer := identity.NewMockSigner()
+	p := newTestProposal(id)
+	registry := NewRegistry(provider, time.Minute)
+	assert.NoError(t, registry.RegisterProposal(p, signer))
+
+	repository := NewRepository(consumer)
+	assert.Eventually(t, proposalCountEquals(repository, 1), 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, registry.UnregisterProposal(p, signer))
+	assert.Eventually(t, proposalCountEquals(repository, 0), 5*time.Second, 10*time.Millisecond)
+
+	_, err := repository.Proposal(p.UniqueID())
+	assert.Error(t, err)
+}
+
+func Test_Node_PullsKnownProposalsWhenJoining(t *testing.T) {
+	provider := startTestNode(t, eventbus.New())
+	defer provider.Stop()
+
+	id, signer := identity.NewMockSigner()
+	assert.NoError(t, NewRegistry(provider, time.Minute).RegisterProposal(newTestProposal(id), signer))
+
+	consumer := startTestNode(t, eventbus.New(), provider.Addr().String())
+	defer consumer.Stop()
+
+	assert.Eventually(t, proposalCountEquals(NewRepository(consumer), 1), 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_Node_RejectsEntriesSignedByOtherIdentity(t *testing.T) {
+	bus := eventbus.New()
+	rejected := make(chan discovery.AppEventProposalRejected, 1)
+	err := bus.Subscribe(discovery.AppTopicProposalRejected, func(e discovery.AppEventProposalRejected) {
+		rejected <- e
+	})
+	assert.NoError(t, err)
+
+	node := startTestNode(t, bus)
+	defer node.Stop()
+
+	victim, _ := identity.NewMockSigner()
+	_, attackerSigner := identity.NewMockSigner()
+	e, err := newEntry(newTestProposal(victim), time.Minute, false, attackerSigner)
+	assert.NoError(t, err)
+	peer := newTestPeer(t, node)
+	defer peer.close()
+	peer.handshake()
+	peer.send(message{Type: messagePush, Entries: []entry{e}})
+
+	select {
+	case e := <-rejected:
+		assert.Equal(t, discovery.AppEventProposalRejected{
+			ProviderID:  victim.Address,
+			ServiceType: "mock_service",
+			Reason:      "invalid_signature",
+		}, e)
+	case <-time.After(2 * time.Second):
+		t.Fatal("entry was not rejected")
+	}
+	assert.Len(t, node.proposals(func(market.ServiceProposal) bool { return true }), 0)
+}
+
+func Test_Node_IgnoresOlderEntries(t *testing.T) {
+	node := startTestNode(t, eventbus.New())
+	defer node.Stop()
+
+	id, signer := identity.NewMockSigner()
+	p := newTestProposal(id)
+	older, err := newEntry(p, time.Minute, true, signer)
+	assert.NoError(t, err)
+	assert.NoError(t, node.Publish(p, time.Minute, false, signer))
+
+	assert.NoError(t, older.decode())
+	assert.NoError(t, node.accept(older, nil))
+
+	_, ok := node.proposal(p.UniqueID())
+	assert.True(t, ok)
+}
+
+func Test_Node_HidesExpiredEntries(t *testing.T) {
+	node := startTestNode(t, eventbus.New())
+	defer node.Stop()
+
+	id, signer := identity.NewMockSigner()
+	p := newTestProposal(id)
+	assert.NoError(t, node.Publish(p, time.Second, false, signer))
+
+	_, ok := node.proposal(p.UniqueID())
+	assert.True(t, ok)
+	assert.Eventually(t, func() bool {
+		_, ok := node.proposal(p.UniqueID())
+		return !ok
+	}, 3*time.Second, 50*time.Millisecond)
+}
+
+func Test_Node_RejectsEntriesLivingTooLong(t *testing.T) {
+	bus := eventbus.New()
+	rejected := make(chan discovery.AppEventProposalRejected, 1)
+	err := bus.Subscribe(discovery.AppTopicProposalRejected, func(e discovery.AppEventProposalRejected) {
+		rejected <- e
+	})
+	assert.NoError(t, err)
+
+	node := startTestNode(t, bus)
+	defer node.Stop()
+
+	id, signer := identity.NewMockSigner()
+	err = node.Publish(newTestProposal(id), 2*time.Hour, false, signer)
+	assert.Equal(t, errInvalidTTL, err)
+
+	select {
+	case e := <-rejected:
+		assert.Equal(t, "invalid_ttl", e.Reason)
+	case <-time.After(2 * time.Second):
+		t.Fatal("entry was not rejected")
+	}
+}
+
+func Test_Node_DoesNotAnswerMessagesWithoutCookie(t *testing.T) {
+	node := startSilentTestNode(t)
+	defer node.Stop()
+	id, signer := identity.NewMockSigner()
+	p := newTestProposal(id)
+	assert.NoError(t, node.Publish(p, time.Minute, false, signer))
+
+	peer := newTestPeer(t, node)
+	defer peer.close()
+	peer.send(message{Type: messageDigest})
+	peer.send(message{Type: messagePull, Keys: []entryKey{newEntryKey(p.UniqueID())}})
+	peer.send(message{Type: messageHello})
+	assert.Empty(t, peer.receiveAll())
+
+	peer.send(message{Type: messageHello, Padding: strings.Repeat("0", helloPaddingSize)})
+	replies := peer.receiveAll()
+	if assert.Len(t, replies, 1) {
+		assert.Equal(t, messageHello, replies[0].Type)
+		assert.Equal(t, "peer-cookie", replies[0].Cookie)
+	}
+	assert.Equal(t, 0, node.Peers())
+}
+
+func Test_Node_LimitsResponsesBySizeOfRequest(t *testing.T) {
+	node := startSilentTestNode(t)
+	defer node.Stop()
+	var keys []entryKey
+	for i := 0; i < 30; i++ {
+		id, signer := identity.NewMockSigner()
+		p := newTestProposal(id)
+		assert.NoError(t, node.Publish(p, time.Minute, false, signer))
+		keys = append(keys, newEntryKey(p.UniqueID()))
+	}
+
+	peer := newTestPeer(t, node)
+	defer peer.close()
+	peer.handshake()
+
+	size := peer.send(message{Type: messageDigest})
+	replies := peer.receiveAll()
+	if assert.Len(t, replies, 1) {
+		assert.True(t, replies[0].Reply)
+		assert.NotEmpty(t, replies[0].Digest)
+		assert.True(t, len(replies[0].Digest) < len(keys))
+	}
+	assert.True(t, peer.received <= maxResponseFactor*size)
+
+	peer.received = 0
+	size = peer.send(message{Type: messagePull, Keys: append(keys, keys...)})
+	replies = peer.receiveAll()
+	assert.NotEmpty(t, replies)
+	assert.True(t, len(replies) <= len(keys))
+	assert.True(t, peer.received <= maxResponseFactor*size)
+}
+
+func Test_Node_GreetsSharedPeersUntilTheyReply(t *testing.T) {
+	node := startTestNode(t, eventbus.New())
+	defer node.Stop()
+	id, signer := identity.NewMockSigner()
+	assert.NoError(t, node.Publish(newTestProposal(id), time.Minute, false, signer))
+
+	peer := newTestPeer(t, node)
+	defer peer.close()
+	peer.handshake()
+	shared := newTestPeer(t, node)
+	defer shared.close()
+	peer.send(message{Type: messageDigest, Reply: true, Peers: []string{shared.conn.LocalAddr().String()}})
+
+	hellos := shared.receiveAll()
+	assert.NotEmpty(t, hellos)
+	for _, msg := range hellos {
+		assert.Equal(t, messageHello, msg.Type)
+		assert.False(t, msg.Reply)
+	}
+
+	shared.send(message{Type: messageHello, Reply: true, Cookie: hellos[0].Issue})
+	assert.Eventually(t, func() bool {
+		for _, msg := range shared.receiveAll() {
+			if msg.Type == messageDigest {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func startTestNode(t *testing.T, publisher eventbus.Publisher, bootstrap ...string) *Node {
+	return startTestNodeWithInterval(t, publisher, 50*time.Millisecond, bootstrap...)
+}
+
+// startSilentTestNode starts node which doesn't gossip on its own, so it only answers messages.
+func startSilentTestNode(t *testing.T) *Node {
+	return startTestNodeWithInterval(t, eventbus.New(), time.Hour)
+}
+
+func startTestNodeWithInterval(t *testing.T, publisher eventbus.Publisher, interval time.Duration, bootstrap ...string) *Node {
+	config := DefaultConfig()
+	config.Address = "127.0.0.1:0"
+	config.Bootstrap = bootstrap
+	config.Interval = interval
+
+	node := NewNode(config, publisher, identity.VerifierIdentityFactory)
+	assert.NoError(t, node.Start())
+	return node
+}
+
+// testPeer talks to the node under test over raw gossip messages.
+type testPeer struct {
+	t        *testing.T
+	node     *Node
+	conn     *net.UDPConn
+	cookie   string
+	received int
+}
+
+func newTestPeer(t *testing.T, node *Node) *testPeer {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+	return &testPeer{t: t, node: node, conn: conn}
+}
+
+// handshake gets the cookie issued by the node.
+func (p *testPeer) handshake() {
+	p.send(message{Type: messageHello, Padding: strings.Repeat("0", helloPaddingSize)})
+	for _, msg := range p.receiveAll() {
+		if msg.Type == messageHello && msg.Reply {
+			p.cookie = msg.Issue
+		}
+	}
+	assert.NotEmpty(p.t, p.cookie)
+	p.received = 0
+}
+
+// send sends the message to the node and returns its size.
+func (p *testPeer) send(msg message) int {
+	msg.Version = protocolVersion
+	msg.Issue = "peer-cookie"
+	if msg.Cookie == "" {
+		msg.Cookie = p.cookie
+	}
+	data, err := json.Marshal(msg)
+	assert.NoError(p.t, err)
+	_, err = p.conn.WriteToUDP(data, p.node.Addr().(*net.UDPAddr))
+	assert.NoError(p.t, err)
+	return len(data)
+}
+
+// receiveAll returns messages received within a short while.
+func (p *testPeer) receiveAll() []message {
+	var messages []message
+	buf := make([]byte, maxDatagramSize)
+	assert.NoError(p.t, p.conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+	for {
+		size, _, err := p.conn.ReadFromUDP(buf)
+		if err != nil {
+			return messages
+		}
+		p.received += size
+
+		var msg message
+		assert.NoError(p.t, json.Unmarshal(buf[:size], &msg))
+		messages = append(messages, msg)
+	}
+}
+
+func (p *testPeer) close() {
+	p.conn.Close()
+}
+
+func proposalCountEquals(repository *Repository, count int) func() bool {
+	return func() bool {
+		proposals, err := repository.Proposals(&proposal.Filter{})
+		return err == nil && len(proposals) == count
+	}
+}
+
+func newTestProposal(id identity.Identity) market.ServiceProposal {
+	return market.ServiceProposal{
+		ProviderID:        id.Address,
+		ServiceType:       "mock_service",
+		ServiceDefinition: mockServiceDefinition{},
+		PaymentMethodType: "mock_payment",
+		PaymentMethod:     mockPaymentMethod{},
+		ProviderContacts:  []market.Contact{{Type: "mock_contact", Definition: mockContact{}}},
+	}
+}
+
+type mockServiceDefinition struct{}
+
+func (service mockServiceDefinition) GetLocation() market.Location {
+	return market.Location{}
+}
+
+type mockPaymentMethod struct{}
+
+func (method mockPaymentMethod) GetPrice() money.Money {
+	return money.Money{}
+}
+
+func (method mockPaymentMethod) GetType() string {
+	return "mock"
+}
+
+func (method mockPaymentMethod) GetRate() market.PaymentRate {
+	return market.PaymentRate{
+		PerTime: time.Minute,
+	}
+}
+
+type mockContact struct{}
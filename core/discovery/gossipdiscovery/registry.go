@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Registry spreads proposals of this node to the gossip network.
+type Registry struct {
+	node *Node
+	ttl  time.Duration
+}
+
+// NewRegistry creates a registry publishing proposals through given node. Published proposals expire after ttl,
+// unless they are pinged in time.
+func NewRegistry(node *Node, ttl time.Duration) *Registry {
+	return &Registry{
+		node: node,
+		ttl:  ttl,
+	}
+}
+
+// RegisterProposal registers service proposal to discovery service
+func (r *Registry) RegisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
+	return r.node.Publish(proposal, r.ttl, false, signer)
+}
+
+// UnregisterProposal unregisters a service proposal when client disconnects
+func (r *Registry) UnregisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
+	return r.node.Publish(proposal, r.ttl, true, signer)
+}
+
+// PingProposal pings service proposal as being alive
+func (r *Registry) PingProposal(proposal market.ServiceProposal, signer identity.Signer) error {
+	return r.node.Publish(proposal, r.ttl, false, signer)
+}
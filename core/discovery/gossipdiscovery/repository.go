@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossipdiscovery
+
+import (
+	"fmt"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Repository provides proposals learned from the gossip network.
+type Repository struct {
+	node *Node
+}
+
+// NewRepository constructs a new proposal repository (backed by the gossip node).
+func NewRepository(node *Node) *Repository {
+	return &Repository{node: node}
+}
+
+// Proposal returns a single proposal by its ID.
+func (r *Repository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	p, ok := r.node.proposal(id)
+	if !ok {
+		return nil, fmt.Errorf(`proposal does not exist: %v`, id)
+	}
+	return &p, nil
+}
+
+// Proposals returns proposals matching the filter.
+func (r *Repository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	return r.node.proposals(filter.Matches), nil
+}
@@ -38,6 +38,8 @@ type Filter struct {
 	LowerGBPriceBound   *big.Int
 	ExcludeUnsupported  bool
 	IncludeFailed       bool
+	// Condition is an additional condition proposals must match, e.g. a parsed proposal query.
+	Condition func(market.ServiceProposal) bool
 }
 
 // Matches return flag if filter matches given proposal
@@ -69,6 +71,10 @@ func (filter *Filter) Matches(proposal market.ServiceProposal) bool {
 		conditions = append(conditions, reducer.PriceGiB(filter.LowerGBPriceBound, filter.UpperGBPriceBound))
 	}
 
+	if filter.Condition != nil {
+		conditions = append(conditions, filter.Condition)
+	}
+
 	if len(conditions) > 0 {
 		return reducer.And(conditions...)(proposal)
 	}
@@ -82,6 +88,7 @@ func (filter *Filter) ToAPIQuery() mysterium.ProposalsQuery {
 		ServiceType:        filter.ServiceType,
 		AccessPolicyID:     filter.AccessPolicyID,
 		AccessPolicySource: filter.AccessPolicySource,
+		NodeType:           filter.LocationType,
 		IncludeFailed:      filter.IncludeFailed,
 	}
 	if filter.ServiceType == "" {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/market/mysterium"
 	"github.com/mysteriumnetwork/node/money"
 	"github.com/stretchr/testify/assert"
 )
@@ -281,6 +282,35 @@ func Test_ProposalFilter_Filters_ByByteBounds(t *testing.T) {
 	assert.True(t, filter.Matches(proposalBytesExactInParts))
 }
 
+func Test_ProposalFilter_Filters_ByCondition(t *testing.T) {
+	filter := &Filter{
+		ServiceType: serviceTypeStreaming,
+		Condition: func(proposal market.ServiceProposal) bool {
+			return proposal.ProviderID == provider2
+		},
+	}
+	assert.False(t, filter.Matches(proposalEmpty))
+	assert.False(t, filter.Matches(proposalProvider1Streaming))
+	assert.False(t, filter.Matches(proposalProvider1Noop))
+	assert.True(t, filter.Matches(proposalProvider2Streaming))
+}
+
+func Test_ProposalFilter_ToAPIQuery(t *testing.T) {
+	filter := &Filter{
+		ProviderID:     provider1,
+		LocationType:   "residential",
+		AccessPolicyID: "whitelist",
+		IncludeFailed:  true,
+	}
+	assert.Equal(t, mysterium.ProposalsQuery{
+		NodeKey:        provider1,
+		ServiceType:    "all",
+		AccessPolicyID: "whitelist",
+		NodeType:       "residential",
+		IncludeFailed:  true,
+	}, filter.ToAPIQuery())
+}
+
 type mockPaymentMethod struct {
 	rate        market.PaymentRate
 	paymentType string
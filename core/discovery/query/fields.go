@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package query
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/reducer"
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/payments/crypto"
+)
+
+const (
+	qualityLevelMedium = 0.2
+	qualityLevelHigh   = 0.5
+)
+
+// Kind is a type of field values.
+type Kind int
+
+const (
+	// KindString is a kind of text fields.
+	KindString Kind = iota
+	// KindNumber is a kind of numeric fields, values are float64.
+	KindNumber
+	// KindBool is a kind of boolean fields.
+	KindBool
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// Field is a proposal property usable in queries and ordering.
+type Field struct {
+	Kind Kind
+	// Select returns the value of the field, or nil if proposal doesn't have it.
+	Select reducer.FieldSelector
+}
+
+// Fields maps field names to fields.
+type Fields map[string]Field
+
+// NewFields returns fields known to proposal queries. Quality is calculated from given metrics, which are
+// only fetched once a query touches the quality. Quality of all proposals is unknown when metrics are nil.
+func NewFields(metrics func() []quality.ConnectMetric) Fields {
+	return Fields{
+		"provider":     {Kind: KindString, Select: reducer.ProviderID},
+		"service_type": {Kind: KindString, Select: reducer.ServiceType},
+		"country":      {Kind: KindString, Select: reducer.LocationCountry},
+		"node_type":    {Kind: KindString, Select: reducer.LocationType},
+		"price_minute": {Kind: KindNumber, Select: pricePerMinute},
+		"price_gib":    {Kind: KindNumber, Select: pricePerGiB},
+		"verified":     {Kind: KindBool, Select: verified},
+		"quality":      {Kind: KindNumber, Select: qualityLevels(metrics)},
+	}
+}
+
+// pricePerMinute selects price of a minute of service in MYST.
+func pricePerMinute(proposal market.ServiceProposal) interface{} {
+	price, rate, ok := paymentPrice(proposal)
+	if !ok || rate.PerTime == 0 {
+		return 0.0
+	}
+	return price * float64(time.Minute) / float64(rate.PerTime)
+}
+
+// pricePerGiB selects price of GiB of traffic in MYST.
+func pricePerGiB(proposal market.ServiceProposal) interface{} {
+	price, rate, ok := paymentPrice(proposal)
+	if !ok || rate.PerByte == 0 {
+		return 0.0
+	}
+	return price * float64(datasize.GiB.Bytes()) / float64(rate.PerByte)
+}
+
+func paymentPrice(proposal market.ServiceProposal) (float64, market.PaymentRate, bool) {
+	if proposal.PaymentMethod == nil {
+		return 0, market.PaymentRate{}, false
+	}
+	amount := proposal.PaymentMethod.GetPrice().Amount
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	return crypto.BigMystToFloat(amount), proposal.PaymentMethod.GetRate(), true
+}
+
+func verified(proposal market.ServiceProposal) interface{} {
+	return proposal.Verified
+}
+
+// qualityLevels returns selector of proposal quality level by quality oracle metrics:
+// 0 - unknown, 1 - low, 2 - medium, 3 - high.
+func qualityLevels(metrics func() []quality.ConnectMetric) reducer.FieldSelector {
+	var once sync.Once
+	levels := make(map[market.ProposalID]float64)
+	load := func() {
+		if metrics == nil {
+			return
+		}
+		for _, metric := range metrics() {
+			levels[market.ProposalID{ProviderID: metric.ProposalID.ProviderID, ServiceType: metric.ProposalID.ServiceType}] = qualityLevel(metric.ConnectCount)
+		}
+	}
+
+	return func(proposal market.ServiceProposal) interface{} {
+		once.Do(load)
+		return levels[proposal.UniqueID()]
+	}
+}
+
+func qualityLevel(counts quality.ConnectCount) float64 {
+	total := counts.Success + counts.Fail + counts.Timeout
+	if total == 0 {
+		return 0
+	}
+
+	ratio := float64(counts.Success) / float64(total)
+	switch {
+	case ratio >= qualityLevelHigh:
+		return 3
+	case ratio >= qualityLevelMedium:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+// is tells whether the token is the given keyword, keywords are case insensitive.
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// tokenize splits query text into tokens.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(text); {
+		c := text[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: pos})
+			pos++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			end := pos + 1
+			if end < len(text) && text[end] == '=' {
+				end++
+			}
+			operator := text[pos:end]
+			if operator == "!" {
+				return nil, fmt.Errorf("unexpected %q at position %d", operator, pos+1)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: pos})
+			pos = end
+		case c == '"' || c == '\'':
+			value, end, err := scanString(text, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: pos})
+			pos = end
+		case isWordChar(c) || c == '-':
+			end := pos + 1
+			for end < len(text) && isWordChar(text[end]) {
+				end++
+			}
+			word := text[pos:end]
+			if _, err := strconv.ParseFloat(word, 64); err == nil {
+				tokens = append(tokens, token{kind: tokenNumber, text: word, pos: pos})
+			} else if c == '-' {
+				return nil, fmt.Errorf("unexpected %q at position %d", word, pos+1)
+			} else {
+				tokens = append(tokens, token{kind: tokenWord, text: word, pos: pos})
+			}
+			pos = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, pos+1)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(text)}), nil
+}
+
+// scanString scans a quoted string, backslash escapes the next character.
+func scanString(text string, start int) (string, int, error) {
+	quote := text[start]
+	var value strings.Builder
+	for pos := start + 1; pos < len(text); pos++ {
+		switch text[pos] {
+		case '\\':
+			pos++
+			if pos < len(text) {
+				value.WriteByte(text[pos])
+			}
+		case quote:
+			return value.String(), pos + 1, nil
+		default:
+			value.WriteByte(text[pos])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string at position %d", start+1)
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
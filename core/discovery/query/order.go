@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Order is a parsed proposal ordering.
+type Order []orderKey
+
+type orderKey struct {
+	field      Field
+	descending bool
+}
+
+// ParseOrder parses comma separated list of field names to order proposals by, names prefixed with "-" order
+// in descending order, e.g. "-quality,price_gib".
+func ParseOrder(text string, fields Fields) (Order, error) {
+	var order Order
+	if strings.TrimSpace(text) == "" {
+		return order, nil
+	}
+	for _, name := range strings.Split(text, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		key := orderKey{}
+		if strings.HasPrefix(name, "-") {
+			key.descending = true
+			name = name[1:]
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown order field %q, known fields are: %s", name, strings.Join(fields.names(), ", "))
+		}
+		key.field = field
+		order = append(order, key)
+	}
+	return order, nil
+}
+
+// Sort sorts proposals in place, proposals missing a field value go last. Ties are broken by provider ID and
+// service type, so that the order is deterministic and pages of sorted proposals don't overlap.
+func (o Order) Sort(proposals []market.ServiceProposal) {
+	values := make(map[market.ProposalID][]interface{}, len(proposals))
+	for _, p := range proposals {
+		keys := make([]interface{}, len(o))
+		for i, key := range o {
+			keys[i] = key.field.Select(p)
+		}
+		values[p.UniqueID()] = keys
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		a, b := values[proposals[i].UniqueID()], values[proposals[j].UniqueID()]
+		for k, key := range o {
+			switch {
+			case a[k] == nil && b[k] == nil:
+				continue
+			case a[k] == nil:
+				return false
+			case b[k] == nil:
+				return true
+			}
+
+			c := compareValues(a[k], b[k])
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != key.descending
+		}
+		if proposals[i].ProviderID != proposals[j].ProviderID {
+			return proposals[i].ProviderID < proposals[j].ProviderID
+		}
+		return proposals[i].ServiceType < proposals[j].ServiceType
+	})
+}
+
+func compareValues(a, b interface{}) int {
+	switch aTyped := a.(type) {
+	case float64:
+		bTyped, _ := b.(float64)
+		switch {
+		case aTyped < bTyped:
+			return -1
+		case aTyped > bTyped:
+			return 1
+		}
+	case string:
+		bTyped, _ := b.(string)
+		return strings.Compare(aTyped, bTyped)
+	case bool:
+		bTyped, _ := b.(bool)
+		switch {
+		case !aTyped && bTyped:
+			return -1
+		case aTyped && !bTyped:
+			return 1
+		}
+	}
+	return 0
+}
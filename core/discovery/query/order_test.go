@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_Sort(t *testing.T) {
+	fields := NewFields(func() []quality.ConnectMetric { return testMetrics })
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{order: "price_gib", expected: []string{"0x4", "0x1", "0x3", "0x2"}},
+		{order: "-price_gib", expected: []string{"0x2", "0x3", "0x1", "0x4"}},
+		{order: "country", expected: []string{"0x1", "0x3", "0x2", "0x4"}},
+		{order: "-country", expected: []string{"0x2", "0x3", "0x1", "0x4"}},
+		{order: "service_type, -quality", expected: []string{"0x4", "0x2", "0x1", "0x3"}},
+		{order: "-verified,provider", expected: []string{"0x1", "0x2", "0x3", "0x4"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.order, func(t *testing.T) {
+			order, err := ParseOrder(test.order, fields)
+			assert.NoError(t, err)
+
+			proposals := append([]market.ServiceProposal{}, testProposals...)
+			order.Sort(proposals)
+
+			var sorted []string
+			for _, p := range proposals {
+				sorted = append(sorted, p.ProviderID)
+			}
+			assert.Equal(t, test.expected, sorted)
+		})
+	}
+}
+
+func TestOrder_Sort_BreaksTies(t *testing.T) {
+	proposals := []market.ServiceProposal{
+		{ProviderID: "0x2", ServiceType: "wireguard"},
+		{ProviderID: "0x1", ServiceType: "wireguard"},
+		{ProviderID: "0x2", ServiceType: "openvpn"},
+		{ProviderID: "0x1", ServiceType: "openvpn"},
+	}
+	expected := []market.ServiceProposal{
+		{ProviderID: "0x1", ServiceType: "openvpn"},
+		{ProviderID: "0x1", ServiceType: "wireguard"},
+		{ProviderID: "0x2", ServiceType: "openvpn"},
+		{ProviderID: "0x2", ServiceType: "wireguard"},
+	}
+
+	for _, text := range []string{"", "country"} {
+		t.Run(text, func(t *testing.T) {
+			order, err := ParseOrder(text, NewFields(nil))
+			assert.NoError(t, err)
+
+			sorted := append([]market.ServiceProposal{}, proposals...)
+			order.Sort(sorted)
+			assert.Equal(t, expected, sorted)
+		})
+	}
+}
+
+func TestParseOrder_UnknownField(t *testing.T) {
+	_, err := ParseOrder("price_gib,-city", NewFields(nil))
+	assert.EqualError(t, err, `unknown order field "city", known fields are: country, node_type, price_gib, price_minute, provider, quality, service_type, verified`)
+}
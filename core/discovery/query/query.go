@@ -0,0 +1,341 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package query implements a textual proposal filter language, e.g.
+//
+//	country in ("DE", "NL") and price_gib < 0.1 and quality > 2 and not provider in ("0x1", "0x2")
+//
+// Queries are parsed into trees of discovery reducers.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/discovery/reducer"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Query is a parsed proposal query.
+type Query struct {
+	root  expression
+	match func(market.ServiceProposal) bool
+}
+
+// Parse parses the query text, field names are resolved from given fields.
+func Parse(text string, fields Fields) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", next)
+	}
+	return &Query{root: root, match: root.matcher()}, nil
+}
+
+// Matches tells whether the proposal matches the query.
+func (q *Query) Matches(proposal market.ServiceProposal) bool {
+	return q.match(proposal)
+}
+
+// Apply adds the query to the filter. Equality conditions the discovery API understands are copied to filter fields
+// as well, to let discovery narrow proposals server-side.
+func (q *Query) Apply(filter *proposal.Filter) {
+	conditions := []expression{q.root}
+	if and, ok := q.root.(andExpression); ok {
+		conditions = and
+	}
+	for _, condition := range conditions {
+		c, ok := condition.(comparison)
+		if !ok || c.operator != "=" {
+			continue
+		}
+		value, _ := c.values[0].(string)
+		switch {
+		case c.name == "provider" && filter.ProviderID == "":
+			filter.ProviderID = value
+		case c.name == "service_type" && filter.ServiceType == "":
+			filter.ServiceType = value
+		case c.name == "node_type" && filter.LocationType == "":
+			filter.LocationType = value
+		}
+	}
+
+	if filter.Condition == nil {
+		filter.Condition = q.match
+	} else {
+		filter.Condition = reducer.And(filter.Condition, q.match)
+	}
+}
+
+type expression interface {
+	matcher() func(market.ServiceProposal) bool
+}
+
+type andExpression []expression
+
+func (e andExpression) matcher() func(market.ServiceProposal) bool {
+	conditions := make([]reducer.AndCondition, len(e))
+	for i, operand := range e {
+		conditions[i] = operand.matcher()
+	}
+	return reducer.And(conditions...)
+}
+
+type orExpression []expression
+
+func (e orExpression) matcher() func(market.ServiceProposal) bool {
+	conditions := make([]reducer.OrCondition, len(e))
+	for i, operand := range e {
+		conditions[i] = operand.matcher()
+	}
+	return reducer.Or(conditions...)
+}
+
+type notExpression struct {
+	operand expression
+}
+
+func (e notExpression) matcher() func(market.ServiceProposal) bool {
+	return reducer.Not(e.operand.matcher())
+}
+
+type comparison struct {
+	name     string
+	field    Field
+	operator string
+	values   []interface{}
+}
+
+func (c comparison) matcher() func(market.ServiceProposal) bool {
+	switch c.operator {
+	case "=":
+		return reducer.Equal(c.field.Select, c.values[0])
+	case "!=":
+		return reducer.Not(reducer.Equal(c.field.Select, c.values[0]))
+	case "in":
+		return reducer.In(c.field.Select, c.values...)
+	case "<":
+		return reducer.Less(c.field.Select, c.values[0].(float64))
+	case "<=":
+		return reducer.LessOrEqual(c.field.Select, c.values[0].(float64))
+	case ">":
+		return reducer.Greater(c.field.Select, c.values[0].(float64))
+	default:
+		return reducer.GreaterOrEqual(c.field.Select, c.values[0].(float64))
+	}
+}
+
+// parser is a recursive descent parser of the grammar:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field operator value | field [ "not" ] "in" "(" value { "," value } ")"
+type parser struct {
+	tokens []token
+	pos    int
+	fields Fields
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (expression, error) {
+	operand, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := orExpression{operand}
+	for p.peek().is("or") {
+		p.next()
+		if operand, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operand, nil
+	}
+	return operands, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := andExpression{operand}
+	for p.peek().is("and") {
+		p.next()
+		if operand, err = p.parseUnary(); err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operand, nil
+	}
+	return operands, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	switch t := p.peek(); {
+	case t.is("not"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: operand}, nil
+	case t.kind == tokenLeftParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected \")\", got %s", closing)
+		}
+		return e, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	name := p.next()
+	if name.kind != tokenWord {
+		return nil, fmt.Errorf("expected field name, got %s", name)
+	}
+	field, ok := p.fields[strings.ToLower(name.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s, known fields are: %s", name, strings.Join(p.fields.names(), ", "))
+	}
+	c := comparison{name: strings.ToLower(name.text), field: field}
+
+	negated := false
+	if p.peek().is("not") {
+		p.next()
+		negated = true
+		if !p.peek().is("in") {
+			return nil, fmt.Errorf("expected \"in\", got %s", p.peek())
+		}
+	}
+
+	switch operator := p.next(); {
+	case operator.is("in"):
+		if field.Kind == KindBool {
+			return nil, fmt.Errorf("operator %s is not supported by %s field", operator, field.Kind)
+		}
+		values, err := p.parseList(field)
+		if err != nil {
+			return nil, err
+		}
+		c.operator, c.values = "in", values
+	case operator.kind == tokenOperator:
+		c.operator = operator.text
+		if c.operator == "==" {
+			c.operator = "="
+		}
+		if c.operator != "=" && c.operator != "!=" && field.Kind != KindNumber {
+			return nil, fmt.Errorf("operator %s is not supported by %s field", operator, field.Kind)
+		}
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		c.values = []interface{}{value}
+	default:
+		return nil, fmt.Errorf("expected operator, got %s", operator)
+	}
+
+	if negated {
+		return notExpression{operand: c}, nil
+	}
+	return c, nil
+}
+
+func (p *parser) parseList(field Field) ([]interface{}, error) {
+	if open := p.next(); open.kind != tokenLeftParen {
+		return nil, fmt.Errorf("expected \"(\", got %s", open)
+	}
+
+	var values []interface{}
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		switch t := p.next(); t.kind {
+		case tokenComma:
+		case tokenRightParen:
+			return values, nil
+		default:
+			return nil, fmt.Errorf("expected \",\" or \")\", got %s", t)
+		}
+	}
+}
+
+func (p *parser) parseValue(field Field) (interface{}, error) {
+	t := p.next()
+	switch field.Kind {
+	case KindNumber:
+		if t.kind == tokenNumber {
+			return strconv.ParseFloat(t.text, 64)
+		}
+	case KindBool:
+		if t.is("true") || t.is("false") {
+			return strings.EqualFold(t.text, "true"), nil
+		}
+	default:
+		if t.kind == tokenString || t.kind == tokenNumber || t.kind == tokenWord {
+			return t.text, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s value, got %s", field.Kind, t)
+}
+
+func (f Fields) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
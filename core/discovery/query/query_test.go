@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package query
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	proposalDE = market.ServiceProposal{
+		ProviderID:        "0x1",
+		ServiceType:       "wireguard",
+		ServiceDefinition: mockService{location: market.Location{Country: "DE", NodeType: "residential"}},
+		PaymentMethod:     mockPaymentMethod{perGiB: 0.05, perMinute: 0.001},
+		Verified:          true,
+	}
+	proposalNL = market.ServiceProposal{
+		ProviderID:        "0x2",
+		ServiceType:       "openvpn",
+		ServiceDefinition: mockService{location: market.Location{Country: "NL", NodeType: "datacenter"}},
+		PaymentMethod:     mockPaymentMethod{perGiB: 0.2, perMinute: 0.002},
+	}
+	proposalLT = market.ServiceProposal{
+		ProviderID:        "0x3",
+		ServiceType:       "wireguard",
+		ServiceDefinition: mockService{location: market.Location{Country: "LT", NodeType: "residential"}},
+		PaymentMethod:     mockPaymentMethod{perGiB: 0.08},
+	}
+	proposalUnknown = market.ServiceProposal{
+		ProviderID:  "0x4",
+		ServiceType: "noop",
+	}
+
+	testMetrics = []quality.ConnectMetric{
+		{ProposalID: quality.ProposalID{ProviderID: "0x1", ServiceType: "wireguard"}, ConnectCount: quality.ConnectCount{Success: 9, Fail: 1}},
+		{ProposalID: quality.ProposalID{ProviderID: "0x2", ServiceType: "openvpn"}, ConnectCount: quality.ConnectCount{Success: 3, Fail: 7}},
+		{ProposalID: quality.ProposalID{ProviderID: "0x3", ServiceType: "wireguard"}, ConnectCount: quality.ConnectCount{Success: 1, Timeout: 9}},
+	}
+	testProposals = []market.ServiceProposal{proposalDE, proposalNL, proposalLT, proposalUnknown}
+)
+
+func TestParse_MatchesProposals(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: `country = "DE"`, expected: []string{"0x1"}},
+		{query: `country == DE`, expected: []string{"0x1"}},
+		{query: `country != "DE"`, expected: []string{"0x2", "0x3", "0x4"}},
+		{query: `country in ("DE", 'NL')`, expected: []string{"0x1", "0x2"}},
+		{query: `country not in ("DE", "NL")`, expected: []string{"0x3", "0x4"}},
+		{query: `price_gib < 0.1`, expected: []string{"0x1", "0x3", "0x4"}},
+		{query: `price_gib >= 0.08 and price_minute > 0`, expected: []string{"0x2"}},
+		{query: `price_minute <= 0.001`, expected: []string{"0x1", "0x3", "0x4"}},
+		{query: `quality > 2`, expected: []string{"0x1"}},
+		{query: `quality = 0`, expected: []string{"0x4"}},
+		{query: `verified = true`, expected: []string{"0x1"}},
+		{query: `service_type = wireguard or node_type = "datacenter"`, expected: []string{"0x1", "0x2", "0x3"}},
+		{query: `not (service_type = wireguard or node_type = "datacenter")`, expected: []string{"0x4"}},
+		{query: `PROVIDER = "0x3" OR provider = 0x4 AND service_type = noop`, expected: []string{"0x3", "0x4"}},
+		{
+			query:    `country in ("DE","NL", "LT") and price_gib < 0.1 and quality > 2 and not provider in ("0x3")`,
+			expected: []string{"0x1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query, NewFields(func() []quality.ConnectMetric { return testMetrics }))
+			assert.NoError(t, err)
+
+			var matched []string
+			for _, p := range testProposals {
+				if q.Matches(p) {
+					matched = append(matched, p.ProviderID)
+				}
+			}
+			assert.Equal(t, test.expected, matched)
+		})
+	}
+}
+
+func TestParse_ReportsErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: ``, err: `expected field name, got end of query`},
+		{query: `city = "Berlin"`, err: `unknown field "city" at position 1, known fields are: country, node_type, price_gib, price_minute, provider, quality, service_type, verified`},
+		{query: `country < "DE"`, err: `operator "<" at position 9 is not supported by string field`},
+		{query: `quality > high`, err: `expected number value, got "high" at position 11`},
+		{query: `verified in (true)`, err: `operator "in" at position 10 is not supported by boolean field`},
+		{query: `country in ("DE" "NL")`, err: `expected "," or ")", got "NL" at position 18`},
+		{query: `(country = "DE"`, err: `expected ")", got end of query`},
+		{query: `country = "DE`, err: `unterminated string at position 11`},
+		{query: `country = "DE" nl`, err: `unexpected "nl" at position 16`},
+		{query: `country ! "DE"`, err: `unexpected "!" at position 9`},
+		{query: `country = "DE" & quality > 1`, err: `unexpected '&' at position 16`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := Parse(test.query, NewFields(nil))
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestQuery_FetchesMetricsOnlyWhenNeeded(t *testing.T) {
+	fetched := 0
+	fields := NewFields(func() []quality.ConnectMetric {
+		fetched++
+		return testMetrics
+	})
+
+	q, err := Parse(`country = "DE"`, fields)
+	assert.NoError(t, err)
+	q.Matches(proposalDE)
+	assert.Equal(t, 0, fetched)
+
+	q, err = Parse(`quality > 1`, fields)
+	assert.NoError(t, err)
+	q.Matches(proposalDE)
+	q.Matches(proposalNL)
+	assert.Equal(t, 1, fetched)
+}
+
+func TestQuery_Apply(t *testing.T) {
+	q, err := Parse(`provider = "0x1" and service_type = wireguard and node_type = residential and price_gib < 1`, NewFields(nil))
+	assert.NoError(t, err)
+
+	filter := &proposal.Filter{ServiceType: "openvpn", ExcludeUnsupported: true}
+	q.Apply(filter)
+
+	assert.Equal(t, "0x1", filter.ProviderID)
+	assert.Equal(t, "openvpn", filter.ServiceType)
+	assert.Equal(t, "residential", filter.LocationType)
+	assert.NotNil(t, filter.Condition)
+	assert.Equal(t, "0x1", filter.ToAPIQuery().NodeKey)
+	assert.Equal(t, "residential", filter.ToAPIQuery().NodeType)
+}
+
+func TestQuery_ApplyIgnoresAlternatives(t *testing.T) {
+	q, err := Parse(`provider = "0x1" or service_type = wireguard`, NewFields(nil))
+	assert.NoError(t, err)
+
+	filter := &proposal.Filter{}
+	q.Apply(filter)
+
+	assert.Equal(t, "", filter.ProviderID)
+	assert.Equal(t, "", filter.ServiceType)
+	assert.True(t, filter.Matches(proposalLT))
+	assert.False(t, filter.Matches(proposalNL))
+}
+
+type mockService struct {
+	location market.Location
+}
+
+func (s mockService) GetLocation() market.Location {
+	return s.location
+}
+
+// mockPaymentMethod charges given amounts of MYST.
+type mockPaymentMethod struct {
+	perGiB    float64
+	perMinute float64
+}
+
+func (m mockPaymentMethod) GetPrice() money.Money {
+	return money.NewMoney(big.NewInt(1e15), money.CurrencyMyst)
+}
+
+func (m mockPaymentMethod) GetType() string {
+	return "mock"
+}
+
+// GetRate returns rate at which 0.001 MYST price is charged to reach the configured prices.
+func (m mockPaymentMethod) GetRate() market.PaymentRate {
+	rate := market.PaymentRate{}
+	if m.perGiB > 0 {
+		rate.PerByte = uint64(float64(datasize.GiB.Bytes()) * 0.001 / m.perGiB)
+	}
+	if m.perMinute > 0 {
+		rate.PerTime = time.Duration(float64(time.Minute) * 0.001 / m.perMinute)
+	}
+	return rate
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reducer
+
+import (
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Less returns a matcher for checking if proposal's numeric field value is less than given value
+func Less(field FieldSelector, valueExpected float64) func(market.ServiceProposal) bool {
+	return compare(field, func(value float64) bool {
+		return value < valueExpected
+	})
+}
+
+// LessOrEqual returns a matcher for checking if proposal's numeric field value is less than or equal to given value
+func LessOrEqual(field FieldSelector, valueExpected float64) func(market.ServiceProposal) bool {
+	return compare(field, func(value float64) bool {
+		return value <= valueExpected
+	})
+}
+
+// Greater returns a matcher for checking if proposal's numeric field value is greater than given value
+func Greater(field FieldSelector, valueExpected float64) func(market.ServiceProposal) bool {
+	return compare(field, func(value float64) bool {
+		return value > valueExpected
+	})
+}
+
+// GreaterOrEqual returns a matcher for checking if proposal's numeric field value is greater than or equal to given value
+func GreaterOrEqual(field FieldSelector, valueExpected float64) func(market.ServiceProposal) bool {
+	return compare(field, func(value float64) bool {
+		return value >= valueExpected
+	})
+}
+
+// compare returns a matcher for numeric fields, non numeric values never match.
+func compare(field FieldSelector, condition func(value float64) bool) func(market.ServiceProposal) bool {
+	return Field(field, func(value interface{}) bool {
+		switch valueTyped := value.(type) {
+		case int:
+			return condition(float64(valueTyped))
+		case int64:
+			return condition(float64(valueTyped))
+		case uint64:
+			return condition(float64(valueTyped))
+		case float64:
+			return condition(valueTyped)
+		}
+		return false
+	})
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reducer
+
+import (
+	"testing"
+
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Less(t *testing.T) {
+	match := Less(fieldID, 1)
+
+	assert.True(t, match(proposalEmpty))
+	assert.False(t, match(market.ServiceProposal{ID: 1}))
+	assert.False(t, match(market.ServiceProposal{ID: 2}))
+}
+
+func Test_LessOrEqual(t *testing.T) {
+	match := LessOrEqual(fieldID, 1)
+
+	assert.True(t, match(proposalEmpty))
+	assert.True(t, match(market.ServiceProposal{ID: 1}))
+	assert.False(t, match(market.ServiceProposal{ID: 2}))
+}
+
+func Test_Greater(t *testing.T) {
+	match := Greater(fieldID, 1)
+
+	assert.False(t, match(proposalEmpty))
+	assert.False(t, match(market.ServiceProposal{ID: 1}))
+	assert.True(t, match(market.ServiceProposal{ID: 2}))
+}
+
+func Test_GreaterOrEqual(t *testing.T) {
+	match := GreaterOrEqual(fieldID, 1)
+
+	assert.False(t, match(proposalEmpty))
+	assert.True(t, match(market.ServiceProposal{ID: 1}))
+	assert.True(t, match(market.ServiceProposal{ID: 2}))
+}
+
+func Test_Compare_NonNumericField(t *testing.T) {
+	match := Greater(fieldProviderID, 0)
+
+	assert.False(t, match(proposalProvider1Streaming))
+}
@@ -76,6 +76,10 @@ func (c *repository) Proposals(filter *proposal.Filter) ([]market.ServiceProposa
 	for i, repoProposals := range proposals {
 		log.Trace().Msgf("Retrieved %d proposals from repository %d", len(repoProposals), i)
 		for _, p := range repoProposals {
+			// Verified proposal copy is preferred over the one of a repository which can't verify it.
+			if known, ok := uniqueProposals[p.UniqueID()]; ok && known.Verified && !p.Verified {
+				continue
+			}
 			uniqueProposals[p.UniqueID()] = p
 		}
 	}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scoring
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/bandwidth"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	historyBucket = "provider-scoring-history"
+
+	// movingAverageWeight is the weight of a new sample in latency and throughput averages.
+	movingAverageWeight = 0.3
+)
+
+// Record holds locally observed outcomes of connections to a proposal.
+type Record struct {
+	Attempts  int
+	Successes int
+	// Latency is the moving average of time it took to establish a connection.
+	Latency time.Duration
+	// Throughput is the moving average of download speed in bits per second.
+	Throughput float64
+}
+
+// SuccessRate returns smoothed ratio of successful connections, unknown proposals get 0.5.
+func (r Record) SuccessRate() float64 {
+	return float64(r.Successes+1) / float64(r.Attempts+2)
+}
+
+type historyStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+// History records outcomes of consumer connections to be used in proposal scoring.
+type History struct {
+	storage historyStorage
+
+	lock    sync.Mutex
+	records map[string]*Record
+	pending map[string]time.Time
+}
+
+// NewHistory creates connection history, records are persisted to the given storage if it is not nil.
+func NewHistory(storage historyStorage) *History {
+	return &History{
+		storage: storage,
+		records: make(map[string]*Record),
+		pending: make(map[string]time.Time),
+	}
+}
+
+// Subscribe subscribes to connection events to record their outcomes.
+func (h *History) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, h.consumeConnectionStateEvent); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(bandwidth.AppTopicConnectionThroughput, h.consumeConnectionThroughputEvent)
+}
+
+// Record returns outcomes recorded for the given proposal.
+func (h *History) Record(proposal market.ServiceProposal) (Record, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	record, ok := h.record(proposalKey(proposal))
+	if !ok {
+		return Record{}, false
+	}
+	return *record, true
+}
+
+// RecordConnect records outcome of the connection attempt to the given proposal.
+func (h *History) RecordConnect(proposal market.ServiceProposal, latency time.Duration, success bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := proposalKey(proposal)
+	record := h.recordOrNew(key)
+	record.Attempts++
+	if success {
+		record.Successes++
+		record.Latency = time.Duration(movingAverage(float64(record.Latency), float64(latency)))
+	}
+	h.persist(key, record)
+}
+
+// RecordThroughput records download speed observed while connected to the given proposal.
+func (h *History) RecordThroughput(proposal market.ServiceProposal, bitsPerSecond float64) {
+	if bitsPerSecond <= 0 {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	record := h.recordOrNew(proposalKey(proposal))
+	record.Throughput = movingAverage(record.Throughput, bitsPerSecond)
+}
+
+func (h *History) consumeConnectionStateEvent(e connectionstate.AppEventConnectionState) {
+	if !e.SessionInfo.IsDefault() || e.SessionInfo.Proposal.ProviderID == "" {
+		return
+	}
+	proposal := e.SessionInfo.Proposal
+	key := proposalKey(proposal)
+
+	h.lock.Lock()
+	startedAt, connecting := h.pending[key]
+	switch e.State {
+	case connectionstate.Connecting:
+		h.pending[key] = e.SessionInfo.StartedAt
+	case connectionstate.Disconnecting, connectionstate.Canceled:
+		// Connection was cancelled by the user or closed, it tells nothing about the provider.
+		delete(h.pending, key)
+		if record, ok := h.record(key); ok {
+			h.persist(key, record)
+		}
+	case connectionstate.Connected, connectionstate.StateConnectionFailed, connectionstate.NotConnected:
+		delete(h.pending, key)
+	}
+	h.lock.Unlock()
+
+	if !connecting {
+		return
+	}
+	switch e.State {
+	case connectionstate.Connected:
+		h.RecordConnect(proposal, time.Since(startedAt), true)
+	case connectionstate.StateConnectionFailed, connectionstate.NotConnected:
+		h.RecordConnect(proposal, 0, false)
+	}
+}
+
+func (h *History) consumeConnectionThroughputEvent(e bandwidth.AppEventConnectionThroughput) {
+	if !e.SessionInfo.IsDefault() || e.SessionInfo.State != connectionstate.Connected {
+		return
+	}
+	h.RecordThroughput(e.SessionInfo.Proposal, float64(e.Throughput.Down))
+}
+
+func (h *History) record(key string) (*Record, bool) {
+	if record, ok := h.records[key]; ok {
+		return record, true
+	}
+	if h.storage == nil {
+		return nil, false
+	}
+
+	var record Record
+	if err := h.storage.GetValue(historyBucket, key, &record); err != nil {
+		if err != storage.ErrNotFound {
+			log.Warn().Err(err).Msgf("Failed to load connection history of %s", key)
+		}
+		return nil, false
+	}
+	h.records[key] = &record
+	return &record, true
+}
+
+func (h *History) recordOrNew(key string) *Record {
+	record, ok := h.record(key)
+	if !ok {
+		record = &Record{}
+		h.records[key] = record
+	}
+	return record
+}
+
+func (h *History) persist(key string, record *Record) {
+	if h.storage == nil {
+		return
+	}
+	if err := h.storage.SetValue(historyBucket, key, *record); err != nil {
+		log.Warn().Err(err).Msgf("Failed to persist connection history of %s", key)
+	}
+}
+
+func proposalKey(proposal market.ServiceProposal) string {
+	return fmt.Sprintf("%s/%s", proposal.ProviderID, proposal.ServiceType)
+}
+
+func movingAverage(average, sample float64) float64 {
+	if average == 0 {
+		return sample
+	}
+	return average + movingAverageWeight*(sample-average)
+}
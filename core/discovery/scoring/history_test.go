@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scoring
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/bandwidth"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+)
+
+var historyProposal = market.ServiceProposal{ProviderID: "0x1", ServiceType: "wireguard"}
+
+func stateEvent(state connectionstate.State, startedAt time.Time) connectionstate.AppEventConnectionState {
+	return connectionstate.AppEventConnectionState{
+		State: state,
+		SessionInfo: connectionstate.Status{
+			StartedAt: startedAt,
+			State:     state,
+			Proposal:  historyProposal,
+		},
+	}
+}
+
+func TestHistory_RecordsConnectionOutcomes(t *testing.T) {
+	history := NewHistory(nil)
+	startedAt := time.Now().Add(-2 * time.Second)
+
+	// Successful connection.
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connected, startedAt))
+	// Reconnects of established connection are not new attempts.
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Reconnecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connected, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Disconnecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.NotConnected, startedAt))
+
+	// Failed connection.
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.StateConnectionFailed, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Canceled, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.NotConnected, startedAt))
+
+	// Connection cancelled by user.
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Disconnecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.NotConnected, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Connecting, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.Canceled, startedAt))
+	history.consumeConnectionStateEvent(stateEvent(connectionstate.NotConnected, startedAt))
+
+	record, ok := history.Record(historyProposal)
+	assert.True(t, ok)
+	assert.Equal(t, 2, record.Attempts)
+	assert.Equal(t, 1, record.Successes)
+	assert.True(t, record.Latency >= 2*time.Second)
+}
+
+func TestHistory_RecordsThroughputOfConnectedSession(t *testing.T) {
+	history := NewHistory(nil)
+
+	history.consumeConnectionThroughputEvent(bandwidth.AppEventConnectionThroughput{
+		Throughput:  bandwidth.Throughput{Down: 1e6},
+		SessionInfo: connectionstate.Status{State: connectionstate.Connecting, Proposal: historyProposal},
+	})
+	_, ok := history.Record(historyProposal)
+	assert.False(t, ok)
+
+	history.consumeConnectionThroughputEvent(bandwidth.AppEventConnectionThroughput{
+		Throughput:  bandwidth.Throughput{Down: 10e6},
+		SessionInfo: connectionstate.Status{State: connectionstate.Connected, Proposal: historyProposal},
+	})
+	history.consumeConnectionThroughputEvent(bandwidth.AppEventConnectionThroughput{
+		Throughput:  bandwidth.Throughput{Down: 20e6},
+		SessionInfo: connectionstate.Status{State: connectionstate.Connected, Proposal: historyProposal},
+	})
+
+	record, ok := history.Record(historyProposal)
+	assert.True(t, ok)
+	assert.Equal(t, 13e6, record.Throughput)
+}
+
+func TestHistory_PersistsRecords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scoringHistoryTest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := boltdb.NewStorage(dir)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	NewHistory(db).RecordConnect(historyProposal, time.Second, true)
+
+	record, ok := NewHistory(db).Record(historyProposal)
+	assert.True(t, ok)
+	assert.Equal(t, Record{Attempts: 1, Successes: 1, Latency: time.Second}, record)
+
+	_, ok = NewHistory(db).Record(market.ServiceProposal{ProviderID: "0x2", ServiceType: "wireguard"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scoring
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const (
+	// referenceLatency is the connect latency which scores 0.5.
+	referenceLatency = 5 * time.Second
+	// referenceThroughput is the download speed in bits per second which scores 0.5.
+	referenceThroughput = 10e6
+
+	// unknownScore is given to factors which have no data yet, so new proposals are not ruled out.
+	unknownScore = 0.5
+)
+
+// QualityProvider provides proposal quality metrics gathered by Mysterium quality oracle.
+type QualityProvider interface {
+	ProposalsMetrics() []quality.ConnectMetric
+}
+
+// Weights define how much each factor contributes to the total score of a proposal.
+type Weights struct {
+	Quality    float64
+	Success    float64
+	Latency    float64
+	Throughput float64
+	Price      float64
+	Location   float64
+}
+
+// DefaultWeights are used to score proposals unless configured otherwise.
+var DefaultWeights = Weights{
+	Quality:    0.3,
+	Success:    0.2,
+	Latency:    0.1,
+	Throughput: 0.15,
+	Price:      0.15,
+	Location:   0.1,
+}
+
+func (w Weights) sum() float64 {
+	return w.Quality + w.Success + w.Latency + w.Throughput + w.Price + w.Location
+}
+
+// Preferences hold consumer preferences taken into account when scoring proposals.
+type Preferences struct {
+	// Countries lists preferred provider countries, the first one being the most preferred.
+	Countries []string
+}
+
+// Score holds total score of a proposal together with scores of each factor, all of them are in [0, 1] range.
+type Score struct {
+	Proposal   market.ServiceProposal
+	Total      float64
+	Quality    float64
+	Success    float64
+	Latency    float64
+	Throughput float64
+	Price      float64
+	Location   float64
+}
+
+// Scorer combines quality oracle metrics, local connection history, price and location
+// preferences into a single proposal score.
+type Scorer struct {
+	quality QualityProvider
+	history *History
+	weights Weights
+}
+
+// NewScorer creates proposal scorer, quality provider and history are optional.
+func NewScorer(quality QualityProvider, history *History, weights Weights) *Scorer {
+	return &Scorer{
+		quality: quality,
+		history: history,
+		weights: weights,
+	}
+}
+
+// Rank scores given proposals and returns them ordered from the best to the worst.
+func (s *Scorer) Rank(proposals []market.ServiceProposal, preferences Preferences) []Score {
+	qualities := s.qualityScores()
+	prices := priceScores(proposals)
+
+	scores := make([]Score, len(proposals))
+	for i, proposal := range proposals {
+		score := Score{
+			Proposal:   proposal,
+			Quality:    unknownScore,
+			Success:    unknownScore,
+			Latency:    unknownScore,
+			Throughput: unknownScore,
+			Price:      prices[i],
+			Location:   locationScore(proposal, preferences),
+		}
+		if q, ok := qualities[proposalKey(proposal)]; ok {
+			score.Quality = q
+		}
+		if s.history != nil {
+			if record, ok := s.history.Record(proposal); ok {
+				score.Success = record.SuccessRate()
+				if record.Successes > 0 {
+					score.Latency = ratioScore(float64(referenceLatency), float64(record.Latency))
+				}
+				if record.Throughput > 0 {
+					score.Throughput = 1 - ratioScore(referenceThroughput, record.Throughput)
+				}
+			}
+		}
+		score.Total = s.total(score)
+		scores[i] = score
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Total > scores[j].Total
+	})
+	return scores
+}
+
+func (s *Scorer) total(score Score) float64 {
+	sum := s.weights.sum()
+	if sum == 0 {
+		return 0
+	}
+	return (s.weights.Quality*score.Quality +
+		s.weights.Success*score.Success +
+		s.weights.Latency*score.Latency +
+		s.weights.Throughput*score.Throughput +
+		s.weights.Price*score.Price +
+		s.weights.Location*score.Location) / sum
+}
+
+func (s *Scorer) qualityScores() map[string]float64 {
+	scores := make(map[string]float64)
+	if s.quality == nil {
+		return scores
+	}
+
+	for _, metric := range s.quality.ProposalsMetrics() {
+		key := proposalKey(market.ServiceProposal{
+			ProviderID:  metric.ProposalID.ProviderID,
+			ServiceType: metric.ProposalID.ServiceType,
+		})
+		if metric.MonitoringFailed {
+			scores[key] = 0
+			continue
+		}
+		count := metric.ConnectCount
+		scores[key] = float64(count.Success+1) / float64(count.Success+count.Fail+count.Timeout+2)
+	}
+	return scores
+}
+
+// ratioScore maps value to (0, 1] range, so that reference value scores 0.5 and lower values score higher.
+func ratioScore(reference, value float64) float64 {
+	return reference / (reference + value)
+}
+
+func locationScore(proposal market.ServiceProposal, preferences Preferences) float64 {
+	if len(preferences.Countries) == 0 {
+		return 1
+	}
+
+	if proposal.ServiceDefinition == nil {
+		return 0
+	}
+
+	country := proposal.ServiceDefinition.GetLocation().Country
+	for i, preferred := range preferences.Countries {
+		if strings.EqualFold(preferred, country) {
+			// The most preferred country scores 1, less preferred ones are still above 0.5.
+			return 1 - float64(i)/float64(2*len(preferences.Countries))
+		}
+	}
+	return 0
+}
+
+// priceScores scores proposals relatively to each other, the cheapest one scores 1 and the most expensive one 0.
+// Prices per GiB and per minute are averaged, unless none of the proposals charges for one of them.
+func priceScores(proposals []market.ServiceProposal) []float64 {
+	perGiB := make([]float64, len(proposals))
+	perMinute := make([]float64, len(proposals))
+	for i, proposal := range proposals {
+		perGiB[i], perMinute[i] = prices(proposal)
+	}
+
+	var dimensions [][]float64
+	for _, values := range [][]float64{perGiB, perMinute} {
+		if isCharged(values) {
+			dimensions = append(dimensions, relativeScores(values))
+		}
+	}
+
+	scores := make([]float64, len(proposals))
+	for i := range proposals {
+		if len(dimensions) == 0 {
+			scores[i] = 1
+			continue
+		}
+		for _, dimension := range dimensions {
+			scores[i] += dimension[i]
+		}
+		scores[i] /= float64(len(dimensions))
+	}
+	return scores
+}
+
+func isCharged(values []float64) bool {
+	for _, v := range values {
+		if v > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func prices(proposal market.ServiceProposal) (perGiB, perMinute float64) {
+	if proposal.PaymentMethod == nil || proposal.PaymentMethod.GetPrice().Amount == nil {
+		return 0, 0
+	}
+
+	amount, _ := new(big.Float).SetInt(proposal.PaymentMethod.GetPrice().Amount).Float64()
+	rate := proposal.PaymentMethod.GetRate()
+	if rate.PerByte > 0 {
+		perGiB = amount * float64(datasize.GiB.Bytes()) / float64(rate.PerByte)
+	}
+	if rate.PerTime > 0 {
+		perMinute = amount * float64(time.Minute) / float64(rate.PerTime)
+	}
+	return perGiB, perMinute
+}
+
+func relativeScores(values []float64) []float64 {
+	scores := make([]float64, len(values))
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	for i, v := range values {
+		if max == min {
+			scores[i] = 1
+		} else {
+			scores[i] = 1 - (v-min)/(max-min)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scoring
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	location market.Location
+}
+
+func (service mockService) GetLocation() market.Location {
+	return service.location
+}
+
+type mockPaymentMethod struct {
+	price money.Money
+	rate  market.PaymentRate
+}
+
+func (m mockPaymentMethod) GetPrice() money.Money {
+	return m.price
+}
+
+func (m mockPaymentMethod) GetType() string {
+	return "mock"
+}
+
+func (m mockPaymentMethod) GetRate() market.PaymentRate {
+	return m.rate
+}
+
+type mockQualityProvider struct {
+	metrics []quality.ConnectMetric
+}
+
+func (m *mockQualityProvider) ProposalsMetrics() []quality.ConnectMetric {
+	return m.metrics
+}
+
+func newProposal(providerID, country string, pricePerMinute int64) market.ServiceProposal {
+	return market.ServiceProposal{
+		ProviderID:        providerID,
+		ServiceType:       "wireguard",
+		ServiceDefinition: mockService{location: market.Location{Country: country}},
+		PaymentMethod: mockPaymentMethod{
+			price: money.NewMoney(big.NewInt(pricePerMinute), money.CurrencyMyst),
+			rate:  market.PaymentRate{PerTime: time.Minute},
+		},
+	}
+}
+
+func providerIDs(scores []Score) []string {
+	var ids []string
+	for _, score := range scores {
+		ids = append(ids, score.Proposal.ProviderID)
+	}
+	return ids
+}
+
+func TestScorer_RankPrefersCheaperProposals(t *testing.T) {
+	scorer := NewScorer(nil, nil, DefaultWeights)
+
+	scores := scorer.Rank([]market.ServiceProposal{
+		newProposal("expensive", "DE", 300),
+		newProposal("cheap", "DE", 100),
+		newProposal("average", "DE", 200),
+	}, Preferences{})
+
+	assert.Equal(t, []string{"cheap", "average", "expensive"}, providerIDs(scores))
+	assert.Equal(t, 1.0, scores[0].Price)
+	assert.Equal(t, 0.5, scores[1].Price)
+	assert.Equal(t, 0.0, scores[2].Price)
+}
+
+func TestScorer_RankUsesQualityMetrics(t *testing.T) {
+	qualityProvider := &mockQualityProvider{metrics: []quality.ConnectMetric{
+		{
+			ProposalID:   quality.ProposalID{ProviderID: "good", ServiceType: "wireguard"},
+			ConnectCount: quality.ConnectCount{Success: 98},
+		},
+		{
+			ProposalID:   quality.ProposalID{ProviderID: "bad", ServiceType: "wireguard"},
+			ConnectCount: quality.ConnectCount{Success: 1, Fail: 50, Timeout: 47},
+		},
+		{
+			ProposalID:       quality.ProposalID{ProviderID: "unmonitored", ServiceType: "wireguard"},
+			ConnectCount:     quality.ConnectCount{Success: 98},
+			MonitoringFailed: true,
+		},
+	}}
+	scorer := NewScorer(qualityProvider, nil, Weights{Quality: 1})
+
+	scores := scorer.Rank([]market.ServiceProposal{
+		newProposal("unmonitored", "DE", 100),
+		newProposal("bad", "DE", 100),
+		newProposal("unknown", "DE", 100),
+		newProposal("good", "DE", 100),
+	}, Preferences{})
+
+	assert.Equal(t, []string{"good", "unknown", "bad", "unmonitored"}, providerIDs(scores))
+	assert.Equal(t, 0.99, scores[0].Total)
+	assert.Equal(t, unknownScore, scores[1].Total)
+	assert.Equal(t, 0.02, scores[2].Total)
+	assert.Equal(t, 0.0, scores[3].Total)
+}
+
+func TestScorer_RankUsesConnectionHistory(t *testing.T) {
+	history := NewHistory(nil)
+	reliable, flaky, slow := newProposal("reliable", "DE", 100), newProposal("flaky", "DE", 100), newProposal("slow", "DE", 100)
+	for i := 0; i < 5; i++ {
+		history.RecordConnect(reliable, time.Second, true)
+		history.RecordConnect(slow, 20*time.Second, true)
+		history.RecordConnect(flaky, time.Second, i == 0)
+	}
+	history.RecordThroughput(reliable, 50e6)
+	history.RecordThroughput(slow, 1e6)
+
+	scorer := NewScorer(nil, history, Weights{Success: 1, Latency: 1, Throughput: 1})
+	scores := scorer.Rank([]market.ServiceProposal{flaky, slow, reliable}, Preferences{})
+	assert.Equal(t, "reliable", scores[0].Proposal.ProviderID)
+
+	byProvider := make(map[string]Score)
+	for _, score := range scores {
+		byProvider[score.Proposal.ProviderID] = score
+	}
+	assert.InDelta(t, 6.0/7.0, byProvider["reliable"].Success, 1e-9)
+	assert.InDelta(t, 2.0/7.0, byProvider["flaky"].Success, 1e-9)
+	assert.InDelta(t, 5.0/6.0, byProvider["reliable"].Latency, 1e-9)
+	assert.InDelta(t, 0.2, byProvider["slow"].Latency, 1e-9)
+	assert.InDelta(t, 50.0/60.0, byProvider["reliable"].Throughput, 1e-9)
+	assert.InDelta(t, 1.0/11.0, byProvider["slow"].Throughput, 1e-9)
+	assert.Equal(t, unknownScore, byProvider["flaky"].Throughput)
+}
+
+func TestScorer_RankUsesLocationPreferences(t *testing.T) {
+	scorer := NewScorer(nil, nil, Weights{Location: 1})
+
+	scores := scorer.Rank([]market.ServiceProposal{
+		newProposal("us", "US", 100),
+		newProposal("nl", "NL", 100),
+		newProposal("de", "DE", 100),
+	}, Preferences{Countries: []string{"de", "nl"}})
+
+	assert.Equal(t, []string{"de", "nl", "us"}, providerIDs(scores))
+	assert.Equal(t, 1.0, scores[0].Location)
+	assert.Equal(t, 0.75, scores[1].Location)
+	assert.Equal(t, 0.0, scores[2].Location)
+}
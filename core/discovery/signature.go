@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var (
+	// ErrUnsigned is returned for proposal messages without signature, older providers don't sign them.
+	ErrUnsigned = NewRejection("unsigned", "proposal message is not signed")
+	// ErrInvalidSignature is returned for proposal messages which are not signed by the proposal provider.
+	ErrInvalidSignature = NewRejection("invalid_signature", "proposal message is not signed by provider")
+)
+
+// Rejection is an error of proposal message verification, its reason is published in AppEventProposalRejected.
+type Rejection struct {
+	Reason  string
+	message string
+}
+
+// NewRejection creates proposal message verification error with the given reason.
+func NewRejection(reason, message string) *Rejection {
+	return &Rejection{Reason: reason, message: message}
+}
+
+// Error returns error message.
+func (r *Rejection) Error() string {
+	return r.message
+}
+
+// SignProposalPayload signs bytes of the proposal message, signature is returned in base64.
+func SignProposalPayload(signer identity.Signer, payload []byte) (string, error) {
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return "", fmt.Errorf("could not sign proposal message: %w", err)
+	}
+	return signature.Base64(), nil
+}
+
+// VerifyProposalPayload checks that bytes of the proposal message were signed by the proposal provider.
+func VerifyProposalPayload(newVerifier identity.VerifierFactory, providerID string, payload []byte, signature string) error {
+	if signature == "" {
+		return ErrUnsigned
+	}
+	verifier := newVerifier(identity.FromAddress(providerID))
+	if !verifier.Verify(payload, identity.SignatureBase64(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// PublishRejection reports proposal message dropped because it failed verification.
+// Errors other than Rejection are reported as malformed messages.
+func PublishRejection(publisher eventbus.Publisher, proposal market.ServiceProposal, err error) {
+	log.Debug().Err(err).Msgf("Dropped proposal message of provider %q", proposal.ProviderID)
+
+	reason := "malformed"
+	var rejection *Rejection
+	if errors.As(err, &rejection) {
+		reason = rejection.Reason
+	}
+	publisher.Publish(AppTopicProposalRejected, AppEventProposalRejected{
+		ProviderID:  proposal.ProviderID,
+		ServiceType: proposal.ServiceType,
+		Reason:      reason,
+	})
+}
@@ -36,6 +36,10 @@ type CachedResolver struct {
 	publicIP         string
 	publicIPLock     sync.Mutex
 	publicIPCachedAt time.Time
+
+	publicIPv6         string
+	publicIPv6Lock     sync.Mutex
+	publicIPv6CachedAt time.Time
 }
 
 // NewCachedResolver creates ip resolver with cache duration.
@@ -86,6 +90,26 @@ func (r *CachedResolver) GetPublicIP() (string, error) {
 	return r.publicIP, nil
 }
 
+// GetPublicIPv6 returns current public IPv6 address.
+func (r *CachedResolver) GetPublicIPv6() (string, error) {
+	r.publicIPv6Lock.Lock()
+	defer r.publicIPv6Lock.Unlock()
+
+	if r.publicIPv6CachedAt.Add(r.cacheDuration).After(time.Now()) && r.publicIPv6 != "" {
+		log.Debug().Msgf("Found cached public IPv6")
+		return r.publicIPv6, nil
+	}
+
+	log.Debug().Msg("Public IPv6 cache is empty, fetching IP")
+	publicIPv6, err := r.resolver.GetPublicIPv6()
+	if err != nil {
+		return "", err
+	}
+	r.publicIPv6CachedAt = time.Now()
+	r.publicIPv6 = publicIPv6
+	return r.publicIPv6, nil
+}
+
 // ClearCache clears ip cache.
 func (r *CachedResolver) ClearCache() {
 	log.Debug().Msg("Clearing ip resolver cache")
@@ -99,4 +123,9 @@ func (r *CachedResolver) ClearCache() {
 	r.publicIP = ""
 	r.publicIPCachedAt = time.Time{}
 	r.publicIPLock.Unlock()
+
+	r.publicIPv6Lock.Lock()
+	r.publicIPv6 = ""
+	r.publicIPv6CachedAt = time.Time{}
+	r.publicIPv6Lock.Unlock()
 }
@@ -125,3 +125,7 @@ func (m *mockRealResolver) GetPublicIP() (string, error) {
 	m.getPublicIPCalls++
 	return "1.1.1.1", nil
 }
+
+func (m *mockRealResolver) GetPublicIPv6() (string, error) {
+	return "2001:db8::1", nil
+}
@@ -48,8 +48,19 @@ func NewResolverMockFailing(err error) Resolver {
 	}
 }
 
+// NewResolverMockIPv6 returns mockResolver which resolves statically entered IPv4 and IPv6 addresses.
+func NewResolverMockIPv6(ip, ipv6 string) Resolver {
+	return &mockResolver{
+		publicIP:   ip,
+		publicIPv6: ipv6,
+		outboundIP: net.ParseIP(ip),
+		error:      nil,
+	}
+}
+
 type mockResolver struct {
 	publicIP   string
+	publicIPv6 string
 	publicIPs  []string
 	outboundIP net.IP
 	error      error
@@ -66,6 +77,10 @@ func (client *mockResolver) GetPublicIP() (string, error) {
 	return client.publicIP, client.error
 }
 
+func (client *mockResolver) GetPublicIPv6() (string, error) {
+	return client.publicIPv6, client.error
+}
+
 func (client *mockResolver) GetOutboundIP() (string, error) {
 	return client.outboundIP.String(), client.error
 }
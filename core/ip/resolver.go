@@ -32,6 +32,7 @@ const apiClient = "goclient-v0.1"
 type Resolver interface {
 	GetOutboundIP() (string, error)
 	GetPublicIP() (string, error)
+	GetPublicIPv6() (string, error)
 }
 
 // ResolverImpl represents data required to operate resolving
@@ -57,6 +58,9 @@ type ipResponse struct {
 // declared as var for override in test
 var checkAddress = "8.8.8.8:53"
 
+// declared as var for override in test
+var checkAddress6 = "[2001:4860:4860::8888]:53"
+
 // GetOutboundIP returns current outbound IP as string for current system
 func (r *ResolverImpl) GetOutboundIP() (string, error) {
 	ip, err := r.getOutboundIP()
@@ -101,3 +105,34 @@ func (r *ResolverImpl) GetPublicIP() (string, error) {
 	log.Debug().Msg("IP detected: " + ipResponse.IP)
 	return ipResponse.IP, nil
 }
+
+// GetPublicIPv6 returns current public IPv6 address. Hosts are reachable without NAT over IPv6,
+// so globally routable outbound address is the public one.
+func (r *ResolverImpl) GetPublicIPv6() (string, error) {
+	var localIPAddress net.UDPAddr
+	// Bind address is used only if it is IPv6, otherwise dial would fail.
+	if ip := net.ParseIP(r.bindAddress); ip != nil && ip.To4() == nil {
+		localIPAddress.IP = ip
+	}
+
+	dialer := net.Dialer{LocalAddr: &localIPAddress}
+	conn, err := dialer.Dial("udp6", checkAddress6)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to determine outbound IPv6")
+	}
+	defer conn.Close()
+
+	ip := conn.LocalAddr().(*net.UDPAddr).IP
+	if !isPublicIPv6(ip) {
+		return "", errors.Errorf("outbound IPv6 %s is not public", ip)
+	}
+	return ip.String(), nil
+}
+
+func isPublicIPv6(ip net.IP) bool {
+	if ip.To4() != nil || !ip.IsGlobalUnicast() {
+		return false
+	}
+	// Unique local addresses fc00::/7 are not routable on the internet.
+	return ip[0]&0xfe != 0xfc
+}
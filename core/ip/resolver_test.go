@@ -18,6 +18,7 @@
 package ip
 
 import (
+	"net"
 	"testing"
 
 	"github.com/mysteriumnetwork/node/requests"
@@ -31,3 +32,11 @@ func TestLocalhostOutboundIPIsReturned(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "127.0.0.1", ip)
 }
+
+func TestIsPublicIPv6(t *testing.T) {
+	assert.True(t, isPublicIPv6(net.ParseIP("2a01:4f8::1")))
+	assert.False(t, isPublicIPv6(net.ParseIP("fd6d:7973:7400::1")))
+	assert.False(t, isPublicIPv6(net.ParseIP("fe80::1")))
+	assert.False(t, isPublicIPv6(net.ParseIP("::1")))
+	assert.False(t, isPublicIPv6(net.ParseIP("1.1.1.1")))
+}
@@ -86,6 +86,10 @@ func (c *Cache) HandleConnectionEvent(se connectionstate.AppEventConnectionState
 	if se.State != connectionstate.Connected && se.State != connectionstate.NotConnected {
 		return
 	}
+	// Additional connections are detached, they do not change the location.
+	if !se.SessionInfo.IsDefault() {
+		return
+	}
 
 	loc, err := c.fetchAndSave()
 	if err != nil {
@@ -0,0 +1,290 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/money"
+	natEvent "github.com/mysteriumnetwork/node/nat/event"
+	"github.com/mysteriumnetwork/node/p2p"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+const (
+	directionProvided = "provided"
+	directionConsumed = "consumed"
+
+	flowSent     = "sent"
+	flowReceived = "received"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// Exporter builds node metrics from the event bus.
+type Exporter struct {
+	registry *Registry
+
+	sessionsActive    *Gauge
+	sessionsRejected  *Counter
+	bytesTransferred  *Counter
+	connectionStates  *Counter
+	natTraversals     *Counter
+	hermesPromises    *Counter
+	settlements       *Counter
+	unsettledBalance  *Gauge
+	keepAliveFailures *Counter
+	proposalsRejected *Counter
+
+	lock     sync.Mutex
+	sessions map[string]*trackedSession
+}
+
+type trackedSession struct {
+	direction   string
+	serviceType string
+	sent        uint64
+	received    uint64
+}
+
+// NewExporter creates metrics exporter with all node metrics registered.
+func NewExporter() *Exporter {
+	registry := NewRegistry()
+	return &Exporter{
+		registry: registry,
+
+		sessionsActive: registry.NewGauge(
+			"myst_sessions_active",
+			"Number of active sessions.",
+			"direction", "service_type",
+		),
+		sessionsRejected: registry.NewCounter(
+			"myst_sessions_rejected_total",
+			"Number of sessions rejected by provider admission control.",
+			"reason",
+		),
+		bytesTransferred: registry.NewCounter(
+			"myst_session_bytes_total",
+			"Number of bytes transferred through sessions.",
+			"direction", "flow",
+		),
+		connectionStates: registry.NewCounter(
+			"myst_connection_state_transitions_total",
+			"Number of consumer connection state transitions.",
+			"state",
+		),
+		natTraversals: registry.NewCounter(
+			"myst_nat_traversal_total",
+			"Number of NAT traversal attempts.",
+			"stage", "result",
+		),
+		hermesPromises: registry.NewCounter(
+			"myst_hermes_promises_total",
+			"Number of promises received from hermes.",
+			"hermes_id",
+		),
+		settlements: registry.NewCounter(
+			"myst_settlements_total",
+			"Number of promise settlements.",
+			"hermes_id", "result",
+		),
+		unsettledBalance: registry.NewGauge(
+			"myst_unsettled_balance_myst",
+			"Provider earnings which are not settled yet, in MYST.",
+			"identity",
+		),
+		keepAliveFailures: registry.NewCounter(
+			"myst_p2p_keepalive_failures_total",
+			"Number of failed p2p channel keep alive pings.",
+			"role",
+		),
+		proposalsRejected: registry.NewCounter(
+			"myst_discovery_proposals_rejected_total",
+			"Number of proposal messages dropped because they failed verification.",
+			"reason",
+		),
+
+		sessions: make(map[string]*trackedSession),
+	}
+}
+
+// Registry returns registry holding the exported metrics.
+func (e *Exporter) Registry() *Registry {
+	return e.registry
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (e *Exporter) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicSession, e.consumeServiceSessionEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferred, e.consumeServiceSessionStatisticsEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicSessionRejected, e.consumeSessionRejectedEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionSession, e.consumeConnectionSessionEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, e.consumeConnectionStatisticsEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, e.consumeConnectionStateEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(natEvent.AppTopicTraversal, e.consumeNATEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(pingpongEvent.AppTopicHermesPromise, e.consumeHermesPromiseEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(pingpongEvent.AppTopicSettlementComplete, e.consumeSettlementEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(pingpongEvent.AppTopicEarningsChanged, e.consumeEarningsChangedEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(p2p.AppTopicKeepAliveFailed, e.consumeKeepAliveFailedEvent); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(discovery.AppTopicProposalRejected, e.consumeProposalRejectedEvent)
+}
+
+func (e *Exporter) consumeServiceSessionEvent(ev sessionEvent.AppEventSession) {
+	switch ev.Status {
+	case sessionEvent.CreatedStatus:
+		e.sessionStarted(ev.Session.ID, directionProvided, ev.Session.Proposal.ServiceType)
+	case sessionEvent.RemovedStatus:
+		e.sessionEnded(ev.Session.ID)
+	}
+}
+
+func (e *Exporter) consumeServiceSessionStatisticsEvent(ev sessionEvent.AppEventDataTransferred) {
+	// Provider receives what consumer uploads.
+	e.sessionTransferred(ev.ID, ev.Down, ev.Up)
+}
+
+func (e *Exporter) consumeSessionRejectedEvent(ev sessionEvent.AppEventSessionRejected) {
+	e.sessionsRejected.Inc(ev.Reason)
+}
+
+func (e *Exporter) consumeConnectionSessionEvent(ev connectionstate.AppEventConnectionSession) {
+	switch ev.Status {
+	case connectionstate.SessionCreatedStatus:
+		e.sessionStarted(string(ev.SessionInfo.SessionID), directionConsumed, ev.SessionInfo.Proposal.ServiceType)
+	case connectionstate.SessionEndedStatus:
+		e.sessionEnded(string(ev.SessionInfo.SessionID))
+	}
+}
+
+func (e *Exporter) consumeConnectionStatisticsEvent(ev connectionstate.AppEventConnectionStatistics) {
+	e.sessionTransferred(string(ev.SessionInfo.SessionID), ev.Stats.BytesSent, ev.Stats.BytesReceived)
+}
+
+func (e *Exporter) consumeConnectionStateEvent(ev connectionstate.AppEventConnectionState) {
+	e.connectionStates.Inc(string(ev.State))
+}
+
+func (e *Exporter) consumeNATEvent(ev natEvent.Event) {
+	result := resultSuccess
+	if !ev.Successful {
+		result = resultFailure
+	}
+	e.natTraversals.Inc(ev.Stage, result)
+}
+
+func (e *Exporter) consumeHermesPromiseEvent(ev pingpongEvent.AppEventHermesPromise) {
+	e.hermesPromises.Inc(ev.HermesID.Hex())
+}
+
+func (e *Exporter) consumeSettlementEvent(ev pingpongEvent.AppEventSettlementComplete) {
+	result := resultSuccess
+	if ev.Error != nil {
+		result = resultFailure
+	}
+	e.settlements.Inc(ev.HermesID.Hex(), result)
+}
+
+func (e *Exporter) consumeEarningsChangedEvent(ev pingpongEvent.AppEventEarningsChanged) {
+	e.unsettledBalance.Set(toMyst(ev.Current.UnsettledBalance), ev.Identity.Address)
+}
+
+func (e *Exporter) consumeKeepAliveFailedEvent(ev p2p.AppEventKeepAliveFailed) {
+	e.keepAliveFailures.Inc(ev.Role)
+}
+
+func (e *Exporter) consumeProposalRejectedEvent(ev discovery.AppEventProposalRejected) {
+	e.proposalsRejected.Inc(ev.Reason)
+}
+
+func (e *Exporter) sessionStarted(id, direction, serviceType string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if _, ok := e.sessions[id]; ok {
+		return
+	}
+	e.sessions[id] = &trackedSession{direction: direction, serviceType: serviceType}
+	e.sessionsActive.Inc(direction, serviceType)
+}
+
+func (e *Exporter) sessionEnded(id string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	s, ok := e.sessions[id]
+	if !ok {
+		return
+	}
+	delete(e.sessions, id)
+	e.sessionsActive.Dec(s.direction, s.serviceType)
+}
+
+// sessionTransferred accounts the difference of cumulative session traffic since the last update.
+func (e *Exporter) sessionTransferred(id string, sent, received uint64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	s, ok := e.sessions[id]
+	if !ok {
+		return
+	}
+	if sent > s.sent {
+		e.bytesTransferred.Add(float64(sent-s.sent), s.direction, flowSent)
+		s.sent = sent
+	}
+	if received > s.received {
+		e.bytesTransferred.Add(float64(received-s.received), s.direction, flowReceived)
+		s.received = received
+	}
+}
+
+func toMyst(amount *big.Int) float64 {
+	if amount == nil {
+		return 0
+	}
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(money.MystSize)).Float64()
+	return value
+}
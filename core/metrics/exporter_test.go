@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(e *Exporter) string {
+	var buf bytes.Buffer
+	e.Registry().WriteTo(&buf)
+	return buf.String()
+}
+
+func TestExporter_TracksProvidedSessions(t *testing.T) {
+	exporter := NewExporter()
+	session := sessionEvent.SessionContext{
+		ID:       "session-1",
+		Proposal: market.ServiceProposal{ServiceType: "wireguard"},
+	}
+
+	exporter.consumeServiceSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.CreatedStatus, Session: session})
+	exporter.consumeServiceSessionStatisticsEvent(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 10, Down: 100})
+	exporter.consumeServiceSessionStatisticsEvent(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 15, Down: 150})
+
+	output := render(exporter)
+	assert.Contains(t, output, `myst_sessions_active{direction="provided",service_type="wireguard"} 1`+"\n")
+	assert.Contains(t, output, `myst_session_bytes_total{direction="provided",flow="sent"} 150`+"\n")
+	assert.Contains(t, output, `myst_session_bytes_total{direction="provided",flow="received"} 15`+"\n")
+
+	exporter.consumeServiceSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.RemovedStatus, Session: session})
+	exporter.consumeServiceSessionStatisticsEvent(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 20, Down: 200})
+
+	output = render(exporter)
+	assert.Contains(t, output, `myst_sessions_active{direction="provided",service_type="wireguard"} 0`+"\n")
+	assert.Contains(t, output, `myst_session_bytes_total{direction="provided",flow="sent"} 150`+"\n")
+}
+
+func TestExporter_CountsSettlements(t *testing.T) {
+	exporter := NewExporter()
+	hermesID := common.HexToAddress("0x1")
+
+	exporter.consumeSettlementEvent(pingpongEvent.AppEventSettlementComplete{HermesID: hermesID})
+	exporter.consumeSettlementEvent(pingpongEvent.AppEventSettlementComplete{HermesID: hermesID, Error: errors.New("boom")})
+
+	output := render(exporter)
+	assert.Contains(t, output, `myst_settlements_total{hermes_id="`+hermesID.Hex()+`",result="failure"} 1`+"\n")
+	assert.Contains(t, output, `myst_settlements_total{hermes_id="`+hermesID.Hex()+`",result="success"} 1`+"\n")
+}
+
+func TestExporter_SubscribesToEvents(t *testing.T) {
+	bus := eventbus.New()
+	exporter := NewExporter()
+	assert.NoError(t, exporter.Subscribe(bus))
+
+	bus.Publish(sessionEvent.AppTopicSessionRejected, sessionEvent.AppEventSessionRejected{Reason: "MAX_SESSIONS"})
+	bus.Publish(p2p.AppTopicKeepAliveFailed, p2p.AppEventKeepAliveFailed{Role: p2p.RoleProvider})
+
+	assert.Eventually(t, func() bool {
+		output := render(exporter)
+		return strings.Contains(output, `myst_sessions_rejected_total{reason="MAX_SESSIONS"} 1`) &&
+			strings.Contains(output, `myst_p2p_keepalive_failures_total{role="provider"} 1`)
+	}, 2*time.Second, 10*time.Millisecond)
+}
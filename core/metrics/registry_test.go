@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("test_total", "Test counter.", "kind")
+	gauge := registry.NewGauge("test_value", "Test gauge.")
+
+	counter.Inc("b")
+	counter.Add(2.5, "a")
+	counter.Add(-1, "a")
+	gauge.Set(10)
+	gauge.Dec()
+
+	var buf bytes.Buffer
+	_, err := registry.WriteTo(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{kind="a"} 2.5
+test_total{kind="b"} 1
+# HELP test_value Test gauge.
+# TYPE test_value gauge
+test_value 9
+`, buf.String())
+}
+
+func TestRegistry_WriteToEscapesHelpAndLabels(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("test_total", "Line\nwith \\ slash.", "kind")
+	counter.Inc("quote \" and\nnewline")
+
+	var buf bytes.Buffer
+	_, err := registry.WriteTo(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP test_total Line\nwith \\ slash.
+# TYPE test_total counter
+test_total{kind="quote \" and\nnewline"} 1
+`, buf.String())
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("test_value", "Test gauge.").Set(1)
+
+	resp := httptest.NewRecorder()
+	registry.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, contentType, resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "test_value 1\n")
+}
@@ -81,6 +81,7 @@ type Options struct {
 	Consumer bool
 
 	P2PPorts *port.Range
+	P2PRelay OptionsP2PRelay
 }
 
 // GetOptions retrieves node options from the app configuration.
@@ -90,6 +91,8 @@ func GetOptions() *Options {
 		Localnet:              config.GetBool(config.FlagLocalnet),
 		Betanet:               config.GetBool(config.FlagBetanet),
 		ExperimentNATPunching: config.GetBool(config.FlagNATPunching),
+		STUNServers:           config.GetStringSlice(config.FlagSTUNServers),
+		PortMappingProtocols:  config.GetStringSlice(config.FlagPortMappingProtocols),
 		MysteriumAPIAddress:   config.GetString(config.FlagAPIAddress),
 		BrokerAddress:         config.GetString(config.FlagBrokerAddress),
 		EtherClientRPC:        config.GetString(config.FlagEtherRPC),
@@ -116,8 +119,9 @@ func GetOptions() *Options {
 			Address: config.GetString(config.FlagMMNAddress),
 		},
 		Quality: OptionsQuality{
-			Type:    QualityType(config.GetString(config.FlagQualityType)),
-			Address: config.GetString(config.FlagQualityAddress),
+			Type:        QualityType(config.GetString(config.FlagQualityType)),
+			Address:     config.GetString(config.FlagQualityAddress),
+			OTLPAddress: config.GetString(config.FlagQualityOTLPAddress),
 		},
 		Location: OptionsLocation{
 			IPDetectorURL: config.GetString(config.FlagIPDetectorURL),
@@ -159,6 +163,10 @@ func GetOptions() *Options {
 			BlockAlways: config.GetBool(config.FlagFirewallKillSwitch),
 		},
 		P2PPorts: getP2PListenPorts(),
+		P2PRelay: OptionsP2PRelay{
+			Addresses: config.GetStringSlice(config.FlagP2PRelayAddresses),
+			Port:      config.GetInt(config.FlagP2PRelayPort),
+		},
 		Consumer: config.GetBool(config.FlagConsumer),
 	}
 }
@@ -194,6 +202,10 @@ func GetDiscoveryOptions() *OptionsDiscovery {
 		PingInterval:  config.GetDuration(config.FlagDiscoveryPingInterval),
 		FetchEnabled:  true,
 		FetchInterval: config.GetDuration(config.FlagDiscoveryFetchInterval),
+		CacheMaxAge:   config.GetDuration(config.FlagDiscoveryCacheMaxAge),
+
+		GossipAddress:   config.GetString(config.FlagDiscoveryGossipAddress),
+		GossipBootstrap: config.GetStringSlice(config.FlagDiscoveryGossipBootstrap),
 	}
 }
 
@@ -27,6 +27,8 @@ const (
 	DiscoveryTypeAPI = DiscoveryType("api")
 	// DiscoveryTypeBroker defines type which discovers proposals through Broker (Mysterium Communication)
 	DiscoveryTypeBroker = DiscoveryType("broker")
+	// DiscoveryTypeGossip defines type which discovers proposals through gossip between nodes
+	DiscoveryTypeGossip = DiscoveryType("gossip")
 )
 
 // OptionsDiscovery describes possible parameters of discovery configuration
@@ -36,4 +38,8 @@ type OptionsDiscovery struct {
 	PingInterval  time.Duration
 	FetchEnabled  bool
 	FetchInterval time.Duration
+	CacheMaxAge   time.Duration
+
+	GossipAddress   string
+	GossipBootstrap []string
 }
@@ -24,6 +24,8 @@ type OptionsNetwork struct {
 	Betanet  bool
 
 	ExperimentNATPunching bool
+	STUNServers           []string
+	PortMappingProtocols  []string
 
 	MysteriumAPIAddress string
 	BrokerAddress       string
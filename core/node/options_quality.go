@@ -33,4 +33,6 @@ const (
 type OptionsQuality struct {
 	Type    QualityType
 	Address string
+	// OTLPAddress enables exporting of quality events to OpenTelemetry collector in addition to Type.
+	OTLPAddress string
 }
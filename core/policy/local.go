@@ -0,0 +1,391 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const (
+	// SourceRemote marks policies fetched from the policy Oracle
+	SourceRemote = "remote"
+	// SourceLocal marks policies defined in local files
+	SourceLocal = "local"
+)
+
+var (
+	// ErrLocalPolicyNotFound indicates that there is no local policy with the given ID
+	ErrLocalPolicyNotFound = errors.New("local policy not found")
+	// ErrLocalPolicyInUse indicates that local policy is used by running services and can not be deleted
+	ErrLocalPolicyInUse = errors.New("local policy is used by running services")
+	// ErrInvalidLocalPolicyID indicates that policy ID can not be used as a file name
+	ErrInvalidLocalPolicyID = errors.New("policy ID may contain only letters, digits, '-' and '_'")
+
+	localPolicyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// SourceOf returns whether the policy is defined locally or fetched from the policy Oracle.
+func SourceOf(policy market.AccessPolicy) string {
+	if policy.Source == SourceLocal {
+		return SourceLocal
+	}
+	return SourceRemote
+}
+
+type localPolicy struct {
+	path        string
+	rules       market.AccessPolicyRuleSet
+	subscribers []*Repository
+}
+
+// LocalPolicies keeps access policies defined in JSON or YAML files of the directory,
+// files are reloaded once they change.
+type LocalPolicies struct {
+	dir            string
+	reloadInterval time.Duration
+
+	lock     sync.Mutex
+	policies map[string]*localPolicy
+	files    map[string]time.Time
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// NewLocalPolicies creates local policies of the given directory.
+func NewLocalPolicies(dir string, reloadInterval time.Duration) *LocalPolicies {
+	return &LocalPolicies{
+		dir:            dir,
+		reloadInterval: reloadInterval,
+		policies:       make(map[string]*localPolicy),
+		files:          make(map[string]time.Time),
+		shutdown:       make(chan struct{}),
+	}
+}
+
+// Start loads policies and reloads them on change until stopped.
+func (lp *LocalPolicies) Start() {
+	if err := lp.Reload(); err != nil {
+		log.Warn().Err(err).Msg("Failed to load local access policies")
+	}
+
+	for {
+		select {
+		case <-lp.shutdown:
+			return
+		case <-time.After(lp.reloadInterval):
+			if err := lp.Reload(); err != nil {
+				log.Warn().Err(err).Msg("Failed to reload local access policies")
+			}
+		}
+	}
+}
+
+// Stop ends reloading policies.
+func (lp *LocalPolicies) Stop() {
+	lp.shutdownOnce.Do(func() {
+		close(lp.shutdown)
+	})
+}
+
+// Has returns flag if the policy with the given ID is defined locally.
+func (lp *LocalPolicies) Has(policyID string) bool {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	_, ok := lp.policies[policyID]
+	return ok
+}
+
+// Policy converts given ID to the local policy.
+func (lp *LocalPolicies) Policy(policyID string) market.AccessPolicy {
+	return market.AccessPolicy{
+		ID:     policyID,
+		Source: SourceLocal,
+	}
+}
+
+// List returns rules of all local policies.
+func (lp *LocalPolicies) List() []market.AccessPolicyRuleSet {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	list := make([]market.AccessPolicyRuleSet, 0, len(lp.policies))
+	for _, policy := range lp.policies {
+		list = append(list, policy.rules)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Get returns rules of the local policy.
+func (lp *LocalPolicies) Get(policyID string) (market.AccessPolicyRuleSet, error) {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	policy, ok := lp.policies[policyID]
+	if !ok {
+		return market.AccessPolicyRuleSet{}, ErrLocalPolicyNotFound
+	}
+	return policy.rules, nil
+}
+
+// SubscribePolicies adds given local policies to repository and keeps them updated on change.
+func (lp *LocalPolicies) SubscribePolicies(policies []market.AccessPolicy, repository *Repository) error {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	for _, policy := range policies {
+		if _, ok := lp.policies[policy.ID]; !ok {
+			return fmt.Errorf("unknown local policy: %s", policy.ID)
+		}
+	}
+	for _, policy := range policies {
+		local := lp.policies[policy.ID]
+		local.subscribers = append(local.subscribers, repository)
+		repository.SetPolicyRules(policy, local.rules)
+	}
+	return nil
+}
+
+// UnsubscribePolicies stops updating the repository, once service using it is stopped.
+func (lp *LocalPolicies) UnsubscribePolicies(repository *Repository) {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	for _, policy := range lp.policies {
+		subscribers := policy.subscribers[:0]
+		for _, subscriber := range policy.subscribers {
+			if subscriber != repository {
+				subscribers = append(subscribers, subscriber)
+			}
+		}
+		policy.subscribers = subscribers
+	}
+}
+
+// IsValidLocalPolicyID checks that policy ID can be used as a file name of the local policy.
+func IsValidLocalPolicyID(policyID string) bool {
+	return localPolicyIDPattern.MatchString(policyID)
+}
+
+// Save creates or updates the local policy file.
+func (lp *LocalPolicies) Save(rules market.AccessPolicyRuleSet) error {
+	if !IsValidLocalPolicyID(rules.ID) {
+		return ErrInvalidLocalPolicyID
+	}
+
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	path := filepath.Join(lp.dir, rules.ID+".json")
+	if policy, ok := lp.policies[rules.ID]; ok {
+		path = policy.path
+	}
+
+	data, err := marshalRuleSet(path, rules)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(lp.dir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create local policies directory")
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write local policy %s", rules.ID)
+	}
+
+	lp.setRules(rules.ID, path, rules)
+	if info, err := os.Stat(path); err == nil {
+		lp.files[path] = info.ModTime()
+	}
+	return nil
+}
+
+// Delete removes the local policy file, policies used by running services can not be deleted.
+func (lp *LocalPolicies) Delete(policyID string) error {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	policy, ok := lp.policies[policyID]
+	if !ok {
+		return ErrLocalPolicyNotFound
+	}
+	if len(policy.subscribers) > 0 {
+		return ErrLocalPolicyInUse
+	}
+
+	if err := os.Remove(policy.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove local policy %s", policyID)
+	}
+	delete(lp.policies, policyID)
+	delete(lp.files, policy.path)
+	return nil
+}
+
+// Reload reads policy files again if any of them were changed, added or removed.
+func (lp *LocalPolicies) Reload() error {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	files, err := lp.listFiles()
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(files, lp.files) {
+		return nil
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	loaded := make(map[string]bool)
+	for _, path := range paths {
+		rules, err := readRuleSet(path)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Skipping local policy file %s", path)
+			continue
+		}
+		if existing, ok := lp.policies[rules.ID]; ok && loaded[rules.ID] {
+			log.Warn().Msgf("Skipping local policy file %s, policy %s is already defined in %s", path, rules.ID, existing.path)
+			continue
+		}
+		for _, rule := range append(append([]market.AccessRule{}, rules.Allow...), rules.Deny...) {
+			if err := rule.Validate(); err != nil {
+				log.Warn().Err(err).Msgf("Local policy %s has invalid rule, it will not be enforced", rules.ID)
+			}
+		}
+		loaded[rules.ID] = true
+		lp.setRules(rules.ID, path, rules)
+	}
+
+	for policyID, policy := range lp.policies {
+		if loaded[policyID] {
+			continue
+		}
+		if len(policy.subscribers) > 0 {
+			log.Warn().Msgf("Local policy %s was removed, running services keep its last rules", policyID)
+			continue
+		}
+		delete(lp.policies, policyID)
+	}
+
+	lp.files = files
+	return nil
+}
+
+// setRules updates rules of the policy and its subscribers, it expects lock to be held.
+func (lp *LocalPolicies) setRules(policyID, path string, rules market.AccessPolicyRuleSet) {
+	policy, ok := lp.policies[policyID]
+	if !ok {
+		policy = &localPolicy{}
+		lp.policies[policyID] = policy
+	}
+	policy.path = path
+	if reflect.DeepEqual(policy.rules, rules) {
+		return
+	}
+
+	policy.rules = rules
+	for _, subscriber := range policy.subscribers {
+		subscriber.SetPolicyRules(lp.Policy(policyID), rules)
+	}
+}
+
+func (lp *LocalPolicies) listFiles() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+
+	infos, err := ioutil.ReadDir(lp.dir)
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list local policies directory")
+	}
+
+	for _, info := range infos {
+		if info.IsDir() || !isPolicyFile(info.Name()) {
+			continue
+		}
+		files[filepath.Join(lp.dir, info.Name())] = info.ModTime()
+	}
+	return files, nil
+}
+
+func isPolicyFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readRuleSet reads policy file, policy ID defaults to the file name.
+func readRuleSet(path string) (market.AccessPolicyRuleSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return market.AccessPolicyRuleSet{}, err
+	}
+
+	var rules market.AccessPolicyRuleSet
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, &rules)
+	} else {
+		err = json.Unmarshal(data, &rules)
+	}
+	if err != nil {
+		return market.AccessPolicyRuleSet{}, errors.Wrap(err, "failed to parse policy")
+	}
+
+	if rules.ID == "" {
+		rules.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return rules, nil
+}
+
+func marshalRuleSet(path string, rules market.AccessPolicyRuleSet) ([]byte, error) {
+	if isYAML(path) {
+		return yaml.Marshal(rules)
+	}
+	return json.MarshalIndent(rules, "", "  ")
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var (
+	localPolicyJSON = `{
+		"title": "Office",
+		"allow": [{"type": "ip_range", "value": "10.0.0.0/8"}]
+	}`
+	localPolicyYAML = `
+id: smtp-block
+title: No SMTP
+deny:
+  - type: port
+    value: tcp/25
+`
+)
+
+func writePolicyFile(t *testing.T, path, content string, modTime time.Time) {
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func newLocalPoliciesDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "localPoliciesTest")
+	assert.NoError(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestLocalPolicies_LoadsJSONAndYAML(t *testing.T) {
+	dir, cleanup := newLocalPoliciesDir(t)
+	defer cleanup()
+	now := time.Now()
+	writePolicyFile(t, filepath.Join(dir, "office.json"), localPolicyJSON, now)
+	writePolicyFile(t, filepath.Join(dir, "other.yml"), localPolicyYAML, now)
+	writePolicyFile(t, filepath.Join(dir, "README.txt"), "not a policy", now)
+
+	lp := NewLocalPolicies(dir, time.Second)
+	assert.NoError(t, lp.Reload())
+
+	assert.Equal(t, []market.AccessPolicyRuleSet{
+		{
+			ID:    "office",
+			Title: "Office",
+			Allow: []market.AccessRule{{Type: market.AccessPolicyTypeIPRange, Value: "10.0.0.0/8"}},
+		},
+		{
+			ID:    "smtp-block",
+			Title: "No SMTP",
+			Deny:  []market.AccessRule{{Type: market.AccessPolicyTypePort, Value: "tcp/25"}},
+		},
+	}, lp.List())
+	assert.True(t, lp.Has("office"))
+	assert.False(t, lp.Has("other"))
+}
+
+func TestLocalPolicies_MissingDirectoryIsEmpty(t *testing.T) {
+	lp := NewLocalPolicies(filepath.Join(os.TempDir(), "localPoliciesTest-missing"), time.Second)
+
+	assert.NoError(t, lp.Reload())
+	assert.Empty(t, lp.List())
+}
+
+func TestLocalPolicies_ReloadUpdatesSubscribers(t *testing.T) {
+	dir, cleanup := newLocalPoliciesDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "office.json")
+	writePolicyFile(t, path, localPolicyJSON, time.Now())
+
+	lp := NewLocalPolicies(dir, time.Second)
+	assert.NoError(t, lp.Reload())
+
+	repository := NewRepository()
+	assert.NoError(t, lp.SubscribePolicies([]market.AccessPolicy{lp.Policy("office")}, repository))
+	assert.Equal(t, market.TrafficRules{AllowedNetworks: []net.IPNet{mustParseIPRange("10.0.0.0/8")}}, repository.TrafficRules())
+
+	writePolicyFile(t, path, `{"deny": [{"type": "port", "value": "25"}]}`, time.Now().Add(time.Minute))
+	assert.NoError(t, lp.Reload())
+	assert.Equal(t, market.TrafficRules{DeniedPorts: []market.PortRange{{From: 25, To: 25}}}, repository.TrafficRules())
+
+	lp.UnsubscribePolicies(repository)
+	writePolicyFile(t, path, localPolicyJSON, time.Now().Add(2*time.Minute))
+	assert.NoError(t, lp.Reload())
+	assert.Equal(t, market.TrafficRules{DeniedPorts: []market.PortRange{{From: 25, To: 25}}}, repository.TrafficRules())
+}
+
+func TestLocalPolicies_SubscribeUnknownPolicy(t *testing.T) {
+	lp := NewLocalPolicies("", time.Second)
+
+	err := lp.SubscribePolicies([]market.AccessPolicy{lp.Policy("unknown")}, NewRepository())
+	assert.EqualError(t, err, "unknown local policy: unknown")
+}
+
+func TestLocalPolicies_SaveAndDelete(t *testing.T) {
+	dir, cleanup := newLocalPoliciesDir(t)
+	defer cleanup()
+	lp := NewLocalPolicies(dir, time.Second)
+
+	rules := market.AccessPolicyRuleSet{
+		ID:    "office",
+		Allow: []market.AccessRule{{Type: market.AccessPolicyTypePort, Value: "tcp/443"}},
+	}
+	assert.NoError(t, lp.Save(rules))
+	assert.FileExists(t, filepath.Join(dir, "office.json"))
+
+	reloaded := NewLocalPolicies(dir, time.Second)
+	assert.NoError(t, reloaded.Reload())
+	saved, err := reloaded.Get("office")
+	assert.NoError(t, err)
+	assert.Equal(t, rules, saved)
+
+	assert.NoError(t, lp.Delete("office"))
+	_, err = os.Stat(filepath.Join(dir, "office.json"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = lp.Get("office")
+	assert.Equal(t, ErrLocalPolicyNotFound, err)
+	assert.Equal(t, ErrLocalPolicyNotFound, lp.Delete("office"))
+}
+
+func TestLocalPolicies_SaveKeepsFileFormat(t *testing.T) {
+	dir, cleanup := newLocalPoliciesDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "other.yaml")
+	writePolicyFile(t, path, localPolicyYAML, time.Now())
+
+	lp := NewLocalPolicies(dir, time.Second)
+	assert.NoError(t, lp.Reload())
+	assert.NoError(t, lp.Save(market.AccessPolicyRuleSet{ID: "smtp-block", Title: "Updated"}))
+
+	saved, err := readRuleSet(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated", saved.Title)
+	assert.NoFileExists(t, filepath.Join(dir, "smtp-block.json"))
+}
+
+func TestLocalPolicies_SaveRejectsInvalidID(t *testing.T) {
+	lp := NewLocalPolicies("", time.Second)
+
+	assert.Equal(t, ErrInvalidLocalPolicyID, lp.Save(market.AccessPolicyRuleSet{ID: "../office"}))
+}
+
+func TestLocalPolicies_PolicyInUseCanNotBeDeleted(t *testing.T) {
+	dir, cleanup := newLocalPoliciesDir(t)
+	defer cleanup()
+	lp := NewLocalPolicies(dir, time.Second)
+	assert.NoError(t, lp.Save(market.AccessPolicyRuleSet{ID: "office"}))
+
+	repository := NewRepository()
+	assert.NoError(t, lp.SubscribePolicies([]market.AccessPolicy{lp.Policy("office")}, repository))
+	assert.Equal(t, ErrLocalPolicyInUse, lp.Delete("office"))
+
+	lp.UnsubscribePolicies(repository)
+	assert.NoError(t, lp.Delete("office"))
+}
+
+func TestSourceOf(t *testing.T) {
+	assert.Equal(t, SourceLocal, SourceOf(market.AccessPolicy{ID: "office", Source: SourceLocal}))
+	assert.Equal(t, SourceRemote, SourceOf(market.AccessPolicy{ID: "dvpn", Source: "https://trust-oracle/lists/dvpn"}))
+}
+
+func mustParseIPRange(value string) net.IPNet {
+	network, err := market.ParseIPRange(value)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
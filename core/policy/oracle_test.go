@@ -161,6 +161,29 @@ func Test_Oracle_SubscribePolicies_MultipleSubscribers(t *testing.T) {
 	assert.Equal(t, []market.AccessPolicyRuleSet{policyOneRulesUpdated}, repo2.Rules())
 }
 
+func Test_Oracle_SubscribePolicies_WithDenyRules(t *testing.T) {
+	server := mockPolicyServer()
+	defer server.Close()
+
+	oracle := createEmptyOracle(server.URL)
+	repo := NewRepository()
+	err := oracle.SubscribePolicies(oracle.Policies([]string{"1", "4"}), repo)
+	assert.NoError(t, err)
+	assert.Equal(t, []market.AccessPolicyRuleSet{
+		policyOneRulesUpdated,
+		{
+			ID:    "4",
+			Title: "Four",
+			Deny: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIdentity, Value: "0x4"},
+				{Type: market.AccessPolicyTypeDNSZone, Value: "ads.example.com"},
+			},
+		},
+	}, repo.Rules())
+	assert.True(t, repo.HasDNSRules())
+	assert.True(t, repo.IsHostDenied("cdn.ads.example.com"))
+}
+
 func Test_Oracle_StartSyncsPolicies(t *testing.T) {
 	repo := NewRepository()
 	server := mockPolicyServer()
@@ -241,6 +264,17 @@ func mockPolicyServer() *httptest.Server {
 					{"type": "dns_zone", "value": "ipinfo.io"}
 				]
 			}`))
+		case "/4":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{
+				"id": "4",
+				"title": "Four",
+				"description": "",
+				"deny": [
+					{"type": "identity", "value": "0x4"},
+					{"type": "dns_zone", "value": "ads.example.com"}
+				]
+			}`))
 		default:
 			w.WriteHeader(http.StatusNotFound)
 		}
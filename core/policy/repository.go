@@ -19,11 +19,13 @@ package policy
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"sync"
 
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/rs/zerolog/log"
 )
 
 type listItem struct {
@@ -116,11 +118,19 @@ func (r *Repository) Rules() []market.AccessPolicyRuleSet {
 	return policiesRules
 }
 
-// IsIdentityAllowed returns flag if given identity should be allowed by rules
+// IsIdentityAllowed returns flag if given identity should be allowed by rules, deny rules take precedence
 func (r *Repository) IsIdentityAllowed(identity identity.Identity) bool {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
+	for _, item := range r.items {
+		for _, rule := range item.rules.Deny {
+			if rule.Type == market.AccessPolicyTypeIdentity && identity.Address == rule.Value {
+				return false
+			}
+		}
+	}
+
 	isAllowedByDefault := true
 	for _, item := range r.items {
 		for _, rule := range item.rules.Allow {
@@ -142,11 +152,26 @@ func (r *Repository) HasDNSRules() bool {
 	defer r.lock.RUnlock()
 
 	for _, item := range r.items {
-		for _, rule := range item.rules.Allow {
-			if rule.Type == market.AccessPolicyTypeDNSZone {
-				return true
+		for _, rules := range [][]market.AccessRule{item.rules.Allow, item.rules.Deny} {
+			for _, rule := range rules {
+				if isDNSRule(rule) {
+					return true
+				}
 			}
-			if rule.Type == market.AccessPolicyTypeDNSHostname {
+		}
+	}
+
+	return false
+}
+
+// HasDNSAllowRules returns flag if any DNS allow rules are applied, i.e. traffic is allowed only to the allowed hosts
+func (r *Repository) HasDNSAllowRules() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, item := range r.items {
+		for _, rule := range item.rules.Allow {
+			if isDNSRule(rule) {
 				return true
 			}
 		}
@@ -155,30 +180,128 @@ func (r *Repository) HasDNSRules() bool {
 	return false
 }
 
-// IsHostAllowed returns flag if given FQDN host should be allowed by rules
+// IsHostAllowed returns flag if given FQDN host should be allowed by rules, deny rules take precedence
 func (r *Repository) IsHostAllowed(host string) bool {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
+	if r.isHostDenied(host) {
+		return false
+	}
+
 	isAllowedByDefault := true
 	for _, item := range r.items {
 		for _, rule := range item.rules.Allow {
-			if rule.Type == market.AccessPolicyTypeDNSZone {
+			if isDNSRule(rule) {
 				isAllowedByDefault = false
-				if strings.HasSuffix(host, rule.Value) {
+				if hostMatches(rule, host) {
 					return true
 				}
 			}
-			if rule.Type == market.AccessPolicyTypeDNSHostname {
-				isAllowedByDefault = false
-				if host == rule.Value {
+		}
+	}
+
+	return isAllowedByDefault
+}
+
+// IsHostDenied returns flag if given FQDN host is explicitly denied by rules
+func (r *Repository) IsHostDenied(host string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.isHostDenied(host)
+}
+
+func (r *Repository) isHostDenied(host string) bool {
+	for _, item := range r.items {
+		for _, rule := range item.rules.Deny {
+			if isDNSRule(rule) && hostMatches(rule, host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasTrafficRules returns flag if any IP range or port rules are applied
+func (r *Repository) HasTrafficRules() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, item := range r.items {
+		for _, rules := range [][]market.AccessRule{item.rules.Allow, item.rules.Deny} {
+			for _, rule := range rules {
+				if rule.Type == market.AccessPolicyTypeIPRange || rule.Type == market.AccessPolicyTypePort {
 					return true
 				}
 			}
 		}
 	}
 
-	return isAllowedByDefault
+	return false
+}
+
+// TrafficRules returns destination networks and ports which traffic is allowed or denied to by rules.
+// Invalid rules are skipped, so allow list of a type with only invalid rules allows nothing.
+func (r *Repository) TrafficRules() market.TrafficRules {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var trafficRules market.TrafficRules
+	for _, item := range r.items {
+		for _, rule := range item.rules.Allow {
+			switch rule.Type {
+			case market.AccessPolicyTypeIPRange:
+				if trafficRules.AllowedNetworks == nil {
+					trafficRules.AllowedNetworks = make([]net.IPNet, 0)
+				}
+			case market.AccessPolicyTypePort:
+				if trafficRules.AllowedPorts == nil {
+					trafficRules.AllowedPorts = make([]market.PortRange, 0)
+				}
+			}
+			trafficRules.AllowedNetworks, trafficRules.AllowedPorts = appendTrafficRule(item.policy, rule, trafficRules.AllowedNetworks, trafficRules.AllowedPorts)
+		}
+		for _, rule := range item.rules.Deny {
+			trafficRules.DeniedNetworks, trafficRules.DeniedPorts = appendTrafficRule(item.policy, rule, trafficRules.DeniedNetworks, trafficRules.DeniedPorts)
+		}
+	}
+
+	return trafficRules
+}
+
+func appendTrafficRule(policy market.AccessPolicy, rule market.AccessRule, networks []net.IPNet, ports []market.PortRange) ([]net.IPNet, []market.PortRange) {
+	switch rule.Type {
+	case market.AccessPolicyTypeIPRange:
+		network, err := market.ParseIPRange(rule.Value)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Skipping invalid rule of policy %s", policy.ID)
+			return networks, ports
+		}
+		return append(networks, network), ports
+	case market.AccessPolicyTypePort:
+		portRange, err := market.ParsePortRange(rule.Value)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Skipping invalid rule of policy %s", policy.ID)
+			return networks, ports
+		}
+		return networks, append(ports, portRange)
+	}
+	return networks, ports
+}
+
+func isDNSRule(rule market.AccessRule) bool {
+	return rule.Type == market.AccessPolicyTypeDNSZone || rule.Type == market.AccessPolicyTypeDNSHostname
+}
+
+func hostMatches(rule market.AccessRule, host string) bool {
+	switch rule.Type {
+	case market.AccessPolicyTypeDNSZone:
+		return strings.HasSuffix(host, rule.Value)
+	case market.AccessPolicyTypeDNSHostname:
+		return host == rule.Value
+	}
+	return false
 }
 
 func (r *Repository) findItemFor(policy market.AccessPolicy) (*listItem, error) {
@@ -18,6 +18,7 @@
 package policy
 
 import (
+	"net"
 	"testing"
 
 	"github.com/mysteriumnetwork/node/identity"
@@ -133,6 +134,103 @@ func Test_Repository_Rules(t *testing.T) {
 	assert.Equal(t, []market.AccessPolicyRuleSet{policyOneRules, policyTwoRules}, repo.Rules())
 }
 
+func Test_Repository_TrafficRules(t *testing.T) {
+	repo := createFullRepo()
+	assert.False(t, repo.HasTrafficRules())
+	assert.True(t, repo.TrafficRules().Empty())
+
+	repo.SetPolicyRules(
+		policyThree,
+		market.AccessPolicyRuleSet{
+			ID:    "3",
+			Title: "Three",
+			Allow: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIPRange, Value: "10.0.0.0/8"},
+				{Type: market.AccessPolicyTypePort, Value: "tcp/443"},
+				{Type: market.AccessPolicyTypePort, Value: "invalid"},
+			},
+			Deny: []market.AccessRule{
+				{Type: market.AccessPolicyTypePort, Value: "25"},
+			},
+		},
+	)
+	assert.True(t, repo.HasTrafficRules())
+	assert.Equal(t, market.TrafficRules{
+		AllowedNetworks: []net.IPNet{{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}},
+		AllowedPorts:    []market.PortRange{{Protocol: market.ProtocolTCP, From: 443, To: 443}},
+		DeniedPorts:     []market.PortRange{{From: 25, To: 25}},
+	}, repo.TrafficRules())
+}
+
+func Test_Repository_TrafficRulesWithOnlyInvalidRulesAllowNothing(t *testing.T) {
+	repo := createEmptyRepo()
+	repo.SetPolicyRules(
+		policyThree,
+		market.AccessPolicyRuleSet{
+			ID:    "3",
+			Title: "Three",
+			Allow: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIPRange, Value: "10.0.0"},
+			},
+		},
+	)
+
+	rules := repo.TrafficRules()
+	assert.NotNil(t, rules.AllowedNetworks)
+	assert.Empty(t, rules.AllowedNetworks)
+	assert.Nil(t, rules.AllowedPorts)
+	assert.False(t, rules.Empty())
+}
+
+func Test_Repository_DenyRulesTakePrecedence(t *testing.T) {
+	repo := createEmptyRepo()
+	repo.SetPolicyRules(
+		policyThree,
+		market.AccessPolicyRuleSet{
+			ID:    "3",
+			Title: "Three",
+			Allow: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIdentity, Value: "0x1"},
+				{Type: market.AccessPolicyTypeDNSZone, Value: "example.com"},
+			},
+			Deny: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIdentity, Value: "0x1"},
+				{Type: market.AccessPolicyTypeDNSHostname, Value: "ads.example.com"},
+			},
+		},
+	)
+
+	assert.False(t, repo.IsIdentityAllowed(identity.FromAddress("0x1")))
+	assert.False(t, repo.IsIdentityAllowed(identity.FromAddress("0x2")))
+	assert.True(t, repo.IsHostAllowed("www.example.com"))
+	assert.False(t, repo.IsHostAllowed("ads.example.com"))
+	assert.True(t, repo.IsHostDenied("ads.example.com"))
+	assert.False(t, repo.IsHostAllowed("ipinfo.io"))
+	assert.False(t, repo.IsHostDenied("ipinfo.io"))
+}
+
+func Test_Repository_DenyOnlyRulesAllowEverythingElse(t *testing.T) {
+	repo := createEmptyRepo()
+	repo.SetPolicyRules(
+		policyThree,
+		market.AccessPolicyRuleSet{
+			ID:    "3",
+			Title: "Three",
+			Deny: []market.AccessRule{
+				{Type: market.AccessPolicyTypeIdentity, Value: "0x1"},
+				{Type: market.AccessPolicyTypeDNSZone, Value: "example.com"},
+			},
+		},
+	)
+
+	assert.True(t, repo.HasDNSRules())
+	assert.False(t, repo.HasDNSAllowRules())
+	assert.False(t, repo.IsIdentityAllowed(identity.FromAddress("0x1")))
+	assert.True(t, repo.IsIdentityAllowed(identity.FromAddress("0x2")))
+	assert.False(t, repo.IsHostAllowed("www.example.com"))
+	assert.True(t, repo.IsHostAllowed("ipinfo.io"))
+}
+
 func createEmptyRepo() *Repository {
 	return NewRepository()
 }
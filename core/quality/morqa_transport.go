@@ -37,6 +37,11 @@ type morqaTransport struct {
 }
 
 func (transport *morqaTransport) SendEvent(event Event) error {
+	// MORQA has no metric for connection probes, they are reported by other transports only.
+	if event.EventName == connectionProbeName {
+		return nil
+	}
+
 	if id, metric := mapEventToMetric(event); metric != nil {
 		return transport.morqaClient.SendMetric(id, metric)
 	}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"github.com/mysteriumnetwork/node/utils"
+)
+
+// NewMultiTransport creates transport which sends events through all given transports.
+func NewMultiTransport(transports ...Transport) *multiTransport {
+	return &multiTransport{
+		transports: transports,
+	}
+}
+
+type multiTransport struct {
+	transports []Transport
+}
+
+func (transport *multiTransport) SendEvent(event Event) error {
+	var errs utils.ErrorCollection
+	for _, t := range transport.transports {
+		errs.Add(t.SendEvent(event))
+	}
+
+	return errs.Errorf("failed to send event: %s", ", ")
+}
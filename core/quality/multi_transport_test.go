@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type transportMock struct {
+	events []Event
+	err    error
+}
+
+func (transport *transportMock) SendEvent(event Event) error {
+	transport.events = append(transport.events, event)
+	return transport.err
+}
+
+func TestMultiTransport_SendEvent(t *testing.T) {
+	first := &transportMock{err: errors.New("first failed")}
+	second := &transportMock{}
+	event := Event{EventName: unlockEventName}
+
+	err := NewMultiTransport(first, second).SendEvent(event)
+
+	assert.EqualError(t, err, "failed to send event: first failed")
+	assert.Equal(t, []Event{event}, first.events)
+	assert.Equal(t, []Event{event}, second.events)
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/pkg/errors"
+)
+
+const (
+	otlpTracesPath = "v1/traces"
+	otlpLogsPath   = "v1/logs"
+	otlpScopeName  = "github.com/mysteriumnetwork/node/core/quality"
+)
+
+// NewOTLPTransport creates transport allowing to export events to OpenTelemetry collector
+// using OTLP over HTTP with protobuf encoding. Trace events are exported as spans, other events as logs.
+func NewOTLPTransport(httpClient *requests.HTTPClient, url string) *otlpTransport {
+	return &otlpTransport{
+		httpClient: httpClient,
+		url:        strings.TrimSuffix(url, "/"),
+	}
+}
+
+type otlpTransport struct {
+	httpClient *requests.HTTPClient
+	url        string
+}
+
+func (transport *otlpTransport) SendEvent(event Event) error {
+	if ctx, ok := event.Context.(sessionTraceContext); ok && event.EventName == traceEventName {
+		span, err := traceContextToSpan(ctx)
+		if err != nil {
+			return err
+		}
+
+		return transport.export(otlpTracesPath, &pb.ExportTraceServiceRequest{
+			ResourceSpans: []*pb.ResourceSpans{{
+				Resource: appInfoToResource(event.Application),
+				ScopeSpans: []*pb.ScopeSpans{{
+					Scope: otlpScope(event.Application),
+					Spans: []*pb.Span{span},
+				}},
+			}},
+		})
+	}
+
+	record, err := eventToLogRecord(event)
+	if err != nil {
+		return err
+	}
+
+	return transport.export(otlpLogsPath, &pb.ExportLogsServiceRequest{
+		ResourceLogs: []*pb.ResourceLogs{{
+			Resource: appInfoToResource(event.Application),
+			ScopeLogs: []*pb.ScopeLogs{{
+				Scope:      otlpScope(event.Application),
+				LogRecords: []*pb.LogRecord{record},
+			}},
+		}},
+	})
+}
+
+func (transport *otlpTransport) export(path string, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal OTLP request")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", transport.url, path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	return transport.httpClient.DoRequest(req)
+}
+
+func appInfoToResource(info appInfo) *pb.Resource {
+	return &pb.Resource{
+		Attributes: []*pb.KeyValue{
+			stringAttribute("service.name", info.Name),
+			stringAttribute("service.version", info.Version),
+			stringAttribute("os.type", info.OS),
+			stringAttribute("host.arch", info.Arch),
+		},
+	}
+}
+
+func otlpScope(info appInfo) *pb.InstrumentationScope {
+	return &pb.InstrumentationScope{
+		Name:    otlpScopeName,
+		Version: info.Version,
+	}
+}
+
+func traceContextToSpan(ctx sessionTraceContext) (*pb.Span, error) {
+	traceID, err := hex.DecodeString(ctx.TraceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid trace ID")
+	}
+	spanID, err := hex.DecodeString(ctx.SpanID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid span ID")
+	}
+	parentSpanID, err := hex.DecodeString(ctx.ParentSpanID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid parent span ID")
+	}
+
+	return &pb.Span{
+		TraceId:           traceID,
+		SpanId:            spanID,
+		ParentSpanId:      parentSpanID,
+		Name:              ctx.Stage,
+		Kind:              pb.SpanKind_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(ctx.StartedAt.UnixNano()),
+		EndTimeUnixNano:   uint64(ctx.StartedAt.Add(ctx.Duration).UnixNano()),
+		Attributes:        sessionContextToAttributes(ctx.sessionContext),
+	}, nil
+}
+
+func sessionContextToAttributes(ctx sessionContext) []*pb.KeyValue {
+	return []*pb.KeyValue{
+		stringAttribute("session.id", ctx.ID),
+		stringAttribute("session.consumer_id", ctx.Consumer),
+		stringAttribute("session.provider_id", ctx.Provider),
+		stringAttribute("session.service_type", ctx.ServiceType),
+		stringAttribute("session.provider_country", ctx.ProviderCountry),
+		stringAttribute("session.consumer_country", ctx.ConsumerCountry),
+		stringAttribute("session.hermes_id", ctx.AccountantID),
+	}
+}
+
+func eventToLogRecord(event Event) (*pb.LogRecord, error) {
+	attributes, err := contextToAttributes(event.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	severity := pb.SeverityNumber_SEVERITY_NUMBER_INFO
+	if ctx, ok := event.Context.(natMappingContext); ok && !ctx.Successful {
+		severity = pb.SeverityNumber_SEVERITY_NUMBER_WARN
+	}
+
+	return &pb.LogRecord{
+		TimeUnixNano:   uint64(time.Unix(event.CreatedAt, 0).UnixNano()),
+		SeverityNumber: severity,
+		SeverityText:   strings.TrimPrefix(severity.String(), "SEVERITY_NUMBER_"),
+		Body:           &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: event.EventName}},
+		Attributes:     attributes,
+	}, nil
+}
+
+// contextToAttributes flattens JSON representation of event context into log attributes.
+func contextToAttributes(context interface{}) ([]*pb.KeyValue, error) {
+	if context == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(context)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal event context")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal event context")
+	}
+
+	var attributes []*pb.KeyValue
+	flattenAttributes("context", value, &attributes)
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes, nil
+}
+
+func flattenAttributes(key string, value interface{}, attributes *[]*pb.KeyValue) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, item := range v {
+			flattenAttributes(key+"."+k, item, attributes)
+		}
+	case string:
+		*attributes = append(*attributes, stringAttribute(key, v))
+	case bool:
+		*attributes = append(*attributes, &pb.KeyValue{Key: key, Value: &pb.AnyValue{Value: &pb.AnyValue_BoolValue{BoolValue: v}}})
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			*attributes = append(*attributes, &pb.KeyValue{Key: key, Value: &pb.AnyValue{Value: &pb.AnyValue_IntValue{IntValue: i}}})
+		} else if f, err := v.Float64(); err == nil {
+			*attributes = append(*attributes, &pb.KeyValue{Key: key, Value: &pb.AnyValue{Value: &pb.AnyValue_DoubleValue{DoubleValue: f}}})
+		} else {
+			*attributes = append(*attributes, stringAttribute(key, v.String()))
+		}
+	default:
+		data, _ := json.Marshal(v)
+		*attributes = append(*attributes, stringAttribute(key, string(data)))
+	}
+}
+
+func stringAttribute(key, value string) *pb.KeyValue {
+	return &pb.KeyValue{Key: key, Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: value}}}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/stretchr/testify/assert"
+)
+
+type otlpCollectorStub struct {
+	server *httptest.Server
+	traces []*pb.ExportTraceServiceRequest
+	logs   []*pb.ExportLogsServiceRequest
+}
+
+func newOTLPCollectorStub(t *testing.T) *otlpCollectorStub {
+	collector := &otlpCollectorStub{}
+	collector.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/v1/traces":
+			var req pb.ExportTraceServiceRequest
+			assert.NoError(t, proto.Unmarshal(body, &req))
+			collector.traces = append(collector.traces, &req)
+		case "/v1/logs":
+			var req pb.ExportLogsServiceRequest
+			assert.NoError(t, proto.Unmarshal(body, &req))
+			collector.logs = append(collector.logs, &req)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return collector
+}
+
+func attributesToMap(attributes []*pb.KeyValue) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, kv := range attributes {
+		switch v := kv.Value.Value.(type) {
+		case *pb.AnyValue_StringValue:
+			result[kv.Key] = v.StringValue
+		case *pb.AnyValue_BoolValue:
+			result[kv.Key] = v.BoolValue
+		case *pb.AnyValue_IntValue:
+			result[kv.Key] = v.IntValue
+		case *pb.AnyValue_DoubleValue:
+			result[kv.Key] = v.DoubleValue
+		}
+	}
+	return result
+}
+
+var otlpTestApp = appInfo{Name: "myst", Version: "1.0.0", OS: runtime.GOOS, Arch: runtime.GOARCH}
+
+func TestOTLPTransport_SendEvent_ExportsTraceAsSpan(t *testing.T) {
+	collector := newOTLPCollectorStub(t)
+	defer collector.server.Close()
+
+	startedAt := time.Unix(1600000000, 0)
+	transport := NewOTLPTransport(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), collector.server.URL+"/")
+	err := transport.SendEvent(Event{
+		Application: otlpTestApp,
+		EventName:   traceEventName,
+		Context: sessionTraceContext{
+			Duration:       time.Second,
+			Stage:          "Provider whole Connect",
+			StartedAt:      startedAt,
+			TraceID:        "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:         "00f067aa0ba902b7",
+			ParentSpanID:   "00f067aa0ba902b8",
+			sessionContext: sessionContext{ID: "session-1", ServiceType: "wireguard"},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, collector.traces, 1)
+	assert.Len(t, collector.logs, 0)
+	resourceSpans := collector.traces[0].ResourceSpans[0]
+	assert.Equal(t, "myst", attributesToMap(resourceSpans.Resource.Attributes)["service.name"])
+
+	span := resourceSpans.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "Provider whole Connect", span.Name)
+	assert.Equal(t, []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}, span.TraceId)
+	assert.Equal(t, []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}, span.SpanId)
+	assert.Equal(t, []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb8}, span.ParentSpanId)
+	assert.Equal(t, uint64(startedAt.UnixNano()), span.StartTimeUnixNano)
+	assert.Equal(t, uint64(startedAt.Add(time.Second).UnixNano()), span.EndTimeUnixNano)
+
+	attributes := attributesToMap(span.Attributes)
+	assert.Equal(t, "session-1", attributes["session.id"])
+	assert.Equal(t, "wireguard", attributes["session.service_type"])
+}
+
+func TestOTLPTransport_SendEvent_ExportsEventAsLog(t *testing.T) {
+	collector := newOTLPCollectorStub(t)
+	defer collector.server.Close()
+
+	errorMessage := "no gateway"
+	transport := NewOTLPTransport(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), collector.server.URL)
+	err := transport.SendEvent(Event{
+		Application: otlpTestApp,
+		EventName:   natMappingEventName,
+		CreatedAt:   1600000000,
+		Context: natMappingContext{
+			Stage:        "port_mapping",
+			Successful:   false,
+			ErrorMessage: &errorMessage,
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, collector.traces, 0)
+	assert.Len(t, collector.logs, 1)
+	record := collector.logs[0].ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, natMappingEventName, record.Body.GetStringValue())
+	assert.Equal(t, pb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber)
+	assert.Equal(t, uint64(time.Unix(1600000000, 0).UnixNano()), record.TimeUnixNano)
+	assert.Equal(t, map[string]interface{}{
+		"context.stage":         "port_mapping",
+		"context.successful":    false,
+		"context.error_message": "no gateway",
+	}, attributesToMap(record.Attributes))
+}
+
+func TestOTLPTransport_SendEvent_WithUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := NewOTLPTransport(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), server.URL)
+	err := transport.SendEvent(Event{Application: otlpTestApp, EventName: unlockEventName, Context: "0x1"})
+
+	assert.Error(t, err)
+}
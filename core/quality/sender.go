@@ -26,6 +26,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
@@ -44,6 +45,7 @@ const (
 	unlockEventName     = "unlock"
 	proposalEventName   = "proposal_event"
 	natMappingEventName = "nat_mapping"
+	connectionProbeName = "connection_probe"
 )
 
 // Transport allows sending events
@@ -107,9 +109,22 @@ type sessionTokensContext struct {
 	sessionContext
 }
 
+type connectionProbeContext struct {
+	Latency    time.Duration
+	Jitter     time.Duration
+	PacketLoss float64
+	// Download and Upload speeds are in bits per second.
+	Download, Upload uint64
+	sessionContext
+}
+
 type sessionTraceContext struct {
-	Duration time.Duration
-	Stage    string
+	Duration     time.Duration
+	Stage        string
+	StartedAt    time.Time
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
 	sessionContext
 }
 
@@ -146,6 +161,9 @@ func (sender *Sender) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.SubscribeAsync(trace.AppTopicTraceEvent, sender.sendTraceEvent); err != nil {
 		return err
 	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionProbe, sender.sendConnectionProbe); err != nil {
+		return err
+	}
 
 	return bus.SubscribeAsync(identity.AppTopicIdentityUnlock, sender.sendUnlockEvent)
 }
@@ -163,6 +181,22 @@ func (sender *Sender) sendSessionData(e connectionstate.AppEventConnectionStatis
 	})
 }
 
+// sendConnectionProbe sends connection quality measured by active probe.
+func (sender *Sender) sendConnectionProbe(e connectionstate.AppEventConnectionProbe) {
+	if e.SessionInfo.SessionID == "" {
+		return
+	}
+
+	sender.sendEvent(connectionProbeName, connectionProbeContext{
+		Latency:        e.Result.Latency,
+		Jitter:         e.Result.Jitter,
+		PacketLoss:     e.Result.PacketLoss,
+		Download:       datasize.BitSize(e.Result.Download).Bits(),
+		Upload:         datasize.BitSize(e.Result.Upload).Bits(),
+		sessionContext: sender.toSessionContext(e.SessionInfo),
+	})
+}
+
 func (sender *Sender) sendSessionEarning(e pingpongEvent.AppEventInvoicePaid) {
 	session, err := sender.recoverSessionContext(e.SessionID)
 	if err != nil {
@@ -251,11 +285,18 @@ func (sender *Sender) sendTraceEvent(stage trace.Event) {
 		return
 	}
 
-	sender.sendEvent(traceEventName, sessionTraceContext{
+	context := sessionTraceContext{
 		Duration:       stage.Duration,
 		Stage:          stage.Key,
+		StartedAt:      stage.Start,
+		TraceID:        stage.TraceID.String(),
+		SpanID:         stage.SpanID.String(),
 		sessionContext: session,
-	})
+	}
+	if stage.ParentSpanID.IsValid() {
+		context.ParentSpanID = stage.ParentSpanID.String()
+	}
+	sender.sendEvent(traceEventName, context)
 }
 
 // SendNATMappingSuccessEvent sends event about successful NAT mapping
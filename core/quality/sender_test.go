@@ -21,10 +21,22 @@ import (
 	"errors"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/stretchr/testify/assert"
 )
 
+type mockServiceDefinition struct {
+	location market.Location
+}
+
+func (service mockServiceDefinition) GetLocation() market.Location {
+	return service.location
+}
+
 type mockEventsTransport struct {
 	sentEvent    Event
 	mockResponse error
@@ -85,3 +97,45 @@ func TestSender_SendNATMappingFailEvent_SendsToTransport(t *testing.T) {
 	assert.Equal(t, "hole_punching", c.Stage)
 	assert.Equal(t, mockGateways, c.Gateways)
 }
+
+func TestSender_SendConnectionProbe_SendsToTransport(t *testing.T) {
+	mockTransport := buildMockEventsTransport(nil)
+	sender := &Sender{Transport: mockTransport, AppVersion: "test version"}
+
+	sender.sendConnectionProbe(connectionstate.AppEventConnectionProbe{
+		Result: connectionstate.ProbeResult{
+			Latency:    40 * time.Millisecond,
+			Jitter:     5 * time.Millisecond,
+			PacketLoss: 0.1,
+			Download:   datasize.BitSpeed(10 * datasize.MiB),
+		},
+		SessionInfo: connectionstate.Status{
+			SessionID: "session1",
+			Proposal: market.ServiceProposal{
+				ProviderID:        "0x1",
+				ServiceType:       "wireguard",
+				ServiceDefinition: mockServiceDefinition{location: market.Location{Country: "LT"}},
+			},
+		},
+	})
+
+	sentEvent := mockTransport.sentEvent
+	assert.Equal(t, "connection_probe", sentEvent.EventName)
+	c := sentEvent.Context.(connectionProbeContext)
+	assert.Equal(t, 40*time.Millisecond, c.Latency)
+	assert.Equal(t, 5*time.Millisecond, c.Jitter)
+	assert.Equal(t, 0.1, c.PacketLoss)
+	assert.Equal(t, datasize.MiB.Bits()*10, c.Download)
+	assert.Zero(t, c.Upload)
+	assert.Equal(t, "session1", c.ID)
+	assert.Equal(t, "LT", c.ProviderCountry)
+}
+
+func TestSender_SendConnectionProbe_SkipsProbeWithoutSession(t *testing.T) {
+	mockTransport := buildMockEventsTransport(nil)
+	sender := &Sender{Transport: mockTransport, AppVersion: "test version"}
+
+	sender.sendConnectionProbe(connectionstate.AppEventConnectionProbe{})
+
+	assert.Empty(t, mockTransport.sentEvent.EventName)
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// AdmissionConfig contains limits of sessions admitted by a single service instance.
+// Zero value of a limit means that the limit is not applied.
+type AdmissionConfig struct {
+	MaxSessions            int
+	MaxSessionsPerConsumer int
+	MaxSessionsPerMinute   int
+	// MaxCPUPercent is a machine CPU usage watermark above which new sessions are rejected.
+	MaxCPUPercent float64
+	// MaxBandwidthMbps is a machine network throughput watermark above which new sessions are rejected.
+	MaxBandwidthMbps float64
+}
+
+// LoadMonitor reports current load of the provider machine.
+type LoadMonitor interface {
+	CPUPercent() float64
+	BandwidthMbps() float64
+}
+
+// admission decides whether new sessions are admitted by the service instance.
+type admission struct {
+	config AdmissionConfig
+	load   LoadMonitor
+	now    func() time.Time
+
+	lock     sync.Mutex
+	admitted []time.Time
+}
+
+func newAdmission(config AdmissionConfig, load LoadMonitor) *admission {
+	return &admission{
+		config: config,
+		load:   load,
+		now:    time.Now,
+	}
+}
+
+// admit checks the session against admission limits and adds it to the pool if it was admitted.
+// All sessions in the pool are counted, so stale sessions have to be removed from it beforehand.
+func (a *admission) admit(session *Session, pool *SessionPool) *pb.SessionRejection {
+	if a == nil {
+		pool.Add(session)
+		return nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if rejection := a.check(session, pool.GetAll()); rejection != nil {
+		return rejection
+	}
+
+	a.admitted = append(a.admitted, a.now())
+	pool.Add(session)
+	return nil
+}
+
+func (a *admission) check(session *Session, sessions []*Session) *pb.SessionRejection {
+	var serviceSessions, consumerSessions int
+	for _, s := range sessions {
+		if s.ConsumerID == session.ConsumerID {
+			consumerSessions++
+		}
+		if s.ServiceID == session.ServiceID {
+			serviceSessions++
+		}
+	}
+
+	if a.config.MaxSessions > 0 && serviceSessions >= a.config.MaxSessions {
+		return rejection(pb.RejectionReason_MAX_SESSIONS, "service reached max sessions limit: %d", a.config.MaxSessions)
+	}
+	if a.config.MaxSessionsPerConsumer > 0 && consumerSessions >= a.config.MaxSessionsPerConsumer {
+		return rejection(pb.RejectionReason_MAX_CONSUMER_SESSIONS, "consumer reached max sessions limit: %d", a.config.MaxSessionsPerConsumer)
+	}
+	if a.config.MaxSessionsPerMinute > 0 && a.recentlyAdmitted() >= a.config.MaxSessionsPerMinute {
+		return rejection(pb.RejectionReason_SESSION_RATE, "service reached new sessions per minute limit: %d", a.config.MaxSessionsPerMinute)
+	}
+	if a.load == nil {
+		return nil
+	}
+	if a.config.MaxCPUPercent > 0 {
+		if usage := a.load.CPUPercent(); usage >= a.config.MaxCPUPercent {
+			return rejection(pb.RejectionReason_CPU_OVERLOAD, "CPU usage %.1f%% is above %.1f%%", usage, a.config.MaxCPUPercent)
+		}
+	}
+	if a.config.MaxBandwidthMbps > 0 {
+		if usage := a.load.BandwidthMbps(); usage >= a.config.MaxBandwidthMbps {
+			return rejection(pb.RejectionReason_BANDWIDTH_OVERLOAD, "bandwidth usage %.1f Mbps is above %.1f Mbps", usage, a.config.MaxBandwidthMbps)
+		}
+	}
+
+	return nil
+}
+
+// recentlyAdmitted drops admissions older than a minute and returns the count of remaining ones.
+func (a *admission) recentlyAdmitted() int {
+	since := a.now().Add(-time.Minute)
+
+	i := 0
+	for i < len(a.admitted) && !a.admitted[i].After(since) {
+		i++
+	}
+	a.admitted = a.admitted[i:]
+
+	return len(a.admitted)
+}
+
+func rejection(reason pb.RejectionReason, format string, args ...interface{}) *pb.SessionRejection {
+	return &pb.SessionRejection{
+		Reason:  reason,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
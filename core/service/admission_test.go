@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/mocks"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLoadMonitor struct {
+	cpu, bandwidth float64
+}
+
+func (m *mockLoadMonitor) CPUPercent() float64 {
+	return m.cpu
+}
+
+func (m *mockLoadMonitor) BandwidthMbps() float64 {
+	return m.bandwidth
+}
+
+func admissionSession(serviceID, serviceType, consumer string) *Session {
+	return &Session{
+		ID:         session.ID(serviceID + "-" + consumer),
+		ServiceID:  serviceID,
+		ConsumerID: identity.FromAddress(consumer),
+		Proposal:   market.ServiceProposal{ServiceType: serviceType},
+	}
+}
+
+func TestAdmission_Admit(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   AdmissionConfig
+		load     LoadMonitor
+		existing []*Session
+		session  *Session
+		reason   pb.RejectionReason
+	}{
+		{
+			name:     "admits without limits",
+			existing: []*Session{admissionSession("1", "wireguard", "0x2")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+		},
+		{
+			name:     "rejects when service is full",
+			config:   AdmissionConfig{MaxSessions: 1},
+			existing: []*Session{admissionSession("1", "wireguard", "0x2")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+			reason:   pb.RejectionReason_MAX_SESSIONS,
+		},
+		{
+			name:     "does not count sessions of other services",
+			config:   AdmissionConfig{MaxSessions: 1},
+			existing: []*Session{admissionSession("2", "openvpn", "0x2")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+		},
+		{
+			name:     "admits when consumer is below sessions limit",
+			config:   AdmissionConfig{MaxSessionsPerConsumer: 3},
+			existing: []*Session{admissionSession("2", "openvpn", "0x1"), admissionSession("3", "wireguard", "0x1")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+		},
+		{
+			name:     "counts all sessions of the consumer",
+			config:   AdmissionConfig{MaxSessionsPerConsumer: 2},
+			existing: []*Session{admissionSession("2", "openvpn", "0x1"), admissionSession("3", "wireguard", "0x1")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+			reason:   pb.RejectionReason_MAX_CONSUMER_SESSIONS,
+		},
+		{
+			name:     "rejects when consumer has too many sessions",
+			config:   AdmissionConfig{MaxSessionsPerConsumer: 1},
+			existing: []*Session{admissionSession("2", "openvpn", "0x1")},
+			session:  admissionSession("1", "wireguard", "0x1"),
+			reason:   pb.RejectionReason_MAX_CONSUMER_SESSIONS,
+		},
+		{
+			name:    "rejects when CPU is overloaded",
+			config:  AdmissionConfig{MaxCPUPercent: 90},
+			load:    &mockLoadMonitor{cpu: 95},
+			session: admissionSession("1", "wireguard", "0x1"),
+			reason:  pb.RejectionReason_CPU_OVERLOAD,
+		},
+		{
+			name:    "rejects when bandwidth is overloaded",
+			config:  AdmissionConfig{MaxCPUPercent: 90, MaxBandwidthMbps: 100},
+			load:    &mockLoadMonitor{cpu: 10, bandwidth: 120},
+			session: admissionSession("1", "wireguard", "0x1"),
+			reason:  pb.RejectionReason_BANDWIDTH_OVERLOAD,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewSessionPool(mocks.NewEventBus())
+			for _, s := range tt.existing {
+				pool.Add(s)
+			}
+
+			rejection := newAdmission(tt.config, tt.load).admit(tt.session, pool)
+			assert.Equal(t, tt.reason, rejection.GetReason())
+
+			_, found := pool.Find(tt.session.ID)
+			assert.Equal(t, rejection == nil, found)
+		})
+	}
+}
+
+func TestAdmission_Admit_LimitsSessionRate(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	admission := newAdmission(AdmissionConfig{MaxSessionsPerMinute: 2}, nil)
+	admission.now = func() time.Time { return now }
+	pool := NewSessionPool(mocks.NewEventBus())
+
+	assert.Nil(t, admission.admit(admissionSession("1", "wireguard", "0x1"), pool))
+	now = now.Add(30 * time.Second)
+	assert.Nil(t, admission.admit(admissionSession("1", "wireguard", "0x2"), pool))
+	assert.Equal(t, pb.RejectionReason_SESSION_RATE, admission.admit(admissionSession("1", "wireguard", "0x3"), pool).GetReason())
+
+	now = now.Add(31 * time.Second)
+	assert.Nil(t, admission.admit(admissionSession("1", "wireguard", "0x3"), pool))
+}
+
+func TestAdmission_Admit_WithoutAdmission(t *testing.T) {
+	var admission *admission
+	pool := NewSessionPool(mocks.NewEventBus())
+	sess := admissionSession("1", "wireguard", "0x1")
+
+	assert.Nil(t, admission.admit(sess, pool))
+	_, found := pool.Find(sess.ID)
+	assert.True(t, found)
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shirou/gopsutil/cpu"
+	gonet "github.com/shirou/gopsutil/net"
+)
+
+// SystemLoadMonitor periodically samples CPU and network usage of the machine.
+type SystemLoadMonitor struct {
+	interval time.Duration
+
+	lock      sync.RWMutex
+	cpu       float64
+	bandwidth float64
+
+	lastBytes   uint64
+	lastSampled time.Time
+
+	once sync.Once
+	stop chan struct{}
+}
+
+// NewSystemLoadMonitor creates a load monitor sampling machine usage at the given interval.
+func NewSystemLoadMonitor(interval time.Duration) *SystemLoadMonitor {
+	return &SystemLoadMonitor{
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start starts sampling the machine load in background.
+func (m *SystemLoadMonitor) Start() {
+	m.sample()
+	go func() {
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-time.After(m.interval):
+				m.sample()
+			}
+		}
+	}()
+}
+
+// Stop stops sampling the machine load.
+func (m *SystemLoadMonitor) Stop() {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+}
+
+// CPUPercent returns CPU usage of the machine during the last sampling interval.
+func (m *SystemLoadMonitor) CPUPercent() float64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.cpu
+}
+
+// BandwidthMbps returns network throughput of the machine during the last sampling interval.
+func (m *SystemLoadMonitor) BandwidthMbps() float64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.bandwidth
+}
+
+func (m *SystemLoadMonitor) sample() {
+	usage, err := cpu.Percent(0, false)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to sample CPU usage")
+	}
+	bytes, err := transferredBytes()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to sample network usage")
+	}
+	now := time.Now()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(usage) > 0 {
+		m.cpu = usage[0]
+	}
+	if !m.lastSampled.IsZero() && bytes >= m.lastBytes {
+		elapsed := now.Sub(m.lastSampled).Seconds()
+		if elapsed > 0 {
+			m.bandwidth = float64(bytes-m.lastBytes) * 8 / elapsed / 1e6
+		}
+	}
+	m.lastBytes = bytes
+	m.lastSampled = now
+}
+
+// transferredBytes returns total bytes sent and received through non loopback interfaces.
+func transferredBytes() (uint64, error) {
+	counters, err := gonet.IOCounters(true)
+	if err != nil {
+		return 0, err
+	}
+
+	loopback := make(map[string]bool)
+	if ifaces, err := net.Interfaces(); err == nil {
+		for _, iface := range ifaces {
+			loopback[iface.Name] = iface.Flags&net.FlagLoopback != 0
+		}
+	}
+
+	var total uint64
+	for _, c := range counters {
+		if loopback[c.Name] {
+			continue
+		}
+		total += c.BytesSent + c.BytesRecv
+	}
+	return total, nil
+}
@@ -67,9 +67,12 @@ func NewManager(
 	discoveryFactory DiscoveryFactory,
 	eventPublisher Publisher,
 	policyOracle *policy.Oracle,
+	localPolicies *policy.LocalPolicies,
 	p2pListener p2p.Listener,
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager,
 	statusStorage connectivity.StatusStorage,
+	admissionConfig AdmissionConfig,
+	loadMonitor LoadMonitor,
 ) *Manager {
 	return &Manager{
 		serviceRegistry:  serviceRegistry,
@@ -77,9 +80,12 @@ func NewManager(
 		discoveryFactory: discoveryFactory,
 		eventPublisher:   eventPublisher,
 		policyOracle:     policyOracle,
+		localPolicies:    localPolicies,
 		p2pListener:      p2pListener,
 		sessionManager:   sessionManager,
 		statusStorage:    statusStorage,
+		admissionConfig:  admissionConfig,
+		loadMonitor:      loadMonitor,
 	}
 }
 
@@ -91,10 +97,14 @@ type Manager struct {
 	discoveryFactory DiscoveryFactory
 	eventPublisher   Publisher
 	policyOracle     *policy.Oracle
+	localPolicies    *policy.LocalPolicies
 
 	p2pListener    p2p.Listener
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager
 	statusStorage  connectivity.StatusStorage
+
+	admissionConfig AdmissionConfig
+	loadMonitor     LoadMonitor
 }
 
 // Start starts an instance of the given service type if knows one in service registry.
@@ -110,15 +120,15 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 	proposal.SetAccessPolicies(nil)
 	policyRules := policy.NewRepository()
 	if len(policyIDs) > 0 {
-		policies := manager.policyOracle.Policies(policyIDs)
-		if err = manager.policyOracle.SubscribePolicies(policies, policyRules); err != nil {
+		policies, err := manager.subscribePolicies(policyIDs, policyRules)
+		if err != nil {
 			log.Warn().Err(err).Msg("Can't find given access policies")
 			return id, ErrUnsupportedAccessPolicy
 		}
 		proposal.SetAccessPolicies(&policies)
 	}
 
-	proposal.SetProviderContacts(providerID, market.ContactList{manager.p2pListener.GetContact()})
+	proposal.SetProviderContacts(providerID, manager.p2pListener.GetContacts())
 
 	id, err = generateID()
 	if err != nil {
@@ -139,6 +149,7 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		policies:       policyRules,
 		discovery:      discovery,
 		eventPublisher: manager.eventPublisher,
+		admission:      newAdmission(manager.admissionConfig, manager.loadMonitor),
 	}
 
 	channelHandlers := func(ch p2p.Channel) {
@@ -171,6 +182,9 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		if stopErr != nil {
 			log.Error().Err(stopErr).Msg("Service stop failed")
 		}
+		if manager.localPolicies != nil {
+			manager.localPolicies.UnsubscribePolicies(policyRules)
+		}
 
 		discovery.Wait()
 	}()
@@ -180,6 +194,34 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 	return id, nil
 }
 
+// subscribePolicies fills repository with rules of local policies and policies fetched from the policy Oracle.
+func (manager *Manager) subscribePolicies(policyIDs []string, repository *policy.Repository) ([]market.AccessPolicy, error) {
+	var localPolicies []market.AccessPolicy
+	var remoteIDs []string
+	for _, policyID := range policyIDs {
+		if manager.localPolicies != nil && manager.localPolicies.Has(policyID) {
+			localPolicies = append(localPolicies, manager.localPolicies.Policy(policyID))
+		} else {
+			remoteIDs = append(remoteIDs, policyID)
+		}
+	}
+
+	var policies []market.AccessPolicy
+	if len(remoteIDs) > 0 {
+		policies = manager.policyOracle.Policies(remoteIDs)
+		if err := manager.policyOracle.SubscribePolicies(policies, repository); err != nil {
+			return nil, err
+		}
+	}
+	if len(localPolicies) > 0 {
+		if err := manager.localPolicies.SubscribePolicies(localPolicies, repository); err != nil {
+			return nil, err
+		}
+		policies = append(policies, localPolicies...)
+	}
+	return policies, nil
+}
+
 func generateID() (ID, error) {
 	uid, err := uuid.NewV4()
 	if err != nil {
@@ -19,6 +19,8 @@ package service
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -57,7 +59,8 @@ func TestManager_StartRemovesServiceFromPoolIfServiceCrashes(t *testing.T) {
 		discoveryFactory,
 		mocks.NewEventBus(),
 		mockPolicyOracle,
-		&mockP2PListener{}, nil, nil,
+		nil,
+		&mockP2PListener{}, nil, nil, AdmissionConfig{}, nil,
 	)
 	_, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{}, nil)
 	assert.Nil(t, err)
@@ -81,7 +84,8 @@ func TestManager_StartDoesNotCrashIfStoppedByUser(t *testing.T) {
 		discoveryFactory,
 		mocks.NewEventBus(),
 		mockPolicyOracle,
-		&mockP2PListener{}, nil, nil,
+		nil,
+		&mockP2PListener{}, nil, nil, AdmissionConfig{}, nil,
 	)
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{}, nil)
 	assert.Nil(t, err)
@@ -91,6 +95,49 @@ func TestManager_StartDoesNotCrashIfStoppedByUser(t *testing.T) {
 	assert.Len(t, manager.servicePool.List(), 0)
 }
 
+func TestManager_StartSubscribesLocalPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localPoliciesTest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	localPolicies := policy.NewLocalPolicies(dir, time.Minute)
+	rules := market.AccessPolicyRuleSet{
+		ID:    "office",
+		Allow: []market.AccessRule{{Type: market.AccessPolicyTypeIPRange, Value: "10.0.0.0/8"}},
+	}
+	assert.NoError(t, localPolicies.Save(rules))
+
+	registry := NewRegistry()
+	mockCopy := *serviceMock
+	mockCopy.mockProcess = make(chan struct{})
+	registry.Register(serviceType, func(options Options) (Service, market.ServiceProposal, error) {
+		return &mockCopy, proposalMock, nil
+	})
+
+	discovery := mockDiscovery{}
+	discoveryFactory := MockDiscoveryFactoryFunc(&discovery)
+	manager := NewManager(
+		registry,
+		discoveryFactory,
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		localPolicies,
+		&mockP2PListener{}, nil, nil, AdmissionConfig{}, nil,
+	)
+	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, []string{"office"}, struct{}{}, nil)
+	assert.NoError(t, err)
+
+	instance := manager.servicePool.Instance(id)
+	assert.Equal(t, &[]market.AccessPolicy{{ID: "office", Source: policy.SourceLocal}}, instance.Proposal.AccessPolicies)
+	assert.Equal(t, []market.AccessPolicyRuleSet{rules}, instance.Policies().Rules())
+	assert.Equal(t, policy.ErrLocalPolicyInUse, localPolicies.Delete("office"))
+
+	assert.NoError(t, manager.Stop(id))
+	discovery.Wait()
+	assert.Eventually(t, func() bool {
+		return localPolicies.Delete("office") == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 	registry := NewRegistry()
 	mockCopy := *serviceMock
@@ -107,7 +154,8 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 		discoveryFactory,
 		eventBus,
 		mockPolicyOracle,
-		&mockP2PListener{}, nil, nil,
+		nil,
+		&mockP2PListener{}, nil, nil, AdmissionConfig{}, nil,
 	)
 
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{}, nil)
@@ -148,8 +196,8 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 type mockP2PListener struct {
 }
 
-func (m mockP2PListener) GetContact() market.Contact {
-	return market.Contact{}
+func (m mockP2PListener) GetContacts() market.ContactList {
+	return market.ContactList{}
 }
 
 func (m mockP2PListener) Listen(providerID identity.Identity, serviceType string, channelHandler func(ch p2p.Channel)) (func(), error) {
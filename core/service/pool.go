@@ -153,6 +153,7 @@ type Instance struct {
 	policies        *policy.Repository
 	discovery       Discovery
 	eventPublisher  Publisher
+	admission       *admission
 	p2pChannelsLock sync.Mutex
 	p2pChannels     []p2p.Channel
 }
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+const (
+	// maxProbeDownloadSize limits the payload size a consumer can request in a single probe download.
+	maxProbeDownloadSize = 256 * 1024
+	// probeTrafficBudget limits the total payload size downloaded and uploaded by a session within
+	// probeBudgetInterval, since traffic of the p2p channel is not paid for.
+	probeTrafficBudget  = 4 * 1024 * 1024
+	probeBudgetInterval = time.Minute
+)
+
+// errProbeBudgetExceeded is returned when session transfers more probe payload than allowed,
+// consumer stops the throughput test once it gets it.
+var errProbeBudgetExceeded = p2p.Errorf(pb.ErrorCode_UNAVAILABLE, "probe traffic budget exceeded")
+
+// handleProbes registers handlers which allow consumer to measure connection quality.
+func handleProbes(channel p2p.ChannelHandler) {
+	handler := &probeHandler{budget: newProbeBudget(probeTrafficBudget, probeBudgetInterval)}
+
+	p2p.HandleProbePing(channel, func(_ context.Context, ping *pb.ProbePing) (*pb.ProbePing, error) {
+		return ping, nil
+	})
+	p2p.HandleProbeDownload(channel, handler.download)
+	p2p.HandleProbeUpload(channel, handler.upload)
+}
+
+// probeHandler serves throughput probes of a session within its traffic budget.
+type probeHandler struct {
+	budget *probeBudget
+}
+
+func (h *probeHandler) download(_ context.Context, req *pb.ProbeDownloadRequest) (*pb.ProbeData, error) {
+	size := req.Size
+	if size > maxProbeDownloadSize {
+		size = maxProbeDownloadSize
+	}
+	if !h.budget.take(int(size)) {
+		return nil, errProbeBudgetExceeded
+	}
+	// Payload is zero filled since channel transport is text based and escapes line endings.
+	return &pb.ProbeData{Data: make([]byte, size)}, nil
+}
+
+func (h *probeHandler) upload(_ context.Context, data *pb.ProbeData) error {
+	if !h.budget.take(len(data.Data)) {
+		return errProbeBudgetExceeded
+	}
+	return nil
+}
+
+// probeBudget limits the payload size served within an interval.
+type probeBudget struct {
+	limit    int
+	interval time.Duration
+	now      func() time.Time
+
+	lock  sync.Mutex
+	start time.Time
+	used  int
+}
+
+func newProbeBudget(limit int, interval time.Duration) *probeBudget {
+	return &probeBudget{
+		limit:    limit,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// take reserves size bytes of the budget, it returns false if the budget of current interval is exhausted.
+func (b *probeBudget) take(size int) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if now := b.now(); now.Sub(b.start) >= b.interval {
+		b.start = now
+		b.used = 0
+	}
+	if b.used+size > b.limit {
+		return false
+	}
+	b.used += size
+	return true
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeBudget_Take(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := newProbeBudget(3*maxProbeDownloadSize, time.Minute)
+	budget.now = func() time.Time { return now }
+
+	assert.True(t, budget.take(maxProbeDownloadSize))
+	assert.True(t, budget.take(2*maxProbeDownloadSize))
+	assert.False(t, budget.take(1))
+
+	now = now.Add(30 * time.Second)
+	assert.False(t, budget.take(1))
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, budget.take(3*maxProbeDownloadSize))
+	assert.False(t, budget.take(1))
+}
+
+func TestProbeHandler_UploadIsChargedAgainstBudget(t *testing.T) {
+	handler := &probeHandler{budget: newProbeBudget(3*maxProbeDownloadSize, time.Minute)}
+
+	_, err := handler.download(context.Background(), &pb.ProbeDownloadRequest{Size: maxProbeDownloadSize})
+	assert.NoError(t, err)
+	assert.NoError(t, handler.upload(context.Background(), &pb.ProbeData{Data: make([]byte, maxProbeDownloadSize)}))
+	assert.NoError(t, handler.upload(context.Background(), &pb.ProbeData{Data: make([]byte, maxProbeDownloadSize)}))
+
+	assert.Equal(t, errProbeBudgetExceeded, handler.upload(context.Background(), &pb.ProbeData{Data: make([]byte, 1)}))
+	_, err = handler.download(context.Background(), &pb.ProbeDownloadRequest{Size: 1})
+	assert.Equal(t, errProbeBudgetExceeded, err)
+}
@@ -18,6 +18,7 @@
 package service
 
 import (
+	"net"
 	"sync"
 	"time"
 
@@ -41,6 +42,8 @@ type Session struct {
 	Proposal         market.ServiceProposal
 	ServiceID        string
 	CreatedAt        time.Time
+	Interface        string
+	ConsumerTunnelIP net.IP
 	request          *pb.SessionRequest
 	done             chan struct{}
 	cleanupLock      sync.Mutex
@@ -90,6 +93,8 @@ func (s *Session) toEvent(status event.Status) event.AppEventSession {
 			ConsumerLocation: s.ConsumerLocation,
 			HermesID:         s.HermesID,
 			Proposal:         s.Proposal,
+			Interface:        s.Interface,
+			ConsumerTunnelIP: s.ConsumerTunnelIP,
 		},
 	}
 }
@@ -53,6 +53,9 @@ type IDGenerator func() (session.ID, error)
 type ConfigParams struct {
 	SessionServiceConfig   ServiceConfiguration
 	SessionDestroyCallback DestroyCallback
+	// SessionInterface and ConsumerTunnelIP identify session traffic, services without a tunnel per consumer leave them empty
+	SessionInterface string
+	ConsumerTunnelIP net.IP
 }
 
 // ServiceConfiguration defines service configuration from underlying transport mechanism to be passed to remote party
@@ -172,9 +175,14 @@ func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionRe
 		log.Debug().Msgf("Provider connection trace: %s", traceResult)
 	}()
 
-	if err = manager.startSession(session); err != nil {
+	rejection, err := manager.startSession(session)
+	if err != nil {
 		return pb.SessionResponse{}, err
 	}
+	if rejection != nil {
+		manager.reject(session, rejection)
+		return pb.SessionResponse{Rejection: rejection}, nil
+	}
 	if err = manager.paymentLoop(session); err != nil {
 		return pb.SessionResponse{}, err
 	}
@@ -196,17 +204,19 @@ func (manager *SessionManager) Acknowledge(consumerID identity.Identity, session
 	return nil
 }
 
-func (manager *SessionManager) startSession(session *Session) error {
+func (manager *SessionManager) startSession(session *Session) (*pb.SessionRejection, error) {
 	trace := session.tracer.StartStage("Provider session create (start)")
 	defer session.tracer.EndStage(trace)
 
 	if err := manager.validateSession(session); err != nil {
-		return err
+		return nil, err
 	}
 
 	manager.clearStaleSession(session.ConsumerID, manager.service.Type)
 
-	manager.sessionStorage.Add(session)
+	if rejection := manager.service.admission.admit(session, manager.sessionStorage); rejection != nil {
+		return rejection, nil
+	}
 	session.addCleanup(func() error {
 		manager.sessionStorage.Remove(session.ID)
 		return nil
@@ -214,7 +224,19 @@ func (manager *SessionManager) startSession(session *Session) error {
 
 	go manager.keepAliveLoop(session, manager.channel)
 
-	return nil
+	return nil, nil
+}
+
+func (manager *SessionManager) reject(session *Session, rejection *pb.SessionRejection) {
+	log.Warn().Msgf("Session rejected for %s consumer: %s: %s", session.ConsumerID.Address, rejection.GetReason(), rejection.GetMessage())
+	session.Close()
+
+	manager.publisher.Publish(sevent.AppTopicSessionRejected, sevent.AppEventSessionRejected{
+		ServiceID:  session.ServiceID,
+		ConsumerID: session.ConsumerID,
+		Reason:     rejection.GetReason().String(),
+		Message:    rejection.GetMessage(),
+	})
 }
 
 func (manager *SessionManager) validateSession(session *Session) error {
@@ -240,6 +262,8 @@ func (manager *SessionManager) clearStaleSession(consumerID identity.Identity, s
 			continue
 		}
 		log.Info().Msgf("Cleaning stale session %s for %s consumer", session.ID, consumerID.Address)
+		// Removing it right away, so it is not counted by admission of the new session.
+		manager.sessionStorage.Remove(session.ID)
 		go session.Close()
 	}
 }
@@ -305,6 +329,9 @@ func (manager *SessionManager) providerService(session *Session, channel p2p.Cha
 			return nil
 		})
 	}
+	session.Interface = config.SessionInterface
+	session.ConsumerTunnelIP = config.ConsumerTunnelIP
+	manager.publisher.Publish(sevent.AppTopicSession, session.toEvent(sevent.ConfiguredStatus))
 
 	data, err := json.Marshal(config.SessionServiceConfig)
 	if err != nil {
@@ -320,15 +347,11 @@ func (manager *SessionManager) providerService(session *Session, channel p2p.Cha
 
 func (manager *SessionManager) keepAliveLoop(sess *Session, channel p2p.Channel) {
 	// Register handler for handling p2p keep alive pings from consumer.
-	channel.Handle(p2p.TopicKeepAlive, func(c p2p.Context) error {
-		var ping pb.P2PKeepAlivePing
-		if err := c.Request().UnmarshalProto(&ping); err != nil {
-			return err
-		}
-
+	p2p.HandleKeepAlivePing(channel, func(_ context.Context, ping *pb.P2PKeepAlivePing) error {
 		log.Debug().Msgf("Received p2p keepalive ping with SessionID=%s", ping.SessionID)
-		return c.OK()
+		return nil
 	})
+	handleProbes(channel)
 
 	// Send pings to consumer.
 	var errCount int
@@ -342,6 +365,11 @@ func (manager *SessionManager) keepAliveLoop(sess *Session, channel p2p.Channel)
 		case <-time.After(manager.config.KeepAlive.SendInterval):
 			if err := manager.sendKeepAlivePing(channel, sess.ID); err != nil {
 				log.Err(err).Msgf("Failed to send p2p keepalive ping. SessionID=%s", sess.ID)
+				manager.publisher.Publish(p2p.AppTopicKeepAliveFailed, p2p.AppEventKeepAliveFailed{
+					Role:      p2p.RoleProvider,
+					SessionID: string(sess.ID),
+					Error:     err.Error(),
+				})
 				errCount++
 				if errCount == manager.config.KeepAlive.MaxSendErrCount {
 					log.Error().Msgf("Max p2p keepalive err count reached, closing p2p channel. SessionID=%s", sess.ID)
@@ -361,6 +389,5 @@ func (manager *SessionManager) sendKeepAlivePing(channel p2p.Channel, sessionID
 	msg := &pb.P2PKeepAlivePing{
 		SessionID: string(sessionID),
 	}
-	_, err := channel.Send(ctx, p2p.TopicKeepAlive, p2p.ProtoMessage(msg))
-	return err
+	return p2p.NewKeepAliveClient(channel).Ping(ctx, msg)
 }
@@ -92,6 +92,7 @@ func (m *mockP2PChannel) Tracer() *trace.Tracer {
 }
 
 func (m *mockP2PChannel) ServiceConn() *net.UDPConn { return nil }
+func (m *mockP2PChannel) Relayed() bool             { return false }
 
 func (m *mockP2PChannel) Conn() *net.UDPConn { return nil }
 
@@ -116,7 +117,7 @@ func TestManager_Start_StoresSession(t *testing.T) {
 
 	assert.Eventually(t, func() bool {
 		history := publisher.GetEventHistory()
-		if len(history) != 6 {
+		if len(history) != 7 {
 			return false
 		}
 
@@ -127,24 +128,29 @@ func TestManager_Start_StoresSession(t *testing.T) {
 		assert.Equal(t, hermesID, startEvent.Session.HermesID)
 		assert.Equal(t, currentProposal, startEvent.Session.Proposal)
 
-		assert.Equal(t, trace.AppTopicTraceEvent, history[1].Topic)
-		traceEvent1 := history[1].Event.(trace.Event)
-		assert.Equal(t, "Provider connect", traceEvent1.Key)
+		assert.Equal(t, sessionEvent.AppTopicSession, history[1].Topic)
+		configuredEvent := history[1].Event.(sessionEvent.AppEventSession)
+		assert.Equal(t, sessionEvent.ConfiguredStatus, configuredEvent.Status)
+		assert.Equal(t, startEvent.Session.ID, configuredEvent.Session.ID)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[2].Topic)
-		traceEvent2 := history[2].Event.(trace.Event)
-		assert.Equal(t, "Provider session create", traceEvent2.Key)
+		traceEvent1 := history[2].Event.(trace.Event)
+		assert.Equal(t, "Provider connect", traceEvent1.Key)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[3].Topic)
-		traceEvent3 := history[3].Event.(trace.Event)
-		assert.Equal(t, "Provider session create (start)", traceEvent3.Key)
+		traceEvent2 := history[3].Event.(trace.Event)
+		assert.Equal(t, "Provider session create", traceEvent2.Key)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[4].Topic)
-		traceEvent4 := history[4].Event.(trace.Event)
-		assert.Equal(t, "Provider session create (payment)", traceEvent4.Key)
+		traceEvent3 := history[4].Event.(trace.Event)
+		assert.Equal(t, "Provider session create (start)", traceEvent3.Key)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[5].Topic)
-		traceEvent5 := history[5].Event.(trace.Event)
+		traceEvent4 := history[5].Event.(trace.Event)
+		assert.Equal(t, "Provider session create (payment)", traceEvent4.Key)
+
+		assert.Equal(t, trace.AppTopicTraceEvent, history[6].Topic)
+		traceEvent5 := history[6].Event.(trace.Event)
 		assert.Equal(t, "Provider session create (configure)", traceEvent5.Key)
 
 		return true
@@ -235,6 +241,86 @@ func TestManager_Start_Second_Session_Destroy_Stale_Session(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond, "Waiting for session destroy")
 }
 
+func TestManager_Start_RejectsSessionOverCapacity(t *testing.T) {
+	service := NewInstance(
+		identity.FromAddress(currentProposal.ProviderID),
+		currentProposal.ServiceType,
+		struct{}{},
+		currentProposal,
+		servicestate.Running,
+		&mockService{},
+		policy.NewRepository(),
+		&mockDiscovery{},
+	)
+	service.admission = newAdmission(AdmissionConfig{MaxSessions: 1}, nil)
+
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(service, sessionStore, publisher, &mockBalanceTracker{})
+
+	response, err := manager.Start(&pb.SessionRequest{
+		Consumer:   &pb.ConsumerInfo{Id: consumerID.Address},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, response.GetRejection())
+
+	response, err = manager.Start(&pb.SessionRequest{
+		Consumer:   &pb.ConsumerInfo{Id: "0x2"},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.RejectionReason_MAX_SESSIONS, response.GetRejection().GetReason())
+	assert.Empty(t, response.GetID())
+	assert.Len(t, sessionStore.GetAll(), 1)
+
+	assert.Eventually(t, func() bool {
+		for _, e := range publisher.GetEventHistory() {
+			if e.Topic != sessionEvent.AppTopicSessionRejected {
+				continue
+			}
+			rejected := e.Event.(sessionEvent.AppEventSessionRejected)
+			return rejected.Reason == "MAX_SESSIONS" && rejected.ConsumerID == identity.FromAddress("0x2")
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Start_AdmitsReconnectOfConsumerAtCapacity(t *testing.T) {
+	service := NewInstance(
+		identity.FromAddress(currentProposal.ProviderID),
+		currentProposal.ServiceType,
+		struct{}{},
+		currentProposal,
+		servicestate.Running,
+		&mockService{},
+		policy.NewRepository(),
+		&mockDiscovery{},
+	)
+	service.admission = newAdmission(AdmissionConfig{MaxSessions: 1, MaxSessionsPerConsumer: 1}, nil)
+
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(service, sessionStore, publisher, &mockBalanceTracker{})
+	sessionRequest := &pb.SessionRequest{
+		Consumer:   &pb.ConsumerInfo{Id: consumerID.Address},
+		ProposalID: int64(currentProposalID),
+	}
+
+	response, err := manager.Start(sessionRequest)
+	assert.NoError(t, err)
+	assert.Nil(t, response.GetRejection())
+	sessionOld := sessionStore.GetAll()[0]
+
+	response, err = manager.Start(sessionRequest)
+	assert.NoError(t, err)
+	assert.Nil(t, response.GetRejection())
+
+	sessions := sessionStore.GetAll()
+	assert.Len(t, sessions, 1)
+	assert.NotEqual(t, sessionOld.ID, sessions[0].ID)
+}
+
 func TestManager_Start_RejectsUnknownProposal(t *testing.T) {
 	publisher := mocks.NewEventBus()
 	sessionStore := NewSessionPool(mocks.NewEventBus())
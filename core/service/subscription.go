@@ -18,6 +18,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"time"
@@ -31,30 +32,18 @@ import (
 )
 
 func subscribeSessionCreate(mng *SessionManager, ch p2p.Channel) {
-	ch.Handle(p2p.TopicSessionCreate, func(c p2p.Context) error {
-		var request pb.SessionRequest
-		if err := c.Request().UnmarshalProto(&request); err != nil {
-			return err
-		}
-		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionCreate, request.String())
-
-		response, err := mng.Start(&request)
+	p2p.HandleSessionCreate(ch, func(_ context.Context, request *pb.SessionRequest) (*pb.SessionResponse, error) {
+		response, err := mng.Start(request)
 		if err != nil {
-			return fmt.Errorf("cannot start session: %s: %w", response.ID, err)
+			return nil, fmt.Errorf("cannot start session: %s: %w", response.ID, err)
 		}
 
-		return c.OkWithReply(p2p.ProtoMessage(&response))
+		return &response, nil
 	})
 }
 
 func subscribeSessionStatus(ch p2p.ChannelHandler, statusStorage connectivity.StatusStorage) {
-	ch.Handle(p2p.TopicSessionStatus, func(c p2p.Context) error {
-		var ss pb.SessionStatus
-		if err := c.Request().UnmarshalProto(&ss); err != nil {
-			return err
-		}
-		log.Debug().Msgf("Received P2P session status message for %q: %s", p2p.TopicSessionStatus, ss.String())
-
+	p2p.HandleSessionStatus(ch, func(_ context.Context, ss *pb.SessionStatus) error {
 		entry := connectivity.StatusEntry{
 			PeerID:       identity.FromAddress(ss.GetConsumerID()),
 			StatusCode:   connectivity.StatusCode(ss.GetCode()),
@@ -64,18 +53,12 @@ func subscribeSessionStatus(ch p2p.ChannelHandler, statusStorage connectivity.St
 		}
 		statusStorage.AddStatusEntry(entry)
 
-		return c.OK()
+		return nil
 	})
 }
 
 func subscribeSessionDestroy(mng *SessionManager, ch p2p.ChannelHandler) {
-	ch.Handle(p2p.TopicSessionDestroy, func(c p2p.Context) error {
-		var si pb.SessionInfo
-		if err := c.Request().UnmarshalProto(&si); err != nil {
-			return err
-		}
-		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionDestroy, si.String())
-
+	p2p.HandleSessionDestroy(ch, func(_ context.Context, si *pb.SessionInfo) error {
 		go func() {
 			consumerID := identity.FromAddress(si.GetConsumerID())
 			sessionID := si.GetSessionID()
@@ -86,17 +69,12 @@ func subscribeSessionDestroy(mng *SessionManager, ch p2p.ChannelHandler) {
 			}
 		}()
 
-		return c.OK()
+		return nil
 	})
 }
 
 func subscribeSessionAcknowledge(mng *SessionManager, ch p2p.ChannelHandler) {
-	ch.Handle(p2p.TopicSessionAcknowledge, func(c p2p.Context) error {
-		var si pb.SessionInfo
-		if err := c.Request().UnmarshalProto(&si); err != nil {
-			return err
-		}
-		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionAcknowledge, si.String())
+	p2p.HandleSessionAcknowledge(ch, func(_ context.Context, si *pb.SessionInfo) error {
 		consumerID := identity.FromAddress(si.GetConsumerID())
 		sessionID := si.GetSessionID()
 
@@ -105,38 +83,32 @@ func subscribeSessionAcknowledge(mng *SessionManager, ch p2p.ChannelHandler) {
 			return fmt.Errorf("cannot acknowledge session %s: %w", sessionID, err)
 		}
 
-		return c.OK()
+		return nil
 	})
 }
 
 const bigIntBase int = 10
 
 func subscribeSessionPayments(mng *SessionManager, ch p2p.ChannelHandler) {
-	ch.Handle(p2p.TopicPaymentMessage, func(c p2p.Context) error {
-		var msg pb.ExchangeMessage
-		if err := c.Request().UnmarshalProto(&msg); err != nil {
-			return fmt.Errorf("could not unmarshal exchange message proto: %w", err)
-		}
-		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicPaymentMessage, msg.String())
-
+	p2p.HandlePaymentExchangeMessage(ch, func(_ context.Context, msg *pb.ExchangeMessage) error {
 		amount, ok := new(big.Int).SetString(msg.GetPromise().GetAmount(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field amount of value %v", amount)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field amount of value %v", amount)
 		}
 
 		fee, ok := new(big.Int).SetString(msg.GetPromise().GetFee(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field fee of value %v", fee)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field fee of value %v", fee)
 		}
 
 		agreementID, ok := new(big.Int).SetString(msg.GetAgreementID(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementID of value %v", agreementID)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field agreementID of value %v", agreementID)
 		}
 
 		agreementTotal, ok := new(big.Int).SetString(msg.GetAgreementTotal(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementTotal of value %v", agreementTotal)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field agreementTotal of value %v", agreementTotal)
 		}
 
 		mng.paymentEngineChan <- crypto.ExchangeMessage{
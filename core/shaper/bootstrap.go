@@ -17,19 +17,58 @@
 
 package shaper
 
+import (
+	"github.com/mysteriumnetwork/node/config"
+)
+
 // Shaper shapes traffic on a network interface.
 type Shaper interface {
 	// Start applies shaping configuration on the specified interface and then continuously ensures it.
+	// Sessions configured on the interface get their own limits, until the interface is cleared.
 	Start(interfaceName string) error
 	// Clear clears shaping rules.
 	Clear(interfaceName string)
 }
 
+// Limits holds bandwidth limits in Kbps, zero means unlimited.
+// Uplink is the traffic sent through the interface, downlink is the traffic received on it.
+type Limits struct {
+	UplinkKbps   int `json:"uplink_kbps,omitempty"`
+	DownlinkKbps int `json:"downlink_kbps,omitempty"`
+}
+
+// Enabled returns true if any limit is set.
+func (l Limits) Enabled() bool {
+	return l.UplinkKbps > 0 || l.DownlinkKbps > 0
+}
+
+// Options holds traffic shaping limits of a service.
+type Options struct {
+	// Interface limits total bandwidth of the service interface
+	Interface Limits `json:"interface"`
+	// Session limits bandwidth of every single session, keyed by consumer's tunnel IP
+	Session Limits `json:"session"`
+}
+
+// GetOptions returns traffic shaping options from application configuration.
+func GetOptions() Options {
+	return Options{
+		Interface: Limits{
+			UplinkKbps:   config.GetInt(config.FlagShaperUplink),
+			DownlinkKbps: config.GetInt(config.FlagShaperDownlink),
+		},
+		Session: Limits{
+			UplinkKbps:   config.GetInt(config.FlagShaperSessionUplink),
+			DownlinkKbps: config.GetInt(config.FlagShaperSessionDownlink),
+		},
+	}
+}
+
 type eventListener interface {
 	SubscribeAsync(topic string, fn interface{}) error
 }
 
 // New creates a traffic shaper (linux) or no-op.
-func New(listener eventListener) (shaper Shaper) {
-	return create(listener)
+func New(listener eventListener, options Options) (shaper Shaper) {
+	return create(listener, options)
 }
@@ -18,60 +18,306 @@
 package shaper
 
 import (
-	"github.com/mysteriumnetwork/go-wondershaper/wondershaper"
-	"github.com/mysteriumnetwork/node/config"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	sevent "github.com/mysteriumnetwork/node/session/event"
+	"github.com/mysteriumnetwork/node/utils/cmdutil"
 )
 
-const limitKbps = 5000
+const (
+	// unlimitedRate is used for HTB classes which are not limited.
+	unlimitedRate = "10gbit"
+	// firstSessionClass is the first class minor number of sessions, 1 is the root class and 2 is the default one.
+	firstSessionClass = 3
+	// maxSessionClass is the last available class minor number.
+	maxSessionClass = 0xffff
+	// ifbPrefix is prefix of devices used to shape traffic received on the interface.
+	ifbPrefix = "ifb-"
+	// maxInterfaceNameLength is the limit of network interface names in linux.
+	maxInterfaceNameLength = 15
+)
 
+// linuxShaper limits bandwidth with HTB queueing discipline. Traffic sent through the interface is shaped
+// on the interface itself, while traffic received on it is redirected to an IFB device and shaped there.
+// Every session gets its own HTB class, matched by consumer's tunnel IP.
 type linuxShaper struct {
-	ws          *wondershaper.Shaper
-	listener    eventListener
-	listenTopic string
+	exec     func(args ...string) error
+	listener eventListener
+	options  Options
+
+	subscribeOnce sync.Once
+	lock          sync.Mutex
+	interfaces    map[string]*shapedInterface
 }
 
-func create(listener eventListener) *linuxShaper {
-	ws := wondershaper.New()
-	ws.Stdout = log.Logger
-	ws.Stderr = log.Logger
+type shapedInterface struct {
+	sessions map[string]shapedSession
+}
+
+type shapedSession struct {
+	class int
+	ip    net.IP
+}
+
+func create(listener eventListener, options Options) *linuxShaper {
 	return &linuxShaper{
-		ws:          ws,
-		listener:    listener,
-		listenTopic: config.AppTopicConfig(config.FlagShaperEnabled.Name),
+		exec:       cmdutil.SudoExec,
+		listener:   listener,
+		options:    options,
+		interfaces: make(map[string]*shapedInterface),
 	}
 }
 
 // Start applies shaping configuration on the specified interface and then continuously ensures it.
 func (s *linuxShaper) Start(interfaceName string) error {
-	applyLimits := func() error {
-		s.ws.Clear(interfaceName)
-
-		if config.GetBool(config.FlagShaperEnabled) {
-			err := s.ws.LimitDownlink(interfaceName, limitKbps)
-			if err != nil {
-				log.Error().Err(err).Msg("Could not limit download speed")
-				return err
-			}
-			err = s.ws.LimitUplink(interfaceName, limitKbps)
-			if err != nil {
-				log.Error().Err(err).Msg("Could not limit upload speed")
-				return err
-			}
+	var err error
+	s.subscribeOnce.Do(func() {
+		err = s.subscribe()
+	})
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.interfaces[interfaceName]; !ok {
+		s.interfaces[interfaceName] = &shapedInterface{sessions: make(map[string]shapedSession)}
+	}
+	return s.applyLimits(interfaceName)
+}
+
+// Clear clears shaping rules.
+func (s *linuxShaper) Clear(interfaceName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.clearLimits(interfaceName)
+	delete(s.interfaces, interfaceName)
+}
+
+func (s *linuxShaper) subscribe() error {
+	topic := config.AppTopicConfig(config.FlagShaperEnabled.Name)
+	if err := s.listener.SubscribeAsync(topic, s.reapplyLimits); err != nil {
+		return errors.Wrap(err, "could not subscribe to topic: "+topic)
+	}
+	if err := s.listener.SubscribeAsync(sevent.AppTopicSession, s.consumeSessionEvent); err != nil {
+		return errors.Wrap(err, "could not subscribe to topic: "+sevent.AppTopicSession)
+	}
+	return nil
+}
+
+func (s *linuxShaper) reapplyLimits() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for interfaceName := range s.interfaces {
+		if err := s.applyLimits(interfaceName); err != nil {
+			log.Error().Err(err).Msgf("Could not apply bandwidth limits on %s", interfaceName)
+		}
+	}
+}
+
+func (s *linuxShaper) consumeSessionEvent(e sevent.AppEventSession) {
+	switch e.Status {
+	case sevent.ConfiguredStatus:
+		s.addSession(e.Session)
+	case sevent.RemovedStatus:
+		s.removeSession(e.Session)
+	}
+}
+
+func (s *linuxShaper) addSession(session sevent.SessionContext) {
+	ip := session.ConsumerTunnelIP.To4()
+	if ip == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	iface, ok := s.interfaces[session.Interface]
+	if !ok {
+		return
+	}
+	class, ok := iface.freeClass()
+	if !ok {
+		log.Warn().Msgf("No free traffic classes left on %s, session %s is not limited", session.Interface, session.ID)
+		return
+	}
+	shaped := shapedSession{class: class, ip: ip}
+	iface.sessions[session.ID] = shaped
+
+	if !s.enabled() {
+		return
+	}
+	if err := s.addSessionLimits(session.Interface, shaped); err != nil {
+		log.Error().Err(err).Msgf("Could not limit bandwidth of session %s", session.ID)
+	}
+}
+
+func (s *linuxShaper) removeSession(session sevent.SessionContext) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for interfaceName, iface := range s.interfaces {
+		shaped, ok := iface.sessions[session.ID]
+		if !ok {
+			continue
+		}
+		delete(iface.sessions, session.ID)
+
+		if s.enabled() {
+			s.removeSessionLimits(interfaceName, shaped)
 		}
+	}
+}
+
+func (s *linuxShaper) enabled() bool {
+	return config.GetBool(config.FlagShaperEnabled)
+}
+
+func (s *linuxShaper) applyLimits(interfaceName string) error {
+	s.clearLimits(interfaceName)
+	if !s.enabled() {
 		return nil
 	}
 
-	err := s.listener.SubscribeAsync(s.listenTopic, applyLimits)
+	if err := s.setupHTB(interfaceName, s.options.Interface.UplinkKbps); err != nil {
+		log.Error().Err(err).Msg("Could not limit upload speed")
+		return err
+	}
+
+	ifb := ifbName(interfaceName)
+	for _, args := range [][]string{
+		{"ip", "link", "add", "name", ifb, "type", "ifb"},
+		{"ip", "link", "set", "dev", ifb, "up"},
+		{"tc", "qdisc", "add", "dev", interfaceName, "handle", "ffff:", "ingress"},
+		{"tc", "filter", "add", "dev", interfaceName, "parent", "ffff:", "protocol", "ip", "u32", "match", "u32", "0", "0", "action", "mirred", "egress", "redirect", "dev", ifb},
+	} {
+		if err := s.exec(args...); err != nil {
+			log.Error().Err(err).Msg("Could not limit download speed")
+			return err
+		}
+	}
+	if err := s.setupHTB(ifb, s.options.Interface.DownlinkKbps); err != nil {
+		log.Error().Err(err).Msg("Could not limit download speed")
+		return err
+	}
+
+	for sessionID, shaped := range s.interfaces[interfaceName].sessions {
+		if err := s.addSessionLimits(interfaceName, shaped); err != nil {
+			log.Error().Err(err).Msgf("Could not limit bandwidth of session %s", sessionID)
+		}
+	}
+	return nil
+}
+
+// setupHTB creates root HTB class limited to the given rate and the default class for traffic of unknown sessions.
+func (s *linuxShaper) setupHTB(device string, kbps int) error {
+	rate := rateOf(kbps)
+	for _, args := range [][]string{
+		{"tc", "qdisc", "add", "dev", device, "root", "handle", "1:", "htb", "default", "2"},
+		{"tc", "class", "add", "dev", device, "parent", "1:", "classid", "1:1", "htb", "rate", rate},
+		{"tc", "class", "add", "dev", device, "parent", "1:1", "classid", "1:2", "htb", "rate", rate, "ceil", rate},
+	} {
+		if err := s.exec(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *linuxShaper) addSessionLimits(interfaceName string, shaped shapedSession) error {
+	if s.options.Session.UplinkKbps > 0 {
+		if err := s.addSessionClass(interfaceName, shaped, "dst", s.options.Session.UplinkKbps); err != nil {
+			return err
+		}
+	}
+	if s.options.Session.DownlinkKbps > 0 {
+		if err := s.addSessionClass(ifbName(interfaceName), shaped, "src", s.options.Session.DownlinkKbps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *linuxShaper) addSessionClass(device string, shaped shapedSession, direction string, kbps int) error {
+	rate := rateOf(kbps)
+	classID := fmt.Sprintf("1:%x", shaped.class)
+	err := s.exec("tc", "class", "add", "dev", device, "parent", "1:1", "classid", classID, "htb", "rate", rate, "ceil", rate)
 	if err != nil {
-		return errors.Wrap(err, "could not subscribe to topic: "+s.listenTopic)
+		return err
 	}
+	return s.exec("tc", "filter", "add", "dev", device, "parent", "1:", "protocol", "ip", "prio", fmt.Sprint(shaped.class),
+		"u32", "match", "ip", direction, shaped.ip.String()+"/32", "flowid", classID)
+}
 
-	return applyLimits()
+func (s *linuxShaper) removeSessionLimits(interfaceName string, shaped shapedSession) {
+	if s.options.Session.UplinkKbps > 0 {
+		s.removeSessionClass(interfaceName, shaped)
+	}
+	if s.options.Session.DownlinkKbps > 0 {
+		s.removeSessionClass(ifbName(interfaceName), shaped)
+	}
 }
 
-// Clear clears shaping rules.
-func (s *linuxShaper) Clear(interfaceName string) {
-	s.ws.Clear(interfaceName)
+func (s *linuxShaper) removeSessionClass(device string, shaped shapedSession) {
+	if err := s.exec("tc", "filter", "del", "dev", device, "parent", "1:", "prio", fmt.Sprint(shaped.class)); err != nil {
+		log.Warn().Err(err).Msgf("Could not remove session filter from %s", device)
+	}
+	if err := s.exec("tc", "class", "del", "dev", device, "classid", fmt.Sprintf("1:%x", shaped.class)); err != nil {
+		log.Warn().Err(err).Msgf("Could not remove session class from %s", device)
+	}
+}
+
+// clearLimits removes every shaping rule of the interface, errors are expected when there are no rules.
+func (s *linuxShaper) clearLimits(interfaceName string) {
+	ifb := ifbName(interfaceName)
+	for _, args := range [][]string{
+		{"tc", "qdisc", "del", "dev", interfaceName, "root"},
+		{"tc", "qdisc", "del", "dev", interfaceName, "ingress"},
+		{"ip", "link", "del", "dev", ifb},
+	} {
+		if err := s.exec(args...); err != nil {
+			log.Debug().Err(err).Msg("Nothing to clear")
+		}
+	}
+}
+
+func (i *shapedInterface) freeClass() (int, bool) {
+	used := make(map[int]bool, len(i.sessions))
+	for _, shaped := range i.sessions {
+		used[shaped.class] = true
+	}
+	for class := firstSessionClass; class <= maxSessionClass; class++ {
+		if !used[class] {
+			return class, true
+		}
+	}
+	return 0, false
+}
+
+// ifbName names IFB device of the interface. Names which do not fit the limit get a checksum of the full
+// interface name instead, so that interfaces sharing a long common prefix do not end up on the same device.
+func ifbName(interfaceName string) string {
+	name := ifbPrefix + interfaceName
+	if len(name) > maxInterfaceNameLength {
+		name = fmt.Sprintf("%s%08x", ifbPrefix, crc32.ChecksumIEEE([]byte(interfaceName)))
+	}
+	return name
+}
+
+func rateOf(kbps int) string {
+	if kbps <= 0 {
+		return unlimitedRate
+	}
+	return fmt.Sprintf("%dkbit", kbps)
 }
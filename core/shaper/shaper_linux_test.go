@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shaper
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/mocks"
+	sevent "github.com/mysteriumnetwork/node/session/event"
+)
+
+type commandRecorder struct {
+	commands []string
+}
+
+func (r *commandRecorder) exec(args ...string) error {
+	r.commands = append(r.commands, strings.Join(args, " "))
+	return nil
+}
+
+func newTestShaper(options Options) (*linuxShaper, *commandRecorder) {
+	recorder := &commandRecorder{}
+	s := create(mocks.NewEventBus(), options)
+	s.exec = recorder.exec
+	return s, recorder
+}
+
+var testOptions = Options{
+	Interface: Limits{UplinkKbps: 10000, DownlinkKbps: 8000},
+	Session:   Limits{UplinkKbps: 1000, DownlinkKbps: 500},
+}
+
+func TestShaper_StartDisabledOnlyClears(t *testing.T) {
+	s, recorder := newTestShaper(testOptions)
+
+	assert.NoError(t, s.Start("wg0"))
+	assert.Equal(t, []string{
+		"tc qdisc del dev wg0 root",
+		"tc qdisc del dev wg0 ingress",
+		"ip link del dev ifb-wg0",
+	}, recorder.commands)
+}
+
+func TestShaper_StartLimitsInterface(t *testing.T) {
+	config.Current.SetUser(config.FlagShaperEnabled.Name, true)
+	defer config.Current.RemoveUser(config.FlagShaperEnabled.Name)
+	s, recorder := newTestShaper(testOptions)
+
+	assert.NoError(t, s.Start("wg0"))
+	assert.Equal(t, []string{
+		"tc qdisc del dev wg0 root",
+		"tc qdisc del dev wg0 ingress",
+		"ip link del dev ifb-wg0",
+		"tc qdisc add dev wg0 root handle 1: htb default 2",
+		"tc class add dev wg0 parent 1: classid 1:1 htb rate 10000kbit",
+		"tc class add dev wg0 parent 1:1 classid 1:2 htb rate 10000kbit ceil 10000kbit",
+		"ip link add name ifb-wg0 type ifb",
+		"ip link set dev ifb-wg0 up",
+		"tc qdisc add dev wg0 handle ffff: ingress",
+		"tc filter add dev wg0 parent ffff: protocol ip u32 match u32 0 0 action mirred egress redirect dev ifb-wg0",
+		"tc qdisc add dev ifb-wg0 root handle 1: htb default 2",
+		"tc class add dev ifb-wg0 parent 1: classid 1:1 htb rate 8000kbit",
+		"tc class add dev ifb-wg0 parent 1:1 classid 1:2 htb rate 8000kbit ceil 8000kbit",
+	}, recorder.commands)
+}
+
+func TestShaper_SessionLimitsFollowSessionLifecycle(t *testing.T) {
+	config.Current.SetUser(config.FlagShaperEnabled.Name, true)
+	defer config.Current.RemoveUser(config.FlagShaperEnabled.Name)
+	s, recorder := newTestShaper(testOptions)
+	assert.NoError(t, s.Start("wg0"))
+
+	session := sevent.SessionContext{ID: "session1", Interface: "wg0", ConsumerTunnelIP: net.ParseIP("10.182.0.2")}
+	recorder.commands = nil
+	s.consumeSessionEvent(sevent.AppEventSession{Status: sevent.ConfiguredStatus, Session: session})
+	assert.Equal(t, []string{
+		"tc class add dev wg0 parent 1:1 classid 1:3 htb rate 1000kbit ceil 1000kbit",
+		"tc filter add dev wg0 parent 1: protocol ip prio 3 u32 match ip dst 10.182.0.2/32 flowid 1:3",
+		"tc class add dev ifb-wg0 parent 1:1 classid 1:3 htb rate 500kbit ceil 500kbit",
+		"tc filter add dev ifb-wg0 parent 1: protocol ip prio 3 u32 match ip src 10.182.0.2/32 flowid 1:3",
+	}, recorder.commands)
+
+	recorder.commands = nil
+	s.consumeSessionEvent(sevent.AppEventSession{Status: sevent.RemovedStatus, Session: session})
+	assert.Equal(t, []string{
+		"tc filter del dev wg0 parent 1: prio 3",
+		"tc class del dev wg0 classid 1:3",
+		"tc filter del dev ifb-wg0 parent 1: prio 3",
+		"tc class del dev ifb-wg0 classid 1:3",
+	}, recorder.commands)
+}
+
+func TestShaper_SessionOfUnknownInterfaceIsIgnored(t *testing.T) {
+	config.Current.SetUser(config.FlagShaperEnabled.Name, true)
+	defer config.Current.RemoveUser(config.FlagShaperEnabled.Name)
+	s, recorder := newTestShaper(testOptions)
+	assert.NoError(t, s.Start("wg0"))
+
+	recorder.commands = nil
+	s.consumeSessionEvent(sevent.AppEventSession{
+		Status:  sevent.ConfiguredStatus,
+		Session: sevent.SessionContext{ID: "session1", Interface: "tun0", ConsumerTunnelIP: net.ParseIP("10.8.0.2")},
+	})
+	assert.Empty(t, recorder.commands)
+}
+
+func TestShaper_ClassesAreReused(t *testing.T) {
+	iface := &shapedInterface{sessions: map[string]shapedSession{
+		"session1": {class: 3},
+		"session3": {class: 5},
+	}}
+
+	class, ok := iface.freeClass()
+	assert.True(t, ok)
+	assert.Equal(t, 4, class)
+}
+
+func TestIfbName(t *testing.T) {
+	assert.Equal(t, "ifb-wg0", ifbName("wg0"))
+	assert.Len(t, ifbName("myst-very-long0"), len(ifbPrefix)+8)
+	assert.NotEqual(t, ifbName("myst-very-long0"), ifbName("myst-very-long1"))
+	assert.Equal(t, ifbName("myst-very-long0"), ifbName("myst-very-long0"))
+}
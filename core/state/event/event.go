@@ -60,6 +60,7 @@ type Connection struct {
 	Statistics connectionstate.Statistics
 	Throughput bandwidth.Throughput
 	Invoice    crypto.Invoice
+	Probe      connectionstate.ProbeResult
 }
 
 func (c Connection) String() string {
@@ -133,7 +133,7 @@ func NewKeeper(deps KeeperDeps, debounceDuration time.Duration) *Keeper {
 	// consumer
 	k.consumeConnectionStatisticsEvent = debounce(k.updateConnectionStats, debounceDuration)
 	k.consumeConnectionThroughputEvent = debounce(k.updateConnectionThroughput, debounceDuration)
-	k.consumeConnectionSpendingEvent = debounce(k.updateConnectionSpending, debounceDuration)
+	k.consumeConnectionSpendingEvent = k.defaultSessionInvoices(debounce(k.updateConnectionSpending, debounceDuration))
 	k.announceStateChanges = debounce(k.announceState, debounceDuration)
 
 	return k
@@ -176,6 +176,9 @@ func (k *Keeper) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.SubscribeAsync(sevent.AppTopicSession, k.consumeServiceSessionEvent); err != nil {
 		return err
 	}
+	if err := bus.SubscribeAsync(sevent.AppTopicSessionRejected, k.consumeServiceSessionRejectedEvent); err != nil {
+		return err
+	}
 	if err := bus.SubscribeAsync(sevent.AppTopicDataTransferred, k.consumeServiceSessionStatisticsEvent); err != nil {
 		return err
 	}
@@ -194,6 +197,9 @@ func (k *Keeper) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.SubscribeAsync(bandwidth.AppTopicConnectionThroughput, k.consumeConnectionThroughputEvent); err != nil {
 		return err
 	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionProbe, k.consumeConnectionProbeEvent); err != nil {
+		return err
+	}
 	if err := bus.SubscribeAsync(pingpongEvent.AppTopicInvoicePaid, k.consumeConnectionSpendingEvent); err != nil {
 		return err
 	}
@@ -272,7 +278,7 @@ func (k *Keeper) updateNatStatus(e interface{}) {
 
 	k.deps.NATStatusProvider.ConsumeNATEvent(event)
 	status := k.deps.NATStatusProvider.Status()
-	k.state.NATStatus = contract.NATStatusDTO{Status: status.Status}
+	k.state.NATStatus = contract.NATStatusDTO{Status: status.Status, Protocol: status.Protocol}
 	if status.Error != nil {
 		k.state.NATStatus.Error = status.Error.Error()
 	}
@@ -298,6 +304,21 @@ func (k *Keeper) consumeServiceSessionEvent(e sevent.AppEventSession) {
 	go k.announceStateChanges(nil)
 }
 
+// consumeServiceSessionRejectedEvent counts sessions rejected by service admission control
+func (k *Keeper) consumeServiceSessionRejectedEvent(e sevent.AppEventSessionRejected) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	for i := range k.state.Services {
+		if k.state.Services[i].ID == e.ServiceID {
+			k.state.Services[i].ConnectionStatistics.Rejected++
+			break
+		}
+	}
+
+	go k.announceStateChanges(nil)
+}
+
 func (k *Keeper) addSession(e sevent.AppEventSession) {
 	k.state.Sessions = append(k.state.Sessions, session.History{
 		SessionID:       nodeSession.ID(e.Session.ID),
@@ -392,6 +413,9 @@ func (k *Keeper) consumeConnectionStateEvent(e interface{}) {
 		log.Warn().Msg("Received a wrong kind of event for connection state update")
 		return
 	}
+	if !evt.SessionInfo.IsDefault() {
+		return
+	}
 
 	if evt.State == connectionstate.NotConnected {
 		k.state.Connection = stateEvent.Connection{}
@@ -410,8 +434,12 @@ func (k *Keeper) updateConnectionStats(e interface{}) {
 		log.Warn().Msg("Received a wrong kind of event for connection state update")
 		return
 	}
+	if !evt.SessionInfo.IsDefault() {
+		return
+	}
 
 	k.state.Connection.Statistics = evt.Stats
+	k.state.Connection.Session.Quota = evt.SessionInfo.Quota
 
 	go k.announceStateChanges(nil)
 }
@@ -430,6 +458,18 @@ func (k *Keeper) updateConnectionThroughput(e interface{}) {
 	go k.announceStateChanges(nil)
 }
 
+func (k *Keeper) consumeConnectionProbeEvent(e connectionstate.AppEventConnectionProbe) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if !e.SessionInfo.IsDefault() {
+		return
+	}
+
+	k.state.Connection.Probe = e.Result
+
+	go k.announceStateChanges(nil)
+}
+
 func (k *Keeper) updateConnectionSpending(e interface{}) {
 	k.lock.Lock()
 	defer k.lock.Unlock()
@@ -438,6 +478,10 @@ func (k *Keeper) updateConnectionSpending(e interface{}) {
 		log.Warn().Msg("Received a wrong kind of event for connection state update")
 		return
 	}
+	// Default session might have changed while the event was debounced.
+	if !k.isDefaultSession(evt.SessionID) {
+		return
+	}
 
 	k.state.Connection.Invoice = evt.Invoice
 	log.Info().Msgf("Session %s", k.state.Connection.String())
@@ -445,6 +489,29 @@ func (k *Keeper) updateConnectionSpending(e interface{}) {
 	go k.announceStateChanges(nil)
 }
 
+// defaultSessionInvoices passes on invoices of the default connection session only, so that invoices of additional
+// simultaneous connections are dropped before they get debounced together with the default one.
+func (k *Keeper) defaultSessionInvoices(next func(interface{})) func(interface{}) {
+	return func(e interface{}) {
+		if evt, ok := e.(pingpongEvent.AppEventInvoicePaid); ok {
+			k.lock.Lock()
+			isDefault := k.isDefaultSession(evt.SessionID)
+			k.lock.Unlock()
+			if !isDefault {
+				return
+			}
+		}
+		next(e)
+	}
+}
+
+// isDefaultSession tells whether the session belongs to the default connection, invoices issued before
+// the default connection gets its session do not belong to it either. Lock must be held by caller.
+func (k *Keeper) isDefaultSession(sessionID string) bool {
+	defaultID := k.state.Connection.Session.SessionID
+	return defaultID != "" && sessionID == string(defaultID)
+}
+
 func (k *Keeper) consumeBalanceChangedEvent(e interface{}) {
 	k.lock.Lock()
 	defer k.lock.Unlock()
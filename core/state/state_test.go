@@ -263,6 +263,32 @@ func Test_ConsumesSessionAcknowledgeEvents(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond)
 }
 
+func Test_ConsumesSessionRejectedEvents(t *testing.T) {
+	// given
+	myID := "test"
+	eventBus := eventbus.New()
+	deps := KeeperDeps{
+		Publisher:        eventBus,
+		IdentityProvider: &mocks.IdentityProvider{},
+	}
+	keeper := NewKeeper(deps, time.Millisecond)
+	keeper.Subscribe(eventBus)
+	keeper.state.Services = []contract.ServiceInfoDTO{
+		{ID: myID},
+	}
+
+	// when
+	eventBus.Publish(sessionEvent.AppTopicSessionRejected, sessionEvent.AppEventSessionRejected{
+		ServiceID: myID,
+		Reason:    "MAX_SESSIONS",
+	})
+
+	// then
+	assert.Eventually(t, func() bool {
+		return keeper.GetState().Services[0].ConnectionStatistics.Rejected == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 func Test_consumeServiceSessionEarningsEvent(t *testing.T) {
 	// given
 	eventBus := eventbus.New()
@@ -425,6 +451,41 @@ func Test_ConsumesConnectionStatisticsEvents(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond)
 }
 
+func Test_ConsumesConnectionProbeEvents(t *testing.T) {
+	// given
+	expected := connectionstate.ProbeResult{
+		At:         time.Now(),
+		Latency:    40 * time.Millisecond,
+		Jitter:     5 * time.Millisecond,
+		PacketLoss: 0.1,
+	}
+	eventBus := eventbus.New()
+	deps := KeeperDeps{
+		NATStatusProvider: &natStatusProviderMock{statusToReturn: mockNATStatus},
+		Publisher:         eventBus,
+		ServiceLister:     &serviceListerMock{},
+		IdentityProvider:  &mocks.IdentityProvider{},
+	}
+	keeper := NewKeeper(deps, time.Millisecond)
+	err := keeper.Subscribe(eventBus)
+	assert.NoError(t, err)
+	assert.True(t, keeper.GetState().Connection.Probe.At.IsZero())
+
+	// when
+	eventBus.Publish(connectionstate.AppTopicConnectionProbe, connectionstate.AppEventConnectionProbe{
+		Result:      connectionstate.ProbeResult{Latency: time.Second},
+		SessionInfo: connectionstate.Status{ConnectionID: "detached"},
+	})
+	eventBus.Publish(connectionstate.AppTopicConnectionProbe, connectionstate.AppEventConnectionProbe{
+		Result: expected,
+	})
+
+	// then
+	assert.Eventually(t, func() bool {
+		return expected == keeper.GetState().Connection.Probe
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 func Test_ConsumesConnectionInvoiceEvents(t *testing.T) {
 	// given
 	expected := crypto.Invoice{
@@ -443,16 +504,38 @@ func Test_ConsumesConnectionInvoiceEvents(t *testing.T) {
 	err := keeper.Subscribe(eventBus)
 	assert.NoError(t, err)
 	assert.True(t, keeper.GetState().Connection.Statistics.At.IsZero())
+	other := crypto.Invoice{AgreementID: big.NewInt(2), AgreementTotal: big.NewInt(5)}
 
 	// when
 	eventBus.Publish(pingpongEvent.AppTopicInvoicePaid, pingpongEvent.AppEventInvoicePaid{
-		Invoice: expected,
+		SessionID: "1",
+		Invoice:   other,
+	})
+	eventBus.Publish(connectionstate.AppTopicConnectionState, connectionstate.AppEventConnectionState{
+		State:       connectionstate.Connected,
+		SessionInfo: connectionstate.Status{State: connectionstate.Connected, SessionID: "1"},
+	})
+	assert.Eventually(t, func() bool {
+		return keeper.GetState().Connection.Session.SessionID == "1"
+	}, 2*time.Second, 10*time.Millisecond)
+	eventBus.Publish(pingpongEvent.AppTopicInvoicePaid, pingpongEvent.AppEventInvoicePaid{
+		SessionID: "2",
+		Invoice:   other,
+	})
+	eventBus.Publish(pingpongEvent.AppTopicInvoicePaid, pingpongEvent.AppEventInvoicePaid{
+		SessionID: "1",
+		Invoice:   expected,
 	})
 
 	// then
 	assert.Eventually(t, func() bool {
 		return expected == keeper.GetState().Connection.Invoice
 	}, 2*time.Second, 10*time.Millisecond)
+	eventBus.Publish(pingpongEvent.AppTopicInvoicePaid, pingpongEvent.AppEventInvoicePaid{
+		Invoice: other,
+	})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, expected, keeper.GetState().Connection.Invoice)
 }
 
 func Test_ConsumesBalanceChangeEvent(t *testing.T) {
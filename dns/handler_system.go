@@ -18,7 +18,8 @@
 package dns
 
 import (
-	"net"
+	"sync"
+	"time"
 
 	"github.com/miekg/dns"
 	"github.com/pkg/errors"
@@ -27,42 +28,49 @@ import (
 
 // ResolveViaSystem creates proxying DNS handler.
 func ResolveViaSystem() (dns.Handler, error) {
-	handler := &proxyHandler{
-		client: &dns.Client{
-			DialTimeout:  dnsTimeout,
-			ReadTimeout:  dnsTimeout,
-			WriteTimeout: dnsTimeout,
-		},
-	}
-	if err := handler.configure(); err != nil {
+	upstreams, err := systemUpstreams()
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to find system DNS configuration")
 	}
 
-	return handler, nil
+	return &proxyHandler{upstreams: upstreams}, nil
 }
 
-type proxyHandler struct {
-	proxyAddrs []string
-	client     *dns.Client
-}
+// ResolveViaUpstreams creates proxying DNS handler, which forwards queries to the given upstreams.
+// Upstreams are tried in the given order, so following ones serve as fallbacks, see parseUpstreams for
+// supported address formats. Empty list resolves via system DNS servers.
+func ResolveViaUpstreams(addresses []string) (dns.Handler, error) {
+	if len(addresses) == 0 {
+		return ResolveViaSystem()
+	}
 
-// configure configures proxy to use system DNS servers.
-func (ph *proxyHandler) configure() (err error) {
-	cfg, err := configuration()
+	upstreams, err := parseUpstreams(addresses)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "failed to configure DNS upstreams")
 	}
-	for _, server := range cfg.Servers {
-		ph.proxyAddrs = append(ph.proxyAddrs, net.JoinHostPort(server, cfg.Port))
+	if len(upstreams) == 0 {
+		return nil, errors.New("no DNS upstreams configured")
 	}
-	return nil
+
+	return &proxyHandler{upstreams: upstreams}, nil
+}
+
+// upstreamBackoff is how long a failed upstream is tried only after the other ones.
+const upstreamBackoff = 30 * time.Second
+
+type proxyHandler struct {
+	upstreams []upstream
+
+	lock     sync.Mutex
+	failedAt map[upstream]time.Time
 }
 
 func (ph *proxyHandler) ServeDNS(writer dns.ResponseWriter, req *dns.Msg) {
-	for _, addr := range ph.proxyAddrs {
-		resp, _, err := ph.client.Exchange(req, addr)
+	for _, u := range ph.orderedUpstreams() {
+		resp, err := u.exchange(req)
+		ph.reportResult(u, err)
 		if err != nil {
-			log.Error().Err(err).Msg("Error proxying DNS query to " + addr)
+			log.Error().Err(err).Msg("Error proxying DNS query to " + u.String())
 			continue
 		}
 
@@ -74,3 +82,35 @@ func (ph *proxyHandler) ServeDNS(writer dns.ResponseWriter, req *dns.Msg) {
 	resp.SetRcode(req, dns.RcodeServerFailure)
 	writer.WriteMsg(resp)
 }
+
+// orderedUpstreams returns upstreams in the configured order, except that the recently failed ones go last,
+// so that a dead upstream doesn't delay every query by the DNS timeout.
+func (ph *proxyHandler) orderedUpstreams() []upstream {
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	ordered := make([]upstream, 0, len(ph.upstreams))
+	var failed []upstream
+	for _, u := range ph.upstreams {
+		if time.Since(ph.failedAt[u]) < upstreamBackoff {
+			failed = append(failed, u)
+		} else {
+			ordered = append(ordered, u)
+		}
+	}
+	return append(ordered, failed...)
+}
+
+func (ph *proxyHandler) reportResult(u upstream, err error) {
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	if err == nil {
+		delete(ph.failedAt, u)
+		return
+	}
+	if ph.failedAt == nil {
+		ph.failedAt = make(map[upstream]time.Time)
+	}
+	ph.failedAt[u] = time.Now()
+}
@@ -46,22 +46,46 @@ type whitelistHandler struct {
 }
 
 func (wh *whitelistHandler) ServeDNS(writer dns.ResponseWriter, req *dns.Msg) {
+	for _, question := range req.Question {
+		if wh.isHostDenied(question.Name) {
+			log.Debug().Msgf("Host %s is denied by policies", question.Name)
+			writeNameError(writer, req)
+			return
+		}
+	}
+
 	resolverWriter := &recordingWriter{writer: writer}
 	wh.resolver.ServeDNS(resolverWriter, req)
 	resp := resolverWriter.responseMsg
 
+	// Denied host might be reached through CNAME of an allowed one.
+	for _, record := range resp.Answer {
+		if wh.isHostDenied(record.Header().Name) {
+			log.Debug().Msgf("Host %s is denied by policies", record.Header().Name)
+			writeNameError(writer, req)
+			return
+		}
+	}
+
 	if err := wh.whitelistByAnswer(resp); err != nil {
 		log.Warn().Err(err).Msgf("Error updating firewall by DNS query: %s", resp.String())
-
-		resp := &dns.Msg{}
-		resp.SetRcode(req, dns.RcodeNameError)
-		writer.WriteMsg(resp)
+		writeNameError(writer, req)
 		return
 	}
 
 	writer.WriteMsg(resp)
 }
 
+func (wh *whitelistHandler) isHostDenied(name string) bool {
+	return wh.policies.IsHostDenied(strings.TrimRight(name, "."))
+}
+
+func writeNameError(writer dns.ResponseWriter, req *dns.Msg) {
+	resp := &dns.Msg{}
+	resp.SetRcode(req, dns.RcodeNameError)
+	writer.WriteMsg(resp)
+}
+
 func (wh *whitelistHandler) whitelistByAnswer(response *dns.Msg) error {
 	for _, record := range response.Answer {
 		switch recordValue := record.(type) {
@@ -78,7 +102,8 @@ func (wh *whitelistHandler) whitelistByARecord(record *dns.A) error {
 	host := strings.TrimRight(record.Hdr.Name, ".")
 	ip := record.A
 
-	if wh.policies.IsHostAllowed(host) {
+	// Without allow rules traffic is not blocked, so there is nothing to whitelist.
+	if wh.policies.HasDNSAllowRules() && wh.policies.IsHostAllowed(host) {
 		_, err := wh.trafficBlocker.AllowIPAccess(ip)
 		return err
 	}
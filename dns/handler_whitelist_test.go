@@ -35,6 +35,9 @@ var (
 		Allow: []market.AccessRule{
 			{Type: market.AccessPolicyTypeDNSZone, Value: "wildcard.com"},
 		},
+		Deny: []market.AccessRule{
+			{Type: market.AccessPolicyTypeDNSHostname, Value: "ads.wildcard.com"},
+		},
 	}
 
 	policyDNSHostname      = market.AccessPolicy{ID: "domain"}
@@ -164,6 +167,97 @@ func Test_WhitelistAnswers(t *testing.T) {
 	}
 }
 
+func Test_WhitelistAnswers_DeniedHostReturnsNameError(t *testing.T) {
+	tests := []struct {
+		name     string
+		question string
+		response *dns.Msg
+	}{
+		{
+			"should deny queried hostname",
+			"ads.wildcard.com.",
+			&dns.Msg{},
+		},
+		{
+			"should deny hostname resolved through CNAME",
+			"cdn.wildcard.com.",
+			&dns.Msg{
+				Answer: []dns.RR{
+					&dns.CNAME{
+						Hdr:    dns.RR_Header{Name: "cdn.wildcard.com.", Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 0},
+						Target: "ads.wildcard.com.",
+					},
+					&dns.A{
+						Hdr: dns.RR_Header{Name: "ads.wildcard.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0},
+						A:   net.ParseIP("0.0.0.5"),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockedBlocker := &trafficBlockerMock{
+				allowIPCalls: map[string]int{},
+			}
+			writer := &recordingWriter{}
+			handler := WhitelistAnswers(
+				dns.HandlerFunc(func(writer dns.ResponseWriter, req *dns.Msg) {
+					writer.WriteMsg(tt.response)
+				}),
+				mockedBlocker,
+				createPolicies(),
+			)
+
+			req := &dns.Msg{}
+			req.SetQuestion(tt.question, dns.TypeA)
+			handler.ServeDNS(writer, req)
+			assert.Equal(t, map[string]int{}, mockedBlocker.allowIPCalls)
+			assert.Equal(t, dns.RcodeNameError, writer.responseMsg.Rcode)
+		})
+	}
+}
+
+func Test_WhitelistAnswers_DenyOnlyPoliciesDoNotWhitelist(t *testing.T) {
+	policies := policy.NewRepository()
+	policies.SetPolicyRules(
+		market.AccessPolicy{ID: "blocklist"},
+		market.AccessPolicyRuleSet{
+			ID:   "blocklist",
+			Deny: []market.AccessRule{{Type: market.AccessPolicyTypeDNSZone, Value: "ads.com"}},
+		},
+	)
+	mockedBlocker := &trafficBlockerMock{allowIPCalls: map[string]int{}}
+	response := &dns.Msg{
+		Answer: []dns.RR{
+			&dns.A{
+				Hdr: dns.RR_Header{Name: "site.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0},
+				A:   net.ParseIP("0.0.0.6"),
+			},
+		},
+	}
+	handler := WhitelistAnswers(
+		dns.HandlerFunc(func(writer dns.ResponseWriter, req *dns.Msg) {
+			writer.WriteMsg(response)
+		}),
+		mockedBlocker,
+		policies,
+	)
+
+	writer := &recordingWriter{}
+	req := &dns.Msg{}
+	req.SetQuestion("site.com.", dns.TypeA)
+	handler.ServeDNS(writer, req)
+	assert.Equal(t, response, writer.responseMsg)
+	assert.Equal(t, map[string]int{}, mockedBlocker.allowIPCalls)
+
+	writer = &recordingWriter{}
+	req.SetQuestion("tracker.ads.com.", dns.TypeA)
+	handler.ServeDNS(writer, req)
+	assert.Equal(t, dns.RcodeNameError, writer.responseMsg.Rcode)
+}
+
 func createPolicies() *policy.Repository {
 	repo := policy.NewRepository()
 	repo.SetPolicyRules(policyDNSZone, policyDNSZoneRules)
@@ -183,6 +277,10 @@ func (tbn *trafficBlockerMock) BlockIncomingTraffic(net.IPNet) (firewall.Incomin
 	return nil, nil
 }
 
+func (tbn *trafficBlockerMock) RestrictIncomingTraffic(net.IPNet, market.TrafficRules) (firewall.IncomingRuleRemove, error) {
+	return nil, nil
+}
+
 func (tbn *trafficBlockerMock) AllowURLAccess(rawURLs ...string) (firewall.IncomingRuleRemove, error) {
 	return nil, nil
 }
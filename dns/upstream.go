@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+const (
+	upstreamSystem = "system"
+	dohMediaType   = "application/dns-message"
+)
+
+// upstream is a DNS server queries are forwarded to.
+type upstream interface {
+	exchange(req *dns.Msg) (*dns.Msg, error)
+	String() string
+}
+
+// parseUpstreams parses upstream addresses in the form of:
+//   - "system" for DNS servers from the system configuration,
+//   - "1.1.1.1", "udp://1.1.1.1:53" or "tcp://1.1.1.1:53" for plain DNS,
+//   - "tls://1.1.1.1" or "tls://dns.quad9.net:853" for DNS-over-TLS,
+//   - "https://cloudflare-dns.com/dns-query" for DNS-over-HTTPS.
+func parseUpstreams(addresses []string) ([]upstream, error) {
+	var upstreams []upstream
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if address == upstreamSystem {
+			system, err := systemUpstreams()
+			if err != nil {
+				return nil, err
+			}
+			upstreams = append(upstreams, system...)
+			continue
+		}
+
+		u, err := parseUpstream(address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid DNS upstream %q", address)
+		}
+		upstreams = append(upstreams, u)
+	}
+	return upstreams, nil
+}
+
+func parseUpstream(address string) (upstream, error) {
+	if !strings.Contains(address, "://") {
+		address = "udp://" + address
+	}
+	addressURL, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if addressURL.Hostname() == "" {
+		return nil, errors.New("missing host")
+	}
+
+	switch addressURL.Scheme {
+	case "udp", "tcp":
+		return newPlainUpstream(addressURL.Scheme, hostPort(addressURL, "53")), nil
+	case "tls":
+		return newTLSUpstream(hostPort(addressURL, "853"), &tls.Config{ServerName: addressURL.Hostname()}), nil
+	case "https":
+		return newHTTPSUpstream(addressURL.String(), &http.Client{Timeout: dnsTimeout}), nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q", addressURL.Scheme)
+	}
+}
+
+func hostPort(addressURL *url.URL, defaultPort string) string {
+	port := addressURL.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(addressURL.Hostname(), port)
+}
+
+// systemUpstreams returns plain DNS upstreams from the system DNS configuration.
+func systemUpstreams() ([]upstream, error) {
+	cfg, err := configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	var upstreams []upstream
+	for _, server := range cfg.Servers {
+		upstreams = append(upstreams, newPlainUpstream("udp", net.JoinHostPort(server, cfg.Port)))
+	}
+	return upstreams, nil
+}
+
+// clientUpstream exchanges messages over UDP, TCP or TLS using DNS wire protocol.
+type clientUpstream struct {
+	address string
+	client  *dns.Client
+}
+
+func newPlainUpstream(network, address string) *clientUpstream {
+	return &clientUpstream{
+		address: address,
+		client: &dns.Client{
+			Net:          network,
+			DialTimeout:  dnsTimeout,
+			ReadTimeout:  dnsTimeout,
+			WriteTimeout: dnsTimeout,
+		},
+	}
+}
+
+func newTLSUpstream(address string, tlsConfig *tls.Config) *clientUpstream {
+	u := newPlainUpstream("tcp-tls", address)
+	u.client.TLSConfig = tlsConfig
+	return u
+}
+
+func (u *clientUpstream) exchange(req *dns.Msg) (*dns.Msg, error) {
+	resp, _, err := u.client.Exchange(req, u.address)
+	return resp, err
+}
+
+func (u *clientUpstream) String() string {
+	network := u.client.Net
+	if network == "tcp-tls" {
+		network = "tls"
+	}
+	return network + "://" + u.address
+}
+
+// httpsUpstream exchanges messages using DNS-over-HTTPS (RFC 8484).
+type httpsUpstream struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSUpstream(endpoint string, client *http.Client) *httpsUpstream {
+	return &httpsUpstream{url: endpoint, client: client}
+}
+
+func (u *httpsUpstream) exchange(req *dns.Msg) (*dns.Msg, error) {
+	// RFC 8484 recommends zero message ID to make responses cache friendly.
+	query := req.Copy()
+	query.Id = 0
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to pack DNS query")
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, u.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", dohMediaType)
+	httpReq.Header.Set("Accept", dohMediaType)
+
+	httpResp, err := u.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected DNS-over-HTTPS response status: %s", httpResp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read DNS-over-HTTPS response")
+	}
+
+	resp := &dns.Msg{}
+	if err := resp.Unpack(body); err != nil {
+		return nil, errors.Wrap(err, "failed to unpack DNS response")
+	}
+	resp.Id = req.Id
+	return resp, nil
+}
+
+func (u *httpsUpstream) String() string {
+	return u.url
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseUpstream(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+		err      string
+	}{
+		{address: "1.1.1.1", expected: "udp://1.1.1.1:53"},
+		{address: "1.1.1.1:5353", expected: "udp://1.1.1.1:5353"},
+		{address: "tcp://[2606:4700:4700::1111]", expected: "tcp://[2606:4700:4700::1111]:53"},
+		{address: "tls://dns.quad9.net", expected: "tls://dns.quad9.net:853"},
+		{address: "tls://1.1.1.1:8853", expected: "tls://1.1.1.1:8853"},
+		{address: "https://cloudflare-dns.com/dns-query", expected: "https://cloudflare-dns.com/dns-query"},
+		{address: "quic://dns.adguard.com", err: `unsupported scheme "quic"`},
+		{address: "tls://", err: "missing host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			u, err := parseUpstream(tt.address)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, u.String())
+		})
+	}
+}
+
+func Test_ResolveViaUpstreams_RejectsInvalidUpstream(t *testing.T) {
+	_, err := ResolveViaUpstreams([]string{"tls://1.1.1.1", "ftp://1.1.1.1"})
+	assert.EqualError(t, err, `failed to configure DNS upstreams: invalid DNS upstream "ftp://1.1.1.1": unsupported scheme "ftp"`)
+}
+
+func Test_HTTPSUpstream_Exchange(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != dohMediaType {
+			resp.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		query := &dns.Msg{}
+		if err := query.Unpack(body); err != nil || query.Id != 0 {
+			resp.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		packed, _ := answer(query, "0.0.0.1").Pack()
+		resp.Header().Set("Content-Type", dohMediaType)
+		resp.Write(packed)
+	}))
+	defer server.Close()
+
+	u := newHTTPSUpstream(server.URL+"/dns-query", server.Client())
+	resp, err := u.exchange(question(1234, "example.com."))
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1234), resp.Id)
+	assert.Equal(t, "0.0.0.1", resp.Answer[0].(*dns.A).A.String())
+}
+
+func Test_HTTPSUpstream_ExchangeFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	u := newHTTPSUpstream(server.URL, server.Client())
+	_, err := u.exchange(question(1, "example.com."))
+
+	assert.EqualError(t, err, "unexpected DNS-over-HTTPS response status: 502 Bad Gateway")
+}
+
+func Test_TLSUpstream_Exchange(t *testing.T) {
+	address, tlsConfig, stop := startTLSServer(t, "0.0.0.2")
+	defer stop()
+
+	u := newTLSUpstream(address, tlsConfig)
+	resp, err := u.exchange(question(4321, "example.com."))
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(4321), resp.Id)
+	assert.Equal(t, "0.0.0.2", resp.Answer[0].(*dns.A).A.String())
+}
+
+func Test_TLSUpstream_ExchangeRejectsUntrustedServer(t *testing.T) {
+	address, _, stop := startTLSServer(t, "0.0.0.2")
+	defer stop()
+
+	u := newTLSUpstream(address, &tls.Config{ServerName: "example.com"})
+	_, err := u.exchange(question(1, "example.com."))
+
+	assert.Error(t, err)
+}
+
+func Test_ProxyHandler_FallsBackToNextUpstream(t *testing.T) {
+	address, tlsConfig, stop := startTLSServer(t, "0.0.0.3")
+	defer stop()
+
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	handler := &proxyHandler{upstreams: []upstream{
+		newHTTPSUpstream(failing.URL, failing.Client()),
+		newTLSUpstream(address, tlsConfig),
+	}}
+	writer := &recordingWriter{}
+	handler.ServeDNS(writer, question(1, "example.com."))
+
+	assert.Equal(t, dns.RcodeSuccess, writer.responseMsg.Rcode)
+	assert.Equal(t, "0.0.0.3", writer.responseMsg.Answer[0].(*dns.A).A.String())
+}
+
+func Test_ProxyHandler_BacksOffFailedUpstream(t *testing.T) {
+	address, tlsConfig, stop := startTLSServer(t, "0.0.0.4")
+	defer stop()
+
+	var failedQueries int32
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&failedQueries, 1)
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	handler := &proxyHandler{upstreams: []upstream{
+		newHTTPSUpstream(failing.URL, failing.Client()),
+		newTLSUpstream(address, tlsConfig),
+	}}
+	for i := 0; i < 3; i++ {
+		writer := &recordingWriter{}
+		handler.ServeDNS(writer, question(uint16(i), "example.com."))
+
+		assert.Equal(t, dns.RcodeSuccess, writer.responseMsg.Rcode)
+		assert.Equal(t, "0.0.0.4", writer.responseMsg.Answer[0].(*dns.A).A.String())
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&failedQueries))
+}
+
+func Test_ProxyHandler_FailsWhenAllUpstreamsFail(t *testing.T) {
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	handler := &proxyHandler{upstreams: []upstream{newHTTPSUpstream(failing.URL, failing.Client())}}
+	writer := &recordingWriter{}
+	handler.ServeDNS(writer, question(1, "example.com."))
+
+	assert.Equal(t, dns.RcodeServerFailure, writer.responseMsg.Rcode)
+}
+
+// startTLSServer starts DNS-over-TLS server answering all queries with the given IP,
+// returns its address and client TLS config trusting it.
+func startTLSServer(t *testing.T, ip string) (string, *tls.Config, func()) {
+	// Borrow self-signed certificate of the test HTTPS server.
+	certServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certServer.Close()
+	clientTLSConfig := certServer.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	clientTLSConfig.ServerName = "example.com"
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certServer.TLS.Certificates})
+	assert.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		Listener:          listener,
+		Net:               "tcp-tls",
+		NotifyStartedFunc: func() { close(started) },
+		Handler: dns.HandlerFunc(func(writer dns.ResponseWriter, req *dns.Msg) {
+			writer.WriteMsg(answer(req, ip))
+		}),
+	}
+	go server.ActivateAndServe()
+	<-started
+
+	return listener.Addr().String(), clientTLSConfig, func() { server.Shutdown() }
+}
+
+func question(id uint16, name string) *dns.Msg {
+	msg := &dns.Msg{}
+	msg.SetQuestion(name, dns.TypeA)
+	msg.Id = id
+	return msg
+}
+
+func answer(req *dns.Msg, ip string) *dns.Msg {
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Answer = append(resp.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP(ip),
+	})
+	return resp
+}
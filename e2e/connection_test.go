@@ -20,6 +20,7 @@ package e2e
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -107,6 +108,10 @@ func TestConsumerConnectsToProvider(t *testing.T) {
 		providerRegistrationFlow(t, tequilapiProvider, providerID, providerPassphrase)
 	})
 
+	t.Run("Consumer discovers provider through gossip", func(t *testing.T) {
+		consumerGossipDiscoveryFlow(t, newTequilapiConsumer("myst-consumer-gossip"))
+	})
+
 	t.Run("Consumer Creates And Registers Identity", func(t *testing.T) {
 		wg := sync.WaitGroup{}
 		wg.Add(len(consumersToTest))
@@ -335,6 +340,26 @@ func consumerPicksProposal(t *testing.T, tequilapi *tequilapi_client.Client, ser
 	return proposals[0]
 }
 
+// consumerGossipDiscoveryFlow checks that consumer which uses gossip discovery only learns verified provider proposals
+// relayed through another node.
+func consumerGossipDiscoveryFlow(t *testing.T, tequilapi *tequilapi_client.Client) {
+	var proposals []contract.ProposalDTO
+	assert.Eventually(t, func() bool {
+		p, err := tequilapi.Proposals()
+		if err != nil {
+			log.Err(err).Msg("Could not list proposals")
+			return false
+		}
+		proposals = p
+		return len(p) > 0
+	}, time.Second*30, time.Millisecond*200)
+
+	for _, proposal := range proposals {
+		assert.Equal(t, providerID, strings.ToLower(proposal.ProviderID))
+		assert.True(t, proposal.Verified)
+	}
+}
+
 func consumerConnectFlow(t *testing.T, tequilapi *tequilapi_client.Client, consumerID, hermesID, serviceType string, proposal contract.ProposalDTO) *big.Int {
 	connectionStatus, err := tequilapi.ConnectionStatus()
 	assert.NoError(t, err)
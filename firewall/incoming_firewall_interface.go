@@ -19,6 +19,8 @@ package firewall
 
 import (
 	"net"
+
+	"github.com/mysteriumnetwork/node/market"
 )
 
 // IncomingTrafficFirewall defines provider side firewall, to control which traffic is enabled to pass and which not.
@@ -26,6 +28,8 @@ type IncomingTrafficFirewall interface {
 	Setup() error
 	Teardown()
 	BlockIncomingTraffic(network net.IPNet) (IncomingRuleRemove, error)
+	// RestrictIncomingTraffic restricts destinations and ports of traffic from the network by given rules.
+	RestrictIncomingTraffic(network net.IPNet, rules market.TrafficRules) (IncomingRuleRemove, error)
 	AllowURLAccess(rawURLs ...string) (IncomingRuleRemove, error)
 	AllowIPAccess(ip net.IP) (IncomingRuleRemove, error)
 }
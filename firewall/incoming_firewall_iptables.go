@@ -18,55 +18,131 @@
 package firewall
 
 import (
+	"fmt"
+	"hash/fnv"
 	"net"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mysteriumnetwork/node/firewall/ipset"
 	"github.com/mysteriumnetwork/node/firewall/iptables"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	incomingFirewallChain = "MYST_PROVIDER_FIREWALL"
-	incomingFirewallIpset = "myst-provider-dst-whitelist"
+	incomingFirewallChain  = "MYST_PROVIDER_FIREWALL"
+	incomingFirewallIpset  = "myst-provider-dst-whitelist"
+	incomingFirewallIpset6 = "myst-provider-dst-whitelist6"
+	// incomingPolicyChainPrefix prefixes chains which restrict traffic of a single network by policy rules
+	incomingPolicyChainPrefix = "MYST_POLICY_"
 )
 
+// ipFamily is iptables executable and destination whitelist of a single IP family.
+type ipFamily struct {
+	ipv6  bool
+	exec  func(args ...string) ([]string, error)
+	ipset string
+}
+
+func ipv4Family() ipFamily {
+	return ipFamily{exec: iptables.Exec, ipset: incomingFirewallIpset}
+}
+
+func ipv6Family() ipFamily {
+	return ipFamily{ipv6: true, exec: iptables.Exec6, ipset: incomingFirewallIpset6}
+}
+
+func familyOf(ip net.IP) ipFamily {
+	if ip.To4() == nil {
+		return ipv6Family()
+	}
+	return ipv4Family()
+}
+
 // incomingFirewallIptables allows incoming traffic blocking in IP granularity.
-type incomingFirewallIptables struct{}
+// IPv6 firewall is set up only when IPv6 network is blocked for the first time,
+// so that hosts without ip6tables are not affected.
+type incomingFirewallIptables struct {
+	mu   sync.Mutex
+	ipv6 bool
+}
 
 func (ibi *incomingFirewallIptables) Setup() error {
 	if err := ibi.checkIpsetVersion(); err != nil {
 		return err
 	}
+	return ibi.setupFamily(ipv4Family())
+}
 
+func (ibi *incomingFirewallIptables) setupFamily(family ipFamily) error {
 	// Clean up setups from previous runs, just in case
-	if err := ibi.cleanupStaleRules(); err != nil {
+	if err := ibi.cleanupStaleRules(family); err != nil {
 		return err
 	}
-	ipset.Exec(ipset.OpDelete(incomingFirewallIpset))
+	ipset.Exec(ipset.OpDelete(family.ipset))
 
-	op := ipset.OpCreate(incomingFirewallIpset, ipset.SetTypeHashIP, 24*time.Hour, nil, 0)
+	op := ipset.OpCreate(family.ipset, ipset.SetTypeHashIP, 24*time.Hour, nil, 0)
+	if family.ipv6 {
+		op = append(op, "family", "inet6")
+	}
 	if _, err := ipset.Exec(op); err != nil {
 		return err
 	}
-	return ibi.setupFirewallChain()
+	return ibi.setupFirewallChain(family)
+}
+
+// setupIPv6 sets up IPv6 firewall unless it is already set up.
+func (ibi *incomingFirewallIptables) setupIPv6() error {
+	ibi.mu.Lock()
+	defer ibi.mu.Unlock()
+
+	if ibi.ipv6 {
+		return nil
+	}
+	if err := ibi.setupFamily(ipv6Family()); err != nil {
+		return fmt.Errorf("could not setup IPv6 firewall: %w", err)
+	}
+	ibi.ipv6 = true
+	return nil
+}
+
+func (ibi *incomingFirewallIptables) ipv6Ready() bool {
+	ibi.mu.Lock()
+	defer ibi.mu.Unlock()
+
+	return ibi.ipv6
 }
 
 func (ibi *incomingFirewallIptables) Teardown() {
-	if err := ibi.cleanupStaleRules(); err != nil {
-		log.Warn().Err(err).Msg("Error cleaning up iptables rules, you might want to do it yourself")
+	families := []ipFamily{ipv4Family()}
+	if ibi.ipv6Ready() {
+		families = append(families, ipv6Family())
 	}
-	if errOutput, err := ipset.Exec(ipset.OpDelete(incomingFirewallIpset)); err != nil {
-		log.Warn().Err(err).Msgf("Error deleting ipset table. %s", strings.Join(errOutput, ""))
+
+	for _, family := range families {
+		if err := ibi.cleanupStaleRules(family); err != nil {
+			log.Warn().Err(err).Msg("Error cleaning up iptables rules, you might want to do it yourself")
+		}
+		if errOutput, err := ipset.Exec(ipset.OpDelete(family.ipset)); err != nil {
+			log.Warn().Err(err).Msgf("Error deleting ipset table. %s", strings.Join(errOutput, ""))
+		}
 	}
 }
 
 func (ibi *incomingFirewallIptables) BlockIncomingTraffic(network net.IPNet) (IncomingRuleRemove, error) {
-	remover, err := iptables.AddRuleWithRemoval(
-		iptables.AppendTo("FORWARD").RuleSpec("-s", network.String(), "-j", incomingFirewallChain),
-	)
+	rule := iptables.AppendTo("FORWARD").RuleSpec("-s", network.String(), "-j", incomingFirewallChain)
+	if familyOf(network.IP).ipv6 {
+		if err := ibi.setupIPv6(); err != nil {
+			return nil, err
+		}
+		rule = rule.IPv6()
+	}
+
+	remover, err := iptables.AddRuleWithRemoval(rule)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +152,49 @@ func (ibi *incomingFirewallIptables) BlockIncomingTraffic(network net.IPNet) (In
 	}, nil
 }
 
+// RestrictIncomingTraffic rejects traffic from the network to denied destinations and ports, and to the ones which are not allowed.
+// Allowed packets return to the FORWARD chain, so that they are still checked by the rest of firewall rules.
+func (ibi *incomingFirewallIptables) RestrictIncomingTraffic(network net.IPNet, rules market.TrafficRules) (IncomingRuleRemove, error) {
+	if rules.Empty() {
+		return func() error { return nil }, nil
+	}
+
+	family := familyOf(network.IP)
+	chain := incomingPolicyChain(network)
+	if _, err := family.exec("-N", chain); err != nil {
+		return nil, err
+	}
+	removeChain := func() {
+		for _, args := range [][]string{{"-F", chain}, {"-X", chain}} {
+			if _, err := family.exec(args...); err != nil {
+				log.Warn().Err(err).Msgf("Error removing chain %s, you might want to do it yourself", chain)
+			}
+		}
+	}
+
+	for _, spec := range policyRuleSpecs(rules, family.ipv6) {
+		if _, err := family.exec(append([]string{"-A", chain}, spec...)...); err != nil {
+			removeChain()
+			return nil, err
+		}
+	}
+
+	rule := iptables.InsertAt("FORWARD", 1).RuleSpec("-s", network.String(), "-j", chain)
+	if family.ipv6 {
+		rule = rule.IPv6()
+	}
+	remover, err := iptables.AddRuleWithRemoval(rule)
+	if err != nil {
+		removeChain()
+		return nil, err
+	}
+	return func() error {
+		remover()
+		removeChain()
+		return nil
+	}, nil
+}
+
 // AllowURLAccess adds URL based exception.
 func (ibi *incomingFirewallIptables) AllowURLAccess(rawURLs ...string) (IncomingRuleRemove, error) {
 	var ruleRemovers []func()
@@ -106,11 +225,17 @@ func (ibi *incomingFirewallIptables) AllowURLAccess(rawURLs ...string) (Incoming
 }
 
 func (ibi *incomingFirewallIptables) AllowIPAccess(ip net.IP) (IncomingRuleRemove, error) {
-	if _, err := ipset.Exec(ipset.OpIPAdd(incomingFirewallIpset, ip, true)); err != nil {
+	family := familyOf(ip)
+	// No IPv6 traffic is blocked until IPv6 firewall is set up.
+	if family.ipv6 && !ibi.ipv6Ready() {
+		return func() error { return nil }, nil
+	}
+
+	if _, err := ipset.Exec(ipset.OpIPAdd(family.ipset, ip, true)); err != nil {
 		return nil, err
 	}
 	return func() error {
-		_, err := ipset.Exec(ipset.OpIPRemove(incomingFirewallIpset, ip))
+		_, err := ipset.Exec(ipset.OpIPRemove(family.ipset, ip))
 		return err
 	}, nil
 }
@@ -126,57 +251,147 @@ func (ibi *incomingFirewallIptables) checkIpsetVersion() error {
 	return nil
 }
 
-func (ibi *incomingFirewallIptables) setupFirewallChain() error {
+func (ibi *incomingFirewallIptables) setupFirewallChain(family ipFamily) error {
 	// Add chain
-	if _, err := iptables.Exec("-N", incomingFirewallChain); err != nil {
+	if _, err := family.exec("-N", incomingFirewallChain); err != nil {
 		return err
 	}
 
 	// Append rule - packets going to firewall with these destination IPs are whitelisted
-	if _, err := iptables.Exec("-A", incomingFirewallChain, "-m", "set", "--match-set", incomingFirewallIpset, "dst", "-j", "ACCEPT"); err != nil {
+	if _, err := family.exec("-A", incomingFirewallChain, "-m", "set", "--match-set", family.ipset, "dst", "-j", "ACCEPT"); err != nil {
 		return err
 	}
 
 	// Append rule - by default all packets going to firewall chain are rejected
-	if _, err := iptables.Exec("-A", incomingFirewallChain, "-j", "REJECT"); err != nil {
+	if _, err := family.exec("-A", incomingFirewallChain, "-j", "REJECT"); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (ibi *incomingFirewallIptables) cleanupStaleRules() error {
+func (ibi *incomingFirewallIptables) cleanupStaleRules(family ipFamily) error {
 	// List rules
-	rules, err := iptables.Exec("-S", "FORWARD")
+	rules, err := family.exec("-S", "FORWARD")
 	if err != nil {
 		return err
 	}
+	var policyChains []string
 	for _, rule := range rules {
-		// detect if any references exist in FORWARD chain like -j MYST_PROVIDER_FIREWALL
-		if strings.HasSuffix(rule, incomingFirewallChain) {
+		// detect if any references exist in FORWARD chain like -j MYST_PROVIDER_FIREWALL or -j MYST_POLICY_0A080000
+		isPolicyRule := strings.Contains(rule, "-j "+incomingPolicyChainPrefix)
+		if strings.HasSuffix(rule, incomingFirewallChain) || isPolicyRule {
 			deleteRule := strings.Replace(rule, "-A", "-D", 1)
 			deleteRuleArgs := strings.Split(deleteRule, " ")
-			if _, err := iptables.Exec(deleteRuleArgs...); err != nil {
+			if _, err := family.exec(deleteRuleArgs...); err != nil {
 				return err
 			}
 		}
+		if isPolicyRule {
+			policyChains = append(policyChains, ruleJumpTarget(rule))
+		}
+	}
+	for _, chain := range policyChains {
+		if _, err := family.exec("-F", chain); err != nil {
+			return err
+		}
+		if _, err := family.exec("-X", chain); err != nil {
+			return err
+		}
 	}
 
 	// List chain rules
-	if _, err := iptables.Exec("-L", incomingFirewallChain); err != nil {
+	if _, err := family.exec("-L", incomingFirewallChain); err != nil {
 		// error means no such chain - log error just in case and bail out
 		log.Info().Err(err).Msg("[setup] Got error while listing kill switch chain rules. Probably nothing to worry about")
 		return nil
 	}
 
 	// Remove chain rules
-	if _, err := iptables.Exec("-F", incomingFirewallChain); err != nil {
+	if _, err := family.exec("-F", incomingFirewallChain); err != nil {
 		return err
 	}
 
 	// Remove chain
-	_, err = iptables.Exec("-X", incomingFirewallChain)
+	_, err = family.exec("-X", incomingFirewallChain)
 	return err
 }
 
+// incomingPolicyChain names policy chain of the network, names are limited to 28 characters by iptables.
+func incomingPolicyChain(network net.IPNet) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(network.String()))
+	return fmt.Sprintf("%s%08X", incomingPolicyChainPrefix, hash.Sum32())
+}
+
+// ruleJumpTarget returns the jump target of the rule listed by "iptables -S".
+func ruleJumpTarget(rule string) string {
+	fields := strings.Fields(rule)
+	for i := range fields {
+		if fields[i] == "-j" && i+1 < len(fields) {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
+// policyRuleSpecs returns specifications of rules rejecting denied destinations and ports first,
+// then accepting every combination of allowed destination and port and rejecting the rest.
+// Only destinations of the given IP family are included.
+func policyRuleSpecs(rules market.TrafficRules, ipv6 bool) [][]string {
+	var specs [][]string
+	for _, spec := range append(destinationSpecs(rules.DeniedNetworks, ipv6), portSpecs(rules.DeniedPorts)...) {
+		specs = append(specs, append(spec, "-j", "REJECT"))
+	}
+
+	if rules.AllowedNetworks == nil && rules.AllowedPorts == nil {
+		return append(specs, []string{"-j", "RETURN"})
+	}
+
+	allowedDestinations := destinationSpecs(rules.AllowedNetworks, ipv6)
+	if rules.AllowedNetworks == nil {
+		allowedDestinations = [][]string{nil}
+	}
+	allowedPorts := portSpecs(rules.AllowedPorts)
+	if rules.AllowedPorts == nil {
+		allowedPorts = [][]string{nil}
+	}
+	for _, destinationSpec := range allowedDestinations {
+		for _, portSpec := range allowedPorts {
+			spec := append(append([]string{}, destinationSpec...), portSpec...)
+			specs = append(specs, append(spec, "-j", "RETURN"))
+		}
+	}
+	return append(specs, []string{"-j", "REJECT"})
+}
+
+func destinationSpecs(networks []net.IPNet, ipv6 bool) [][]string {
+	var specs [][]string
+	for _, network := range networks {
+		if (network.IP.To4() == nil) != ipv6 {
+			continue
+		}
+		specs = append(specs, []string{"-d", network.String()})
+	}
+	return specs
+}
+
+func portSpecs(ports []market.PortRange) [][]string {
+	var specs [][]string
+	for _, port := range ports {
+		dport := strconv.Itoa(port.From)
+		if port.To != port.From {
+			dport += ":" + strconv.Itoa(port.To)
+		}
+		protocols := []string{market.ProtocolTCP, market.ProtocolUDP}
+		if port.Protocol != "" {
+			protocols = []string{port.Protocol}
+		}
+		for _, protocol := range protocols {
+			specs = append(specs, []string{"-p", protocol, "--dport", dport})
+		}
+	}
+	return specs
+}
+
 var _ IncomingTrafficFirewall = &incomingFirewallIptables{}
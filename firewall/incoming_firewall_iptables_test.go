@@ -23,6 +23,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/firewall/ipset"
 	"github.com/mysteriumnetwork/node/firewall/iptables"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -134,6 +135,45 @@ func Test_incomingFirewallIptables_BlockIncomingTraffic(t *testing.T) {
 	assert.True(t, mockedIptables.VerifyCalledWithArgs("-D FORWARD -s 10.8.0.0/24 -j MYST_PROVIDER_FIREWALL"))
 }
 
+func Test_incomingFirewallIptables_BlockIncomingTrafficIPv6(t *testing.T) {
+	mockedIpset := ipsetExecMock{
+		mocks: map[string]ipsetExecResult{},
+	}
+	ipset.Exec = mockedIpset.Exec
+
+	mockedIptables := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{},
+	}
+	iptables.Exec = mockedIptables.Exec
+	mockedIptables6 := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{},
+	}
+	iptables.Exec6 = mockedIptables6.Exec
+
+	fw := &incomingFirewallIptables{}
+
+	// IPv6 addresses are not whitelisted until IPv6 firewall is set up.
+	_, err := fw.AllowIPAccess(net.ParseIP("2001:db8::1"))
+	assert.NoError(t, err)
+	assert.Empty(t, mockedIpset.mocks)
+
+	_, network, _ := net.ParseCIDR("fd00:6d79::2/64")
+	removeRule, err := fw.BlockIncomingTraffic(*network)
+	assert.NoError(t, err)
+	assert.True(t, mockedIpset.VerifyCalledWithArgs("create myst-provider-dst-whitelist6 hash:ip --timeout 86400 family inet6"))
+	assert.True(t, mockedIptables6.VerifyCalledWithArgs("-N MYST_PROVIDER_FIREWALL"))
+	assert.True(t, mockedIptables6.VerifyCalledWithArgs("-A MYST_PROVIDER_FIREWALL -m set --match-set myst-provider-dst-whitelist6 dst -j ACCEPT"))
+	assert.True(t, mockedIptables6.VerifyCalledWithArgs("-A FORWARD -s fd00:6d79::/64 -j MYST_PROVIDER_FIREWALL"))
+	assert.Empty(t, mockedIptables.mocks)
+
+	_, err = fw.AllowIPAccess(net.ParseIP("2001:db8::1"))
+	assert.NoError(t, err)
+	assert.True(t, mockedIpset.VerifyCalledWithArgs("add myst-provider-dst-whitelist6 2001:db8::1 --exist"))
+
+	removeRule()
+	assert.True(t, mockedIptables6.VerifyCalledWithArgs("-D FORWARD -s fd00:6d79::/64 -j MYST_PROVIDER_FIREWALL"))
+}
+
 func Test_incomingFirewallIptables_AllowIPAccess(t *testing.T) {
 	mockedIpset := ipsetExecMock{
 		mocks: map[string]ipsetExecResult{},
@@ -150,3 +190,102 @@ func Test_incomingFirewallIptables_AllowIPAccess(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, mockedIpset.VerifyCalledWithArgs("del myst-provider-dst-whitelist 1.2.3.4"))
 }
+
+func Test_incomingFirewallIptables_RestrictIncomingTraffic(t *testing.T) {
+	mockedIptables := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{},
+	}
+	iptables.Exec = mockedIptables.Exec
+
+	fw := &incomingFirewallIptables{}
+
+	_, network, _ := net.ParseCIDR("10.8.0.1/24")
+	_, destination, _ := net.ParseCIDR("10.0.0.0/8")
+	chain := incomingPolicyChain(*network)
+	removeRule, err := fw.RestrictIncomingTraffic(*network, market.TrafficRules{
+		AllowedNetworks: []net.IPNet{*destination},
+		AllowedPorts:    []market.PortRange{{Protocol: market.ProtocolTCP, From: 443, To: 443}, {From: 8000, To: 9000}},
+		DeniedPorts:     []market.PortRange{{Protocol: market.ProtocolTCP, From: 25, To: 25}},
+	})
+	assert.NoError(t, err)
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-N", chain))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-A", chain, "-p tcp --dport 25 -j REJECT"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-A", chain, "-d 10.0.0.0/8 -p tcp --dport 443 -j RETURN"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-A", chain, "-d 10.0.0.0/8 -p tcp --dport 8000:9000 -j RETURN"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-A", chain, "-d 10.0.0.0/8 -p udp --dport 8000:9000 -j RETURN"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-A", chain, "-j REJECT"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-I FORWARD 1 -s 10.8.0.0/24 -j", chain))
+
+	assert.NoError(t, removeRule())
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-D FORWARD -s 10.8.0.0/24 -j", chain))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-F", chain))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-X", chain))
+}
+
+func Test_incomingFirewallIptables_RestrictIncomingTrafficWithoutRules(t *testing.T) {
+	mockedIptables := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{},
+	}
+	iptables.Exec = mockedIptables.Exec
+
+	fw := &incomingFirewallIptables{}
+
+	_, network, _ := net.ParseCIDR("10.8.0.1/24")
+	_, err := fw.RestrictIncomingTraffic(*network, market.TrafficRules{})
+	assert.NoError(t, err)
+	assert.Empty(t, mockedIptables.mocks)
+}
+
+func Test_policyRuleSpecs(t *testing.T) {
+	_, destination, _ := net.ParseCIDR("10.0.0.0/8")
+
+	assert.Equal(t, [][]string{
+		{"-d", "10.0.0.0/8", "-j", "RETURN"},
+		{"-j", "REJECT"},
+	}, policyRuleSpecs(market.TrafficRules{AllowedNetworks: []net.IPNet{*destination}}, false))
+	assert.Equal(t, [][]string{
+		{"-p", "udp", "--dport", "53", "-j", "RETURN"},
+		{"-j", "REJECT"},
+	}, policyRuleSpecs(market.TrafficRules{AllowedPorts: []market.PortRange{{Protocol: market.ProtocolUDP, From: 53, To: 53}}}, false))
+	assert.Equal(t, [][]string{
+		{"-j", "REJECT"},
+	}, policyRuleSpecs(market.TrafficRules{AllowedNetworks: []net.IPNet{}}, false))
+	assert.Equal(t, [][]string{
+		{"-d", "10.0.0.0/8", "-j", "REJECT"},
+		{"-p", "tcp", "--dport", "25", "-j", "REJECT"},
+		{"-p", "udp", "--dport", "25", "-j", "REJECT"},
+		{"-j", "RETURN"},
+	}, policyRuleSpecs(market.TrafficRules{DeniedNetworks: []net.IPNet{*destination}, DeniedPorts: []market.PortRange{{From: 25, To: 25}}}, false))
+
+	// Destinations of the other IP family are skipped.
+	_, destination6, _ := net.ParseCIDR("2001:db8::/32")
+	assert.Equal(t, [][]string{
+		{"-d", "2001:db8::/32", "-j", "RETURN"},
+		{"-j", "REJECT"},
+	}, policyRuleSpecs(market.TrafficRules{AllowedNetworks: []net.IPNet{*destination, *destination6}}, true))
+}
+
+func Test_incomingFirewallIptables_TeardownRemovesPolicyChains(t *testing.T) {
+	mockedIpset := ipsetExecMock{
+		mocks: map[string]ipsetExecResult{},
+	}
+	ipset.Exec = mockedIpset.Exec
+
+	mockedIptables := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{
+			"-S FORWARD": {
+				output: []string{
+					"-P FORWARD ACCEPT",
+					"-A FORWARD -s 10.8.0.0/24 -j MYST_POLICY_0A080000",
+				},
+			},
+		},
+	}
+	iptables.Exec = mockedIptables.Exec
+
+	fw := &incomingFirewallIptables{}
+	fw.Teardown()
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-D FORWARD -s 10.8.0.0/24 -j MYST_POLICY_0A080000"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-F MYST_POLICY_0A080000"))
+	assert.True(t, mockedIptables.VerifyCalledWithArgs("-X MYST_POLICY_0A080000"))
+}
@@ -20,6 +20,7 @@ package firewall
 import (
 	"net"
 
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/rs/zerolog/log"
 )
 
@@ -47,6 +48,15 @@ func (ifn *incomingFirewallNoop) BlockIncomingTraffic(network net.IPNet) (Incomi
 	}, nil
 }
 
+// RestrictIncomingTraffic just logs the call.
+func (ifn *incomingFirewallNoop) RestrictIncomingTraffic(network net.IPNet, rules market.TrafficRules) (IncomingRuleRemove, error) {
+	log.Info().Msgf("Incoming traffic restriction requested for %s", network.String())
+	return func() error {
+		log.Info().Msgf("Incoming traffic restriction removed for %s", network.String())
+		return nil
+	}, nil
+}
+
 // AllowIPAccess logs URL for which access was requested.
 func (ifn *incomingFirewallNoop) AllowURLAccess(rawURLs ...string) (IncomingRuleRemove, error) {
 	for _, rawURL := range rawURLs {
@@ -24,6 +24,7 @@ type Rule struct {
 	chainName string
 	action    []string
 	ruleSpec  []string
+	ipv6      bool
 }
 
 // AppendTo creates a new rule to be appended to the specified chain.
@@ -48,6 +49,20 @@ func (r Rule) RuleSpec(spec ...string) Rule {
 	return r
 }
 
+// IPv6 marks the rule to be managed by ip6tables.
+func (r Rule) IPv6() Rule {
+	r.ipv6 = true
+	return r
+}
+
+// Executable returns path of the executable which manages the rule.
+func (r Rule) Executable() string {
+	if r.ipv6 {
+		return ip6tablesPath
+	}
+	return iptablesPath
+}
+
 // ApplyArgs returns an argument list to be passed to the iptables executable to APPLY the rule.
 func (r Rule) ApplyArgs() []string {
 	return append(r.action, r.ruleSpec...)
@@ -61,6 +76,7 @@ func (r Rule) RemoveArgs() []string {
 // Equals checks if two Rules are equal.
 func (r Rule) Equals(another Rule) bool {
 	return r.chainName == another.chainName &&
+		r.ipv6 == another.ipv6 &&
 		equalStringSlice(r.ruleSpec, another.ruleSpec)
 }
 
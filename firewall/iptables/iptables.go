@@ -26,11 +26,27 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	iptablesPath  = "/usr/sbin/iptables"
+	ip6tablesPath = "/usr/sbin/ip6tables"
+)
+
 // Exec executes given args
 var Exec = defaultExec
 
+// Exec6 executes given args using ip6tables
+var Exec6 = defaultExec6
+
 func defaultExec(args ...string) ([]string, error) {
-	args = append([]string{"sudo", "/usr/sbin/iptables"}, args...)
+	return execOutput(iptablesPath, args...)
+}
+
+func defaultExec6(args ...string) ([]string, error) {
+	return execOutput(ip6tablesPath, args...)
+}
+
+func execOutput(executable string, args ...string) ([]string, error) {
+	args = append([]string{"sudo", executable}, args...)
 	output, err := cmdutil.ExecOutput(args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "iptables cmd error")
@@ -44,13 +60,24 @@ func defaultExec(args ...string) ([]string, error) {
 	return lines, outputScanner.Err()
 }
 
+// IPv6NATSupported checks whether ip6tables is available and supports the nat table.
+func IPv6NATSupported() bool {
+	_, err := Exec6("--table", "nat", "--list-rules", "POSTROUTING")
+	return err == nil
+}
+
 // AddRuleWithRemoval activates given rule
 func AddRuleWithRemoval(rule Rule) (func(), error) {
-	if _, err := Exec(rule.ApplyArgs()...); err != nil {
+	exec := Exec
+	if rule.ipv6 {
+		exec = Exec6
+	}
+
+	if _, err := exec(rule.ApplyArgs()...); err != nil {
 		return nil, err
 	}
 	return func() {
-		_, err := Exec(rule.RemoveArgs()...)
+		_, err := exec(rule.RemoveArgs()...)
 		if err != nil {
 			log.Warn().Err(err).Msgf("Error executing rule: %v you might wanna do it yourself", rule.RemoveArgs())
 		}
@@ -1,6 +1,9 @@
+//go:generate go install ./p2p/protoc-gen-go-p2p
+//go:generate protoc -I=. --go_out=./pb ./pb/rpc.proto
 //go:generate protoc -I=. --go_out=./pb ./pb/ping.proto
-//go:generate protoc -I=. --go_out=./pb ./pb/p2p.proto
-//go:generate protoc -I=. --go_out=./pb ./pb/session.proto
-//go:generate protoc -I=. --go_out=./pb ./pb/payment.proto
+//go:generate protoc -I=. --go_out=./pb --go-p2p_out=Mpb/p2p.proto=github.com/mysteriumnetwork/node/pb:./p2p ./pb/p2p.proto
+//go:generate protoc -I=. --go_out=./pb --go-p2p_out=Mpb/session.proto=github.com/mysteriumnetwork/node/pb:./p2p ./pb/session.proto
+//go:generate protoc -I=. --go_out=./pb --go-p2p_out=Mpb/payment.proto=github.com/mysteriumnetwork/node/pb:./p2p ./pb/payment.proto
+//go:generate protoc -I=. --go_out=./pb --go-p2p_out=Mpb/probe.proto=github.com/mysteriumnetwork/node/pb:./p2p ./pb/probe.proto
 
 package main
@@ -47,6 +47,21 @@ func NewMockKeystore() *mockKeystore {
 	return NewMockKeystoreWith(map[common.Address]MockKey{})
 }
 
+// NewMockSigner creates identity with a new key in mock keystore and returns it along with its signer
+func NewMockSigner() (Identity, Signer) {
+	ks := NewMockKeystore()
+	account, err := ks.NewAccount("")
+	if err != nil {
+		panic(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		panic(err)
+	}
+
+	id := FromAddress(account.Address.Hex())
+	return id, NewSigner(ks, id)
+}
+
 // NewMockKeystoreWith returns a new mock keystore with specified keys
 func NewMockKeystoreWith(keys map[common.Address]MockKey) *mockKeystore {
 	copied := make(map[common.Address]MockKey)
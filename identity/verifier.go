@@ -17,6 +17,14 @@
 
 package identity
 
+// VerifierFactory callback returning Verifier of the given identity
+type VerifierFactory func(id Identity) Verifier
+
+// VerifierIdentityFactory is VerifierFactory of verifiers constructed by NewVerifierIdentity
+var VerifierIdentityFactory VerifierFactory = func(id Identity) Verifier {
+	return NewVerifierIdentity(id)
+}
+
 // Verifier checks message's sanity
 type Verifier interface {
 	Verify(message []byte, signature Signature) bool
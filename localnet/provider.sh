@@ -24,6 +24,7 @@ exec /node/build/myst/myst \
   --hermes.hermes-id=0xf2e2c77D2e7207d8341106E6EfA469d1940FD0d8 \
   --transactor.address=http://transactor:8888/api/v1 \
   --quality.address=http://morqa:8085/api/v1 \
+  --p2p.relay.addresses=172.30.0.220:4060 \
   --keystore.lightweight service \
   --agreed-terms-and-conditions \
   --identity=0xd1a23227bd5ad77f36ba62badcb78a410a1db6c5 \
@@ -17,6 +17,14 @@
 
 package market
 
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
 const (
 	// AccessPolicyTypeIdentity Explicitly allow just specific identities ("0xd1faed693fec75389c3d1e59b863e4835ac6f5d1")
 	AccessPolicyTypeIdentity = "identity"
@@ -24,6 +32,16 @@ const (
 	AccessPolicyTypeDNSHostname = "dns_hostname"
 	// AccessPolicyTypeDNSZone Explicitly allow just specific DNS zone ("example.com" matches "example.com" and all of its subdomains)
 	AccessPolicyTypeDNSZone = "dns_zone"
+	// AccessPolicyTypeIPRange Explicitly allow just traffic to specific IP or CIDR ("10.0.0.0/8")
+	AccessPolicyTypeIPRange = "ip_range"
+	// AccessPolicyTypePort Explicitly allow just traffic to specific port or port range, optionally of a single protocol ("tcp/443", "8000-9000")
+	AccessPolicyTypePort = "port"
+)
+
+// Protocols which port rules can be restricted to, empty protocol matches both of them.
+const (
+	ProtocolTCP = "tcp"
+	ProtocolUDP = "udp"
 )
 
 // AccessPolicy represents the access controls for proposal
@@ -32,12 +50,14 @@ type AccessPolicy struct {
 	Source string `json:"source"`
 }
 
-// AccessPolicyRuleSet represents named list with rules specifying whether access is allowed
+// AccessPolicyRuleSet represents named list with rules specifying whether access is allowed.
+// Deny rules take precedence over allow rules, access is allowed by default when there are only deny rules of the type.
 type AccessPolicyRuleSet struct {
 	ID          string       `json:"id"`
 	Title       string       `json:"title"`
 	Description string       `json:"description"`
 	Allow       []AccessRule `json:"allow"`
+	Deny        []AccessRule `json:"deny,omitempty"`
 }
 
 // AccessRule represents rule specifying whether connection should be allowed
@@ -45,3 +65,119 @@ type AccessRule struct {
 	Type  string `json:"type"`
 	Value string `json:"value"`
 }
+
+// Validate checks that rule is of a known type and its value can be parsed.
+func (r AccessRule) Validate() error {
+	switch r.Type {
+	case AccessPolicyTypeIdentity, AccessPolicyTypeDNSHostname, AccessPolicyTypeDNSZone:
+		if r.Value == "" {
+			return errors.New("rule value is empty")
+		}
+		return nil
+	case AccessPolicyTypeIPRange:
+		_, err := ParseIPRange(r.Value)
+		return err
+	case AccessPolicyTypePort:
+		_, err := ParsePortRange(r.Value)
+		return err
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+}
+
+// ParseIPRange parses value of "ip_range" rule, which is either a single IP or a CIDR.
+func ParseIPRange(value string) (net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return net.IPNet{}, fmt.Errorf("invalid IP %q", value)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return net.IPNet{}, fmt.Errorf("invalid CIDR %q: %w", value, err)
+	}
+	return *network, nil
+}
+
+// TrafficRules holds destinations of traffic resolved from "ip_range" and "port" rules.
+// Traffic is rejected when it matches any denied network or port. Otherwise it has to match both
+// allow lists, nil list doesn't restrict traffic while an empty one allows nothing.
+type TrafficRules struct {
+	AllowedNetworks []net.IPNet
+	AllowedPorts    []PortRange
+	DeniedNetworks  []net.IPNet
+	DeniedPorts     []PortRange
+}
+
+// Empty returns true if rules don't restrict traffic at all.
+func (t TrafficRules) Empty() bool {
+	return t.AllowedNetworks == nil && t.AllowedPorts == nil && len(t.DeniedNetworks) == 0 && len(t.DeniedPorts) == 0
+}
+
+// PortRange represents destination ports of "port" rule.
+type PortRange struct {
+	// Protocol is either "tcp", "udp" or empty for both of them
+	Protocol string
+	From     int
+	To       int
+}
+
+// String formats port range in the same way as it is parsed.
+func (p PortRange) String() string {
+	ports := strconv.Itoa(p.From)
+	if p.To != p.From {
+		ports += "-" + strconv.Itoa(p.To)
+	}
+	if p.Protocol == "" {
+		return ports
+	}
+	return p.Protocol + "/" + ports
+}
+
+// ParsePortRange parses value of "port" rule, e.g. "443", "tcp/443" or "udp/8000-9000".
+func ParsePortRange(value string) (PortRange, error) {
+	var portRange PortRange
+
+	ports := value
+	if i := strings.Index(value, "/"); i >= 0 {
+		portRange.Protocol = strings.ToLower(value[:i])
+		ports = value[i+1:]
+		if portRange.Protocol != ProtocolTCP && portRange.Protocol != ProtocolUDP {
+			return PortRange{}, fmt.Errorf("invalid protocol in port rule %q", value)
+		}
+	}
+
+	from, to := ports, ports
+	if i := strings.Index(ports, "-"); i >= 0 {
+		from, to = ports[:i], ports[i+1:]
+	}
+
+	var err error
+	if portRange.From, err = parsePort(from); err != nil {
+		return PortRange{}, fmt.Errorf("invalid port rule %q: %w", value, err)
+	}
+	if portRange.To, err = parsePort(to); err != nil {
+		return PortRange{}, fmt.Errorf("invalid port rule %q: %w", value, err)
+	}
+	if portRange.From > portRange.To {
+		return PortRange{}, fmt.Errorf("invalid port rule %q: range start is greater than its end", value)
+	}
+	return portRange, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port %d is out of range", port)
+	}
+	return port, nil
+}
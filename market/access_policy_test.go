@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPRange(t *testing.T) {
+	var tests = []struct {
+		value   string
+		network string
+		wantErr bool
+	}{
+		{value: "10.0.0.0/8", network: "10.0.0.0/8"},
+		{value: "10.1.2.3/8", network: "10.0.0.0/8"},
+		{value: "1.2.3.4", network: "1.2.3.4/32"},
+		{value: "2001:db8::1", network: "2001:db8::1/128"},
+		{value: "1.2.3", wantErr: true},
+		{value: "10.0.0.0/33", wantErr: true},
+	}
+
+	for _, test := range tests {
+		network, err := ParseIPRange(test.value)
+		if test.wantErr {
+			assert.Error(t, err, test.value)
+			continue
+		}
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.network, network.String())
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	var tests = []struct {
+		value     string
+		portRange PortRange
+		wantErr   bool
+	}{
+		{value: "443", portRange: PortRange{From: 443, To: 443}},
+		{value: "tcp/443", portRange: PortRange{Protocol: ProtocolTCP, From: 443, To: 443}},
+		{value: "UDP/8000-9000", portRange: PortRange{Protocol: ProtocolUDP, From: 8000, To: 9000}},
+		{value: "icmp/1", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "65536", wantErr: true},
+		{value: "9000-8000", wantErr: true},
+		{value: "tcp/", wantErr: true},
+	}
+
+	for _, test := range tests {
+		portRange, err := ParsePortRange(test.value)
+		if test.wantErr {
+			assert.Error(t, err, test.value)
+			continue
+		}
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.portRange, portRange)
+	}
+}
+
+func TestPortRange_String(t *testing.T) {
+	assert.Equal(t, "25", PortRange{From: 25, To: 25}.String())
+	assert.Equal(t, "udp/8000-9000", PortRange{Protocol: ProtocolUDP, From: 8000, To: 9000}.String())
+}
+
+func TestAccessRule_Validate(t *testing.T) {
+	assert.NoError(t, AccessRule{Type: AccessPolicyTypeIdentity, Value: "0x1"}.Validate())
+	assert.NoError(t, AccessRule{Type: AccessPolicyTypeIPRange, Value: "10.0.0.0/8"}.Validate())
+	assert.NoError(t, AccessRule{Type: AccessPolicyTypePort, Value: "tcp/443"}.Validate())
+	assert.Error(t, AccessRule{Type: AccessPolicyTypeDNSZone}.Validate())
+	assert.Error(t, AccessRule{Type: AccessPolicyTypeIPRange, Value: "10.0.0"}.Validate())
+	assert.Error(t, AccessRule{Type: AccessPolicyTypePort, Value: "http"}.Validate())
+	assert.Error(t, AccessRule{Type: "unknown", Value: "value"}.Validate())
+}
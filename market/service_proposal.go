@@ -57,6 +57,13 @@ type ServiceProposal struct {
 
 	// AccessPolicies represents the access controls for proposal
 	AccessPolicies *[]AccessPolicy `json:"access_policies,omitempty"`
+
+	// Verified is set by discovery once it checked that the proposal is signed by provider identity,
+	// it is not a part of the announced proposal.
+	Verified bool `json:"-"`
+
+	// Stale is set by discovery cache for proposals restored from disk, which no discovery source confirmed yet.
+	Stale bool `json:"-"`
 }
 
 // UniqueID returns unique proposal composite ID
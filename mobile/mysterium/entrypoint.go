@@ -169,6 +169,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 			Types:        []node.DiscoveryType{node.DiscoveryTypeAPI, node.DiscoveryTypeBroker},
 			Address:      network.MysteriumAPIAddress,
 			FetchEnabled: false,
+			CacheMaxAge:  24 * time.Hour,
 		},
 		Location: node.OptionsLocation{
 			IPDetectorURL: options.IPDetectorURL,
@@ -348,6 +349,7 @@ type ConnectResponse struct {
 const (
 	connectErrInvalidProposal     = "InvalidProposal"
 	connectErrInsufficientBalance = "InsufficientBalance"
+	connectErrSessionRejected     = "SessionRejected"
 	connectErrUnknown             = "Unknown"
 )
 
@@ -374,6 +376,12 @@ func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
 				ErrorCode: connectErrInsufficientBalance,
 			}
 		}
+		if _, ok := err.(*connection.SessionRejectedError); ok {
+			return &ConnectResponse{
+				ErrorCode:    connectErrSessionRejected,
+				ErrorMessage: err.Error(),
+			}
+		}
 		return &ConnectResponse{
 			ErrorCode:    connectErrUnknown,
 			ErrorMessage: err.Error(),
@@ -69,6 +69,7 @@ type proposalDTO struct {
 	NodeType         string                 `json:"nodeType"`
 	QualityLevel     proposalQualityLevel   `json:"qualityLevel"`
 	MonitoringFailed bool                   `json:"monitoringFailed"`
+	Stale            bool                   `json:"stale"`
 	Payment          *proposalPaymentMethod `json:"payment"`
 }
 
@@ -197,6 +198,7 @@ func (m *proposalsManager) mapProposal(p *market.ServiceProposal, metricsMap map
 		ProviderID:   p.ProviderID,
 		ServiceType:  p.ServiceType,
 		QualityLevel: proposalQualityLevelUnknown,
+		Stale:        p.Stale,
 	}
 
 	if p.ServiceDefinition != nil {
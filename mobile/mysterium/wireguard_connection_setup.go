@@ -113,6 +113,16 @@ func (c *wireguardConnection) Statistics() (connectionstate.Statistics, error) {
 }
 
 func (c *wireguardConnection) Start(ctx context.Context, options connection.ConnectOptions) (err error) {
+	if options.Chained {
+		return connection.ErrChainingNotSupported
+	}
+	if options.Params.SplitTunnel.Enabled() {
+		return connection.ErrSplitTunnelNotSupported
+	}
+	if options.Params.Detached {
+		return connection.ErrDetachingNotSupported
+	}
+
 	var config wireguard.ServiceConfig
 	err = json.Unmarshal(options.SessionConfig, &config)
 	if err != nil {
@@ -131,6 +141,9 @@ func (c *wireguardConnection) Start(ctx context.Context, options connection.Conn
 		options.ProviderNATConn.Close()
 		config.LocalPort = options.ProviderNATConn.LocalAddr().(*net.UDPAddr).Port
 		config.Provider.Endpoint.Port = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).Port
+		if options.Relayed {
+			config.Provider.Endpoint.IP = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).IP
+		}
 	}
 
 	if err := c.device.Start(c.privateKey, config, options.ChannelConn); err != nil {
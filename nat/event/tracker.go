@@ -43,6 +43,12 @@ func BuildFailureEvent(stage string, err error) Event {
 	return Event{Stage: stage, Successful: false, Error: err}
 }
 
+// WithProtocol returns event with the port mapping protocol which succeeded.
+func (e Event) WithProtocol(protocol string) Event {
+	e.Protocol = protocol
+	return e
+}
+
 // NewTracker returns a new instance of event tracker
 func NewTracker() *Tracker {
 	return &Tracker{eventChan: make(chan Event, 1)}
@@ -85,4 +91,5 @@ type Event struct {
 	Stage      string `json:"stage"`
 	Successful bool   `json:"successful"`
 	Error      error  `json:"error,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
 }
@@ -30,7 +30,9 @@ type NATService interface {
 
 // Options params to setup firewall/NAT rules.
 type Options struct {
-	VPNNetwork        net.IPNet
+	VPNNetwork net.IPNet
+	// VPNNetwork6 is optional IPv6 network of the tunnel, it is masqueraded behind provider's IPv6 address.
+	VPNNetwork6       net.IPNet
 	ProviderExtIP     net.IP
 	EnableDNSRedirect bool
 	DNSIP             net.IP
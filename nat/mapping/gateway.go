@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackpal/gateway"
+)
+
+// gatewayPort is the port NAT-PMP and PCP servers listen on.
+const gatewayPort = 5351
+
+var errNoGatewayResponse = errors.New("no response from gateway")
+
+// gatewayAddr returns address of the default gateway NAT-PMP and PCP requests are sent to.
+type gatewayAddr func() (*net.UDPAddr, error)
+
+func defaultGateway() (*net.UDPAddr, error) {
+	ip, err := gateway.DiscoverGateway()
+	if err != nil {
+		return nil, fmt.Errorf("could not discover default gateway: %w", err)
+	}
+	return &net.UDPAddr{IP: ip, Port: gatewayPort}, nil
+}
+
+// gatewayClient sends UDP requests to the gateway, retransmitting them with the doubling
+// timeout as required by both NAT-PMP and PCP.
+type gatewayClient struct {
+	gateway        gatewayAddr
+	initialTimeout time.Duration
+	attempts       int
+}
+
+func newGatewayClient(gateway gatewayAddr) gatewayClient {
+	return gatewayClient{
+		gateway:        gateway,
+		initialTimeout: 250 * time.Millisecond,
+		attempts:       4,
+	}
+}
+
+// request sends request to the gateway and returns the first response accepted by the given func.
+// The build func receives local address of the connection as some protocols need to include it.
+func (c gatewayClient) request(build func(local *net.UDPAddr) []byte, accept func(res []byte) bool) ([]byte, error) {
+	addr, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial gateway: %w", err)
+	}
+	defer conn.Close()
+
+	req := build(conn.LocalAddr().(*net.UDPAddr))
+	buf := make([]byte, 1100)
+	timeout := c.initialTimeout
+	for i := 0; i < c.attempts; i++ {
+		if _, err := conn.Write(req); err != nil {
+			return nil, fmt.Errorf("could not send request to gateway: %w", err)
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, fmt.Errorf("could not set read deadline: %w", err)
+		}
+
+		for {
+			n, err := conn.Read(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			if err != nil {
+				// Gateway not listening results in ICMP port unreachable.
+				return nil, fmt.Errorf("could not read gateway response: %w", err)
+			}
+			if accept(buf[:n]) {
+				res := make([]byte, n)
+				copy(res, buf[:n])
+				return res, nil
+			}
+		}
+		timeout *= 2
+	}
+	return nil, errNoGatewayResponse
+}
+
+// lifetimeSeconds converts lifetime to seconds, zero lifetime means permanent lease
+// which is requested as the maximum lifetime.
+func lifetimeSeconds(lifetime time.Duration) uint32 {
+	if lifetime == 0 {
+		return 1<<32 - 1
+	}
+	return uint32(lifetime / time.Second)
+}
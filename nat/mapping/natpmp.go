@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	portmap "github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	natPMPVersion = 0
+
+	natPMPOpExternalAddress = 0
+	natPMPOpMapUDP          = 1
+	natPMPOpMapTCP          = 2
+	natPMPOpResponse        = 128
+)
+
+// natPMP is NAT-PMP (RFC 6886) client.
+type natPMP struct {
+	client gatewayClient
+}
+
+// NewNATPMP returns NAT-PMP client of the default gateway.
+func NewNATPMP() portmap.Interface {
+	return newNATPMP(newGatewayClient(defaultGateway))
+}
+
+func newNATPMP(client gatewayClient) *natPMP {
+	return &natPMP{client: client}
+}
+
+// AddMapping maps external port of the gateway to the internal port.
+func (n *natPMP) AddMapping(protocol string, extport, intport int, name string, lifetime time.Duration) error {
+	_, err := n.mapPort(protocol, extport, intport, lifetimeSeconds(lifetime))
+	return err
+}
+
+// DeleteMapping deletes mapping of the internal port.
+func (n *natPMP) DeleteMapping(protocol string, extport, intport int) error {
+	_, err := n.mapPort(protocol, 0, intport, 0)
+	return err
+}
+
+// ExternalIP returns external IP of the gateway.
+func (n *natPMP) ExternalIP() (net.IP, error) {
+	res, err := n.request([]byte{natPMPVersion, natPMPOpExternalAddress}, natPMPOpExternalAddress, 12)
+	if err != nil {
+		return nil, err
+	}
+	return net.IP(res[8:12]), nil
+}
+
+// String returns name of the protocol.
+func (n *natPMP) String() string {
+	return "NAT-PMP"
+}
+
+func (n *natPMP) mapPort(protocol string, extport, intport int, lifetime uint32) (int, error) {
+	op := byte(natPMPOpMapUDP)
+	if strings.EqualFold(protocol, "TCP") {
+		op = natPMPOpMapTCP
+	}
+
+	req := make([]byte, 12)
+	req[0] = natPMPVersion
+	req[1] = op
+	binary.BigEndian.PutUint16(req[4:], uint16(intport))
+	binary.BigEndian.PutUint16(req[6:], uint16(extport))
+	binary.BigEndian.PutUint32(req[8:], lifetime)
+
+	res, err := n.request(req, op, 16)
+	if err != nil {
+		return 0, err
+	}
+	mapped := int(binary.BigEndian.Uint16(res[10:]))
+	if lifetime > 0 && mapped != extport {
+		// Gateway mapped different port, release it as we announce the requested one.
+		if _, err := n.mapPort(protocol, 0, intport, 0); err != nil {
+			log.Warn().Err(err).Msgf("Couldn't delete NAT-PMP mapping of port %d", intport)
+		}
+		return 0, fmt.Errorf("NAT-PMP gateway mapped port %d instead of %d", mapped, extport)
+	}
+	return mapped, nil
+}
+
+func (n *natPMP) request(req []byte, op byte, size int) ([]byte, error) {
+	res, err := n.client.request(
+		func(*net.UDPAddr) []byte { return req },
+		func(res []byte) bool {
+			return len(res) >= size && res[0] == natPMPVersion && res[1] == natPMPOpResponse+op
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("NAT-PMP request failed: %w", err)
+	}
+	if code := binary.BigEndian.Uint16(res[2:]); code != 0 {
+		return nil, fmt.Errorf("NAT-PMP gateway returned result code %d", code)
+	}
+	return res, nil
+}
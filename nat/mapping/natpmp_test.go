@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATPMP_ExternalIP(t *testing.T) {
+	gw := newFakeGateway(t, func(req []byte) []byte {
+		res := make([]byte, 12)
+		res[1] = natPMPOpResponse + natPMPOpExternalAddress
+		copy(res[8:], net.ParseIP("1.2.3.4").To4())
+		return res
+	})
+	defer gw.close()
+
+	ip, err := newNATPMP(gw.client()).ExternalIP()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", ip.String())
+}
+
+func TestNATPMP_AddMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		mappedPort  uint16
+		resultCode  uint16
+		expectedErr string
+	}{
+		{name: "mapped", mappedPort: 51334},
+		{name: "mapped different port", mappedPort: 60000, expectedErr: "NAT-PMP gateway mapped port 60000 instead of 51334"},
+		{name: "not authorized", resultCode: 2, expectedErr: "NAT-PMP gateway returned result code 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gw := newFakeGateway(t, func(req []byte) []byte {
+				res := make([]byte, 16)
+				res[1] = natPMPOpResponse + req[1]
+				binary.BigEndian.PutUint16(res[2:], test.resultCode)
+				copy(res[8:10], req[4:6])
+				if binary.BigEndian.Uint32(req[8:]) > 0 {
+					binary.BigEndian.PutUint16(res[10:], test.mappedPort)
+				}
+				copy(res[12:], req[8:12])
+				return res
+			})
+			defer gw.close()
+
+			err := newNATPMP(gw.client()).AddMapping("UDP", 51334, 51334, "Test", 20*time.Minute)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			req := gw.requests()[0]
+			assert.Equal(t, byte(natPMPOpMapUDP), req[1])
+			assert.Equal(t, uint16(51334), binary.BigEndian.Uint16(req[4:]))
+			assert.Equal(t, uint16(51334), binary.BigEndian.Uint16(req[6:]))
+			assert.Equal(t, uint32(1200), binary.BigEndian.Uint32(req[8:]))
+		})
+	}
+}
+
+func TestNATPMP_NoGateway(t *testing.T) {
+	gw := newFakeGateway(t, func(req []byte) []byte { return nil })
+	defer gw.close()
+
+	_, err := newNATPMP(gw.client()).ExternalIP()
+
+	assert.EqualError(t, err, "NAT-PMP request failed: no response from gateway")
+}
+
+// fakeGateway is NAT-PMP or PCP server which replies to requests using given handler.
+type fakeGateway struct {
+	conn *net.UDPConn
+
+	mu   sync.Mutex
+	reqs [][]byte
+}
+
+func newFakeGateway(t *testing.T, handle func(req []byte) []byte) *fakeGateway {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+
+	gw := &fakeGateway{conn: conn}
+	go func() {
+		buf := make([]byte, 1100)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			req := make([]byte, n)
+			copy(req, buf[:n])
+			gw.mu.Lock()
+			gw.reqs = append(gw.reqs, req)
+			gw.mu.Unlock()
+
+			if res := handle(req); res != nil {
+				conn.WriteToUDP(res, addr)
+			}
+		}
+	}()
+	return gw
+}
+
+func (gw *fakeGateway) client() gatewayClient {
+	addr := gw.conn.LocalAddr().(*net.UDPAddr)
+	return gatewayClient{
+		gateway:        func() (*net.UDPAddr, error) { return addr, nil },
+		initialTimeout: 20 * time.Millisecond,
+		attempts:       2,
+	}
+}
+
+func (gw *fakeGateway) requests() [][]byte {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	return gw.reqs
+}
+
+func (gw *fakeGateway) close() {
+	gw.conn.Close()
+}
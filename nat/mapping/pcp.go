@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	portmap "github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	pcpVersion    = 2
+	pcpOpMap      = 1
+	pcpOpResponse = 0x80
+
+	pcpHeaderLen     = 24
+	pcpMapPayloadLen = 36
+
+	pcpProtocolTCP = 6
+	pcpProtocolUDP = 17
+
+	// pcpProbeLifetime is the lifetime of mapping used to learn external IP of the gateway.
+	pcpProbeLifetime = 60
+)
+
+type pcpNonce [12]byte
+
+// pcp is PCP (RFC 6887) client which uses MAP opcode.
+type pcp struct {
+	client gatewayClient
+
+	mu     sync.Mutex
+	nonces map[string]pcpNonce
+}
+
+// NewPCP returns PCP client of the default gateway.
+func NewPCP() portmap.Interface {
+	return newPCP(newGatewayClient(defaultGateway))
+}
+
+func newPCP(client gatewayClient) *pcp {
+	return &pcp{
+		client: client,
+		nonces: make(map[string]pcpNonce),
+	}
+}
+
+// AddMapping maps external port of the gateway to the internal port.
+func (p *pcp) AddMapping(protocol string, extport, intport int, name string, lifetime time.Duration) error {
+	nonce, err := p.nonce(protocol, intport)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.mapPort(nonce, protocol, extport, intport, lifetimeSeconds(lifetime))
+	if err != nil {
+		return err
+	}
+	if mapped := int(binary.BigEndian.Uint16(res[pcpHeaderLen+18:])); mapped != extport {
+		if err := p.deleteMapping(nonce, protocol, intport); err != nil {
+			log.Warn().Err(err).Msgf("Couldn't delete PCP mapping of port %d", intport)
+		}
+		return fmt.Errorf("PCP gateway mapped port %d instead of %d", mapped, extport)
+	}
+	return nil
+}
+
+// DeleteMapping deletes mapping of the internal port.
+func (p *pcp) DeleteMapping(protocol string, extport, intport int) error {
+	p.mu.Lock()
+	key := pcpMappingKey(protocol, intport)
+	nonce, ok := p.nonces[key]
+	delete(p.nonces, key)
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no PCP mapping of %s port %d", protocol, intport)
+	}
+	return p.deleteMapping(nonce, protocol, intport)
+}
+
+// ExternalIP returns external IP of the gateway. PCP has no dedicated request for it,
+// so short lived mapping of the discard port is created and deleted right away.
+func (p *pcp) ExternalIP() (net.IP, error) {
+	var nonce pcpNonce
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("could not generate PCP nonce: %w", err)
+	}
+
+	const discardPort = 9
+	res, err := p.mapPort(nonce, "UDP", discardPort, discardPort, pcpProbeLifetime)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.deleteMapping(nonce, "UDP", discardPort); err != nil {
+		log.Warn().Err(err).Msg("Couldn't delete PCP probe mapping")
+	}
+
+	ip := net.IP(res[pcpHeaderLen+20 : pcpHeaderLen+pcpMapPayloadLen])
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4, nil
+	}
+	return ip, nil
+}
+
+// String returns name of the protocol.
+func (p *pcp) String() string {
+	return "PCP"
+}
+
+// nonce returns nonce of the mapping, the same nonce has to be used to renew and delete it.
+func (p *pcp) nonce(protocol string, intport int) (pcpNonce, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := pcpMappingKey(protocol, intport)
+	if nonce, ok := p.nonces[key]; ok {
+		return nonce, nil
+	}
+
+	var nonce pcpNonce
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nonce, fmt.Errorf("could not generate PCP nonce: %w", err)
+	}
+	p.nonces[key] = nonce
+	return nonce, nil
+}
+
+func (p *pcp) deleteMapping(nonce pcpNonce, protocol string, intport int) error {
+	_, err := p.mapPort(nonce, protocol, 0, intport, 0)
+	return err
+}
+
+func (p *pcp) mapPort(nonce pcpNonce, protocol string, extport, intport int, lifetime uint32) ([]byte, error) {
+	proto := byte(pcpProtocolUDP)
+	if strings.EqualFold(protocol, "TCP") {
+		proto = pcpProtocolTCP
+	}
+
+	build := func(local *net.UDPAddr) []byte {
+		req := make([]byte, pcpHeaderLen+pcpMapPayloadLen)
+		req[0] = pcpVersion
+		req[1] = pcpOpMap
+		binary.BigEndian.PutUint32(req[4:], lifetime)
+		copy(req[8:24], local.IP.To16())
+
+		payload := req[pcpHeaderLen:]
+		copy(payload[0:12], nonce[:])
+		payload[12] = proto
+		binary.BigEndian.PutUint16(payload[16:], uint16(intport))
+		binary.BigEndian.PutUint16(payload[18:], uint16(extport))
+		copy(payload[20:36], net.IPv4zero.To16())
+		return req
+	}
+	accept := func(res []byte) bool {
+		return len(res) >= pcpHeaderLen+pcpMapPayloadLen &&
+			res[0] == pcpVersion &&
+			res[1] == pcpOpResponse|pcpOpMap &&
+			bytes.Equal(res[pcpHeaderLen:pcpHeaderLen+12], nonce[:])
+	}
+
+	res, err := p.client.request(build, accept)
+	if err != nil {
+		return nil, fmt.Errorf("PCP request failed: %w", err)
+	}
+	if code := res[3]; code != 0 {
+		return nil, fmt.Errorf("PCP gateway returned result code %d", code)
+	}
+	return res, nil
+}
+
+func pcpMappingKey(protocol string, intport int) string {
+	return fmt.Sprintf("%s/%d", strings.ToUpper(protocol), intport)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pcpGatewayHandler replies to PCP MAP requests mapping requested external port on 1.2.3.4.
+func pcpGatewayHandler(req []byte) []byte {
+	res := make([]byte, pcpHeaderLen+pcpMapPayloadLen)
+	res[0] = pcpVersion
+	res[1] = pcpOpResponse | req[1]
+	copy(res[4:8], req[4:8])
+	copy(res[pcpHeaderLen:], req[pcpHeaderLen:pcpHeaderLen+20])
+	copy(res[pcpHeaderLen+20:], net.ParseIP("1.2.3.4").To16())
+	return res
+}
+
+func TestPCP_ExternalIP(t *testing.T) {
+	gw := newFakeGateway(t, pcpGatewayHandler)
+	defer gw.close()
+
+	ip, err := newPCP(gw.client()).ExternalIP()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", ip.String())
+	// Probe mapping is deleted right away.
+	reqs := gw.requests()
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(reqs[1][4:]))
+}
+
+func TestPCP_AddAndDeleteMapping(t *testing.T) {
+	gw := newFakeGateway(t, pcpGatewayHandler)
+	defer gw.close()
+	client := newPCP(gw.client())
+
+	assert.NoError(t, client.AddMapping("UDP", 51334, 51334, "Test", 20*time.Minute))
+	assert.NoError(t, client.AddMapping("UDP", 51334, 51334, "Test", 20*time.Minute))
+	assert.NoError(t, client.DeleteMapping("UDP", 51334, 51334))
+	assert.Error(t, client.DeleteMapping("UDP", 51334, 51334))
+
+	reqs := gw.requests()
+	assert.Len(t, reqs, 3)
+	add, renew, del := reqs[0], reqs[1], reqs[2]
+	assert.Equal(t, byte(pcpOpMap), add[1])
+	assert.Equal(t, uint32(1200), binary.BigEndian.Uint32(add[4:]))
+	assert.Equal(t, net.ParseIP("127.0.0.1").To16(), net.IP(add[8:24]))
+	assert.Equal(t, byte(pcpProtocolUDP), add[pcpHeaderLen+12])
+	assert.Equal(t, uint16(51334), binary.BigEndian.Uint16(add[pcpHeaderLen+16:]))
+	assert.Equal(t, uint16(51334), binary.BigEndian.Uint16(add[pcpHeaderLen+18:]))
+	// The same nonce identifies the mapping when it is renewed and deleted.
+	assert.Equal(t, add[pcpHeaderLen:pcpHeaderLen+12], renew[pcpHeaderLen:pcpHeaderLen+12])
+	assert.Equal(t, add[pcpHeaderLen:pcpHeaderLen+12], del[pcpHeaderLen:pcpHeaderLen+12])
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(del[4:]))
+}
+
+func TestPCP_AddMapping_Failure(t *testing.T) {
+	gw := newFakeGateway(t, func(req []byte) []byte {
+		res := pcpGatewayHandler(req)
+		res[3] = 8 // NO_RESOURCES
+		return res
+	})
+	defer gw.close()
+
+	err := newPCP(gw.client()).AddMapping("UDP", 51334, 51334, "Test", 20*time.Minute)
+
+	assert.EqualError(t, err, "PCP gateway returned result code 8")
+}
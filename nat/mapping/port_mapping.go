@@ -19,6 +19,7 @@ package mapping
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"time"
 
@@ -31,10 +32,34 @@ import (
 // StageName is used to indicate port mapping NAT traversal stage
 const StageName = "port_mapping"
 
-// DefaultConfig returns default port mapping config.
-func DefaultConfig() *Config {
+// Names of supported port mapping protocols.
+const (
+	ProtocolUPnP   = "upnp"
+	ProtocolNATPMP = "natpmp"
+	ProtocolPCP    = "pcp"
+)
+
+// DefaultProtocols are port mapping protocols tried by default, in order.
+var DefaultProtocols = []string{ProtocolUPnP, ProtocolNATPMP, ProtocolPCP}
+
+// DefaultConfig returns default port mapping config which tries given protocols in order.
+func DefaultConfig(protocols []string) *Config {
+	var mapInterfaces []MapInterface
+	for _, protocol := range protocols {
+		switch protocol {
+		case ProtocolUPnP:
+			mapInterfaces = append(mapInterfaces, MapInterface{Protocol: protocol, Interface: portmap.UPnP()})
+		case ProtocolNATPMP:
+			mapInterfaces = append(mapInterfaces, MapInterface{Protocol: protocol, Interface: NewNATPMP()})
+		case ProtocolPCP:
+			mapInterfaces = append(mapInterfaces, MapInterface{Protocol: protocol, Interface: NewPCP()})
+		default:
+			log.Warn().Msgf("Unknown port mapping protocol %q, skipping it", protocol)
+		}
+	}
+
 	return &Config{
-		MapInterface:      portmap.Any(),
+		MapInterfaces:     mapInterfaces,
 		MapLifetime:       20 * time.Minute,
 		MapUpdateInterval: 15 * time.Minute,
 	}
@@ -42,12 +67,19 @@ func DefaultConfig() *Config {
 
 // Config represents port mapping config.
 type Config struct {
-	MapInterface      portmap.Interface
+	// MapInterfaces are port mapping protocols tried in order until mapping succeeds.
+	MapInterfaces     []MapInterface
 	MapLifetime       time.Duration
 	MapUpdateInterval time.Duration
 }
 
-// PortMapper tries to map port using router's uPnP or NAT-PMP depending on given config map interface.
+// MapInterface is the port mapping protocol client.
+type MapInterface struct {
+	Protocol string
+	portmap.Interface
+}
+
+// PortMapper tries to map port using router's uPnP, NAT-PMP or PCP depending on given config map interfaces.
 type PortMapper interface {
 	// Map maps port for given protocol. It returns release func which
 	// must be called when port no longer needed and ok which is true if
@@ -69,25 +101,34 @@ type portMapper struct {
 }
 
 func (p *portMapper) Map(protocol string, port int, name string) (release func(), ok bool) {
-	if !p.routerIPPublic() {
-		err := errors.New("failed to find router public IP")
-		log.Info().Err(err).Msg("Port mapping is useless, skipping it.")
-		p.notify(err)
+	err := errors.New("no port mapping protocols configured")
+	for _, mapInterface := range p.config.MapInterfaces {
+		if !p.routerIPPublic(mapInterface) {
+			err = fmt.Errorf("failed to find router public IP using %s", mapInterface.Protocol)
+			log.Info().Err(err).Msg("Port mapping is useless, skipping it.")
+			continue
+		}
 
-		return nil, false
-	}
+		// Try add mapping first to determine if it is supported and
+		// if permanent lease only is supported.
+		var permanent bool
+		permanent, err = p.addMapping(mapInterface, protocol, port, port, name)
+		if err != nil {
+			continue
+		}
+		p.notify(mapInterface.Protocol, nil)
 
-	// Try add mapping first to determine if it is supported and
-	// if permanent lease only is supported.
-	permanent, err := p.addMapping(protocol, port, port, name)
-	p.notify(err)
-	if err != nil {
-		return nil, false
+		return p.keepMapping(mapInterface, permanent, protocol, port, name), true
 	}
 
+	p.notify("", err)
+	return nil, false
+}
+
+func (p *portMapper) keepMapping(mapInterface MapInterface, permanent bool, protocol string, port int, name string) (release func()) {
 	// If only permanent lease is supported we don't need to update it in intervals.
 	if permanent {
-		return func() { p.deleteMapping(protocol, port, port) }, true
+		return func() { p.deleteMapping(mapInterface, protocol, port, port) }
 	}
 
 	stopUpdate := make(chan struct{})
@@ -97,26 +138,26 @@ func (p *portMapper) Map(protocol string, port int, name string) (release func()
 			case <-stopUpdate:
 				return
 			case <-time.After(p.config.MapUpdateInterval):
-				_, err := p.addMapping(protocol, port, port, name)
-				p.notify(err)
+				_, err := p.addMapping(mapInterface, protocol, port, port, name)
+				p.notify(mapInterface.Protocol, err)
 			}
 		}
 	}()
 
 	return func() {
-		p.deleteMapping(protocol, port, port)
+		p.deleteMapping(mapInterface, protocol, port, port)
 		close(stopUpdate)
-	}, true
+	}
 }
 
-func (p *portMapper) routerIPPublic() bool {
-	ip, err := p.config.MapInterface.ExternalIP()
+func (p *portMapper) routerIPPublic(mapInterface MapInterface) bool {
+	ip, err := mapInterface.ExternalIP()
 	if err != nil {
-		log.Warn().Err(err).Msg("Couldn't detect router IP address")
+		log.Warn().Err(err).Msgf("Couldn't detect router IP address using %s", mapInterface.Protocol)
 		return false
 	}
 
-	log.Debug().Msgf("Detected router public IP address: %s", ip)
+	log.Debug().Msgf("Detected router public IP address using %s: %s", mapInterface.Protocol, ip)
 
 	for _, s := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"} {
 		_, subnet, _ := net.ParseCIDR(s)
@@ -128,31 +169,31 @@ func (p *portMapper) routerIPPublic() bool {
 	return true
 }
 
-func (p *portMapper) notify(err error) {
+func (p *portMapper) notify(mappingProtocol string, err error) {
 	if err != nil {
 		p.publisher.Publish(event.AppTopicTraversal, event.BuildFailureEvent(StageName, err))
 	} else {
-		p.publisher.Publish(event.AppTopicTraversal, event.BuildSuccessfulEvent(StageName))
+		p.publisher.Publish(event.AppTopicTraversal, event.BuildSuccessfulEvent(StageName).WithProtocol(mappingProtocol))
 	}
 }
 
-func (p *portMapper) addMapping(protocol string, extPort, intPort int, name string) (permanent bool, err error) {
-	if err := p.config.MapInterface.AddMapping(protocol, extPort, intPort, name, p.config.MapLifetime); err != nil {
-		log.Warn().Err(err).Msgf("Couldn't add port mapping for port %d: retrying with permanent lease", extPort)
-		if err := p.config.MapInterface.AddMapping(protocol, extPort, intPort, name, 0); err != nil {
+func (p *portMapper) addMapping(mapInterface MapInterface, protocol string, extPort, intPort int, name string) (permanent bool, err error) {
+	if err := mapInterface.AddMapping(protocol, extPort, intPort, name, p.config.MapLifetime); err != nil {
+		log.Warn().Err(err).Msgf("Couldn't add %s port mapping for port %d: retrying with permanent lease", mapInterface.Protocol, extPort)
+		if err := mapInterface.AddMapping(protocol, extPort, intPort, name, 0); err != nil {
 			// some gateways support only permanent leases
-			log.Warn().Err(err).Msgf("Couldn't add port mapping for port %d", extPort)
+			log.Warn().Err(err).Msgf("Couldn't add %s port mapping for port %d", mapInterface.Protocol, extPort)
 			return false, err
 		}
 		return true, nil
 	}
-	log.Info().Msgf("Mapped network port: %d", extPort)
+	log.Info().Msgf("Mapped network port using %s: %d", mapInterface.Protocol, extPort)
 	return false, nil
 }
 
-func (p *portMapper) deleteMapping(protocol string, extPort, intPort int) {
-	log.Debug().Msgf("Deleting port mapping for port: %d", extPort)
-	if err := p.config.MapInterface.DeleteMapping(protocol, extPort, intPort); err != nil {
+func (p *portMapper) deleteMapping(mapInterface MapInterface, protocol string, extPort, intPort int) {
+	log.Debug().Msgf("Deleting %s port mapping for port: %d", mapInterface.Protocol, extPort)
+	if err := mapInterface.DeleteMapping(protocol, extPort, intPort); err != nil {
 		log.Warn().Err(err).Msg("Couldn't delete port mapping")
 	}
 }
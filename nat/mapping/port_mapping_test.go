@@ -25,13 +25,14 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/mocks"
+	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestMap_uPnP_Enabled(t *testing.T) {
 	router := &mockRouter{uPnPEnabled: true}
 	config := &Config{
-		MapInterface:      router,
+		MapInterfaces:     []MapInterface{{Protocol: ProtocolUPnP, Interface: router}},
 		MapUpdateInterval: 5 * time.Millisecond,
 		MapLifetime:       10 * time.Millisecond,
 	}
@@ -54,7 +55,7 @@ func TestMap_uPnP_Enabled(t *testing.T) {
 func TestMap_uPnP_Enabled_With_Permanent_Lease(t *testing.T) {
 	router := &mockRouter{uPnPEnabled: true, permanentLease: true}
 	config := &Config{
-		MapInterface:      router,
+		MapInterfaces:     []MapInterface{{Protocol: ProtocolUPnP, Interface: router}},
 		MapUpdateInterval: 5 * time.Millisecond,
 		MapLifetime:       10 * time.Millisecond,
 	}
@@ -77,7 +78,7 @@ func TestMap_uPnP_Enabled_With_Permanent_Lease(t *testing.T) {
 func TestMap_uPnP_Disabled(t *testing.T) {
 	router := &mockRouter{uPnPEnabled: false}
 	config := &Config{
-		MapInterface: router,
+		MapInterfaces: []MapInterface{{Protocol: ProtocolUPnP, Interface: router}},
 	}
 	portMapper := NewPortMapper(config, mocks.NewEventBus())
 
@@ -88,6 +89,29 @@ func TestMap_uPnP_Disabled(t *testing.T) {
 	assert.Equal(t, mapping{}, router.addedMapping())
 }
 
+func TestMap_FallsBackToNextProtocol(t *testing.T) {
+	upnp := &mockRouter{uPnPEnabled: false}
+	pmp := &mockRouter{uPnPEnabled: true}
+	config := &Config{
+		MapInterfaces: []MapInterface{
+			{Protocol: ProtocolUPnP, Interface: upnp},
+			{Protocol: ProtocolNATPMP, Interface: pmp},
+		},
+		MapUpdateInterval: time.Minute,
+		MapLifetime:       time.Minute,
+	}
+	bus := mocks.NewEventBus()
+	portMapper := NewPortMapper(config, bus)
+
+	release, ok := portMapper.Map("UDP", 51334, "Test")
+	defer release()
+
+	assert.True(t, ok)
+	assert.Equal(t, mapping{}, upnp.addedMapping())
+	assert.Equal(t, 51334, pmp.addedMapping().extport)
+	assert.Equal(t, event.BuildSuccessfulEvent(StageName).WithProtocol(ProtocolNATPMP), bus.Pop())
+}
+
 func TestMap_uPnP_routerIPPublic(t *testing.T) {
 	tests := []struct {
 		ip             string
@@ -102,7 +126,7 @@ func TestMap_uPnP_routerIPPublic(t *testing.T) {
 	for _, tt := range tests {
 		t.Run("Test mapping with router IP detection", func(t *testing.T) {
 			router := &mockRouter{uPnPEnabled: true, routerIP: net.ParseIP(tt.ip)}
-			config := &Config{MapInterface: router}
+			config := &Config{MapInterfaces: []MapInterface{{Protocol: ProtocolUPnP, Interface: router}}}
 			portMapper := NewPortMapper(config, mocks.NewEventBus())
 
 			release, ok := portMapper.Map("UDP", 51334, "Test port mapping")
@@ -18,6 +18,7 @@
 package nat
 
 import (
+	"net"
 	"strconv"
 	"sync"
 
@@ -29,9 +30,10 @@ import (
 )
 
 type serviceIPTables struct {
-	mu        sync.Mutex
-	rules     []iptables.Rule
-	ipForward serviceIPForward
+	mu         sync.Mutex
+	rules      []iptables.Rule
+	ipForward  serviceIPForward
+	ipForward6 serviceIPForward
 }
 
 const (
@@ -94,17 +96,22 @@ func (svc *serviceIPTables) Enable() error {
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to enable IP forwarding")
 	}
+	// Host without IPv6 can still serve IPv4 traffic.
+	if err := svc.ipForward6.Enable(); err != nil {
+		log.Warn().Err(err).Msg("Failed to enable IPv6 forwarding")
+	}
 	return err
 }
 
 // Disable disables NAT service and deletes all rules.
 func (svc *serviceIPTables) Disable() error {
 	svc.ipForward.Disable()
+	svc.ipForward6.Disable()
 	return svc.Del(untypedIptRules(svc.rules))
 }
 
 func (svc *serviceIPTables) applyRule(rule iptables.Rule) error {
-	if err := iptablesExec(rule.ApplyArgs()...); err != nil {
+	if err := iptablesExec(rule.Executable(), rule.ApplyArgs()...); err != nil {
 		return err
 	}
 	svc.rules = append(svc.rules, rule)
@@ -112,7 +119,7 @@ func (svc *serviceIPTables) applyRule(rule iptables.Rule) error {
 }
 
 func (svc *serviceIPTables) removeRule(rule iptables.Rule) error {
-	if err := iptablesExec(rule.RemoveArgs()...); err != nil {
+	if err := iptablesExec(rule.Executable(), rule.RemoveArgs()...); err != nil {
 		return err
 	}
 	for i := range svc.rules {
@@ -149,6 +156,9 @@ func makeIPTablesRules(opts Options) (rules []iptables.Rule) {
 
 	// Protect private networks rule
 	for _, ipNet := range protectedNetworks() {
+		if ipNet.IP.To4() == nil {
+			continue
+		}
 		rule := iptables.AppendTo(chainForward).RuleSpec(
 			"--source", vpnNetwork, "--destination", ipNet.String(),
 			"--jump", "DROP")
@@ -161,11 +171,38 @@ func makeIPTablesRules(opts Options) (rules []iptables.Rule) {
 		"--table", "nat")
 	rules = append(rules, rule)
 
+	if opts.VPNNetwork6.IP != nil {
+		rules = append(rules, makeIP6TablesRules(opts.VPNNetwork6)...)
+	}
+
+	return rules
+}
+
+func makeIP6TablesRules(vpnNetwork6 net.IPNet) (rules []iptables.Rule) {
+	vpnNetwork := vpnNetwork6.String()
+
+	// Protect private networks rule
+	for _, ipNet := range protectedNetworks() {
+		if ipNet.IP.To4() != nil {
+			continue
+		}
+		rule := iptables.AppendTo(chainForward).RuleSpec(
+			"--source", vpnNetwork, "--destination", ipNet.String(),
+			"--jump", "DROP").IPv6()
+		rules = append(rules, rule)
+	}
+
+	// NAT forwarding rule, provider may have several IPv6 addresses, so outgoing interface address is used
+	rule := iptables.AppendTo(chainPostRouting).RuleSpec("--source", vpnNetwork, "!", "--destination", vpnNetwork,
+		"--jump", "MASQUERADE",
+		"--table", "nat").IPv6()
+	rules = append(rules, rule)
+
 	return rules
 }
 
-func iptablesExec(args ...string) error {
-	args = append([]string{"/usr/sbin/iptables"}, args...)
+func iptablesExec(executable string, args ...string) error {
+	args = append([]string{executable}, args...)
 	if err := cmdutil.SudoExec(args...); err != nil {
 		return errors.Wrap(err, "error calling IPTables")
 	}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeIPTablesRules(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	_, vpnNetwork6, _ := net.ParseCIDR("fd6d:7973:7400::/64")
+
+	var rules []string
+	for _, rule := range makeIPTablesRules(Options{
+		VPNNetwork:    *vpnNetwork,
+		VPNNetwork6:   *vpnNetwork6,
+		ProviderExtIP: net.ParseIP("1.2.3.4"),
+	}) {
+		rules = append(rules, rule.Executable()+" "+strings.Join(rule.ApplyArgs(), " "))
+	}
+
+	assert.Equal(t, []string{
+		"/usr/sbin/iptables -A POSTROUTING --source 10.182.0.0/24 ! --destination 10.182.0.0/24 --jump SNAT --to 1.2.3.4 --table nat",
+		"/usr/sbin/ip6tables -A POSTROUTING --source fd6d:7973:7400::/64 ! --destination fd6d:7973:7400::/64 --jump MASQUERADE --table nat",
+	}, rules)
+}
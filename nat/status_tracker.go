@@ -34,10 +34,12 @@ const (
 	statusFailure     = "failure"
 )
 
-// Status represents NAT traversal status (either "not_finished", "successful" or "failure") and an optional error.
+// Status represents NAT traversal status (either "not_finished", "successful" or "failure"), an optional error
+// and port mapping protocol if port mapping succeeded.
 type Status struct {
-	Status string
-	Error  error
+	Status   string
+	Error    error
+	Protocol string
 }
 
 // Status returns NAT traversal status
@@ -53,7 +55,7 @@ func (t *StatusTracker) ConsumeNATEvent(event event.Event) {
 	}
 
 	if event.Successful {
-		t.status = Status{Status: statusSuccessful}
+		t.status = Status{Status: statusSuccessful, Protocol: event.Protocol}
 		return
 	}
 
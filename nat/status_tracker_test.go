@@ -42,6 +42,15 @@ func Test_StatusTracker_Status_ReturnsSuccessful_WithSuccessfulEvent(t *testing.
 	assert.Nil(t, status.Error)
 }
 
+func Test_StatusTracker_Status_ReturnsPortMappingProtocol_WithSuccessfulEvent(t *testing.T) {
+	tracker := NewStatusTracker("last stage")
+	tracker.ConsumeNATEvent(event.BuildSuccessfulEvent("port_mapping").WithProtocol("pcp"))
+	status := tracker.Status()
+
+	assert.Equal(t, "successful", status.Status)
+	assert.Equal(t, "pcp", status.Protocol)
+}
+
 func Test_StatusTracker_Status_ReturnsFailure_WithHolepunchingFailureEvent(t *testing.T) {
 	tracker := NewStatusTracker("last stage")
 	tracker.ConsumeNATEvent(event.Event{Successful: false, Stage: "last stage", Error: errors.New("test error")})
@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package stun implements STUN client which classifies NAT the node is behind
+// using NAT behavior discovery (RFC 5780).
+package stun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// NATType is the type of NAT the node is behind.
+type NATType string
+
+const (
+	// NATTypeUnknown means that NAT type was not detected yet or detection failed.
+	NATTypeUnknown NATType = "unknown"
+	// NATTypeBlocked means that UDP traffic is blocked.
+	NATTypeBlocked NATType = "blocked"
+	// NATTypeOpen means that node has public IP and incoming traffic is not filtered.
+	NATTypeOpen NATType = "open"
+	// NATTypeFullCone means that NAT maps all traffic from the local address to the same public address
+	// and accepts incoming traffic from anyone.
+	NATTypeFullCone NATType = "full-cone"
+	// NATTypeRestricted means that NAT accepts incoming traffic only from IPs which node sent traffic to.
+	NATTypeRestricted NATType = "restricted"
+	// NATTypePortRestricted means that NAT accepts incoming traffic only from IPs and ports which node sent traffic to.
+	NATTypePortRestricted NATType = "port-restricted"
+	// NATTypeSymmetric means that NAT maps traffic to each destination to different public address.
+	NATTypeSymmetric NATType = "symmetric"
+)
+
+// ErrBehaviorDiscoveryUnsupported is returned when STUN server does not report its alternate address.
+var ErrBehaviorDiscoveryUnsupported = errors.New("STUN server does not support NAT behavior discovery")
+
+var errNoResponse = errors.New("no response from STUN server")
+
+const (
+	defaultTimeout  = 500 * time.Millisecond
+	defaultAttempts = 3
+)
+
+// Client discovers NAT type using STUN server which supports NAT behavior discovery.
+type Client struct {
+	server   string
+	timeout  time.Duration
+	attempts int
+}
+
+// NewClient creates new STUN client of the given server address.
+func NewClient(server string) *Client {
+	return &Client{
+		server:   server,
+		timeout:  defaultTimeout,
+		attempts: defaultAttempts,
+	}
+}
+
+// Discover classifies NAT the given connection is behind.
+func (c *Client) Discover(ctx context.Context, conn net.PacketConn) (NATType, error) {
+	server, err := net.ResolveUDPAddr("udp4", c.server)
+	if err != nil {
+		return NATTypeUnknown, fmt.Errorf("could not resolve STUN server address: %w", err)
+	}
+
+	res, err := c.request(ctx, conn, server, false, false)
+	if errors.Is(err, errNoResponse) {
+		return NATTypeBlocked, nil
+	}
+	if err != nil {
+		return NATTypeUnknown, err
+	}
+	if res.mappedAddr == nil {
+		return NATTypeUnknown, errors.New("STUN server did not return mapped address")
+	}
+	mapped := res.mappedAddr
+	log.Debug().Msgf("STUN server %s mapped address: %s", server, mapped)
+
+	noNAT := isLocalAddr(mapped, conn.LocalAddr())
+	if res.otherAddr == nil {
+		if noNAT {
+			return NATTypeOpen, nil
+		}
+		return NATTypeUnknown, ErrBehaviorDiscoveryUnsupported
+	}
+	other := res.otherAddr
+
+	// Filtering tests have to be done before sending anything to the alternate address,
+	// otherwise NAT would accept traffic from it.
+	filtering, err := c.filteringType(ctx, conn, server)
+	if err != nil {
+		return NATTypeUnknown, err
+	}
+	if noNAT {
+		if filtering == NATTypeFullCone {
+			return NATTypeOpen, nil
+		}
+		// Firewall without address translation behaves like port restricted NAT.
+		return NATTypePortRestricted, nil
+	}
+
+	mappingDependent, err := c.mappingDependent(ctx, conn, mapped, server, other)
+	if err != nil {
+		return NATTypeUnknown, err
+	}
+	if mappingDependent {
+		return NATTypeSymmetric, nil
+	}
+	return filtering, nil
+}
+
+// filteringType tests which peers are allowed to reach the mapped address.
+func (c *Client) filteringType(ctx context.Context, conn net.PacketConn, server *net.UDPAddr) (NATType, error) {
+	_, err := c.request(ctx, conn, server, true, true)
+	if err == nil {
+		return NATTypeFullCone, nil
+	}
+	if !errors.Is(err, errNoResponse) {
+		return NATTypeUnknown, err
+	}
+
+	_, err = c.request(ctx, conn, server, false, true)
+	if err == nil {
+		return NATTypeRestricted, nil
+	}
+	if !errors.Is(err, errNoResponse) {
+		return NATTypeUnknown, err
+	}
+	return NATTypePortRestricted, nil
+}
+
+// mappingDependent tests whether NAT maps traffic to different destinations to different public addresses.
+func (c *Client) mappingDependent(ctx context.Context, conn net.PacketConn, mapped, server, other *net.UDPAddr) (bool, error) {
+	for _, dst := range []*net.UDPAddr{{IP: other.IP, Port: server.Port}, other} {
+		res, err := c.request(ctx, conn, dst, false, false)
+		if err != nil {
+			return false, fmt.Errorf("mapping test to %s failed: %w", dst, err)
+		}
+		if res.mappedAddr == nil || res.mappedAddr.String() != mapped.String() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// request sends binding request and waits for the response, request is retransmitted if no response is received.
+func (c *Client) request(ctx context.Context, conn net.PacketConn, server *net.UDPAddr, changeIP, changePort bool) (*message, error) {
+	req, err := newBindingRequest(changeIP, changePort)
+	if err != nil {
+		return nil, err
+	}
+	data := req.marshal()
+
+	buf := make([]byte, 1500)
+	for i := 0; i < c.attempts; i++ {
+		if _, err := conn.WriteTo(data, server); err != nil {
+			return nil, fmt.Errorf("could not send binding request: %w", err)
+		}
+
+		deadline := time.Now().Add(c.timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("could not set read deadline: %w", err)
+		}
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("could not read binding response: %w", err)
+			}
+			res, err := unmarshalMessage(buf[:n])
+			if err != nil || res.typ != typeBindingResponse || res.id != req.id {
+				continue
+			}
+			return res, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, errNoResponse
+}
+
+// isLocalAddr checks whether mapped address is the local address of the node, meaning there is no NAT.
+func isLocalAddr(mapped *net.UDPAddr, local net.Addr) bool {
+	localAddr, ok := local.(*net.UDPAddr)
+	if !ok || localAddr.Port != mapped.Port {
+		return false
+	}
+	if !localAddr.IP.IsUnspecified() {
+		return localAddr.IP.Equal(mapped.IP)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(mapped.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stun
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_MarshalUnmarshal(t *testing.T) {
+	req, err := newBindingRequest(true, false)
+	require.NoError(t, err)
+	req.mappedAddr = &net.UDPAddr{IP: net.ParseIP("1.2.3.4").To4(), Port: 5678}
+	req.otherAddr = &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 3479}
+
+	msg, err := unmarshalMessage(req.marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, req, msg)
+
+	_, err = unmarshalMessage([]byte("not a STUN message, but long enough"))
+	assert.Equal(t, errNotSTUNMessage, err)
+}
+
+func TestClient_Discover(t *testing.T) {
+	server := NewServer("127.0.0.1", "127.0.0.2")
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	tests := []struct {
+		name     string
+		conn     func() net.PacketConn
+		expected NATType
+	}{
+		{
+			name: "No NAT",
+			conn: func() net.PacketConn {
+				conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+				require.NoError(t, err)
+				return conn
+			},
+			expected: NATTypeOpen,
+		},
+		{
+			name:     "Full cone NAT",
+			conn:     func() net.PacketConn { return newNATSimulator(t, false, filterNone) },
+			expected: NATTypeFullCone,
+		},
+		{
+			name:     "Restricted cone NAT",
+			conn:     func() net.PacketConn { return newNATSimulator(t, false, filterIP) },
+			expected: NATTypeRestricted,
+		},
+		{
+			name:     "Port restricted cone NAT",
+			conn:     func() net.PacketConn { return newNATSimulator(t, false, filterIPPort) },
+			expected: NATTypePortRestricted,
+		},
+		{
+			name:     "Symmetric NAT",
+			conn:     func() net.PacketConn { return newNATSimulator(t, true, filterIPPort) },
+			expected: NATTypeSymmetric,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := test.conn()
+			defer conn.Close()
+
+			client := NewClient(server.Addr().String())
+			client.timeout = 100 * time.Millisecond
+			client.attempts = 2
+			natType, err := client.Discover(context.Background(), conn)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, natType)
+		})
+	}
+}
+
+func TestClient_DiscoverBlocked(t *testing.T) {
+	server := NewServer("127.0.0.1", "127.0.0.2")
+	require.NoError(t, server.Start())
+	addr := server.Addr().String()
+	server.Stop()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := NewClient(addr)
+	client.timeout = 100 * time.Millisecond
+	natType, err := client.Discover(context.Background(), conn)
+	assert.NoError(t, err)
+	assert.Equal(t, NATTypeBlocked, natType)
+}
+
+func TestDetector_Detect(t *testing.T) {
+	server := NewServer("127.0.0.1", "127.0.0.2")
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	detector := NewDetector([]string{"invalid address", server.Addr().String()}, time.Minute)
+	assert.Equal(t, NATTypeUnknown, detector.NATType())
+
+	natType, err := detector.Detect()
+	assert.NoError(t, err)
+	// Detector listens on all interfaces, so server sees loopback address.
+	assert.Equal(t, NATTypeOpen, natType)
+	assert.Equal(t, NATTypeOpen, detector.NATType())
+}
+
+type filtering int
+
+const (
+	filterNone filtering = iota
+	filterIP
+	filterIPPort
+)
+
+type natPacket struct {
+	data []byte
+	addr net.Addr
+}
+
+// natSimulator is a packet conn which translates traffic through public sockets the same way NAT would.
+type natSimulator struct {
+	t                 *testing.T
+	endpointDependent bool
+	filtering         filtering
+
+	mu          sync.Mutex
+	mappings    map[string]*net.UDPConn
+	permissions map[string]bool
+	deadline    time.Time
+
+	in   chan natPacket
+	stop chan struct{}
+}
+
+func newNATSimulator(t *testing.T, endpointDependent bool, filtering filtering) *natSimulator {
+	return &natSimulator{
+		t:                 t,
+		endpointDependent: endpointDependent,
+		filtering:         filtering,
+		mappings:          make(map[string]*net.UDPConn),
+		permissions:       make(map[string]bool),
+		in:                make(chan natPacket, 10),
+		stop:              make(chan struct{}),
+	}
+}
+
+func (n *natSimulator) mapping(dst *net.UDPAddr) *net.UDPConn {
+	key := ""
+	if n.endpointDependent {
+		key = dst.String()
+	}
+	if conn, ok := n.mappings[key]; ok {
+		return conn
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	require.NoError(n.t, err)
+	n.mappings[key] = conn
+	go n.receive(conn)
+	return conn
+}
+
+func (n *natSimulator) receive(conn *net.UDPConn) {
+	buf := make([]byte, 1500)
+	for {
+		size, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		n.mu.Lock()
+		allowed := n.filtering == filterNone ||
+			n.filtering == filterIP && n.permissions[addr.IP.String()] ||
+			n.filtering == filterIPPort && n.permissions[addr.String()]
+		n.mu.Unlock()
+		if !allowed {
+			continue
+		}
+
+		data := make([]byte, size)
+		copy(data, buf[:size])
+		select {
+		case n.in <- natPacket{data: data, addr: addr}:
+		case <-n.stop:
+			return
+		}
+	}
+}
+
+func (n *natSimulator) WriteTo(b []byte, addr net.Addr) (int, error) {
+	dst := addr.(*net.UDPAddr)
+
+	n.mu.Lock()
+	n.permissions[dst.IP.String()] = true
+	n.permissions[dst.String()] = true
+	conn := n.mapping(dst)
+	n.mu.Unlock()
+
+	return conn.WriteToUDP(b, dst)
+}
+
+func (n *natSimulator) ReadFrom(b []byte) (int, net.Addr, error) {
+	n.mu.Lock()
+	timeout := time.Until(n.deadline)
+	n.mu.Unlock()
+
+	select {
+	case packet := <-n.in:
+		return copy(b, packet.data), packet.addr, nil
+	case <-time.After(timeout):
+		return 0, nil, timeoutError{}
+	}
+}
+
+func (n *natSimulator) Close() error {
+	close(n.stop)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, conn := range n.mappings {
+		conn.Close()
+	}
+	return nil
+}
+
+func (n *natSimulator) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 5000}
+}
+
+func (n *natSimulator) SetDeadline(t time.Time) error {
+	return n.SetReadDeadline(t)
+}
+
+func (n *natSimulator) SetReadDeadline(t time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deadline = t
+	return nil
+}
+
+func (n *natSimulator) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultDetectInterval is the default interval of NAT type detection.
+	DefaultDetectInterval = 30 * time.Minute
+
+	detectTimeout = 30 * time.Second
+)
+
+// Detector periodically detects NAT type using given STUN servers and keeps the last result.
+type Detector struct {
+	servers  []string
+	interval time.Duration
+
+	mu      sync.RWMutex
+	natType NATType
+	err     error
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewDetector creates new NAT type detector. NAT type is detected using the first STUN server
+// which supports NAT behavior discovery.
+func NewDetector(servers []string, interval time.Duration) *Detector {
+	return &Detector{
+		servers:  servers,
+		interval: interval,
+		natType:  NATTypeUnknown,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start detects NAT type right away and then periodically, until stopped.
+func (d *Detector) Start() {
+	if len(d.servers) == 0 {
+		log.Info().Msg("No STUN servers configured, NAT type detection disabled")
+		return
+	}
+
+	for {
+		d.Detect()
+
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(d.interval):
+		}
+	}
+}
+
+// Stop stops periodic detection.
+func (d *Detector) Stop() {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+}
+
+// NATType returns last detected NAT type.
+func (d *Detector) NATType() NATType {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.natType
+}
+
+// Status returns last detected NAT type and detection error if it failed.
+func (d *Detector) Status() (NATType, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.natType, d.err
+}
+
+// Detect detects NAT type and stores the result.
+func (d *Detector) Detect() (NATType, error) {
+	natType, err := d.detect()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to detect NAT type")
+	} else {
+		log.Info().Msgf("Detected NAT type: %s", natType)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.natType, d.err = natType, err
+	return natType, err
+}
+
+func (d *Detector) detect() (NATType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), detectTimeout)
+	defer cancel()
+
+	err := errors.New("no STUN servers configured")
+	for _, server := range d.servers {
+		var natType NATType
+		natType, err = discover(ctx, server)
+		if err == nil {
+			return natType, nil
+		}
+		log.Debug().Err(err).Msgf("NAT type detection using %s failed", server)
+	}
+	return NATTypeUnknown, err
+}
+
+func discover(ctx context.Context, server string) (NATType, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return NATTypeUnknown, fmt.Errorf("could not listen UDP: %w", err)
+	}
+	defer conn.Close()
+
+	return NewClient(server).Discover(ctx, conn)
+}
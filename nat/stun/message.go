@@ -0,0 +1,222 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stun
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	headerLen   = 20
+	magicCookie = 0x2112A442
+
+	typeBindingRequest  uint16 = 0x0001
+	typeBindingResponse uint16 = 0x0101
+
+	attrMappedAddress    uint16 = 0x0001
+	attrChangeRequest    uint16 = 0x0003
+	attrChangedAddress   uint16 = 0x0005
+	attrXORMappedAddress uint16 = 0x0020
+	attrResponseOrigin   uint16 = 0x802b
+	attrOtherAddress     uint16 = 0x802c
+
+	changeIP   uint32 = 0x04
+	changePort uint32 = 0x02
+
+	familyIPv4 byte = 0x01
+	familyIPv6 byte = 0x02
+)
+
+var errNotSTUNMessage = errors.New("not a STUN message")
+
+type transactionID [12]byte
+
+// message is STUN binding message with the attributes needed for NAT behavior discovery.
+type message struct {
+	typ uint16
+	id  transactionID
+
+	mappedAddr     *net.UDPAddr
+	otherAddr      *net.UDPAddr
+	responseOrigin *net.UDPAddr
+	changeIP       bool
+	changePort     bool
+}
+
+func newBindingRequest(changeIP, changePort bool) (*message, error) {
+	msg := &message{typ: typeBindingRequest, changeIP: changeIP, changePort: changePort}
+	if _, err := rand.Read(msg.id[:]); err != nil {
+		return nil, fmt.Errorf("could not generate transaction ID: %w", err)
+	}
+	return msg, nil
+}
+
+func (m *message) marshal() []byte {
+	var attrs []byte
+	if m.changeIP || m.changePort {
+		var flags uint32
+		if m.changeIP {
+			flags |= changeIP
+		}
+		if m.changePort {
+			flags |= changePort
+		}
+		value := make([]byte, 4)
+		binary.BigEndian.PutUint32(value, flags)
+		attrs = appendAttr(attrs, attrChangeRequest, value)
+	}
+	if m.mappedAddr != nil {
+		attrs = appendAttr(attrs, attrXORMappedAddress, encodeAddr(m.mappedAddr, &m.id, true))
+		attrs = appendAttr(attrs, attrMappedAddress, encodeAddr(m.mappedAddr, &m.id, false))
+	}
+	if m.otherAddr != nil {
+		attrs = appendAttr(attrs, attrOtherAddress, encodeAddr(m.otherAddr, &m.id, false))
+	}
+	if m.responseOrigin != nil {
+		attrs = appendAttr(attrs, attrResponseOrigin, encodeAddr(m.responseOrigin, &m.id, false))
+	}
+
+	buf := make([]byte, headerLen, headerLen+len(attrs))
+	binary.BigEndian.PutUint16(buf[0:], m.typ)
+	binary.BigEndian.PutUint16(buf[2:], uint16(len(attrs)))
+	binary.BigEndian.PutUint32(buf[4:], magicCookie)
+	copy(buf[8:], m.id[:])
+	return append(buf, attrs...)
+}
+
+func unmarshalMessage(buf []byte) (*message, error) {
+	if len(buf) < headerLen || binary.BigEndian.Uint32(buf[4:]) != magicCookie || buf[0]&0xc0 != 0 {
+		return nil, errNotSTUNMessage
+	}
+	length := int(binary.BigEndian.Uint16(buf[2:]))
+	if len(buf) < headerLen+length {
+		return nil, errors.New("truncated STUN message")
+	}
+
+	msg := &message{typ: binary.BigEndian.Uint16(buf[0:])}
+	copy(msg.id[:], buf[8:headerLen])
+
+	attrs := buf[headerLen : headerLen+length]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		attrLen := int(binary.BigEndian.Uint16(attrs[2:]))
+		if len(attrs) < 4+attrLen {
+			return nil, errors.New("truncated STUN attribute")
+		}
+		value := attrs[4 : 4+attrLen]
+
+		var err error
+		switch typ {
+		case attrXORMappedAddress:
+			msg.mappedAddr, err = decodeAddr(value, &msg.id, true)
+		case attrMappedAddress:
+			if msg.mappedAddr == nil {
+				msg.mappedAddr, err = decodeAddr(value, &msg.id, false)
+			}
+		case attrOtherAddress, attrChangedAddress:
+			msg.otherAddr, err = decodeAddr(value, &msg.id, false)
+		case attrResponseOrigin:
+			msg.responseOrigin, err = decodeAddr(value, &msg.id, false)
+		case attrChangeRequest:
+			if len(value) != 4 {
+				return nil, errors.New("invalid CHANGE-REQUEST attribute")
+			}
+			flags := binary.BigEndian.Uint32(value)
+			msg.changeIP, msg.changePort = flags&changeIP != 0, flags&changePort != 0
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode attribute 0x%04x: %w", typ, err)
+		}
+
+		// Attributes are padded to the multiple of 4 bytes.
+		next := 4 + (attrLen+3)&^3
+		if next > len(attrs) {
+			break
+		}
+		attrs = attrs[next:]
+	}
+	return msg, nil
+}
+
+func appendAttr(buf []byte, typ uint16, value []byte) []byte {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint16(header[0:], typ)
+	binary.BigEndian.PutUint16(header[2:], uint16(len(value)))
+	buf = append(buf, header...)
+	buf = append(buf, value...)
+	if pad := len(value) % 4; pad != 0 {
+		buf = append(buf, make([]byte, 4-pad)...)
+	}
+	return buf
+}
+
+func encodeAddr(addr *net.UDPAddr, id *transactionID, xor bool) []byte {
+	family, ip := familyIPv4, addr.IP.To4()
+	if ip == nil {
+		family, ip = familyIPv6, addr.IP.To16()
+	}
+	value := make([]byte, 4+len(ip))
+	value[1] = family
+	binary.BigEndian.PutUint16(value[2:], uint16(addr.Port))
+	copy(value[4:], ip)
+	if xor {
+		xorAddr(value, id)
+	}
+	return value
+}
+
+func decodeAddr(value []byte, id *transactionID, xor bool) (*net.UDPAddr, error) {
+	if len(value) < 4 {
+		return nil, errors.New("invalid address attribute")
+	}
+	var ipLen int
+	switch value[1] {
+	case familyIPv4:
+		ipLen = net.IPv4len
+	case familyIPv6:
+		ipLen = net.IPv6len
+	default:
+		return nil, fmt.Errorf("unknown address family %d", value[1])
+	}
+	if len(value) != 4+ipLen {
+		return nil, errors.New("invalid address attribute length")
+	}
+
+	buf := make([]byte, len(value))
+	copy(buf, value)
+	if xor {
+		xorAddr(buf, id)
+	}
+	return &net.UDPAddr{IP: net.IP(buf[4:]), Port: int(binary.BigEndian.Uint16(buf[2:]))}, nil
+}
+
+// xorAddr obfuscates address port and IP with magic cookie and transaction ID as defined by XOR-MAPPED-ADDRESS.
+func xorAddr(value []byte, id *transactionID) {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint32(key, magicCookie)
+	copy(key[4:], id[:])
+	value[2] ^= key[0]
+	value[3] ^= key[1]
+	for i := range value[4:] {
+		value[4+i] ^= key[i]
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stun
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Server is minimal STUN server supporting NAT behavior discovery. It is a local stand-in
+// of the public STUN servers to be used in tests and local environments.
+//
+// Server listens on two IPs and two ports, so that responses can be sent from alternate address.
+type Server struct {
+	primaryIP   net.IP
+	alternateIP net.IP
+
+	// conns are indexed by [IP index][port index], index 0 is primary and 1 is alternate.
+	conns [2][2]*net.UDPConn
+
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewServer creates new STUN server which will listen on given primary and alternate IPs.
+func NewServer(primaryIP, alternateIP string) *Server {
+	return &Server{
+		primaryIP:   net.ParseIP(primaryIP),
+		alternateIP: net.ParseIP(alternateIP),
+	}
+}
+
+// Start starts listening on random primary and alternate ports.
+func (s *Server) Start() error {
+	for p := 0; p < 2; p++ {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: s.primaryIP})
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("could not listen on primary IP: %w", err)
+		}
+		s.conns[0][p] = conn
+
+		port := conn.LocalAddr().(*net.UDPAddr).Port
+		conn, err = net.ListenUDP("udp", &net.UDPAddr{IP: s.alternateIP, Port: port})
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("could not listen on alternate IP: %w", err)
+		}
+		s.conns[1][p] = conn
+	}
+
+	for i := range s.conns {
+		for p := range s.conns[i] {
+			s.wg.Add(1)
+			go s.serve(i, p)
+		}
+	}
+	return nil
+}
+
+// Addr returns primary address of the server.
+func (s *Server) Addr() *net.UDPAddr {
+	return s.conns[0][0].LocalAddr().(*net.UDPAddr)
+}
+
+// Stop stops the server.
+func (s *Server) Stop() {
+	s.once.Do(func() {
+		for i := range s.conns {
+			for _, conn := range s.conns[i] {
+				if conn != nil {
+					conn.Close()
+				}
+			}
+		}
+	})
+	s.wg.Wait()
+}
+
+func (s *Server) serve(ipIndex, portIndex int) {
+	defer s.wg.Done()
+
+	conn := s.conns[ipIndex][portIndex]
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		req, err := unmarshalMessage(buf[:n])
+		if err != nil || req.typ != typeBindingRequest {
+			continue
+		}
+
+		responseIP, responsePort := ipIndex, portIndex
+		if req.changeIP {
+			responseIP = 1 - ipIndex
+		}
+		if req.changePort {
+			responsePort = 1 - portIndex
+		}
+		responseConn := s.conns[responseIP][responsePort]
+
+		res := message{
+			typ:            typeBindingResponse,
+			id:             req.id,
+			mappedAddr:     addr,
+			otherAddr:      s.conns[1-ipIndex][1-portIndex].LocalAddr().(*net.UDPAddr),
+			responseOrigin: responseConn.LocalAddr().(*net.UDPAddr),
+		}
+		if _, err := responseConn.WriteToUDP(res.marshal(), addr); err != nil {
+			log.Warn().Err(err).Msg("STUN server failed to send response")
+		}
+	}
+}
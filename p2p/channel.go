@@ -29,6 +29,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/trace"
 	"github.com/rs/zerolog/log"
 	kcp "github.com/xtaci/kcp-go/v5"
@@ -75,6 +76,9 @@ type Channel interface {
 	// ServiceConn returns UDP connection which can be used for services.
 	ServiceConn() *net.UDPConn
 
+	// Relayed tells whether channel and service connections go through the relay instead of the peer.
+	Relayed() bool
+
 	// Conn returns underlying channel's UDP connection.
 	Conn() *net.UDPConn
 
@@ -147,6 +151,9 @@ type channel struct {
 	// to pass it to services as p2p channel will be available anyway.
 	serviceConn *net.UDPConn
 
+	// relayed is set when connections are bound to the relay because NAT hole punching failed.
+	relayed bool
+
 	// topicHandlers is similar to HTTP Server handlers and is responsible for handling peer requests.
 	topicHandlers map[string]HandlerFunc
 
@@ -412,6 +419,10 @@ func (c *channel) handleRequest(msg *transportMsg) {
 		log.Err(ctx.publicError).Msgf("Handler %q public error", msg.topic)
 		resMsg.statusCode = statusCodePublicErr
 		resMsg.data = []byte(ctx.publicError.Error())
+		var rpcErr *Error
+		if errors.As(ctx.publicError, &rpcErr) {
+			resMsg.errorCode = uint64(rpcErr.Code)
+		}
 	} else {
 		resMsg.statusCode = statusCodeOK
 		if ctx.res != nil {
@@ -431,6 +442,14 @@ func (c *channel) ServiceConn() *net.UDPConn {
 	return c.serviceConn
 }
 
+// Relayed tells whether channel and service connections go through the relay instead of the peer.
+func (c *channel) Relayed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.relayed
+}
+
 // Close closes channel.
 func (c *channel) Close() error {
 	c.mu.Lock()
@@ -504,12 +523,12 @@ func (c *channel) sendRequest(ctx context.Context, topic string, m *Message) (*M
 	case res := <-s.resCh:
 		if res.statusCode != statusCodeOK {
 			if res.statusCode == statusCodePublicErr {
-				return nil, fmt.Errorf("public peer error: %s", string(res.data))
+				return nil, fmt.Errorf("public peer error: %w", &Error{Code: pb.ErrorCode(res.errorCode), Message: string(res.data)})
 			}
 			if res.statusCode == statusCodeHandlerNotFoundErr {
 				return nil, fmt.Errorf("%s: %w", string(res.data), ErrHandlerNotFound)
 			}
-			return nil, fmt.Errorf("peer error: %w", errors.New(res.msg))
+			return nil, fmt.Errorf("peer error: %w", &Error{Code: pb.ErrorCode_INTERNAL, Message: res.msg})
 		}
 		return &Message{Data: res.data}, nil
 	}
@@ -547,6 +566,13 @@ func (c *channel) setServiceConn(conn *net.UDPConn) {
 	c.serviceConn = conn
 }
 
+func (c *channel) setRelayed(relayed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.relayed = relayed
+}
+
 func (c *channel) setUpnpPortsRelease(release []func()) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -571,7 +597,12 @@ func (c *channel) checkIfChannelAlive() {
 func reopenConn(conn *net.UDPConn) (*net.UDPConn, error) {
 	// conn first must be closed to prevent use of WriteTo with pre-connected connection error.
 	conn.Close()
-	conn, err := net.ListenUDP("udp4", conn.LocalAddr().(*net.UDPAddr))
+	laddr := conn.LocalAddr().(*net.UDPAddr)
+	network := "udp4"
+	if laddr.IP.To4() == nil {
+		network = "udp6"
+	}
+	conn, err := net.ListenUDP(network, laddr)
 	if err != nil {
 		return nil, fmt.Errorf("could not listen UDP: %w", err)
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestChannelFullCommunicationFlow(t *testing.T) {
@@ -43,7 +44,7 @@ func TestChannelFullCommunicationFlow(t *testing.T) {
 
 		consumer.Handle("ping.pong", func(c Context) error {
 			var res pb.PingPong
-			err := c.Request().UnmarshalProto(&res)
+			err := proto.Unmarshal(c.Request().Data, &res)
 			assert.NoError(t, err)
 			consumerReceivedMsg <- &res
 			return c.OK()
@@ -51,19 +52,19 @@ func TestChannelFullCommunicationFlow(t *testing.T) {
 
 		provider.Handle("ping.pong", func(c Context) error {
 			var res pb.PingPong
-			err := c.Request().UnmarshalProto(&res)
+			err := proto.Unmarshal(c.Request().Data, &res)
 			assert.NoError(t, err)
 			providerReceivedMsg <- &res
 			return c.OK()
 		})
 
 		publishedConsumerMsg := &pb.PingPong{Value: "Consumer BigZ"}
-		msg := ProtoMessage(publishedConsumerMsg)
+		msg := protoMessage(t, publishedConsumerMsg)
 		_, err := consumer.Send(context.Background(), "ping.pong", msg)
 		assert.NoError(t, err)
 
 		publishedProviderMsg := &pb.PingPong{Value: "Provider SmallZ"}
-		msg = ProtoMessage(publishedProviderMsg)
+		msg = protoMessage(t, publishedProviderMsg)
 		_, err = provider.Send(context.Background(), "ping.pong", msg)
 		assert.NoError(t, err)
 
@@ -85,20 +86,20 @@ func TestChannelFullCommunicationFlow(t *testing.T) {
 	t.Run("Test request reply pattern", func(t *testing.T) {
 		provider.Handle("testreq", func(c Context) error {
 			var req pb.PingPong
-			err := c.Request().UnmarshalProto(&req)
+			err := proto.Unmarshal(c.Request().Data, &req)
 			assert.NoError(t, err)
 
-			msg := ProtoMessage(&pb.PingPong{Value: req.Value + "-pong"})
+			msg := protoMessage(t, &pb.PingPong{Value: req.Value + "-pong"})
 			assert.NoError(t, err)
 			return c.OkWithReply(msg)
 		})
 
-		msg := ProtoMessage(&pb.PingPong{Value: "ping"})
+		msg := protoMessage(t, &pb.PingPong{Value: "ping"})
 		res, err := consumer.Send(context.Background(), "testreq", msg)
 		assert.NoError(t, err)
 
 		var resMsg pb.PingPong
-		err = res.UnmarshalProto(&resMsg)
+		err = proto.Unmarshal(res.Data, &resMsg)
 		assert.NoError(t, err)
 		assert.Equal(t, "ping-pong", resMsg.Value)
 	})
@@ -357,3 +358,9 @@ func acquirePorts(n int) ([]int, error) {
 	}
 	return res, nil
 }
+
+func protoMessage(t *testing.T, m proto.Message) *Message {
+	data, err := proto.Marshal(m)
+	require.NoError(t, err)
+	return &Message{Data: data}
+}
@@ -26,13 +26,16 @@ import (
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/nat/stun"
 	"github.com/mysteriumnetwork/node/pb"
 
 	"google.golang.org/protobuf/proto"
 )
 
 const (
-	pingMaxPorts       = 20
+	pingMaxPorts = 20
+	// pingFullConePorts is enough for full cone NAT, since it keeps the same mapping for every destination.
+	pingFullConePorts  = 4
 	requiredConnCount  = 2
 	consumerInitialTTL = 128
 )
@@ -49,6 +52,10 @@ type natProviderPinger interface {
 	PingConsumerPeer(ctx context.Context, ip string, localPorts, remotePorts []int, initialTTL int, n int) (conns []*net.UDPConn, err error)
 }
 
+type natTypeProvider interface {
+	NATType() stun.NATType
+}
+
 func configExchangeSubject(providerID identity.Identity, serviceType string) string {
 	return fmt.Sprintf("%s.%s.p2p-config-exchange", providerID.Address, serviceType)
 }
@@ -57,6 +64,10 @@ func configExchangeACKSubject(providerID identity.Identity, serviceType string)
 	return fmt.Sprintf("%s.%s.p2p-config-exchange-ack", providerID.Address, serviceType)
 }
 
+func relayDecisionSubject(providerID identity.Identity, serviceType string, receiverKey PublicKey) string {
+	return fmt.Sprintf("%s.%s.p2p-relay-decision.%s", providerID.Address, serviceType, receiverKey.Hex())
+}
+
 func channelHandlersReadySubject(providerID identity.Identity, serviceType string) string {
 	return fmt.Sprintf("%s.%s.p2p-channel-handlers-ready", providerID.Address, serviceType)
 }
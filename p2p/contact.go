@@ -33,6 +33,8 @@ var (
 const (
 	// ContactTypeV1 is p2p contact type.
 	ContactTypeV1 = "nats/p2p/v1"
+	// ContactTypeRelayV1 is UDP relay contact type.
+	ContactTypeRelayV1 = "relay/udp/v1"
 )
 
 // ContactDefinition represents p2p contact which contains NATS broker addresses for connection.
@@ -40,6 +42,11 @@ type ContactDefinition struct {
 	BrokerAddresses []string `json:"broker_addresses"`
 }
 
+// RelayContactDefinition represents UDP relays through which peer can be reached if NAT hole punching fails.
+type RelayContactDefinition struct {
+	Addresses []string `json:"addresses"`
+}
+
 // ParseContact tries to parse p2p contact from given contacts list.
 func ParseContact(contacts market.ContactList) (ContactDefinition, error) {
 	for _, c := range contacts {
@@ -54,6 +61,20 @@ func ParseContact(contacts market.ContactList) (ContactDefinition, error) {
 	return ContactDefinition{}, ErrContactNotFound
 }
 
+// ParseRelayContact tries to parse UDP relay contact from given contacts list.
+func ParseRelayContact(contacts market.ContactList) (RelayContactDefinition, error) {
+	for _, c := range contacts {
+		if c.Type == ContactTypeRelayV1 {
+			def, ok := c.Definition.(RelayContactDefinition)
+			if !ok {
+				return RelayContactDefinition{}, fmt.Errorf("invalid relay contact definition: %#v", c.Definition)
+			}
+			return def, nil
+		}
+	}
+	return RelayContactDefinition{}, ErrContactNotFound
+}
+
 // RegisterContactUnserializer registers global proposal contact unserializer.
 func RegisterContactUnserializer() {
 	market.RegisterContactUnserializer(
@@ -64,4 +85,12 @@ func RegisterContactUnserializer() {
 			return contact, err
 		},
 	)
+	market.RegisterContactUnserializer(
+		ContactTypeRelayV1,
+		func(rawDefinition *json.RawMessage) (market.ContactDefinition, error) {
+			var contact RelayContactDefinition
+			err := json.Unmarshal(*rawDefinition, &contact)
+			return contact, err
+		},
+	)
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/pb"
 
 	"github.com/rs/zerolog/log"
@@ -44,8 +45,9 @@ const maxBrokerConnectAttempts = 25
 // Dialer knows how to exchange p2p keys and encrypted configuration and creates ready to use p2p channels.
 type Dialer interface {
 	// Dial exchanges p2p configuration via broker, performs NAT pinging if needed
-	// and create p2p channel which is ready for communication.
-	Dial(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, contactDef ContactDefinition, tracer *trace.Tracer) (Channel, error)
+	// and create p2p channel which is ready for communication. If NAT pinging fails
+	// channel is relayed through the first of the given relays.
+	Dial(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, contactDef ContactDefinition, relayDef RelayContactDefinition, tracer *trace.Tracer) (Channel, error)
 }
 
 // NewDialer creates new p2p communication dialer which is used on consumer side.
@@ -72,7 +74,7 @@ type dialer struct {
 
 // Dial exchanges p2p configuration via broker, performs NAT pinging if needed
 // and create p2p channel which is ready for communication.
-func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, contactDef ContactDefinition, tracer *trace.Tracer) (Channel, error) {
+func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, contactDef ContactDefinition, relayDef RelayContactDefinition, tracer *trace.Tracer) (Channel, error) {
 	config := &p2pConnectConfig{tracer: tracer}
 
 	// Send initial exchange with signed consumer public key.
@@ -102,17 +104,43 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 		return nil, fmt.Errorf("could not prepare ports: %w", err)
 	}
 
+	var decision *relayDecision
+	if len(config.peerPorts) != requiredConnCount && len(relayDef.Addresses) > 0 {
+		decision, err = newRelayDecision(brokerConn, providerID, serviceType, config)
+		if err != nil {
+			return nil, err
+		}
+		defer decision.close()
+	}
+	config.publicIPv6 = resolvePublicIPv6(m.ipResolver)
+
 	// Finally send consumer encrypted and signed connect config in ack message.
 	err = m.ackConfigExchange(config, ctx, brokerConn, providerID, serviceType, consumerID)
 	if err != nil {
 		return nil, fmt.Errorf("could not ack config: %w", err)
 	}
 
-	dial := m.dialPinger
-	if len(config.peerPorts) == requiredConnCount {
-		dial = m.dialDirect
+	var conn1, conn2 *net.UDPConn
+	var relayed bool
+	if config.ipv6() {
+		conn1, conn2, err = m.dialIPv6(ctx, config)
+		if err != nil {
+			log.Warn().Err(err).Msg("Could not reach provider over IPv6, falling back to IPv4")
+		}
+	}
+	if conn1 != nil {
+		log.Debug().Msgf("Connected to provider over IPv6 %s", config.peerPublicIPv6)
+	} else if len(config.peerPorts) == requiredConnCount {
+		conn1, conn2, err = m.dialDirect(ctx, providerID, config)
+	} else {
+		conn1, conn2, err = m.dialPinger(ctx, providerID, config)
+		if decision != nil && decision.relay(ctx, err) {
+			log.Warn().Err(err).Msg("NAT hole punching failed, falling back to relay")
+			closeUDPConns(conn1, conn2)
+			conn1, conn2, err = m.dialRelay(ctx, relayDef.Addresses[0], config)
+			relayed = true
+		}
 	}
-	conn1, conn2, err := dial(ctx, providerID, config)
 	if err != nil {
 		return nil, fmt.Errorf("could not dial p2p channel: %w", err)
 	}
@@ -132,6 +160,7 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 	}
 	channel.setTracer(tracer)
 	channel.setServiceConn(conn2)
+	channel.setRelayed(relayed)
 	channel.launchReadSendLoops()
 	config.tracer.EndStage(traceAck)
 
@@ -165,7 +194,8 @@ func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Conte
 	}
 
 	beginExchangeMsg := &pb.P2PConfigExchangeMsg{
-		PublicKey: pubKey.Hex(),
+		PublicKey:   pubKey.Hex(),
+		Traceparent: config.tracer.SpanContext().Traceparent(),
 	}
 	log.Debug().Msgf("Consumer %s sending public key %s to provider %s", consumerID.Address, beginExchangeMsg.PublicKey, providerID.Address)
 	packedMsg, err := packSignedMsg(m.signer, consumerID, beginExchangeMsg)
@@ -200,6 +230,7 @@ func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Conte
 	config.privateKey = privateKey
 	config.peerPubKey = peerPubKey
 	config.peerPublicIP = peerConnConfig.PublicIP
+	config.peerPublicIPv6 = peerConnConfig.PublicIPv6
 	config.peerPorts = int32ToIntSlice(peerConnConfig.Ports)
 	return config, nil
 }
@@ -209,8 +240,9 @@ func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context
 	defer config.tracer.EndStage(trace)
 
 	connConfig := &pb.P2PConnectConfig{
-		PublicIP: config.publicIP,
-		Ports:    intToInt32Slice(config.localPorts),
+		PublicIP:   config.publicIP,
+		PublicIPv6: config.publicIPv6,
+		Ports:      intToInt32Slice(config.localPorts),
 	}
 	connConfigCiphertext, err := encryptConnConfigMsg(connConfig, config.privateKey, config.peerPubKey)
 	if err != nil {
@@ -277,6 +309,18 @@ func (m *dialer) dialDirect(ctx context.Context, providerID identity.Identity, c
 	return conn1, conn2, err
 }
 
+func (m *dialer) dialIPv6(ctx context.Context, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	trace := config.tracer.StartStage("Consumer P2P dial (ipv6)")
+	defer config.tracer.EndStage(trace)
+
+	if _, err := firewall.AllowIPAccess(config.peerPublicIPv6); err != nil {
+		return nil, nil, fmt.Errorf("could not add peer IPv6 firewall rule: %w", err)
+	}
+
+	log.Debug().Msgf("Dialing provider with IPv6 %s using ports %v:%v", config.peerPublicIPv6, config.localPorts, config.peerPorts)
+	return dialIPv6(ctx, config)
+}
+
 func (m *dialer) dialPinger(ctx context.Context, providerID identity.Identity, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
 	trace := config.tracer.StartStage("Consumer P2P dial (pinger)")
 	defer config.tracer.EndStage(trace)
@@ -293,6 +337,14 @@ func (m *dialer) dialPinger(ctx context.Context, providerID identity.Identity, c
 	return conns[0], conns[1], nil
 }
 
+func (m *dialer) dialRelay(ctx context.Context, relayAddr string, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	trace := config.tracer.StartStage("Consumer P2P dial (relay)")
+	defer config.tracer.EndStage(trace)
+
+	log.Debug().Msgf("Dialing provider via relay %s", relayAddr)
+	return dialRelay(ctx, relayAddr, config, relay.SideDialer)
+}
+
 func (m *dialer) sendSignedMsg(ctx context.Context, subject string, msg []byte, brokerConn nats.Connection) ([]byte, error) {
 	reply, err := brokerConn.RequestWithContext(ctx, subject, msg)
 	if err != nil {
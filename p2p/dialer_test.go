@@ -19,6 +19,7 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
 	"time"
@@ -28,13 +29,16 @@ import (
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/nat/mapping"
+	"github.com/mysteriumnetwork/node/nat/stun"
 	"github.com/mysteriumnetwork/node/nat/traversal"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/trace"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDialer_Exchange_And_Communication_With_Provider(t *testing.T) {
 	providerPinger, consumerPinger := natTestPingers(t)
+	fullConeProviderPinger, fullConeConsumerPinger := natTestPingers(t)
 
 	tests := []struct {
 		name              string
@@ -42,6 +46,9 @@ func TestDialer_Exchange_And_Communication_With_Provider(t *testing.T) {
 		natProviderPinger natProviderPinger
 		natConsumerPinger natConsumerPinger
 		portMapper        mapping.PortMapper
+		natType           stun.NATType
+		relay             bool
+		ipv6              bool
 	}{
 		{
 			name:              "Provider with public IP",
@@ -70,6 +77,38 @@ func TestDialer_Exchange_And_Communication_With_Provider(t *testing.T) {
 			natConsumerPinger: traversal.NewNoopPinger(),
 			portMapper:        &mockPortMapper{enabled: false},
 		},
+		{
+			name:              "Provider behind full cone NAT",
+			ipResolver:        ip.NewResolverMockMultiple("127.0.0.1", "1.1.1.1"),
+			natProviderPinger: fullConeProviderPinger,
+			natConsumerPinger: fullConeConsumerPinger,
+			portMapper:        &mockPortMapper{},
+			natType:           stun.NATTypeFullCone,
+		},
+		{
+			name:              "Provider behind symmetric NAT with relay",
+			ipResolver:        ip.NewResolverMockMultiple("127.0.0.1", "1.1.1.1"),
+			natProviderPinger: &mockProviderNATPinger{err: errors.New("ping timeout")},
+			natConsumerPinger: &mockConsumerNATPinger{err: errors.New("ping timeout")},
+			portMapper:        &mockPortMapper{},
+			relay:             true,
+		},
+		{
+			name:              "Only consumer fails NAT hole punching with relay",
+			ipResolver:        ip.NewResolverMockMultiple("127.0.0.1", "1.1.1.1"),
+			natProviderPinger: providerPinger,
+			natConsumerPinger: &mockConsumerNATPinger{err: errors.New("ping timeout")},
+			portMapper:        &mockPortMapper{},
+			relay:             true,
+		},
+		{
+			name:              "Peers with public IPv6",
+			ipResolver:        ip.NewResolverMockIPv6("1.1.1.1", "::1"),
+			natProviderPinger: &mockProviderNATPinger{err: errors.New("ping timeout")},
+			natConsumerPinger: &mockConsumerNATPinger{err: errors.New("ping timeout")},
+			portMapper:        &mockPortMapper{},
+			ipv6:              true,
+		},
 	}
 
 	for _, test := range tests {
@@ -84,9 +123,19 @@ func TestDialer_Exchange_And_Communication_With_Provider(t *testing.T) {
 			mockBroker := &mockBroker{conn: brokerConn}
 			portPool := port.NewPool()
 
+			var relayAddresses []string
+			if test.relay {
+				relayServer := relay.NewServer("127.0.0.1:0", relay.DefaultIdleTimeout, relay.DefaultMaxSessions)
+				assert.NoError(t, relayServer.Start())
+				defer relayServer.Stop()
+				relayAddresses = []string{relayServer.Addr().String()}
+			}
+
 			// Provider starts listening.
-			channelListener := NewListener(brokerConn, signerFactory, verifier, test.ipResolver, test.natProviderPinger, portPool, test.portMapper)
+			channelListener := NewListener(brokerConn, signerFactory, verifier, test.ipResolver, test.natProviderPinger, portPool, test.portMapper, &mockNATTypeProvider{natType: test.natType}, relayAddresses)
+			providerChannel := make(chan Channel, 1)
 			_, err := channelListener.Listen(providerID, "wireguard", func(ch Channel) {
+				providerChannel <- ch
 				ch.Handle("test", func(c Context) error {
 					return c.OkWithReply(&Message{Data: []byte("pong")})
 				})
@@ -97,13 +146,52 @@ func TestDialer_Exchange_And_Communication_With_Provider(t *testing.T) {
 			channelDialer := NewDialer(mockBroker, signerFactory, verifier, test.ipResolver, test.natConsumerPinger, portPool)
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			consumerChannel, err := channelDialer.Dial(ctx, identity.FromAddress("0x2"), providerID, "wireguard", ContactDefinition{BrokerAddresses: []string{"broker"}}, trace.NewTracer("Dial"))
+			relayDef, _ := ParseRelayContact(channelListener.GetContacts())
+			consumerChannel, err := channelDialer.Dial(ctx, identity.FromAddress("0x2"), providerID, "wireguard", ContactDefinition{BrokerAddresses: []string{"broker"}}, relayDef, trace.NewTracer("Dial"))
 			assert.NoError(t, err)
 			defer consumerChannel.Close()
 
 			res, err := consumerChannel.Send(context.Background(), "test", &Message{Data: []byte("ping")})
 			assert.NoError(t, err)
 			assert.Equal(t, "pong", string(res.Data))
+			assert.Equal(t, test.relay, consumerChannel.Relayed())
+			assert.Equal(t, test.relay, (<-providerChannel).Relayed())
+			if test.relay {
+				assert.Equal(t, relayAddresses[0], consumerChannel.ServiceConn().RemoteAddr().String())
+			}
+			if test.ipv6 {
+				assert.Equal(t, "::1", consumerChannel.ServiceConn().RemoteAddr().(*net.UDPAddr).IP.String())
+			}
+		})
+	}
+}
+
+func TestListener_PrepareLocalPorts_KeepsPingerPortsBehindNAT(t *testing.T) {
+	tests := []struct {
+		natType       stun.NATType
+		expectedPorts int
+	}{
+		{natType: stun.NATTypeFullCone, expectedPorts: pingFullConePorts},
+		{natType: stun.NATTypeOpen, expectedPorts: pingFullConePorts},
+		{natType: stun.NATTypeSymmetric, expectedPorts: pingMaxPorts},
+		{natType: stun.NATTypeUnknown, expectedPorts: pingMaxPorts},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.natType), func(t *testing.T) {
+			l := &listener{
+				ipResolver:     ip.NewResolverMock("1.1.1.1"),
+				portPool:       port.NewPool(),
+				portMapper:     &mockPortMapper{},
+				providerPinger: &mockProviderNATPinger{},
+				natType:        &mockNATTypeProvider{natType: test.natType},
+			}
+
+			publicIP, ports, _, err := l.prepareLocalPorts("127.0.0.1", trace.NewTracer(""))
+
+			assert.NoError(t, err)
+			assert.Equal(t, "1.1.1.1", publicIP)
+			assert.Len(t, ports, test.expectedPorts)
 		})
 	}
 }
@@ -124,18 +212,20 @@ func natTestPingers(t *testing.T) (providerPinger natProviderPinger, consumerPin
 
 type mockConsumerNATPinger struct {
 	conns []*net.UDPConn
+	err   error
 }
 
 func (m *mockConsumerNATPinger) PingProviderPeer(ctx context.Context, ip string, localPorts, remotePorts []int, initialTTL int, n int) (conns []*net.UDPConn, err error) {
-	return m.conns, nil
+	return m.conns, m.err
 }
 
 type mockProviderNATPinger struct {
 	conns []*net.UDPConn
+	err   error
 }
 
 func (m *mockProviderNATPinger) PingConsumerPeer(ctx context.Context, ip string, localPorts, remotePorts []int, initialTTL int, n int) (conns []*net.UDPConn, err error) {
-	return m.conns, nil
+	return m.conns, m.err
 }
 
 type mockBroker struct {
@@ -146,6 +236,14 @@ func (m *mockBroker) Connect(serverURIs ...string) (nats.Connection, error) {
 	return m.conn, nil
 }
 
+type mockNATTypeProvider struct {
+	natType stun.NATType
+}
+
+func (m *mockNATTypeProvider) NATType() stun.NATType {
+	return m.natType
+}
+
 type mockPortMapper struct {
 	enabled bool
 }
@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+// AppTopicKeepAliveFailed is a topic for publish events about failed p2p channel keep alive pings.
+const AppTopicKeepAliveFailed = "p2p_keepalive_failed"
+
+const (
+	// RoleConsumer marks events of the consumer side of the p2p channel.
+	RoleConsumer = "consumer"
+	// RoleProvider marks events of the provider side of the p2p channel.
+	RoleProvider = "provider"
+)
+
+// AppEventKeepAliveFailed represents a failed keep alive ping sent through p2p channel.
+type AppEventKeepAliveFailed struct {
+	Role      string
+	SessionID string
+	Error     string
+}
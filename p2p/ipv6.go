@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+)
+
+const (
+	// ipv6DialTimeout is how long peers try to reach each other over IPv6 before falling back to IPv4.
+	ipv6DialTimeout = 5 * time.Second
+	// ipv6ProbeInterval is how often probes are sent until the peer answers.
+	ipv6ProbeInterval = 200 * time.Millisecond
+	// ipv6ProbeGrace is how long pings of the peer are still answered after probing succeeded.
+	ipv6ProbeGrace = 3 * ipv6ProbeInterval
+)
+
+var (
+	ipv6ProbePing = []byte("myst-p2p-ipv6-ping")
+	ipv6ProbePong = []byte("myst-p2p-ipv6-pong")
+)
+
+// resolvePublicIPv6 returns public IPv6 address of the host or empty string if there is none.
+func resolvePublicIPv6(resolver ip.Resolver) string {
+	publicIPv6, err := resolver.GetPublicIPv6()
+	if err != nil {
+		log.Debug().Err(err).Msg("Public IPv6 is not available, only IPv4 will be used for p2p")
+		return ""
+	}
+	return publicIPv6
+}
+
+// dialIPv6 creates p2p channel and service connections directly to the public IPv6 address of the peer.
+// Peers are not behind NAT over IPv6, but stateful firewalls still drop unsolicited packets,
+// so both peers keep probing each other until each of them received a probe and an answer to its own probe.
+func dialIPv6(ctx context.Context, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, ipv6DialTimeout)
+	defer cancel()
+
+	peerIP := net.ParseIP(config.peerPublicIPv6)
+	if peerIP == nil {
+		return nil, nil, fmt.Errorf("invalid peer IPv6 address %q", config.peerPublicIPv6)
+	}
+
+	var conns []*net.UDPConn
+	closeConns := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	for i := 0; i < requiredConnCount; i++ {
+		conn, err := net.DialUDP("udp6", &net.UDPAddr{Port: config.localPorts[i]}, &net.UDPAddr{IP: peerIP, Port: config.peerPorts[i]})
+		if err != nil {
+			closeConns()
+			return nil, nil, fmt.Errorf("could not create UDP6 conn: %w", err)
+		}
+		conns = append(conns, conn)
+	}
+
+	errs := make(chan error, len(conns))
+	for _, conn := range conns {
+		go func(conn *net.UDPConn) {
+			errs <- probeIPv6(ctx, conn)
+		}(conn)
+	}
+	var probeErr error
+	for range conns {
+		if err := <-errs; err != nil && probeErr == nil {
+			probeErr = err
+		}
+	}
+	if probeErr != nil {
+		closeConns()
+		return nil, nil, probeErr
+	}
+
+	for _, conn := range conns {
+		if err := conn.SetReadDeadline(time.Time{}); err != nil {
+			closeConns()
+			return nil, nil, fmt.Errorf("could not reset read deadline: %w", err)
+		}
+	}
+	return conns[0], conns[1], nil
+}
+
+// probeIPv6 sends pings to the peer and answers its pings until connection works in both directions.
+func probeIPv6(ctx context.Context, conn *net.UDPConn) error {
+	var gotPing, gotPong bool
+	buf := make([]byte, 64)
+	for !gotPing || !gotPong {
+		if ctx.Err() != nil {
+			return errors.New("timeout while probing peer over IPv6")
+		}
+		if !gotPong {
+			if _, err := conn.Write(ipv6ProbePing); err != nil {
+				log.Debug().Err(err).Msg("Could not send IPv6 probe")
+			}
+		}
+
+		if err := conn.SetReadDeadline(time.Now().Add(ipv6ProbeInterval)); err != nil {
+			return fmt.Errorf("could not set read deadline: %w", err)
+		}
+		n, err := conn.Read(buf)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			continue
+		}
+		if err != nil {
+			// Peer might not be listening yet, which results in ICMP port unreachable.
+			select {
+			case <-ctx.Done():
+			case <-time.After(ipv6ProbeInterval):
+			}
+			continue
+		}
+
+		switch {
+		case bytes.Equal(buf[:n], ipv6ProbePing):
+			gotPing = true
+			if _, err := conn.Write(ipv6ProbePong); err != nil {
+				log.Debug().Err(err).Msg("Could not answer IPv6 probe")
+			}
+		case bytes.Equal(buf[:n], ipv6ProbePong):
+			gotPong = true
+		}
+	}
+
+	answerIPv6Probes(ctx, conn, buf)
+	return nil
+}
+
+// answerIPv6Probes keeps answering pings of the peer for a grace period, so the peer
+// still gets an answer if the last one was lost. Every ping received extends the period.
+func answerIPv6Probes(ctx context.Context, conn *net.UDPConn, buf []byte) {
+	deadline := time.Now().Add(ipv6ProbeGrace)
+	for ctx.Err() == nil {
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return
+		}
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		if bytes.Equal(buf[:n], ipv6ProbePing) {
+			if _, err := conn.Write(ipv6ProbePong); err != nil {
+				log.Debug().Err(err).Msg("Could not answer IPv6 probe")
+			}
+			deadline = time.Now().Add(ipv6ProbeGrace)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeIPv6_AnswersPingsAfterLostPong(t *testing.T) {
+	peer, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	defer peer.Close()
+	conn, err := net.DialUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback}, peer.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- probeIPv6(context.Background(), conn)
+	}()
+
+	// Peer pings and answers the ping, but the answer to its own ping gets lost.
+	_, err = peer.WriteToUDP(ipv6ProbePing, conn.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	readProbes(t, peer, ipv6ProbePing, ipv6ProbePong)
+	_, err = peer.WriteToUDP(ipv6ProbePong, conn.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+
+	// Peer pings again and still gets an answer.
+	_, err = peer.WriteToUDP(ipv6ProbePing, conn.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	readProbes(t, peer, ipv6ProbePong)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(ipv6DialTimeout):
+		t.Fatal("probing did not finish")
+	}
+}
+
+// readProbes reads packets until every wanted probe was received.
+func readProbes(t *testing.T, conn *net.UDPConn, want ...[]byte) {
+	buf := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	for len(want) > 0 {
+		n, _, err := conn.ReadFromUDP(buf)
+		require.NoError(t, err)
+		for i := range want {
+			if bytes.Equal(buf[:n], want[i]) {
+				want = append(want[:i], want[i+1:]...)
+				break
+			}
+		}
+	}
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/nat/mapping"
+	"github.com/mysteriumnetwork/node/nat/stun"
 	"github.com/mysteriumnetwork/node/nat/traversal"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/trace"
 
@@ -45,13 +47,14 @@ type Listener interface {
 	// to channelHandlers
 	Listen(providerID identity.Identity, serviceType string, channelHandler func(ch Channel)) (func(), error)
 
-	// GetContact returns contract which is later can be added to proposal contacts definition so consumer can
+	// GetContacts returns contacts which are later added to proposal contacts definition so consumer can
 	// know how to connect to this p2p listener.
-	GetContact() market.Contact
+	GetContacts() market.ContactList
 }
 
 // NewListener creates new p2p communication listener which is used on provider side.
-func NewListener(brokerConn nats.Connection, signer identity.SignerFactory, verifier identity.Verifier, ipResolver ip.Resolver, providerPinger natProviderPinger, portPool port.ServicePortSupplier, portMapper mapping.PortMapper) Listener {
+// Given relays are used by the consumers which fail to reach provider using NAT hole punching.
+func NewListener(brokerConn nats.Connection, signer identity.SignerFactory, verifier identity.Verifier, ipResolver ip.Resolver, providerPinger natProviderPinger, portPool port.ServicePortSupplier, portMapper mapping.PortMapper, natType natTypeProvider, relayAddresses []string) Listener {
 	return &listener{
 		brokerConn:     brokerConn,
 		pendingConfigs: map[PublicKey]p2pConnectConfig{},
@@ -61,6 +64,8 @@ func NewListener(brokerConn nats.Connection, signer identity.SignerFactory, veri
 		portPool:       portPool,
 		providerPinger: providerPinger,
 		portMapper:     portMapper,
+		natType:        natType,
+		relayAddresses: relayAddresses,
 	}
 }
 
@@ -73,6 +78,8 @@ type listener struct {
 	verifier       identity.Verifier
 	ipResolver     ip.Resolver
 	portMapper     mapping.PortMapper
+	natType        natTypeProvider
+	relayAddresses []string
 
 	// Keys holds pendingConfigs temporary configs for provider side since it
 	// need to handle key exchange in two steps.
@@ -83,6 +90,8 @@ type listener struct {
 type p2pConnectConfig struct {
 	publicIP         string
 	peerPublicIP     string
+	publicIPv6       string
+	peerPublicIPv6   string
 	peerPorts        []int
 	localPorts       []int
 	publicKey        PublicKey
@@ -100,10 +109,23 @@ func (c *p2pConnectConfig) peerIP() string {
 	return c.peerPublicIP
 }
 
-func (m *listener) GetContact() market.Contact {
-	return market.Contact{
+// ipv6 returns true if both peers have public IPv6 addresses.
+func (c *p2pConnectConfig) ipv6() bool {
+	return c.publicIPv6 != "" && c.peerPublicIPv6 != ""
+}
+
+func (m *listener) GetContacts() market.ContactList {
+	contacts := market.ContactList{{
 		Type:       ContactTypeV1,
-		Definition: ContactDefinition{BrokerAddresses: m.brokerConn.Servers()}}
+		Definition: ContactDefinition{BrokerAddresses: m.brokerConn.Servers()},
+	}}
+	if len(m.relayAddresses) > 0 {
+		contacts = append(contacts, market.Contact{
+			Type:       ContactTypeRelayV1,
+			Definition: RelayContactDefinition{Addresses: m.relayAddresses},
+		})
+	}
+	return contacts
 }
 
 // Listen listens for incoming peer connections to establish new p2p channels. Establishes p2p channel and passes it
@@ -151,7 +173,18 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		}(msg.Reply)
 
 		var conn1, conn2 *net.UDPConn
-		if len(config.peerPorts) == requiredConnCount {
+		var relayed bool
+		if config.ipv6() {
+			traceDial := config.tracer.StartStage("Provider P2P dial (ipv6)")
+			conn1, conn2, err = dialIPv6(context.Background(), config)
+			config.tracer.EndStage(traceDial)
+			if err != nil {
+				log.Warn().Err(err).Msg("Could not reach consumer over IPv6, falling back to IPv4")
+			}
+		}
+		if conn1 != nil {
+			log.Debug().Msgf("Connected to consumer over IPv6 %s", config.peerPublicIPv6)
+		} else if len(config.peerPorts) == requiredConnCount {
 			traceDial := config.tracer.StartStage("Provider P2P dial (upnp)")
 			log.Debug().Msg("Skipping consumer ping")
 			conn1, err = net.DialUDP("udp4", &net.UDPAddr{Port: config.localPorts[0]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: config.peerPorts[0]})
@@ -166,17 +199,41 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 			}
 			config.tracer.EndStage(traceDial)
 		} else {
+			var decision *relayDecision
+			if len(m.relayAddresses) > 0 {
+				decision, err = newRelayDecision(m.brokerConn, providerID, serviceType, config)
+				if err != nil {
+					log.Err(err).Msg("Could not prepare relay fallback")
+					return
+				}
+				defer decision.close()
+			}
+
 			traceDial := config.tracer.StartStage("Provider P2P dial (pinger)")
 			log.Debug().Msgf("Pinging consumer with IP %s using ports %v:%v initial ttl: %v",
 				config.peerIP(), config.localPorts, config.peerPorts, providerInitialTTL)
 			conns, err := m.providerPinger.PingConsumerPeer(context.Background(), config.peerIP(), config.localPorts, config.peerPorts, providerInitialTTL, requiredConnCount)
-			if err != nil {
+			config.tracer.EndStage(traceDial)
+			if err == nil {
+				conn1 = conns[0]
+				conn2 = conns[1]
+			}
+			switch {
+			case decision != nil && decision.relay(context.Background(), err):
+				log.Warn().Err(err).Msg("NAT hole punching failed, falling back to relay")
+				closeUDPConns(conn1, conn2)
+				traceRelay := config.tracer.StartStage("Provider P2P dial (relay)")
+				conn1, conn2, err = dialRelay(context.Background(), m.relayAddresses[0], config, relay.SideListener)
+				config.tracer.EndStage(traceRelay)
+				if err != nil {
+					log.Err(err).Msg("Could not dial peer via relay")
+					return
+				}
+				relayed = true
+			case err != nil:
 				log.Err(err).Msg("Could not ping peer")
 				return
 			}
-			conn1 = conns[0]
-			conn2 = conns[1]
-			config.tracer.EndStage(traceDial)
 		}
 
 		traceAck := config.tracer.StartStage("Provider P2P dial ack")
@@ -187,6 +244,7 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		}
 		channel.setTracer(config.tracer)
 		channel.setServiceConn(conn2)
+		channel.setRelayed(relayed)
 		channel.setUpnpPortsRelease(config.upnpPortsRelease)
 
 		channelHandlers(channel)
@@ -220,16 +278,6 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 }
 
 func (m *listener) providerStartConfigExchange(signerID identity.Identity, msg *nats_lib.Msg, outboundIP string) error {
-	tracer := trace.NewTracer("Provider whole Connect")
-
-	trace := tracer.StartStage("Provider P2P exchange")
-	defer tracer.EndStage(trace)
-
-	pubKey, privateKey, err := GenerateKey()
-	if err != nil {
-		return fmt.Errorf("could not generate provider p2p keys: %w", err)
-	}
-
 	// Get initial peer exchange with it's public key.
 	signedMsg, err := unpackSignedMsg(m.verifier, msg.Data)
 	if err != nil {
@@ -239,6 +287,16 @@ func (m *listener) providerStartConfigExchange(signerID identity.Identity, msg *
 	if err := proto.Unmarshal(signedMsg.Data, &peerExchangeMsg); err != nil {
 		return err
 	}
+
+	tracer := trace.NewChildTracer("Provider whole Connect", consumerSpanContext(peerExchangeMsg.Traceparent))
+
+	trace := tracer.StartStage("Provider P2P exchange")
+	defer tracer.EndStage(trace)
+
+	pubKey, privateKey, err := GenerateKey()
+	if err != nil {
+		return fmt.Errorf("could not generate provider p2p keys: %w", err)
+	}
 	peerPubKey, err := DecodePublicKey(peerExchangeMsg.PublicKey)
 	if err != nil {
 		return err
@@ -250,8 +308,11 @@ func (m *listener) providerStartConfigExchange(signerID identity.Identity, msg *
 		return fmt.Errorf("could not prepare ports: %w", err)
 	}
 
+	publicIPv6 := resolvePublicIPv6(m.ipResolver)
+
 	m.setPendingConfig(p2pConnectConfig{
 		publicIP:         publicIP,
+		publicIPv6:       publicIPv6,
 		localPorts:       localPorts,
 		publicKey:        pubKey,
 		privateKey:       privateKey,
@@ -263,8 +324,9 @@ func (m *listener) providerStartConfigExchange(signerID identity.Identity, msg *
 	})
 
 	config := pb.P2PConnectConfig{
-		PublicIP: publicIP,
-		Ports:    intToInt32Slice(localPorts),
+		PublicIP:   publicIP,
+		PublicIPv6: publicIPv6,
+		Ports:      intToInt32Slice(localPorts),
 	}
 	configCiphertext, err := encryptConnConfigMsg(&config, privateKey, peerPubKey)
 	if err != nil {
@@ -289,7 +351,7 @@ func (m *listener) providerStartConfigExchange(signerID identity.Identity, msg *
 // prepareLocalPorts acquires ports for p2p connections. It tries to acquire only
 // required ports count for actual p2p and service connections and fallback to
 // acquiring extra ports for nat pinger if provider is behind nat, port mapping failed
-// and no manual port forwarding is enabled.
+// and no manual port forwarding is enabled. NAT type tunes the count of extra ports.
 func (m *listener) prepareLocalPorts(outboundIP string, tracer *trace.Tracer) (string, []int, []func(), error) {
 	trace := tracer.StartStage("Provider P2P exchange (ports)")
 	defer tracer.EndStage(trace)
@@ -331,8 +393,13 @@ func (m *listener) prepareLocalPorts(outboundIP string, tracer *trace.Tracer) (s
 		return publicIP, localPorts, nil, nil
 	}
 
-	// Acquire more ports for nat pinger.
-	morePorts, err := acquireLocalPorts(m.portPool, pingMaxPorts-requiredConnCount)
+	// Acquire more ports for nat pinger. Full cone NAT needs only a few of them to open the mapping.
+	pingPorts := pingMaxPorts
+	if natType := m.natType.NATType(); natType == stun.NATTypeOpen || natType == stun.NATTypeFullCone {
+		log.Debug().Msgf("Using %d ports for NAT pinger, NAT type is %s", pingFullConePorts, natType)
+		pingPorts = pingFullConePorts
+	}
+	morePorts, err := acquireLocalPorts(m.portPool, pingPorts-requiredConnCount)
 	if err != nil {
 		return publicIP, nil, nil, fmt.Errorf("could not acquire more local ports: %v", err)
 	}
@@ -342,6 +409,18 @@ func (m *listener) prepareLocalPorts(outboundIP string, tracer *trace.Tracer) (s
 	return publicIP, localPorts, nil, nil
 }
 
+// consumerSpanContext returns consumer trace context, older consumers do not send it.
+func consumerSpanContext(traceparent string) trace.SpanContext {
+	if traceparent == "" {
+		return trace.SpanContext{}
+	}
+	sc, err := trace.ParseTraceparent(traceparent)
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring consumer trace context")
+	}
+	return sc
+}
+
 func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConfig, error) {
 	signedMsg, err := unpackSignedMsg(m.verifier, msg.Data)
 	if err != nil {
@@ -371,12 +450,14 @@ func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConf
 
 	return &p2pConnectConfig{
 		peerPublicIP:     peerConfig.PublicIP,
+		peerPublicIPv6:   peerConfig.PublicIPv6,
 		peerPorts:        int32ToIntSlice(peerConfig.Ports),
 		localPorts:       config.localPorts,
 		publicKey:        config.publicKey,
 		privateKey:       config.privateKey,
 		peerPubKey:       config.peerPubKey,
 		publicIP:         config.publicIP,
+		publicIPv6:       config.publicIPv6,
 		tracer:           config.tracer,
 		upnpPortsRelease: config.upnpPortsRelease,
 	}, nil
@@ -22,9 +22,6 @@ import (
 	"fmt"
 	"net/textproto"
 	"strconv"
-
-	"github.com/rs/zerolog/log"
-	"google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -34,52 +31,17 @@ func init() {
 	textproto.CanonicalMIMEHeaderKey("")
 }
 
-const (
-	// TopicKeepAlive is keep alive endpoint.
-	TopicKeepAlive = "p2p-keepalive"
-
-	// TopicSessionCreate is a session create endpoint for p2p communication.
-	TopicSessionCreate = "p2p-session-create"
-	// TopicSessionAcknowledge is a session acknowledge endpoint for p2p communication.
-	TopicSessionAcknowledge = "p2p-session-acknowledge"
-	// TopicSessionStatus is a session status notification for p2p communication.
-	TopicSessionStatus = "p2p-session-connectivity-status"
-	// TopicSessionDestroy is a session destroy endpoint for p2p communication.
-	TopicSessionDestroy = "p2p-session-destroy"
-
-	// TopicPaymentMessage is a payment messages endpoint for p2p communication.
-	TopicPaymentMessage = "p2p-payment-message"
-	// TopicPaymentInvoice is a payment invoices endpoint for p2p communication.
-	TopicPaymentInvoice = "p2p-payment-invoice"
-)
-
 // Message represent message with data bytes.
 type Message struct {
 	Data []byte
 }
 
-// UnmarshalProto is convenient helper to unmarshal message data into strongly typed proto message.
-func (m *Message) UnmarshalProto(to proto.Message) error {
-	return proto.Unmarshal(m.Data, to)
-}
-
-// ProtoMessage is convenient helper to return message with marshaled proto data bytes.
-func ProtoMessage(m proto.Message) *Message {
-	pbBytes, err := proto.Marshal(m)
-	if err != nil {
-		// In general this should never happen as passed input value
-		// should implement proto.Message interface.
-		log.Err(err).Msg("Failed to marshal proto message")
-		return &Message{Data: []byte{}}
-	}
-	return &Message{Data: pbBytes}
-}
-
 const (
 	headerFieldRequestID = "Request-ID"
 	headerFieldTopic     = "Topic"
 	headerStatusCode     = "Status-Code"
 	headerMsg            = "Message"
+	headerErrorCode      = "Error-Code"
 
 	statusCodeOK                 = 1
 	statusCodePublicErr          = 2
@@ -94,6 +56,7 @@ type transportMsg struct {
 	statusCode uint64
 	topic      string
 	msg        string
+	errorCode  uint64
 
 	// Data field.
 	data []byte
@@ -117,6 +80,13 @@ func (m *transportMsg) readFrom(conn *textproto.Reader) error {
 	m.statusCode = statusCode
 	m.topic = header.Get(headerFieldTopic)
 	m.msg = header.Get(headerMsg)
+	// Error code is not sent by older peers.
+	if errorCode := header.Get(headerErrorCode); errorCode != "" {
+		m.errorCode, err = strconv.ParseUint(errorCode, 10, 64)
+		if err != nil {
+			return fmt.Errorf("could not parse error code: %w", err)
+		}
+	}
 
 	// Read data.
 	data, err := conn.ReadDotBytes()
@@ -136,6 +106,9 @@ func (m *transportMsg) writeTo(conn *textproto.Writer) error {
 	header.WriteString(fmt.Sprintf("%s:%s\r\n", headerFieldTopic, m.topic))
 	header.WriteString(fmt.Sprintf("%s:%d\r\n", headerStatusCode, m.statusCode))
 	header.WriteString(fmt.Sprintf("%s:%s\r\n", headerMsg, m.msg))
+	if m.errorCode != 0 {
+		header.WriteString(fmt.Sprintf("%s:%d\r\n", headerErrorCode, m.errorCode))
+	}
 	header.WriteByte('\n')
 	w.Write(header.Bytes())
 	w.Write(m.data)
@@ -0,0 +1,64 @@
+// Code generated by protoc-gen-go-p2p. DO NOT EDIT.
+// source: pb/payment.proto
+
+package p2p
+
+import (
+	context "context"
+	time "time"
+
+	pb "github.com/mysteriumnetwork/node/pb"
+	proto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// TopicPaymentInvoice is the topic of Payment.Invoice method.
+	TopicPaymentInvoice = "p2p-payment-invoice"
+	// TopicPaymentExchangeMessage is the topic of Payment.ExchangeMessage method.
+	TopicPaymentExchangeMessage = "p2p-payment-message"
+)
+
+var (
+	methodPaymentInvoice         = rpcMethod{name: "Payment.Invoice", topic: TopicPaymentInvoice, deadline: 10000 * time.Millisecond}
+	methodPaymentExchangeMessage = rpcMethod{name: "Payment.ExchangeMessage", topic: TopicPaymentExchangeMessage, deadline: 20000 * time.Millisecond}
+)
+
+// PaymentClient calls Payment service methods of the peer.
+type PaymentClient struct {
+	sender                ChannelSender
+	invoiceStream         *rpcStream
+	exchangeMessageStream *rpcStream
+}
+
+// NewPaymentClient creates client of the Payment service.
+func NewPaymentClient(sender ChannelSender) *PaymentClient {
+	return &PaymentClient{
+		sender:                sender,
+		invoiceStream:         newRPCStream(sender, methodPaymentInvoice),
+		exchangeMessageStream: newRPCStream(sender, methodPaymentExchangeMessage),
+	}
+}
+
+// Invoice sends message to the Payment.Invoice stream of the peer, messages are handled in the order they are sent.
+func (c *PaymentClient) Invoice(ctx context.Context, msg *pb.Invoice) error {
+	return c.invoiceStream.send(ctx, msg)
+}
+
+// ExchangeMessage sends message to the Payment.ExchangeMessage stream of the peer, messages are handled in the order they are sent.
+func (c *PaymentClient) ExchangeMessage(ctx context.Context, msg *pb.ExchangeMessage) error {
+	return c.exchangeMessageStream.send(ctx, msg)
+}
+
+// HandlePaymentInvoice registers handler of the Payment.Invoice method.
+func HandlePaymentInvoice(ch ChannelHandler, handler func(ctx context.Context, req *pb.Invoice) error) {
+	handleRPC(ch, methodPaymentInvoice, func() proto.Message { return new(pb.Invoice) }, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return nil, handler(ctx, req.(*pb.Invoice))
+	})
+}
+
+// HandlePaymentExchangeMessage registers handler of the Payment.ExchangeMessage method.
+func HandlePaymentExchangeMessage(ch ChannelHandler, handler func(ctx context.Context, req *pb.ExchangeMessage) error) {
+	handleRPC(ch, methodPaymentExchangeMessage, func() proto.Message { return new(pb.ExchangeMessage) }, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return nil, handler(ctx, req.(*pb.ExchangeMessage))
+	})
+}
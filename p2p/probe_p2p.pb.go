@@ -0,0 +1,83 @@
+// Code generated by protoc-gen-go-p2p. DO NOT EDIT.
+// source: pb/probe.proto
+
+package p2p
+
+import (
+	context "context"
+	time "time"
+
+	pb "github.com/mysteriumnetwork/node/pb"
+	proto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// TopicProbePing is the topic of Probe.Ping method.
+	TopicProbePing = "p2p-probe-ping"
+	// TopicProbeDownload is the topic of Probe.Download method.
+	TopicProbeDownload = "p2p-probe-download"
+	// TopicProbeUpload is the topic of Probe.Upload method.
+	TopicProbeUpload = "p2p-probe-upload"
+)
+
+var (
+	methodProbePing     = rpcMethod{name: "Probe.Ping", topic: TopicProbePing, deadline: 5000 * time.Millisecond}
+	methodProbeDownload = rpcMethod{name: "Probe.Download", topic: TopicProbeDownload, deadline: 30000 * time.Millisecond}
+	methodProbeUpload   = rpcMethod{name: "Probe.Upload", topic: TopicProbeUpload, deadline: 30000 * time.Millisecond}
+)
+
+// ProbeClient calls Probe service methods of the peer.
+type ProbeClient struct {
+	sender ChannelSender
+}
+
+// NewProbeClient creates client of the Probe service.
+func NewProbeClient(sender ChannelSender) *ProbeClient {
+	return &ProbeClient{
+		sender: sender,
+	}
+}
+
+// Ping calls Probe.Ping method of the peer.
+func (c *ProbeClient) Ping(ctx context.Context, req *pb.ProbePing) (*pb.ProbePing, error) {
+	res := new(pb.ProbePing)
+	if err := invoke(ctx, c.sender, methodProbePing, req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Download calls Probe.Download method of the peer.
+func (c *ProbeClient) Download(ctx context.Context, req *pb.ProbeDownloadRequest) (*pb.ProbeData, error) {
+	res := new(pb.ProbeData)
+	if err := invoke(ctx, c.sender, methodProbeDownload, req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Upload calls Probe.Upload method of the peer.
+func (c *ProbeClient) Upload(ctx context.Context, req *pb.ProbeData) error {
+	return invoke(ctx, c.sender, methodProbeUpload, req, nil)
+}
+
+// HandleProbePing registers handler of the Probe.Ping method.
+func HandleProbePing(ch ChannelHandler, handler func(ctx context.Context, req *pb.ProbePing) (*pb.ProbePing, error)) {
+	handleRPC(ch, methodProbePing, func() proto.Message { return new(pb.ProbePing) }, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return handler(ctx, req.(*pb.ProbePing))
+	})
+}
+
+// HandleProbeDownload registers handler of the Probe.Download method.
+func HandleProbeDownload(ch ChannelHandler, handler func(ctx context.Context, req *pb.ProbeDownloadRequest) (*pb.ProbeData, error)) {
+	handleRPC(ch, methodProbeDownload, func() proto.Message { return new(pb.ProbeDownloadRequest) }, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return handler(ctx, req.(*pb.ProbeDownloadRequest))
+	})
+}
+
+// HandleProbeUpload registers handler of the Probe.Upload method.
+func HandleProbeUpload(ch ChannelHandler, handler func(ctx context.Context, req *pb.ProbeData) error) {
+	handleRPC(ch, methodProbeUpload, func() proto.Message { return new(pb.ProbeData) }, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return nil, handler(ctx, req.(*pb.ProbeData))
+	})
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// protoc-gen-go-p2p generates typed p2p channel clients and handlers of the proto services.
+// Every method has to declare the topic it is served on with the (pb.topic) option,
+// methods returning google.protobuf.Empty are one-way, streaming methods deliver messages in order.
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+const (
+	contextPackage = protogen.GoImportPath("context")
+	timePackage    = protogen.GoImportPath("time")
+	protoPackage   = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	p2pPackage     = protogen.GoImportPath("github.com/mysteriumnetwork/node/p2p")
+
+	emptyMessage = "google.protobuf.Empty"
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		for _, file := range gen.Files {
+			if !file.Generate || len(file.Services) == 0 {
+				continue
+			}
+			if err := generateFile(gen, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type method struct {
+	*protogen.Method
+	service  *protogen.Service
+	topic    string
+	deadline time.Duration
+}
+
+func (m method) name() string {
+	return m.service.GoName + m.GoName
+}
+
+func (m method) fullName() string {
+	return m.service.GoName + "." + m.GoName
+}
+
+func (m method) isEmpty() bool {
+	return m.Output.Desc.FullName() == emptyMessage
+}
+
+func generateFile(gen *protogen.Plugin, file *protogen.File) error {
+	var methods []method
+	for _, service := range file.Services {
+		for _, m := range service.Methods {
+			options := m.Desc.Options()
+			rm := method{
+				Method:   m,
+				service:  service,
+				topic:    proto.GetExtension(options, pb.E_Topic).(string),
+				deadline: time.Duration(proto.GetExtension(options, pb.E_DeadlineMs).(uint32)) * time.Millisecond,
+			}
+			if rm.topic == "" {
+				return fmt.Errorf("%s: method has no topic option", rm.fullName())
+			}
+			if m.Desc.IsStreamingServer() {
+				return fmt.Errorf("%s: server streaming is not supported", rm.fullName())
+			}
+			if m.Desc.IsStreamingClient() && !rm.isEmpty() {
+				return fmt.Errorf("%s: streaming methods have to return %s", rm.fullName(), emptyMessage)
+			}
+			methods = append(methods, rm)
+		}
+	}
+
+	filename := path.Base(file.GeneratedFilenamePrefix) + "_p2p.pb.go"
+	g := gen.NewGeneratedFile(filename, p2pPackage)
+	g.P("// Code generated by protoc-gen-go-p2p. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package p2p")
+	g.P()
+
+	g.P("const (")
+	for _, m := range methods {
+		g.P("// Topic", m.name(), " is the topic of ", m.fullName(), " method.")
+		g.P("Topic", m.name(), " = ", fmt.Sprintf("%q", m.topic))
+	}
+	g.P(")")
+	g.P()
+	g.P("var (")
+	for _, m := range methods {
+		g.P("method", m.name(), " = rpcMethod{name: ", fmt.Sprintf("%q", m.fullName()), ", topic: Topic", m.name(),
+			", deadline: ", m.deadline.Milliseconds(), " * ", g.QualifiedGoIdent(timePackage.Ident("Millisecond")), "}")
+	}
+	g.P(")")
+
+	for _, service := range file.Services {
+		generateClient(g, service, methods)
+	}
+	for _, m := range methods {
+		generateHandler(g, m)
+	}
+	return nil
+}
+
+func generateClient(g *protogen.GeneratedFile, service *protogen.Service, methods []method) {
+	clientName := service.GoName + "Client"
+	var streams []method
+	for _, m := range methods {
+		if m.service == service && m.Desc.IsStreamingClient() {
+			streams = append(streams, m)
+		}
+	}
+
+	g.P()
+	g.P("// ", clientName, " calls ", service.GoName, " service methods of the peer.")
+	g.P("type ", clientName, " struct {")
+	g.P("sender ChannelSender")
+	for _, m := range streams {
+		g.P(streamField(m), " *rpcStream")
+	}
+	g.P("}")
+	g.P()
+	g.P("// New", clientName, " creates client of the ", service.GoName, " service.")
+	g.P("func New", clientName, "(sender ChannelSender) *", clientName, " {")
+	g.P("return &", clientName, "{")
+	g.P("sender: sender,")
+	for _, m := range streams {
+		g.P(streamField(m), ": newRPCStream(sender, method", m.name(), "),")
+	}
+	g.P("}")
+	g.P("}")
+
+	ctx := g.QualifiedGoIdent(contextPackage.Ident("Context"))
+	for _, m := range methods {
+		if m.service != service {
+			continue
+		}
+		input := g.QualifiedGoIdent(m.Input.GoIdent)
+		g.P()
+		switch {
+		case m.Desc.IsStreamingClient():
+			g.P("// ", m.GoName, " sends message to the ", m.fullName(), " stream of the peer, messages are handled in the order they are sent.")
+			g.P("func (c *", clientName, ") ", m.GoName, "(ctx ", ctx, ", msg *", input, ") error {")
+			g.P("return c.", streamField(m), ".send(ctx, msg)")
+			g.P("}")
+		case m.isEmpty():
+			g.P("// ", m.GoName, " calls ", m.fullName(), " method of the peer.")
+			g.P("func (c *", clientName, ") ", m.GoName, "(ctx ", ctx, ", req *", input, ") error {")
+			g.P("return invoke(ctx, c.sender, method", m.name(), ", req, nil)")
+			g.P("}")
+		default:
+			output := g.QualifiedGoIdent(m.Output.GoIdent)
+			g.P("// ", m.GoName, " calls ", m.fullName(), " method of the peer.")
+			g.P("func (c *", clientName, ") ", m.GoName, "(ctx ", ctx, ", req *", input, ") (*", output, ", error) {")
+			g.P("res := new(", output, ")")
+			g.P("if err := invoke(ctx, c.sender, method", m.name(), ", req, res); err != nil {")
+			g.P("return nil, err")
+			g.P("}")
+			g.P("return res, nil")
+			g.P("}")
+		}
+	}
+}
+
+func generateHandler(g *protogen.GeneratedFile, m method) {
+	ctx := g.QualifiedGoIdent(contextPackage.Ident("Context"))
+	message := g.QualifiedGoIdent(protoPackage.Ident("Message"))
+	input := g.QualifiedGoIdent(m.Input.GoIdent)
+
+	g.P()
+	g.P("// Handle", m.name(), " registers handler of the ", m.fullName(), " method.")
+	if m.isEmpty() {
+		g.P("func Handle", m.name(), "(ch ChannelHandler, handler func(ctx ", ctx, ", req *", input, ") error) {")
+	} else {
+		output := g.QualifiedGoIdent(m.Output.GoIdent)
+		g.P("func Handle", m.name(), "(ch ChannelHandler, handler func(ctx ", ctx, ", req *", input, ") (*", output, ", error)) {")
+	}
+	g.P("handleRPC(ch, method", m.name(), ", func() ", message, " { return new(", input, ") }, func(ctx ", ctx, ", req ", message, ") (", message, ", error) {")
+	if m.isEmpty() {
+		g.P("return nil, handler(ctx, req.(*", input, "))")
+	} else {
+		g.P("return handler(ctx, req.(*", input, "))")
+	}
+	g.P("})")
+	g.P("}")
+}
+
+func streamField(m method) string {
+	return strings.ToLower(m.GoName[:1]) + m.GoName[1:] + "Stream"
+}
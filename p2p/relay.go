@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"time"
+
+	nats_lib "github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/mysteriumnetwork/node/communication/nats"
+	"github.com/mysteriumnetwork/node/firewall"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p/relay"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+const (
+	// relayBindTimeout is how long peer waits for the other peer to bind to the relay.
+	relayBindTimeout = 20 * time.Second
+	// relayDecisionTimeout is how long peer waits for the other peer hole punching outcome.
+	// It has to cover the whole NAT pinger timeout of the other peer.
+	relayDecisionTimeout = 12 * time.Second
+)
+
+// dialRelay creates p2p channel and service connections which are relayed to the peer through given UDP relay.
+func dialRelay(ctx context.Context, relayAddr string, config *p2pConnectConfig, side relay.Side) (*net.UDPConn, *net.UDPConn, error) {
+	addr, err := net.ResolveUDPAddr("udp4", relayAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not resolve relay address: %w", err)
+	}
+	if _, err := firewall.AllowIPAccess(addr.IP.String()); err != nil {
+		return nil, nil, fmt.Errorf("could not add relay IP firewall rule: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, relayBindTimeout)
+	defer cancel()
+
+	var conns []*net.UDPConn
+	closeConns := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	for i := 0; i < requiredConnCount; i++ {
+		conn, err := net.DialUDP("udp4", nil, addr)
+		if err != nil {
+			closeConns()
+			return nil, nil, fmt.Errorf("could not create UDP conn to relay: %w", err)
+		}
+		conns = append(conns, conn)
+
+		if err := relay.Bind(ctx, conn, relayToken(config.privateKey, config.peerPubKey, i), side); err != nil {
+			closeConns()
+			return nil, nil, fmt.Errorf("could not bind to relay %s: %w", relayAddr, err)
+		}
+	}
+	return conns[0], conns[1], nil
+}
+
+// relayToken derives relayed session token of the n-th connection from the peers shared key,
+// so that both peers get the same token and nobody else can bind to their session.
+func relayToken(privateKey PrivateKey, peerPubKey PublicKey, n int) relay.Token {
+	var sharedKey [32]byte
+	box.Precompute(&sharedKey, (*[32]byte)(&peerPubKey), (*[32]byte)(&privateKey))
+
+	h := sha256.New()
+	h.Write([]byte("p2p relay"))
+	h.Write(sharedKey[:])
+	h.Write([]byte{byte(n)})
+
+	var token relay.Token
+	copy(token[:], h.Sum(nil))
+	return token
+}
+
+// relayDecision exchanges NAT hole punching outcome with the peer over the broker, so that both peers
+// fall back to the relay together instead of one binding to the relay while the other goes direct.
+type relayDecision struct {
+	brokerConn nats.Connection
+	subject    string
+	sub        *nats_lib.Subscription
+	peerRelay  chan bool
+	config     *p2pConnectConfig
+}
+
+// newRelayDecision subscribes to the peer outcome, it has to be called before hole punching starts.
+func newRelayDecision(brokerConn nats.Connection, providerID identity.Identity, serviceType string, config *p2pConnectConfig) (*relayDecision, error) {
+	d := &relayDecision{
+		brokerConn: brokerConn,
+		subject:    relayDecisionSubject(providerID, serviceType, config.peerPubKey),
+		peerRelay:  make(chan bool, 1),
+		config:     config,
+	}
+
+	sub, err := brokerConn.Subscribe(relayDecisionSubject(providerID, serviceType, config.publicKey), func(msg *nats_lib.Msg) {
+		peerConfig, err := decryptConnConfigMsg(msg.Data, config.privateKey, config.peerPubKey)
+		if err != nil {
+			log.Err(err).Msg("Could not decrypt peer relay decision")
+			return
+		}
+		select {
+		case d.peerRelay <- peerConfig.Relay:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not subscribe to relay decision: %w", err)
+	}
+	d.sub = sub
+	return d, nil
+}
+
+// relay sends local hole punching outcome to the peer and tells whether peers should fall back to the relay.
+// Peers not sending their outcome are assumed to decide on their own, as older peers do.
+func (d *relayDecision) relay(ctx context.Context, punchErr error) bool {
+	localRelay := punchErr != nil
+	ciphertext, err := encryptConnConfigMsg(&pb.P2PConnectConfig{Relay: localRelay}, d.config.privateKey, d.config.peerPubKey)
+	if err != nil {
+		log.Err(err).Msg("Could not encrypt relay decision")
+		return localRelay
+	}
+	if err := d.brokerConn.Publish(d.subject, ciphertext); err != nil {
+		log.Err(err).Msg("Could not publish relay decision")
+		return localRelay
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, relayDecisionTimeout)
+	defer cancel()
+	select {
+	case peerRelay := <-d.peerRelay:
+		if peerRelay && !localRelay {
+			log.Warn().Msg("Peer failed NAT hole punching, falling back to relay")
+		}
+		return localRelay || peerRelay
+	case <-ctx.Done():
+		log.Warn().Msg("Peer did not send relay decision")
+		return localRelay
+	}
+}
+
+// close unsubscribes from the peer outcome.
+func (d *relayDecision) close() {
+	if err := d.sub.Unsubscribe(); err != nil {
+		log.Err(err).Msg("Failed to unsubscribe from relay decision")
+	}
+}
+
+func closeUDPConns(conns ...*net.UDPConn) {
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package relay implements UDP relay which forwards traffic between two peers
+// which failed to establish direct connection using NAT hole punching.
+//
+// Both peers bind their UDP connection to the relay using the same session token,
+// once both of them are bound relay forwards all the other packets to the opposite peer as is.
+package relay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Token identifies relayed session, both peers have to bind with the same token.
+type Token [32]byte
+
+// Side identifies peer of the relayed session.
+type Side byte
+
+const (
+	// SideDialer is the peer which initiates the connection.
+	SideDialer Side = 0
+	// SideListener is the peer which accepts the connection.
+	SideListener Side = 1
+)
+
+func (s Side) opposite() Side {
+	return 1 - s
+}
+
+const (
+	packetBind  byte = 1
+	packetBound byte = 2
+
+	bindRetryInterval = 250 * time.Millisecond
+)
+
+// magic prefixes relay control packets. It can't be confused with WireGuard packets,
+// which start with message type followed by zero bytes.
+var magic = []byte{0xff, 'm', 'r', 'l'}
+
+var packetLen = len(magic) + 2 + len(Token{})
+
+func newPacket(packetType byte, token Token, side Side) []byte {
+	packet := make([]byte, 0, packetLen)
+	packet = append(packet, magic...)
+	packet = append(packet, packetType, byte(side))
+	return append(packet, token[:]...)
+}
+
+func parsePacket(packet []byte) (packetType byte, token Token, side Side, ok bool) {
+	if len(packet) != packetLen || !bytes.HasPrefix(packet, magic) {
+		return 0, token, 0, false
+	}
+	packetType, side = packet[len(magic)], Side(packet[len(magic)+1])
+	if side != SideDialer && side != SideListener {
+		return 0, token, 0, false
+	}
+	copy(token[:], packet[len(magic)+2:])
+	return packetType, token, side, true
+}
+
+// Bind binds given UDP connection, which has to be connected to the relay, as the given side
+// of the relayed session. It blocks until the opposite peer binds too or context is done.
+func Bind(ctx context.Context, conn *net.UDPConn, token Token, side Side) error {
+	request := newPacket(packetBind, token, side)
+	buf := make([]byte, packetLen+1)
+	for {
+		deadline := time.Now().Add(bindRetryInterval)
+		// Send and read errors are not fatal, since relay might be not reachable yet.
+		if _, err := conn.Write(request); err == nil {
+			if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+				deadline = ctxDeadline
+			}
+			if err := conn.SetReadDeadline(deadline); err != nil {
+				return fmt.Errorf("could not set read deadline: %w", err)
+			}
+			if waitBound(conn, buf, token, side) {
+				return conn.SetReadDeadline(time.Time{})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("peer did not bind to the relay: %w", ctx.Err())
+		case <-time.After(time.Until(deadline)):
+		}
+	}
+}
+
+// waitBound reads packets until bound reply is received or read fails.
+func waitBound(conn *net.UDPConn, buf []byte, token Token, side Side) bool {
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return false
+		}
+		packetType, replyToken, replySide, ok := parsePacket(buf[:n])
+		if ok && packetType == packetBound && replyToken == token && replySide == side {
+			return true
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultIdleTimeout is the time after which inactive relayed session is forgotten.
+	DefaultIdleTimeout = 2 * time.Minute
+	// DefaultMaxSessions is the maximum number of concurrently relayed sessions.
+	DefaultMaxSessions = 1000
+
+	maxPacketSize = 65535
+)
+
+// session holds addresses of both peers of the relayed session.
+type session struct {
+	peers    [2]*net.UDPAddr
+	lastSeen time.Time
+}
+
+type peerRef struct {
+	token Token
+	side  Side
+}
+
+// Server is UDP relay server.
+type Server struct {
+	addr        string
+	idleTimeout time.Duration
+	maxSessions int
+
+	conn *net.UDPConn
+
+	mu       sync.Mutex
+	sessions map[Token]*session
+	peers    map[string]peerRef
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServer creates new UDP relay server which will listen on the given address.
+func NewServer(addr string, idleTimeout time.Duration, maxSessions int) *Server {
+	return &Server{
+		addr:        addr,
+		idleTimeout: idleTimeout,
+		maxSessions: maxSessions,
+		sessions:    make(map[Token]*session),
+		peers:       make(map[string]peerRef),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start starts listening for the peers.
+func (s *Server) Start() error {
+	addr, err := net.ResolveUDPAddr("udp4", s.addr)
+	if err != nil {
+		return fmt.Errorf("could not resolve relay address: %w", err)
+	}
+	s.conn, err = net.ListenUDP("udp4", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen UDP: %w", err)
+	}
+	log.Info().Msgf("UDP relay listening on %s", s.conn.LocalAddr())
+
+	go s.serve()
+	go s.expireSessions()
+	return nil
+}
+
+// Addr returns address the relay is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Stop stops the relay.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		if s.conn != nil {
+			s.conn.Close()
+		}
+	})
+}
+
+func (s *Server) serve() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-s.stop:
+				return
+			default:
+			}
+			log.Warn().Err(err).Msg("Relay failed to read packet")
+			continue
+		}
+
+		packetType, token, side, ok := parsePacket(buf[:n])
+		if ok && packetType == packetBind {
+			s.bind(token, side, addr)
+			continue
+		}
+		s.forward(buf[:n], addr)
+	}
+}
+
+func (s *Server) bind(token Token, side Side, addr *net.UDPAddr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok {
+		if len(s.sessions) >= s.maxSessions {
+			log.Warn().Msgf("Relay sessions limit %d reached, ignoring bind from %s", s.maxSessions, addr)
+			return
+		}
+		sess = &session{}
+		s.sessions[token] = sess
+	}
+	sess.lastSeen = time.Now()
+
+	rebound := sess.peers[side] == nil || sess.peers[side].String() != addr.String()
+	if rebound {
+		if sess.peers[side] != nil {
+			delete(s.peers, sess.peers[side].String())
+		}
+		sess.peers[side] = addr
+		s.peers[addr.String()] = peerRef{token: token, side: side}
+	}
+
+	peer := sess.peers[side.opposite()]
+	if peer == nil {
+		return
+	}
+	s.write(newPacket(packetBound, token, side), addr)
+	if rebound {
+		s.write(newPacket(packetBound, token, side.opposite()), peer)
+	}
+}
+
+func (s *Server) forward(packet []byte, addr *net.UDPAddr) {
+	s.mu.Lock()
+	ref, ok := s.peers[addr.String()]
+	var peer *net.UDPAddr
+	if ok {
+		sess := s.sessions[ref.token]
+		sess.lastSeen = time.Now()
+		peer = sess.peers[ref.side.opposite()]
+	}
+	s.mu.Unlock()
+
+	if peer == nil {
+		return
+	}
+	s.write(packet, peer)
+}
+
+func (s *Server) write(packet []byte, addr *net.UDPAddr) {
+	if _, err := s.conn.WriteToUDP(packet, addr); err != nil {
+		log.Warn().Err(err).Msgf("Relay failed to write packet to %s", addr)
+	}
+}
+
+func (s *Server) expireSessions() {
+	ticker := time.NewTicker(s.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			for token, sess := range s.sessions {
+				if time.Since(sess.lastSeen) < s.idleTimeout {
+					continue
+				}
+				for _, peer := range sess.peers {
+					if peer != nil {
+						delete(s.peers, peer.String())
+					}
+				}
+				delete(s.sessions, token)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
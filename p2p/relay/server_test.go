@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestServer(t *testing.T, maxSessions int) *Server {
+	server := NewServer("127.0.0.1:0", DefaultIdleTimeout, maxSessions)
+	require.NoError(t, server.Start())
+	return server
+}
+
+func dialTestRelay(t *testing.T, server *Server) *net.UDPConn {
+	conn, err := net.DialUDP("udp4", nil, server.Addr().(*net.UDPAddr))
+	require.NoError(t, err)
+	return conn
+}
+
+func bindAsync(conn *net.UDPConn, token Token, side Side, timeout time.Duration) chan error {
+	result := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		result <- Bind(ctx, conn, token, side)
+	}()
+	return result
+}
+
+func TestServer_RelaysTrafficBetweenBoundPeers(t *testing.T) {
+	server := startTestServer(t, DefaultMaxSessions)
+	defer server.Stop()
+
+	dialer := dialTestRelay(t, server)
+	defer dialer.Close()
+	listener := dialTestRelay(t, server)
+	defer listener.Close()
+
+	token := Token{1}
+	dialerBound := bindAsync(dialer, token, SideDialer, 5*time.Second)
+	time.Sleep(100 * time.Millisecond)
+	listenerBound := bindAsync(listener, token, SideListener, 5*time.Second)
+	assert.NoError(t, <-dialerBound)
+	assert.NoError(t, <-listenerBound)
+
+	buf := make([]byte, 100)
+	_, err := dialer.Write([]byte("ping"))
+	assert.NoError(t, err)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := listener.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping", string(buf[:n]))
+
+	_, err = listener.Write([]byte("pong"))
+	assert.NoError(t, err)
+	dialer.SetReadDeadline(time.Now().Add(time.Second))
+	n, err = dialer.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "pong", string(buf[:n]))
+}
+
+func TestServer_DoesNotRelayBetweenDifferentSessions(t *testing.T) {
+	server := startTestServer(t, DefaultMaxSessions)
+	defer server.Stop()
+
+	dialer := dialTestRelay(t, server)
+	defer dialer.Close()
+	listener := dialTestRelay(t, server)
+	defer listener.Close()
+
+	dialerBound := bindAsync(dialer, Token{1}, SideDialer, 500*time.Millisecond)
+	listenerBound := bindAsync(listener, Token{2}, SideListener, 500*time.Millisecond)
+	assert.Error(t, <-dialerBound)
+	assert.Error(t, <-listenerBound)
+
+	_, err := dialer.Write([]byte("ping"))
+	assert.NoError(t, err)
+	listener.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, err = listener.Read(make([]byte, 100))
+	assert.Error(t, err)
+}
+
+func TestServer_LimitsSessions(t *testing.T) {
+	server := startTestServer(t, 1)
+	defer server.Stop()
+
+	first := dialTestRelay(t, server)
+	defer first.Close()
+	second := dialTestRelay(t, server)
+	defer second.Close()
+
+	bindAsync(first, Token{1}, SideDialer, 300*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Error(t, <-bindAsync(second, Token{2}, SideDialer, 300*time.Millisecond))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Len(t, server.sessions, 1)
+	_, ok := server.sessions[Token{1}]
+	assert.True(t, ok)
+}
+
+func TestBind_FailsWhenRelayIsUnreachable(t *testing.T) {
+	server := startTestServer(t, DefaultMaxSessions)
+	addr := server.Addr().(*net.UDPAddr)
+	server.Stop()
+
+	conn, err := net.DialUDP("udp4", nil, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+	defer cancel()
+	err = Bind(ctx, conn, Token{1}, SideDialer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "peer did not bind to the relay")
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// Error is an error of the RPC method with structured code, it is sent to the peer as is.
+type Error struct {
+	Code    pb.ErrorCode
+	Message string
+}
+
+// Error returns error message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf creates RPC error with the given code.
+func Errorf(code pb.ErrorCode, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ErrorCode returns structured code of the RPC error, errors without code are UNKNOWN.
+func ErrorCode(err error) pb.ErrorCode {
+	var rpcErr *Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr.Code
+	case errors.Is(err, ErrSendTimeout), errors.Is(err, context.DeadlineExceeded):
+		return pb.ErrorCode_DEADLINE_EXCEEDED
+	case errors.Is(err, ErrHandlerNotFound):
+		return pb.ErrorCode_UNIMPLEMENTED
+	default:
+		return pb.ErrorCode_UNKNOWN
+	}
+}
+
+// maxLoggedMessageSize limits size of the messages logged with their content, bulk payloads are logged by size only.
+const maxLoggedMessageSize = 1024
+
+func logMessage(format string, topic string, msg proto.Message) {
+	if size := proto.Size(msg); size > maxLoggedMessageSize {
+		log.Debug().Msgf(format, topic, fmt.Sprintf("%d bytes", size))
+		return
+	}
+	log.Debug().Msgf(format, topic, msg)
+}
+
+// rpcMethod describes method of the generated service stubs.
+type rpcMethod struct {
+	name     string
+	topic    string
+	deadline time.Duration
+}
+
+func (m rpcMethod) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, m.deadline)
+}
+
+// invoke sends request to the method topic and unmarshals peer reply into response, nil response ignores the reply.
+func invoke(ctx context.Context, sender ChannelSender, method rpcMethod, req, res proto.Message) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not marshal %s request: %w", method.name, err)
+	}
+	logMessage("Sending P2P message to %q: %v", method.topic, req)
+
+	ctx, cancel := method.withDeadline(ctx)
+	defer cancel()
+	reply, err := sender.Send(ctx, method.topic, &Message{Data: data})
+	if err != nil {
+		return fmt.Errorf("could not call %s: %w", method.name, err)
+	}
+
+	if res == nil || reply == nil {
+		return nil
+	}
+	if err := proto.Unmarshal(reply.Data, res); err != nil {
+		return fmt.Errorf("could not unmarshal %s reply: %w", method.name, err)
+	}
+	return nil
+}
+
+// rpcStream sends messages of the streaming method one by one, the next message is sent
+// only once the peer handled the previous one, so that peer receives them in order.
+type rpcStream struct {
+	sender ChannelSender
+	method rpcMethod
+	lock   sync.Mutex
+}
+
+func newRPCStream(sender ChannelSender, method rpcMethod) *rpcStream {
+	return &rpcStream{sender: sender, method: method}
+}
+
+func (s *rpcStream) send(ctx context.Context, msg proto.Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return invoke(ctx, s.sender, s.method, msg, nil)
+}
+
+// handleRPC registers handler of the method topic. RPC errors returned by the handler are sent to the peer
+// with their codes, other errors are reported to the peer as internal ones.
+func handleRPC(ch ChannelHandler, method rpcMethod, newRequest func() proto.Message, handler func(ctx context.Context, req proto.Message) (proto.Message, error)) {
+	ch.Handle(method.topic, func(c Context) error {
+		req := newRequest()
+		if err := proto.Unmarshal(c.Request().Data, req); err != nil {
+			return c.Error(Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal %s request: %v", method.name, err))
+		}
+		logMessage("Received P2P message for %q: %v", method.topic, req)
+
+		ctx, cancel := method.withDeadline(context.Background())
+		defer cancel()
+		res, err := handler(ctx, req)
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return c.Error(err)
+		}
+		if err != nil {
+			return err
+		}
+
+		if res == nil {
+			return c.OK()
+		}
+		data, err := proto.Marshal(res)
+		if err != nil {
+			return fmt.Errorf("could not marshal %s reply: %w", method.name, err)
+		}
+		return c.OkWithReply(&Message{Data: data})
+	})
+}
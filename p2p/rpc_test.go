@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+func TestRPC(t *testing.T) {
+	provider, consumer, err := createTestChannels()
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	t.Run("Test typed request reply", func(t *testing.T) {
+		HandleSessionCreate(provider, func(_ context.Context, req *pb.SessionRequest) (*pb.SessionResponse, error) {
+			return &pb.SessionResponse{ID: fmt.Sprintf("session-%d", req.ProposalID)}, nil
+		})
+
+		res, err := NewSessionClient(consumer).Create(context.Background(), &pb.SessionRequest{ProposalID: 42})
+		assert.NoError(t, err)
+		assert.Equal(t, "session-42", res.ID)
+	})
+
+	t.Run("Test structured error is returned to peer", func(t *testing.T) {
+		HandleSessionAcknowledge(provider, func(_ context.Context, req *pb.SessionInfo) error {
+			return Errorf(pb.ErrorCode_NOT_FOUND, "session %s not found", req.SessionID)
+		})
+
+		err := NewSessionClient(consumer).Acknowledge(context.Background(), &pb.SessionInfo{SessionID: "unknown"})
+		assert.EqualError(t, err, "could not call Session.Acknowledge: public peer error: session unknown not found")
+		assert.Equal(t, pb.ErrorCode_NOT_FOUND, ErrorCode(err))
+	})
+
+	t.Run("Test internal error is returned to peer", func(t *testing.T) {
+		HandleSessionDestroy(provider, func(_ context.Context, req *pb.SessionInfo) error {
+			return errors.New("database is down")
+		})
+
+		err := NewSessionClient(consumer).Destroy(context.Background(), &pb.SessionInfo{})
+		assert.EqualError(t, err, "could not call Session.Destroy: peer error: database is down")
+		assert.Equal(t, pb.ErrorCode_INTERNAL, ErrorCode(err))
+	})
+
+	t.Run("Test missing handler is unimplemented", func(t *testing.T) {
+		err := NewSessionClient(consumer).Status(context.Background(), &pb.SessionStatus{})
+		assert.Equal(t, pb.ErrorCode_UNIMPLEMENTED, ErrorCode(err))
+	})
+
+	t.Run("Test malformed request is invalid argument", func(t *testing.T) {
+		HandleKeepAlivePing(provider, func(_ context.Context, _ *pb.P2PKeepAlivePing) error {
+			return nil
+		})
+
+		_, err := consumer.Send(context.Background(), TopicKeepAlivePing, &Message{Data: []byte("not a proto")})
+		assert.Equal(t, pb.ErrorCode_INVALID_ARGUMENT, ErrorCode(err))
+	})
+
+	t.Run("Test stream messages are handled one by one", func(t *testing.T) {
+		var lock sync.Mutex
+		var inFlight, maxInFlight, received int
+		HandlePaymentInvoice(provider, func(_ context.Context, _ *pb.Invoice) error {
+			lock.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			lock.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			lock.Lock()
+			inFlight--
+			received++
+			lock.Unlock()
+			return nil
+		})
+
+		client := NewPaymentClient(consumer)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, client.Invoice(context.Background(), &pb.Invoice{}))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, received)
+		assert.Equal(t, 1, maxInFlight)
+	})
+}
+
+func TestRPC_Deadline(t *testing.T) {
+	provider, consumer, err := createTestChannels()
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	handlerDeadline := make(chan time.Duration, 1)
+	HandleSessionDestroy(provider, func(ctx context.Context, _ *pb.SessionInfo) error {
+		deadline, _ := ctx.Deadline()
+		handlerDeadline <- time.Until(deadline)
+		time.Sleep(time.Hour)
+		return nil
+	})
+
+	start := time.Now()
+	err = NewSessionClient(consumer).Destroy(context.Background(), &pb.SessionInfo{})
+	assert.Equal(t, pb.ErrorCode_DEADLINE_EXCEEDED, ErrorCode(err))
+	assert.True(t, errors.Is(err, ErrSendTimeout))
+	assert.InDelta(t, methodSessionDestroy.deadline, time.Since(start), float64(500*time.Millisecond))
+	assert.InDelta(t, methodSessionDestroy.deadline, <-handlerDeadline, float64(500*time.Millisecond))
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, pb.ErrorCode_NOT_FOUND, ErrorCode(fmt.Errorf("wrapped: %w", Errorf(pb.ErrorCode_NOT_FOUND, "not found"))))
+	assert.Equal(t, pb.ErrorCode_DEADLINE_EXCEEDED, ErrorCode(fmt.Errorf("wrapped: %w", ErrSendTimeout)))
+	assert.Equal(t, pb.ErrorCode_UNIMPLEMENTED, ErrorCode(ErrHandlerNotFound))
+	assert.Equal(t, pb.ErrorCode_UNKNOWN, ErrorCode(errors.New("unknown")))
+}
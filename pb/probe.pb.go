@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.11.2
+// source: pb/probe.proto
+
+package pb
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// ProbePing is echoed back by the peer to measure round trip time of the p2p channel.
+type ProbePing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq uint32 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (x *ProbePing) Reset() {
+	*x = ProbePing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_probe_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbePing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbePing) ProtoMessage() {}
+
+func (x *ProbePing) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_probe_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbePing.ProtoReflect.Descriptor instead.
+func (*ProbePing) Descriptor() ([]byte, []int) {
+	return file_pb_probe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProbePing) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// ProbeDownloadRequest asks the peer to send back the given amount of bytes.
+type ProbeDownloadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size uint32 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *ProbeDownloadRequest) Reset() {
+	*x = ProbeDownloadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_probe_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeDownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeDownloadRequest) ProtoMessage() {}
+
+func (x *ProbeDownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_probe_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeDownloadRequest.ProtoReflect.Descriptor instead.
+func (*ProbeDownloadRequest) Descriptor() ([]byte, []int) {
+	return file_pb_probe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProbeDownloadRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// ProbeData carries payload of the throughput test.
+type ProbeData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ProbeData) Reset() {
+	*x = ProbeData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_probe_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeData) ProtoMessage() {}
+
+func (x *ProbeData) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_probe_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeData.ProtoReflect.Descriptor instead.
+func (*ProbeData) Descriptor() ([]byte, []int) {
+	return file_pb_probe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProbeData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_pb_probe_proto protoreflect.FileDescriptor
+
+var file_pb_probe_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x70, 0x62, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x02, 0x70, 0x62, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x0c, 0x70, 0x62, 0x2f, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x1d, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03,
+	0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0x2a,
+	0x0a, 0x14, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x1f, 0x0a, 0x09, 0x50, 0x72,
+	0x6f, 0x62, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0xe6, 0x01, 0x0a, 0x05,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x3d, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x0d, 0x2e,
+	0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x50, 0x69, 0x6e, 0x67, 0x1a, 0x0d, 0x2e, 0x70,
+	0x62, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x50, 0x69, 0x6e, 0x67, 0x22, 0x17, 0xc2, 0xf3, 0x18,
+	0x0e, 0x70, 0x32, 0x70, 0x2d, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x2d, 0x70, 0x69, 0x6e, 0x67, 0xc8,
+	0xf3, 0x18, 0x88, 0x27, 0x12, 0x51, 0x0a, 0x08, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x62, 0x2e,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x61, 0x74, 0x61, 0x22, 0x1c, 0xc2, 0xf3, 0x18, 0x12, 0x70,
+	0x32, 0x70, 0x2d, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x2d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0xc8, 0xf3, 0x18, 0xb0, 0xea, 0x01, 0x12, 0x4b, 0x0a, 0x06, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x12, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x1a, 0xc2, 0xf3, 0x18, 0x10, 0x70, 0x32,
+	0x70, 0x2d, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x2d, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0xc8, 0xf3,
+	0x18, 0xb0, 0xea, 0x01, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_probe_proto_rawDescOnce sync.Once
+	file_pb_probe_proto_rawDescData = file_pb_probe_proto_rawDesc
+)
+
+func file_pb_probe_proto_rawDescGZIP() []byte {
+	file_pb_probe_proto_rawDescOnce.Do(func() {
+		file_pb_probe_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_probe_proto_rawDescData)
+	})
+	return file_pb_probe_proto_rawDescData
+}
+
+var file_pb_probe_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pb_probe_proto_goTypes = []interface{}{
+	(*ProbePing)(nil),            // 0: pb.ProbePing
+	(*ProbeDownloadRequest)(nil), // 1: pb.ProbeDownloadRequest
+	(*ProbeData)(nil),            // 2: pb.ProbeData
+	(*empty.Empty)(nil),          // 3: google.protobuf.Empty
+}
+var file_pb_probe_proto_depIdxs = []int32{
+	0, // 0: pb.Probe.Ping:input_type -> pb.ProbePing
+	1, // 1: pb.Probe.Download:input_type -> pb.ProbeDownloadRequest
+	2, // 2: pb.Probe.Upload:input_type -> pb.ProbeData
+	0, // 3: pb.Probe.Ping:output_type -> pb.ProbePing
+	2, // 4: pb.Probe.Download:output_type -> pb.ProbeData
+	3, // 5: pb.Probe.Upload:output_type -> google.protobuf.Empty
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pb_probe_proto_init() }
+func file_pb_probe_proto_init() {
+	if File_pb_probe_proto != nil {
+		return
+	}
+	file_pb_rpc_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_pb_probe_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbePing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_probe_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeDownloadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_probe_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pb_probe_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pb_probe_proto_goTypes,
+		DependencyIndexes: file_pb_probe_proto_depIdxs,
+		MessageInfos:      file_pb_probe_proto_msgTypes,
+	}.Build()
+	File_pb_probe_proto = out.File
+	file_pb_probe_proto_rawDesc = nil
+	file_pb_probe_proto_goTypes = nil
+	file_pb_probe_proto_depIdxs = nil
+}
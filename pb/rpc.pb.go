@@ -0,0 +1,200 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.11.2
+// source: pb/rpc.proto
+
+package pb
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	proto "github.com/golang/protobuf/proto"
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// ErrorCode is structured code of the error returned by p2p RPC handler.
+type ErrorCode int32
+
+const (
+	ErrorCode_UNKNOWN           ErrorCode = 0
+	ErrorCode_INVALID_ARGUMENT  ErrorCode = 1
+	ErrorCode_NOT_FOUND         ErrorCode = 2
+	ErrorCode_PERMISSION_DENIED ErrorCode = 3
+	ErrorCode_UNAVAILABLE       ErrorCode = 4
+	ErrorCode_DEADLINE_EXCEEDED ErrorCode = 5
+	ErrorCode_INTERNAL          ErrorCode = 6
+	ErrorCode_UNIMPLEMENTED     ErrorCode = 7
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "INVALID_ARGUMENT",
+		2: "NOT_FOUND",
+		3: "PERMISSION_DENIED",
+		4: "UNAVAILABLE",
+		5: "DEADLINE_EXCEEDED",
+		6: "INTERNAL",
+		7: "UNIMPLEMENTED",
+	}
+	ErrorCode_value = map[string]int32{
+		"UNKNOWN":           0,
+		"INVALID_ARGUMENT":  1,
+		"NOT_FOUND":         2,
+		"PERMISSION_DENIED": 3,
+		"UNAVAILABLE":       4,
+		"DEADLINE_EXCEEDED": 5,
+		"INTERNAL":          6,
+		"UNIMPLEMENTED":     7,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_rpc_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_pb_rpc_proto_enumTypes[0]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_pb_rpc_proto_rawDescGZIP(), []int{0}
+}
+
+var file_pb_rpc_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptor.MethodOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         51000,
+		Name:          "pb.topic",
+		Tag:           "bytes,51000,opt,name=topic",
+		Filename:      "pb/rpc.proto",
+	},
+	{
+		ExtendedType:  (*descriptor.MethodOptions)(nil),
+		ExtensionType: (*uint32)(nil),
+		Field:         51001,
+		Name:          "pb.deadline_ms",
+		Tag:           "varint,51001,opt,name=deadline_ms",
+		Filename:      "pb/rpc.proto",
+	},
+}
+
+// Extension fields to descriptor.MethodOptions.
+var (
+	// optional string topic = 51000;
+	E_Topic = &file_pb_rpc_proto_extTypes[0] // p2p channel topic the method is served on.
+	// optional uint32 deadline_ms = 51001;
+	E_DeadlineMs = &file_pb_rpc_proto_extTypes[1] // Maximum time to wait for the method reply.
+)
+
+var File_pb_rpc_proto protoreflect.FileDescriptor
+
+var file_pb_rpc_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x70, 0x62, 0x2f, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2a, 0x9d, 0x01, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x14, 0x0a, 0x10, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x41, 0x52, 0x47, 0x55, 0x4d,
+	0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55,
+	0x4e, 0x44, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x55,
+	0x4e, 0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11,
+	0x44, 0x45, 0x41, 0x44, 0x4c, 0x49, 0x4e, 0x45, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45,
+	0x44, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10,
+	0x06, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x4e, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x4d, 0x45, 0x4e, 0x54,
+	0x45, 0x44, 0x10, 0x07, 0x3a, 0x36, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x1e, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x8e,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x3a, 0x41, 0x0a, 0x0b,
+	0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x6d, 0x73, 0x12, 0x1e, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb9, 0x8e, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x4d, 0x73, 0x42,
+	0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_rpc_proto_rawDescOnce sync.Once
+	file_pb_rpc_proto_rawDescData = file_pb_rpc_proto_rawDesc
+)
+
+func file_pb_rpc_proto_rawDescGZIP() []byte {
+	file_pb_rpc_proto_rawDescOnce.Do(func() {
+		file_pb_rpc_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_rpc_proto_rawDescData)
+	})
+	return file_pb_rpc_proto_rawDescData
+}
+
+var file_pb_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pb_rpc_proto_goTypes = []interface{}{
+	(ErrorCode)(0),                   // 0: pb.ErrorCode
+	(*descriptor.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+}
+var file_pb_rpc_proto_depIdxs = []int32{
+	1, // 0: pb.topic:extendee -> google.protobuf.MethodOptions
+	1, // 1: pb.deadline_ms:extendee -> google.protobuf.MethodOptions
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pb_rpc_proto_init() }
+func file_pb_rpc_proto_init() {
+	if File_pb_rpc_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pb_rpc_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_pb_rpc_proto_goTypes,
+		DependencyIndexes: file_pb_rpc_proto_depIdxs,
+		EnumInfos:         file_pb_rpc_proto_enumTypes,
+		ExtensionInfos:    file_pb_rpc_proto_extTypes,
+	}.Build()
+	File_pb_rpc_proto = out.File
+	file_pb_rpc_proto_rawDesc = nil
+	file_pb_rpc_proto_goTypes = nil
+	file_pb_rpc_proto_depIdxs = nil
+}
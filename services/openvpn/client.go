@@ -112,6 +112,16 @@ func (c *Client) Statistics() (connectionstate.Statistics, error) {
 func (c *Client) Start(ctx context.Context, options connection.ConnectOptions) error {
 	log.Info().Msg("Starting connection")
 
+	if options.Chained {
+		return connection.ErrChainingNotSupported
+	}
+	if options.Params.SplitTunnel.Enabled() {
+		return connection.ErrSplitTunnelNotSupported
+	}
+	if options.Params.Detached {
+		return connection.ErrDetachingNotSupported
+	}
+
 	sessionConfig := VPNConfig{}
 	err := json.Unmarshal(options.SessionConfig, &sessionConfig)
 	if err != nil {
@@ -100,10 +100,13 @@ func NewClientConfigFromSession(vpnConfig VPNConfig, scriptDir string, runtimeDi
 	}
 
 	var remotePort, localPort int
-	if options.ProviderNATConn != nil && vpnConfig.RemoteIP != "127.0.0.1" {
+	if options.ProviderNATConn != nil && (vpnConfig.RemoteIP != "127.0.0.1" || options.Relayed) {
 		options.ProviderNATConn.Close()
 		remotePort = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).Port
 		localPort = options.ProviderNATConn.LocalAddr().(*net.UDPAddr).Port
+		if options.Relayed {
+			vpnConfig.RemoteIP = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).IP.String()
+		}
 	} else {
 		remotePort = vpnConfig.RemotePort
 		localPort = vpnConfig.LocalPort
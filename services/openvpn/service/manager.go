@@ -87,10 +87,12 @@ func (m *Manager) Serve(instance *service.Instance) (err error) {
 	}
 
 	var dnsPort = 11153
-	dnsHandler, err := dns.ResolveViaSystem()
+	dnsHandler, err := dns.ResolveViaUpstreams(m.serviceOptions.DNSUpstreams)
 	if err == nil {
 		if instance.Policies().HasDNSRules() {
 			dnsHandler = dns.WhitelistAnswers(dnsHandler, m.trafficFirewall, instance.Policies())
+		}
+		if instance.Policies().HasDNSAllowRules() {
 			removeRule, err := m.trafficFirewall.BlockIncomingTraffic(m.vpnNetwork)
 			if err != nil {
 				return fmt.Errorf("failed to enable traffic blocking: %w", err)
@@ -113,6 +115,18 @@ func (m *Manager) Serve(instance *service.Instance) (err error) {
 		log.Warn().Err(err).Msg("Provider DNS will not be available")
 	}
 
+	if instance.Policies().HasTrafficRules() {
+		removeRule, err := m.trafficFirewall.RestrictIncomingTraffic(m.vpnNetwork, instance.Policies().TrafficRules())
+		if err != nil {
+			return fmt.Errorf("failed to enable traffic restriction: %w", err)
+		}
+		defer func() {
+			if err := removeRule(); err != nil {
+				log.Warn().Err(err).Msg("failed to disable traffic restriction")
+			}
+		}()
+	}
+
 	servicePort, err := m.ports.Acquire()
 	if err != nil {
 		return fmt.Errorf("failed to acquire an unused port: %w", err)
@@ -153,7 +167,7 @@ func (m *Manager) Serve(instance *service.Instance) (err error) {
 		return fmt.Errorf("failed to setup NAT/firewall rules: %w", err)
 	}
 
-	s := shaper.New(m.bus)
+	s := shaper.New(m.bus, m.serviceOptions.Shaper)
 	err = s.Start(m.openvpnProcess.DeviceName())
 	if err != nil {
 		log.Error().Err(err).Msg("Could not start traffic shaper")
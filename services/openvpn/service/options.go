@@ -22,6 +22,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,15 +32,21 @@ type Options struct {
 	Port     int    `json:"port"`
 	Subnet   string `json:"subnet"`
 	Netmask  string `json:"netmask"`
+	// Shaper limits bandwidth of the service, OpenVPN doesn't report consumer's tunnel IP so session limits are not applied
+	Shaper shaper.Options `json:"shaper"`
+	// DNSUpstreams are DNS servers consumer DNS queries are forwarded to.
+	DNSUpstreams []string `json:"dns_upstreams"`
 }
 
 // GetOptions returns effective OpenVPN service options from application configuration.
 func GetOptions() Options {
 	return Options{
-		Protocol: config.GetString(config.FlagOpenvpnProtocol),
-		Port:     config.GetInt(config.FlagOpenvpnPort),
-		Subnet:   config.GetString(config.FlagOpenvpnSubnet),
-		Netmask:  config.GetString(config.FlagOpenvpnNetmask),
+		Protocol:     config.GetString(config.FlagOpenvpnProtocol),
+		Port:         config.GetInt(config.FlagOpenvpnPort),
+		Subnet:       config.GetString(config.FlagOpenvpnSubnet),
+		Netmask:      config.GetString(config.FlagOpenvpnNetmask),
+		Shaper:       shaper.GetOptions(),
+		DNSUpstreams: config.GetStringSlice(config.FlagDNSUpstreams),
 	}
 }
 
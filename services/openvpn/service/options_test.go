@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
 )
@@ -32,6 +33,10 @@ var DefaultOptionsOpenvpn = Options{
 	Port:     config.FlagOpenvpnPort.Value,
 	Subnet:   config.FlagOpenvpnSubnet.Value,
 	Netmask:  config.FlagOpenvpnNetmask.Value,
+	Shaper: shaper.Options{
+		Interface: shaper.Limits{UplinkKbps: config.FlagShaperUplink.Value, DownlinkKbps: config.FlagShaperDownlink.Value},
+	},
+	DNSUpstreams: []string{"system"},
 }
 
 func Test_ParseJSONOptions_HandlesNil(t *testing.T) {
@@ -53,7 +58,7 @@ func Test_ParseJSONOptions_HandlesEmptyRequest(t *testing.T) {
 
 func Test_ParseJSONOptions_ValidRequest(t *testing.T) {
 	configureDefaults()
-	request := json.RawMessage(`{"port": 1123, "protocol": "udp", "subnet": "10.10.10.0", "netmask": "255.255.255.0"}`)
+	request := json.RawMessage(`{"port": 1123, "protocol": "udp", "subnet": "10.10.10.0", "netmask": "255.255.255.0", "shaper": {"interface": {"uplink_kbps": 2000}, "session": {"uplink_kbps": 100}}, "dns_upstreams": ["tls://1.1.1.1"]}`)
 	options, err := ParseJSONOptions(&request)
 
 	assert.NoError(t, err)
@@ -62,12 +67,22 @@ func Test_ParseJSONOptions_ValidRequest(t *testing.T) {
 		Port:     1123,
 		Subnet:   "10.10.10.0",
 		Netmask:  "255.255.255.0",
+		Shaper: shaper.Options{
+			Interface: shaper.Limits{UplinkKbps: 2000, DownlinkKbps: config.FlagShaperDownlink.Value},
+			Session:   shaper.Limits{UplinkKbps: 100},
+		},
+		DNSUpstreams: []string{"tls://1.1.1.1"},
 	}, options)
 }
 
 func configureDefaults() {
 	ctx := emptyContext()
 	config.ParseFlagsServiceOpenvpn(ctx)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperUplink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperDownlink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperSessionUplink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperSessionDownlink)
+	config.Current.ParseStringSliceFlag(ctx, config.FlagDNSUpstreams)
 }
 
 func emptyContext() *cli.Context {
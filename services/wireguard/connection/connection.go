@@ -57,6 +57,7 @@ func NewConnection(opts Options, ipResolver ip.Resolver, endpointFactory wg.Endp
 		ipResolver:          ipResolver,
 		connEndpointFactory: endpointFactory,
 		handshakeWaiter:     handshakeWaiter,
+		lookup:              consumerLookup,
 	}, nil
 }
 
@@ -71,9 +72,12 @@ type Connection struct {
 	ipResolver          ip.Resolver
 	connectionEndpoint  wg.ConnectionEndpoint
 	removeAllowedIPRule func()
+	excludedRulesLock   sync.Mutex
+	removeExcludedRules []func()
 	opts                Options
 	connEndpointFactory wg.EndpointFactory
 	handshakeWaiter     HandshakeWaiter
+	lookup              func(dnsIPs []string) lookupFunc
 }
 
 var _ connection.Connection = &Connection{}
@@ -103,6 +107,16 @@ func (c *Connection) Start(ctx context.Context, options connection.ConnectOption
 		return errors.Wrap(err, "failed to unmarshal connection config")
 	}
 
+	if options.ProviderNATConn != nil {
+		options.ProviderNATConn.Close()
+		remoteAddr := options.ProviderNATConn.RemoteAddr().(*net.UDPAddr)
+		config.LocalPort = options.ProviderNATConn.LocalAddr().(*net.UDPAddr).Port
+		config.Provider.Endpoint.Port = remoteAddr.Port
+		if options.Relayed {
+			config.Provider.Endpoint.IP = remoteAddr.IP
+		}
+	}
+
 	removeAllowedIPRule, err := firewall.AllowIPAccess(config.Provider.Endpoint.IP.String())
 	if err != nil {
 		return errors.Wrap(err, "failed to add firewall exception for wireguard remote IP")
@@ -117,29 +131,43 @@ func (c *Connection) Start(ctx context.Context, options connection.ConnectOption
 
 	c.stateCh <- connectionstate.Connecting
 
-	if options.ProviderNATConn != nil {
-		options.ProviderNATConn.Close()
-		config.LocalPort = options.ProviderNATConn.LocalAddr().(*net.UDPAddr).Port
-		config.Provider.Endpoint.Port = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).Port
+	if err := c.allowExcludedAccess(options.Params.SplitTunnel.ExcludedNetworks()); err != nil {
+		return err
 	}
 
 	dnsIPs, err := options.Params.DNS.ResolveIPs(config.Consumer.DNSIPs)
 	if err != nil {
 		return errors.Wrap(err, "could not resolve DNS IPs")
 	}
+	// Detached connection must not take over host DNS, so it is left untouched.
+	deviceDNS := dnsIPs
+	if options.Params.Detached {
+		deviceDNS = nil
+	}
+
+	allowedIPs := []string{"0.0.0.0/0", "::/0"}
+	if !options.Params.SplitTunnel.FullTunnel() {
+		// Tunnel subnet stays allowed, so that provider DNS is reachable.
+		subnet := net.IPNet{IP: config.Consumer.IPAddress.IP.Mask(config.Consumer.IPAddress.Mask), Mask: config.Consumer.IPAddress.Mask}
+		allowedIPs = append(options.Params.SplitTunnel.IncludedNetworks(), subnet.String())
+	}
 
 	log.Info().Msg("Starting new connection")
 	conn, err := c.startConn(wgcfg.DeviceConfig{
 		IfaceName:    "", // Interface name will be generated by connection endpoint.
 		Subnet:       config.Consumer.IPAddress,
+		Subnet6:      config.Consumer.IPAddress6,
 		PrivateKey:   c.privateKey,
 		ListenPort:   config.LocalPort,
-		DNS:          dnsIPs,
+		DNS:          deviceDNS,
 		DNSScriptDir: c.opts.DNSScriptDir,
+		Chained:      options.Chained,
+		ExcludedIPs:  options.Params.SplitTunnel.ExcludedNetworks(),
+		Detached:     options.Params.Detached,
 		Peer: wgcfg.Peer{
 			Endpoint:               &config.Provider.Endpoint,
 			PublicKey:              config.Provider.PublicKey,
-			AllowedIPs:             []string{"0.0.0.0/0", "::/0"},
+			AllowedIPs:             allowedIPs,
 			KeepAlivePeriodSeconds: 18,
 		},
 	})
@@ -156,6 +184,31 @@ func (c *Connection) Start(ctx context.Context, options connection.ConnectOption
 	}
 
 	c.stateCh <- connectionstate.Connected
+
+	resolver := newSplitTunnelResolver(options.Params.SplitTunnel, c.lookup(dnsIPs), func(include, exclude []string) error {
+		if err := c.allowExcludedAccess(exclude); err != nil {
+			return err
+		}
+		return conn.AddRoutes(include, exclude)
+	})
+	if resolver.enabled() {
+		go resolver.run(c.done)
+	}
+	return nil
+}
+
+// allowExcludedAccess adds kill switch exceptions for networks routed outside of the tunnel.
+func (c *Connection) allowExcludedAccess(networks []string) error {
+	c.excludedRulesLock.Lock()
+	defer c.excludedRulesLock.Unlock()
+
+	for _, network := range networks {
+		removeRule, err := firewall.AllowIPAccess(network)
+		if err != nil {
+			return errors.Wrap(err, "failed to add firewall exception for excluded network")
+		}
+		c.removeExcludedRules = append(c.removeExcludedRules, removeRule)
+	}
 	return nil
 }
 
@@ -173,6 +226,14 @@ func (c *Connection) startConn(conf wgcfg.DeviceConfig) (wg.ConnectionEndpoint,
 	return conn, nil
 }
 
+// InterfaceName returns name of the tunnel interface.
+func (c *Connection) InterfaceName() string {
+	if c.connectionEndpoint == nil {
+		return ""
+	}
+	return c.connectionEndpoint.InterfaceName()
+}
+
 // Wait blocks until wireguard connection not stopped.
 func (c *Connection) Wait() error {
 	<-c.done
@@ -202,6 +263,13 @@ func (c *Connection) Stop() {
 			c.removeAllowedIPRule()
 		}
 
+		c.excludedRulesLock.Lock()
+		for _, removeRule := range c.removeExcludedRules {
+			removeRule()
+		}
+		c.removeExcludedRules = nil
+		c.excludedRulesLock.Unlock()
+
 		if c.connectionEndpoint != nil {
 			if err := c.connectionEndpoint.Stop(); err != nil {
 				log.Error().Err(err).Msg("Failed to close wireguard connection")
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -93,6 +94,49 @@ func TestConnectionStopOnceAfterHandshakeErrorAndStopCall(t *testing.T) {
 	assert.Equal(t, connectionstate.NotConnected, <-conn.State())
 }
 
+func TestConnectionStartWithSplitTunnel(t *testing.T) {
+	endpoint := &mockConnectionEndpoint{}
+	conn := newConn(t)
+	conn.connEndpointFactory = func() (wg.ConnectionEndpoint, error) {
+		return endpoint, nil
+	}
+	conn.lookup = func(dnsIPs []string) lookupFunc {
+		assert.Equal(t, []string{"1.2.3.4"}, dnsIPs)
+		return func(_ context.Context, host string) ([]net.IPAddr, error) {
+			switch host {
+			case "example.com":
+				return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+			case "local.example.com":
+				return []net.IPAddr{{IP: net.ParseIP("192.168.1.10")}, {IP: net.ParseIP("fe80::1")}}, nil
+			}
+			return nil, errors.New("no such host")
+		}
+	}
+
+	sessionConfig, _ := json.Marshal(newServiceConfig())
+	err := conn.Start(context.Background(), connection.ConnectOptions{
+		Params: connection.ConnectParams{
+			DNS: "1.2.3.4",
+			SplitTunnel: connection.SplitTunnel{
+				Include: []string{"10.0.0.0/8", "example.com"},
+				Exclude: []string{"1.1.1.1", "local.example.com"},
+			},
+		},
+		SessionConfig: sessionConfig,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.0/25"}, endpoint.config.Peer.AllowedIPs)
+	assert.Equal(t, []string{"1.1.1.1/32"}, endpoint.config.ExcludedIPs)
+	assert.Eventually(t, func() bool {
+		included, excluded := endpoint.routes()
+		return assert.ObjectsAreEqual([]string{"93.184.216.34/32"}, included) &&
+			assert.ObjectsAreEqual([]string{"192.168.1.10/32"}, excluded)
+	}, time.Second, 10*time.Millisecond)
+
+	conn.Stop()
+}
+
 func newConn(t *testing.T) *Connection {
 	endpointFactory := func() (wg.ConnectionEndpoint, error) {
 		return &mockConnectionEndpoint{}, nil
@@ -118,8 +162,9 @@ func newServiceConfig() wg.ServiceConfig {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			IPAddress6 net.IPNet
+			DNSIPs     string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -130,9 +175,17 @@ func newServiceConfig() wg.ServiceConfig {
 	}
 }
 
-type mockConnectionEndpoint struct{}
+type mockConnectionEndpoint struct {
+	mu       sync.Mutex
+	config   wgcfg.DeviceConfig
+	included []string
+	excluded []string
+}
 
-func (mce *mockConnectionEndpoint) StartConsumerMode(config wgcfg.DeviceConfig) error { return nil }
+func (mce *mockConnectionEndpoint) StartConsumerMode(config wgcfg.DeviceConfig) error {
+	mce.config = config
+	return nil
+}
 func (mce *mockConnectionEndpoint) StartProviderMode(ip string, config wgcfg.DeviceConfig) error {
 	return nil
 }
@@ -142,6 +195,18 @@ func (mce *mockConnectionEndpoint) Config() (wg.ServiceConfig, error)    { retur
 func (mce *mockConnectionEndpoint) AddPeer(_ string, _ wgcfg.Peer) error { return nil }
 func (mce *mockConnectionEndpoint) RemovePeer(_ string) error            { return nil }
 func (mce *mockConnectionEndpoint) ConfigureRoutes(_ net.IP) error       { return nil }
+func (mce *mockConnectionEndpoint) AddRoutes(include, exclude []string) error {
+	mce.mu.Lock()
+	defer mce.mu.Unlock()
+	mce.included = append(mce.included, include...)
+	mce.excluded = append(mce.excluded, exclude...)
+	return nil
+}
+func (mce *mockConnectionEndpoint) routes() (included, excluded []string) {
+	mce.mu.Lock()
+	defer mce.mu.Unlock()
+	return mce.included, mce.excluded
+}
 func (mce *mockConnectionEndpoint) PeerStats() (*wgcfg.Stats, error) {
 	return &wgcfg.Stats{LastHandshake: time.Now(), BytesSent: 10, BytesReceived: 11}, nil
 }
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package routes
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
+	"github.com/mysteriumnetwork/node/utils/netutil"
+)
+
+// Configure sets up host routes of the consumer tunnel device.
+//
+// Provider's IP is excluded from the tunnel and default routes forward all traffic via the tunnel,
+// unless peer allowed IPs are narrowed down by split tunneling - then only allowed IPs are routed.
+// Detached tunnels do not get any routes besides provider's IP exclusion.
+// Chained tunnels keep provider's IP on the tunnel in front of them instead.
+func Configure(cfg wgcfg.DeviceConfig) error {
+	if cfg.Peer.Endpoint == nil {
+		return nil
+	}
+
+	if cfg.Chained {
+		if err := netutil.PinRoute(cfg.Peer.Endpoint.IP); err != nil {
+			return fmt.Errorf("could not pin route %s: %w", cfg.Peer.Endpoint.IP.String(), err)
+		}
+		if err := netutil.ReplaceDefaultRoute(cfg.IfaceName); err != nil {
+			return fmt.Errorf("could not replace default route for %s: %w", cfg.IfaceName, err)
+		}
+		return nil
+	}
+
+	if err := excludeEndpoint(cfg.Peer.Endpoint.IP); err != nil {
+		return fmt.Errorf("could not exclude route %s: %w", cfg.Peer.Endpoint.IP.String(), err)
+	}
+
+	// Traffic reaches detached tunnel only when it is bound to the tunnel interface.
+	if cfg.Detached {
+		return nil
+	}
+
+	if cfg.FullTunnel() {
+		if err := netutil.AddDefaultRoute(cfg.IfaceName); err != nil {
+			return fmt.Errorf("could not add default route for %s: %w", cfg.IfaceName, err)
+		}
+		// IPv6 traffic is forwarded via the tunnel only if provider assigned IPv6 address,
+		// the halves of address space take precedence over the host default route.
+		var include []string
+		if cfg.Subnet6.IP != nil {
+			include = []string{"::/1", "8000::/1"}
+		}
+		return Add(cfg.IfaceName, include, cfg.ExcludedIPs)
+	}
+
+	// Tunnel subnets are already routed through the device once its addresses are assigned.
+	subnets := map[string]bool{networkOf(cfg.Subnet): true}
+	if cfg.Subnet6.IP != nil {
+		subnets[networkOf(cfg.Subnet6)] = true
+	}
+	var include []string
+	for _, cidr := range cfg.Peer.AllowedIPs {
+		if !subnets[cidr] {
+			include = append(include, cidr)
+		}
+	}
+	return Add(cfg.IfaceName, include, cfg.ExcludedIPs)
+}
+
+// excludeEndpoint keeps provider's IP outside of the tunnel. Default gateway is known only for IPv4,
+// so IPv6 endpoint is pinned to the route it currently goes through instead.
+func excludeEndpoint(ip net.IP) error {
+	if ip.To4() == nil {
+		return netutil.PinRoute(ip)
+	}
+	return netutil.ExcludeRoute(ip)
+}
+
+func networkOf(subnet net.IPNet) string {
+	return (&net.IPNet{IP: subnet.IP.Mask(subnet.Mask), Mask: subnet.Mask}).String()
+}
+
+// Add routes included networks through the tunnel device and excluded networks outside of it.
+func Add(iface string, include, exclude []string) error {
+	for _, cidr := range include {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("could not parse included network %q: %w", cidr, err)
+		}
+		if err := netutil.RouteNetwork(*network, iface); err != nil {
+			return fmt.Errorf("could not route %s through %s: %w", cidr, iface, err)
+		}
+	}
+
+	for _, cidr := range exclude {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("could not parse excluded network %q: %w", cidr, err)
+		}
+		if err := netutil.ExcludeNetwork(*network); err != nil {
+			return fmt.Errorf("could not exclude route %s: %w", cidr, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection"
+)
+
+const (
+	splitTunnelResolveInterval = time.Minute
+	splitTunnelResolveTimeout  = 5 * time.Second
+)
+
+type lookupFunc func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+// splitTunnelResolver keeps routes of split tunnel DNS names up to date with the IPs they resolve to.
+type splitTunnelResolver struct {
+	include   []string
+	exclude   []string
+	lookup    lookupFunc
+	addRoutes func(include, exclude []string) error
+	known     map[string]struct{}
+}
+
+func newSplitTunnelResolver(splitTunnel connection.SplitTunnel, lookup lookupFunc, addRoutes func(include, exclude []string) error) *splitTunnelResolver {
+	return &splitTunnelResolver{
+		include:   splitTunnel.IncludedDomains(),
+		exclude:   splitTunnel.ExcludedDomains(),
+		lookup:    lookup,
+		addRoutes: addRoutes,
+		known:     make(map[string]struct{}),
+	}
+}
+
+// consumerLookup resolves names through the DNS servers of consumer connection,
+// falling back to the system resolver when connection uses system DNS.
+func consumerLookup(dnsIPs []string) lookupFunc {
+	if len(dnsIPs) == 0 {
+		return net.DefaultResolver.LookupIPAddr
+	}
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, net.JoinHostPort(dnsIPs[0], "53"))
+		},
+	}
+	return resolver.LookupIPAddr
+}
+
+func (r *splitTunnelResolver) enabled() bool {
+	return len(r.include) > 0 || len(r.exclude) > 0
+}
+
+// run resolves DNS names periodically until done is closed.
+func (r *splitTunnelResolver) run(done <-chan struct{}) {
+	for {
+		r.resolve()
+
+		select {
+		case <-done:
+			return
+		case <-time.After(splitTunnelResolveInterval):
+		}
+	}
+}
+
+// resolve routes IPs of DNS names which were not seen before.
+func (r *splitTunnelResolver) resolve() {
+	pending := make(map[string]struct{})
+	include := r.newNetworks(r.include, false, pending)
+	exclude := r.newNetworks(r.exclude, true, pending)
+	if len(include) == 0 && len(exclude) == 0 {
+		return
+	}
+
+	if err := r.addRoutes(include, exclude); err != nil {
+		log.Error().Err(err).Msg("Failed to route split tunnel domains")
+		return
+	}
+
+	for _, network := range append(include, exclude...) {
+		r.known[network] = struct{}{}
+	}
+}
+
+func (r *splitTunnelResolver) newNetworks(domains []string, ipv4Only bool, pending map[string]struct{}) (networks []string) {
+	for _, domain := range domains {
+		ctx, cancel := context.WithTimeout(context.Background(), splitTunnelResolveTimeout)
+		addrs, err := r.lookup(ctx, domain)
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to resolve split tunnel domain %s", domain)
+			continue
+		}
+
+		for _, addr := range addrs {
+			if ipv4Only && addr.IP.To4() == nil {
+				continue
+			}
+			network := connection.HostNetwork(addr.IP).String()
+			if _, ok := r.known[network]; ok {
+				continue
+			}
+			if _, ok := pending[network]; ok {
+				continue
+			}
+			pending[network] = struct{}{}
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
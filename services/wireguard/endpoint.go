@@ -30,6 +30,7 @@ type ConnectionEndpoint interface {
 	StartConsumerMode(config wgcfg.DeviceConfig) error
 	StartProviderMode(publicIP string, config wgcfg.DeviceConfig) error
 	PeerStats() (*wgcfg.Stats, error)
+	AddRoutes(include, exclude []string) error
 	Config() (ServiceConfig, error)
 	InterfaceName() string
 	Stop() error
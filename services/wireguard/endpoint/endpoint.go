@@ -88,6 +88,9 @@ func (ce *connectionEndpoint) StartProviderMode(publicIP string, config wgcfg.De
 
 	config.IfaceName = iface
 	config.Subnet.IP = netutil.FirstIP(config.Subnet)
+	if config.Subnet6.IP != nil {
+		config.Subnet6.IP = netutil.FirstIP(config.Subnet6)
+	}
 	ce.cfg = config
 	ce.endpoint = net.UDPAddr{IP: net.ParseIP(publicIP), Port: config.ListenPort}
 
@@ -107,6 +110,11 @@ func (ce *connectionEndpoint) PeerStats() (*wgcfg.Stats, error) {
 	return ce.wgClient.PeerStats(ce.cfg.IfaceName)
 }
 
+// AddRoutes routes included networks through the tunnel and excluded networks outside of it.
+func (ce *connectionEndpoint) AddRoutes(include, exclude []string) error {
+	return ce.wgClient.AddRoutes(ce.cfg.IfaceName, include, exclude)
+}
+
 // Config provides wireguard service configuration for the current connection endpoint.
 func (ce *connectionEndpoint) Config() (wg.ServiceConfig, error) {
 	publicKey, err := key.PrivateKeyToPublicKey(ce.cfg.PrivateKey)
@@ -119,9 +127,19 @@ func (ce *connectionEndpoint) Config() (wg.ServiceConfig, error) {
 	config.Provider.Endpoint = ce.endpoint
 	config.Consumer.IPAddress = ce.cfg.Subnet
 	config.Consumer.IPAddress.IP = ce.consumerIP(ce.cfg.Subnet)
+	if ce.cfg.Subnet6.IP != nil {
+		config.Consumer.IPAddress6 = net.IPNet{IP: consumerIP6(ce.cfg.Subnet6), Mask: ce.cfg.Subnet6.Mask}
+	}
 	return config, nil
 }
 
+// consumerIP6 returns IPv6 address of the consumer which follows provider address in the subnet.
+func consumerIP6(subnet net.IPNet) net.IP {
+	ip := netutil.FirstIP(subnet)
+	ip[len(ip)-1]++
+	return ip
+}
+
 // Stop closes wireguard client and destroys wireguard network interface.
 func (ce *connectionEndpoint) Stop() error {
 	if err := ce.wgClient.Close(); err != nil {
@@ -24,17 +24,19 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/services/wireguard/connection/dns"
+	"github.com/mysteriumnetwork/node/services/wireguard/connection/routes"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 	"github.com/mysteriumnetwork/node/utils"
 	"github.com/mysteriumnetwork/node/utils/cmdutil"
-	"github.com/mysteriumnetwork/node/utils/netutil"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
 
 type client struct {
 	iface      string
+	peer       wgcfg.Peer
 	wgClient   *wgctrl.Client
 	dnsManager dns.Manager
 }
@@ -64,18 +66,22 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) error {
 	if err := c.up(config.IfaceName, config.Subnet); err != nil {
 		return err
 	}
-
-	if config.Peer.Endpoint != nil {
-		if err := configureRoutes(config.IfaceName, config.Peer.Endpoint.IP); err != nil {
-			return err
+	if config.Subnet6.IP != nil {
+		if err := cmdutil.SudoExec("ip", "-6", "address", "replace", "dev", config.IfaceName, config.Subnet6.String()); err != nil {
+			log.Warn().Err(err).Msg("Failed to assign IPv6 address, tunnel will be IPv4 only")
 		}
 	}
+
+	if err := routes.Configure(config); err != nil {
+		return err
+	}
 	peer, err := addPeerConfig(config.Peer)
 	if err != nil {
 		return err
 	}
 	deviceConfig.Peers = []wgtypes.PeerConfig{peer}
 	c.iface = config.IfaceName
+	c.peer = config.Peer
 	if err := c.wgClient.ConfigureDevice(c.iface, deviceConfig); err != nil {
 		return fmt.Errorf("could not configure kernel space device: %w", err)
 	}
@@ -90,6 +96,20 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) error {
 	return nil
 }
 
+func (c *client) AddRoutes(iface string, include, exclude []string) error {
+	if len(include) > 0 {
+		peer, err := addPeerConfig(wgcfg.Peer{PublicKey: c.peer.PublicKey, AllowedIPs: include})
+		if err != nil {
+			return err
+		}
+		peer.UpdateOnly = true
+		if err := c.wgClient.ConfigureDevice(iface, wgtypes.Config{Peers: []wgtypes.PeerConfig{peer}}); err != nil {
+			return fmt.Errorf("could not add allowed IPs: %w", err)
+		}
+	}
+	return routes.Add(iface, include, exclude)
+}
+
 func addPeerConfig(peer wgcfg.Peer) (wgtypes.PeerConfig, error) {
 	endpoint := peer.Endpoint
 	publicKey, err := stringToKey(peer.PublicKey)
@@ -174,13 +194,6 @@ func (c *client) Close() (err error) {
 	return nil
 }
 
-func configureRoutes(iface string, ip net.IP) error {
-	if err := netutil.ExcludeRoute(ip); err != nil {
-		return err
-	}
-	return netutil.AddDefaultRoute(iface)
-}
-
 func stringToKey(key string) (wgtypes.Key, error) {
 	k, err := base64.StdEncoding.DecodeString(key)
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/user"
+	"strings"
 	"sync"
 
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
@@ -74,6 +75,14 @@ func (c *client) DestroyDevice(iface string) error {
 	return nil
 }
 
+func (c *client) AddRoutes(iface string, include, exclude []string) error {
+	_, err := supervisorclient.Command("wg-route", "-iface", iface, "-include", strings.Join(include, ","), "-exclude", strings.Join(exclude, ","))
+	if err != nil {
+		return fmt.Errorf("failed to add wg routes: %w", err)
+	}
+	return nil
+}
+
 func (c *client) PeerStats(iface string) (*wgcfg.Stats, error) {
 	statsJSON, err := supervisorclient.Command("wg-stats", "-iface", iface)
 	if err != nil {
@@ -23,9 +23,11 @@ import (
 	"strings"
 
 	"github.com/mysteriumnetwork/node/services/wireguard/connection/dns"
+	"github.com/mysteriumnetwork/node/services/wireguard/connection/routes"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 	"github.com/mysteriumnetwork/node/utils/netutil"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"golang.zx2c4.com/wireguard/device"
 	"golang.zx2c4.com/wireguard/tun"
 )
@@ -33,6 +35,7 @@ import (
 type client struct {
 	tun        tun.Device
 	devAPI     *device.Device
+	peer       wgcfg.Peer
 	dnsManager dns.Manager
 }
 
@@ -47,6 +50,11 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) (err error) {
 	if c.tun, err = CreateTUN(config.IfaceName, config.Subnet); err != nil {
 		return errors.Wrap(err, "failed to create TUN device")
 	}
+	if config.Subnet6.IP != nil {
+		if err := netutil.AssignIP(config.IfaceName, config.Subnet6); err != nil {
+			log.Warn().Err(err).Msg("Failed to assign IPv6 address, tunnel will be IPv4 only")
+		}
+	}
 
 	c.devAPI = device.NewDevice(c.tun, device.NewLogger(device.LogLevelDebug, "[userspace-wg]"))
 	if err := c.setDeviceConfig(config.Encode()); err != nil {
@@ -54,16 +62,10 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) (err error) {
 	}
 
 	c.devAPI.Up()
+	c.peer = config.Peer
 
-	// For consumer mode we need to exclude provider's IP from VPN tunnel
-	// and add default routes to forward all traffic via VPN tunnel.
-	if config.Peer.Endpoint != nil {
-		if err := netutil.ExcludeRoute(config.Peer.Endpoint.IP); err != nil {
-			return fmt.Errorf("could not exclude route %s: %w", config.Peer.Endpoint.IP.String(), err)
-		}
-		if err := netutil.AddDefaultRoute(config.IfaceName); err != nil {
-			return fmt.Errorf("could not add default route for %s: %w", config.IfaceName, err)
-		}
+	if err := routes.Configure(config); err != nil {
+		return err
 	}
 
 	if err := c.dnsManager.Set(dns.Config{
@@ -77,6 +79,15 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) (err error) {
 	return nil
 }
 
+func (c *client) AddRoutes(iface string, include, exclude []string) error {
+	if len(include) > 0 {
+		if err := c.setDeviceConfig(c.peer.EncodeAllowedIPs(include)); err != nil {
+			return fmt.Errorf("could not add allowed IPs: %w", err)
+		}
+	}
+	return routes.Add(iface, include, exclude)
+}
+
 func (c *client) Close() error {
 	c.devAPI.Close() // c.devAPI.Close() closes c.tun too
 	if err := c.dnsManager.Clean(); err != nil {
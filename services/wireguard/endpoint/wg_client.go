@@ -34,6 +34,7 @@ type WgClient interface {
 	ConfigureDevice(config wgcfg.DeviceConfig) error
 	DestroyDevice(name string) error
 	PeerStats(iface string) (*wgcfg.Stats, error)
+	AddRoutes(iface string, include, exclude []string) error
 	Close() error
 }
 
@@ -46,16 +46,19 @@ type Allocator struct {
 
 	portSupplier portSupplier
 	subnet       net.IPNet
+	subnet6      net.IPNet
 }
 
 // NewAllocator creates new resource pool for wireguard connection.
-func NewAllocator(ports portSupplier, subnet net.IPNet) *Allocator {
+// Empty subnet6 disables allocation of IPv6 networks.
+func NewAllocator(ports portSupplier, subnet, subnet6 net.IPNet) *Allocator {
 	return &Allocator{
 		Ifaces:      make(map[int]struct{}),
 		IPAddresses: make(map[int]struct{}),
 
 		portSupplier: ports,
 		subnet:       subnet,
+		subnet6:      subnet6,
 	}
 }
 
@@ -123,6 +126,17 @@ func (a *Allocator) AllocateIPNet() (net.IPNet, error) {
 	return net.IPNet{}, errors.New("no more unused subnets")
 }
 
+// IPNet6 returns IPv6 network paired with the allocated IPv4 network.
+// It shares allocation with the IPv4 network, so releasing IPv4 network releases it too.
+// Empty network is returned if IPv6 is disabled.
+func (a *Allocator) IPNet6(ipnet net.IPNet) net.IPNet {
+	ip4 := ipnet.IP.To4()
+	if a.subnet6.IP == nil || ip4 == nil {
+		return net.IPNet{}
+	}
+	return calcIPNet6(a.subnet6, int(ip4[2]))
+}
+
 // AllocatePort provides available UDP port for the wireguard endpoint.
 func (a *Allocator) AllocatePort() (int, error) {
 	a.mu.Lock()
@@ -188,3 +202,10 @@ func calcIPNet(ipnet net.IPNet, index int) net.IPNet {
 	ip[2] = byte(index)
 	return net.IPNet{IP: ip, Mask: net.IPv4Mask(255, 255, 255, 0)}
 }
+
+func calcIPNet6(ipnet net.IPNet, index int) net.IPNet {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, ipnet.IP.To16())
+	ip[7] = byte(index)
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(64, 8*net.IPv6len)}
+}
@@ -19,21 +19,30 @@ package service
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/mysteriumnetwork/node/services/wireguard/resources"
 	"github.com/rs/zerolog/log"
 )
 
 // Options describes options which are required to start Wireguard service.
 type Options struct {
-	Ports  *port.Range
-	Subnet net.IPNet
+	Ports   *port.Range
+	Subnet  net.IPNet
+	Subnet6 net.IPNet
+	Shaper  shaper.Options
+	// DNSUpstreams are DNS servers consumer DNS queries are forwarded to.
+	DNSUpstreams []string
 }
 
+// maxSubnet6Prefix is the longest IPv6 subnet prefix which still fits /64 network for every connection.
+const maxSubnet6Prefix = 56
+
 // DefaultOptions is a wireguard service configuration that will be used if no options provided.
 var DefaultOptions = Options{
 	Ports: port.UnspecifiedRange(),
@@ -41,6 +50,13 @@ var DefaultOptions = Options{
 		IP:   net.ParseIP("10.182.0.0").To4(),
 		Mask: net.IPv4Mask(255, 255, 0, 0),
 	},
+	Shaper: shaper.Options{
+		Interface: shaper.Limits{
+			UplinkKbps:   config.FlagShaperUplink.Value,
+			DownlinkKbps: config.FlagShaperDownlink.Value,
+		},
+	},
+	DNSUpstreams: config.FlagDNSUpstreams.Value.Value(),
 }
 
 // GetOptions returns effective Wireguard service options from application configuration.
@@ -51,6 +67,11 @@ func GetOptions() Options {
 		ipnet = &DefaultOptions.Subnet
 	}
 
+	subnet6, err := parseSubnet6(config.GetString(config.FlagWireguardListenSubnet6))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to parse IPv6 subnet option, IPv6 is disabled")
+	}
+
 	portRange, err := port.ParseRange(config.GetString(config.FlagWireguardListenPorts))
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to parse listen port range, using default value")
@@ -62,11 +83,33 @@ func GetOptions() Options {
 		portRange = port.UnspecifiedRange()
 	}
 	return Options{
-		Ports:  portRange,
-		Subnet: *ipnet,
+		Ports:        portRange,
+		Subnet:       *ipnet,
+		Subnet6:      subnet6,
+		Shaper:       shaper.GetOptions(),
+		DNSUpstreams: config.GetStringSlice(config.FlagDNSUpstreams),
 	}
 }
 
+// parseSubnet6 parses IPv6 subnet of the service, empty value means IPv6 is disabled.
+func parseSubnet6(value string) (net.IPNet, error) {
+	if value == "" {
+		return net.IPNet{}, nil
+	}
+
+	ip, ipnet, err := net.ParseCIDR(value)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	if ip.To4() != nil {
+		return net.IPNet{}, fmt.Errorf("%s is not IPv6 subnet", value)
+	}
+	if ones, _ := ipnet.Mask.Size(); ones > maxSubnet6Prefix {
+		return net.IPNet{}, fmt.Errorf("subnet prefix of %s is longer than /%d", value, maxSubnet6Prefix)
+	}
+	return *ipnet, nil
+}
+
 // ParseJSONOptions function fills in Wireguard options from JSON request
 func ParseJSONOptions(request *json.RawMessage) (service.Options, error) {
 	var requestOptions = GetOptions()
@@ -75,26 +118,42 @@ func ParseJSONOptions(request *json.RawMessage) (service.Options, error) {
 	}
 
 	opts := DefaultOptions
+	opts.Shaper = requestOptions.Shaper
+	opts.DNSUpstreams = requestOptions.DNSUpstreams
 	err := json.Unmarshal(*request, &opts)
 	return opts, err
 }
 
 // MarshalJSON implements json.Marshaler interface to provide human readable configuration.
 func (o Options) MarshalJSON() ([]byte, error) {
+	var subnet6 string
+	if o.Subnet6.IP != nil {
+		subnet6 = o.Subnet6.String()
+	}
+
 	return json.Marshal(&struct {
-		Ports  string `json:"ports"`
-		Subnet string `json:"subnet"`
+		Ports        string         `json:"ports"`
+		Subnet       string         `json:"subnet"`
+		Subnet6      string         `json:"subnet6"`
+		Shaper       shaper.Options `json:"shaper"`
+		DNSUpstreams []string       `json:"dns_upstreams"`
 	}{
-		Ports:  o.Ports.String(),
-		Subnet: o.Subnet.String(),
+		Ports:        o.Ports.String(),
+		Subnet:       o.Subnet.String(),
+		Subnet6:      subnet6,
+		Shaper:       o.Shaper,
+		DNSUpstreams: o.DNSUpstreams,
 	})
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface to receive human readable configuration.
 func (o *Options) UnmarshalJSON(data []byte) error {
 	var options struct {
-		Ports  string `json:"ports"`
-		Subnet string `json:"subnet"`
+		Ports        string          `json:"ports"`
+		Subnet       string          `json:"subnet"`
+		Subnet6      *string         `json:"subnet6"`
+		Shaper       *shaper.Options `json:"shaper"`
+		DNSUpstreams []string        `json:"dns_upstreams"`
 	}
 
 	if err := json.Unmarshal(data, &options); err != nil {
@@ -115,6 +174,19 @@ func (o *Options) UnmarshalJSON(data []byte) error {
 		}
 		o.Subnet = *ipnet
 	}
+	if options.Subnet6 != nil {
+		ipnet, err := parseSubnet6(*options.Subnet6)
+		if err != nil {
+			return err
+		}
+		o.Subnet6 = ipnet
+	}
+	if options.Shaper != nil {
+		o.Shaper = *options.Shaper
+	}
+	if options.DNSUpstreams != nil {
+		o.DNSUpstreams = options.DNSUpstreams
+	}
 
 	return nil
 }
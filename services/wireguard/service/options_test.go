@@ -25,6 +25,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/port"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
 )
@@ -48,7 +49,7 @@ func Test_ParseJSONOptions_HandlesEmptyRequest(t *testing.T) {
 
 func Test_ParseJSONOptions_ValidRequest(t *testing.T) {
 	configureDefaults()
-	request := json.RawMessage(`{"ports": "52820:53075", "subnet":"10.10.0.0/16"}`)
+	request := json.RawMessage(`{"ports": "52820:53075", "subnet":"10.10.0.0/16", "subnet6": "fd00:1::/48", "shaper": {"session": {"uplink_kbps": 1000, "downlink_kbps": 500}}, "dns_upstreams": ["tls://1.1.1.1"]}`)
 	options, err := ParseJSONOptions(&request)
 
 	assert.NoError(t, err)
@@ -58,12 +59,42 @@ func Test_ParseJSONOptions_ValidRequest(t *testing.T) {
 			IP:   net.ParseIP("10.10.0.0").To4(),
 			Mask: net.IPv4Mask(255, 255, 0, 0),
 		},
+		Subnet6: net.IPNet{
+			IP:   net.ParseIP("fd00:1::"),
+			Mask: net.CIDRMask(48, 128),
+		},
+		Shaper: shaper.Options{
+			Session: shaper.Limits{UplinkKbps: 1000, DownlinkKbps: 500},
+		},
+		DNSUpstreams: []string{"tls://1.1.1.1"},
 	}, options)
 }
 
+func Test_ParseJSONOptions_DisablesIPv6(t *testing.T) {
+	configureDefaults()
+	request := json.RawMessage(`{"subnet6": ""}`)
+	options, err := ParseJSONOptions(&request)
+
+	assert.NoError(t, err)
+	assert.Nil(t, options.(Options).Subnet6.IP)
+}
+
+func Test_ParseJSONOptions_RejectsLongIPv6Prefix(t *testing.T) {
+	configureDefaults()
+	request := json.RawMessage(`{"subnet6": "fd00:1::/64"}`)
+	_, err := ParseJSONOptions(&request)
+
+	assert.Error(t, err)
+}
+
 func configureDefaults() {
 	ctx := emptyContext()
 	config.ParseFlagsServiceWireguard(ctx)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperUplink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperDownlink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperSessionUplink)
+	config.Current.ParseIntFlag(ctx, config.FlagShaperSessionDownlink)
+	config.Current.ParseStringSliceFlag(ctx, config.FlagDNSUpstreams)
 }
 
 func emptyContext() *cli.Context {
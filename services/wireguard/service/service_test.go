@@ -27,10 +27,12 @@ import (
 	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/nat"
 	wg "github.com/mysteriumnetwork/node/services/wireguard"
+	"github.com/mysteriumnetwork/node/services/wireguard/resources"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 	"github.com/stretchr/testify/assert"
 )
@@ -90,6 +92,101 @@ func Test_Manager_ProviderConfig_FailsWhenSessionConfigIsInvalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_Manager_ProviderConfig_IPv6(t *testing.T) {
+	subnet6 := net.IPNet{IP: net.ParseIP("fd00:1::"), Mask: net.CIDRMask(48, 8*net.IPv6len)}
+	tests := []struct {
+		name            string
+		subnet6         net.IPNet
+		resolver        ip.Resolver
+		ip6NATSupported bool
+		wantIPv6        bool
+	}{
+		{
+			name:            "IPv6 is disabled by options",
+			resolver:        ip.NewResolverMockIPv6("1.2.3.4", "2001:db8::1"),
+			ip6NATSupported: true,
+		},
+		{
+			name:            "provider has no public IPv6",
+			subnet6:         subnet6,
+			resolver:        ip.NewResolverMock("1.2.3.4"),
+			ip6NATSupported: true,
+		},
+		{
+			name:     "ip6tables NAT is not usable",
+			subnet6:  subnet6,
+			resolver: ip.NewResolverMockIPv6("1.2.3.4", "2001:db8::1"),
+		},
+		{
+			name:            "IPv6 is available",
+			subnet6:         subnet6,
+			resolver:        ip.NewResolverMockIPv6("1.2.3.4", "2001:db8::1"),
+			ip6NATSupported: true,
+			wantIPv6:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip6NATSupported := tt.ip6NATSupported
+			manager := newManagerStub(pubIP, outIP, country)
+			manager.ipResolver = tt.resolver
+			manager.subnet6 = tt.subnet6
+			manager.ip6NATSupported = func() bool { return ip6NATSupported }
+			manager.resourcesAllocator = resources.NewAllocator(nil, DefaultOptions.Subnet, tt.subnet6)
+
+			manager.ipv6 = manager.ipv6Available()
+			config, err := manager.createProviderConfig(52820, "")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantIPv6, config.Subnet6.IP != nil)
+		})
+	}
+}
+
+func Test_Manager_BlockSessionTraffic(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     market.AccessPolicyRuleSet
+		wantBlock bool
+	}{
+		{
+			name: "deny-only DNS policy does not block traffic",
+			rules: market.AccessPolicyRuleSet{
+				ID:   "1",
+				Deny: []market.AccessRule{{Type: market.AccessPolicyTypeDNSZone, Value: "ads.com"}},
+			},
+		},
+		{
+			name: "DNS allow policy blocks traffic",
+			rules: market.AccessPolicyRuleSet{
+				ID:    "1",
+				Allow: []market.AccessRule{{Type: market.AccessPolicyTypeDNSZone, Value: "site.com"}},
+			},
+			wantBlock: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies := policy.NewRepository()
+			policies.SetPolicyRules(market.AccessPolicy{ID: "1"}, tt.rules)
+			trafficFirewall := &incomingFirewallMock{}
+			manager := newManagerStub(pubIP, outIP, country)
+			manager.trafficFirewall = trafficFirewall
+			manager.serviceInstance = service.NewInstance(identity.FromAddress("0x1"), "", nil, market.ServiceProposal{}, servicestate.Running, nil, policies, nil)
+
+			release, err := manager.blockSessionTraffic(wgcfg.DeviceConfig{Subnet: DefaultOptions.Subnet})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBlock, release != nil)
+			if tt.wantBlock {
+				assert.Equal(t, []net.IPNet{DefaultOptions.Subnet}, trafficFirewall.blocked)
+			} else {
+				assert.Empty(t, trafficFirewall.blocked)
+			}
+		})
+	}
+}
+
 // usually time.Sleep call gives a chance for other goroutines to kick in important when testing async code
 func waitABit() {
 	time.Sleep(10 * time.Millisecond)
@@ -107,6 +204,7 @@ func (mce *mockConnectionEndpoint) Config() (wg.ServiceConfig, error)    { retur
 func (mce *mockConnectionEndpoint) AddPeer(_ string, _ wgcfg.Peer) error { return nil }
 func (mce *mockConnectionEndpoint) RemovePeer(_ string) error            { return nil }
 func (mce *mockConnectionEndpoint) ConfigureRoutes(_ net.IP) error       { return nil }
+func (mce *mockConnectionEndpoint) AddRoutes(_, _ []string) error        { return nil }
 func (mce *mockConnectionEndpoint) PeerStats() (*wgcfg.Stats, error) {
 	return &wgcfg.Stats{LastHandshake: time.Now()}, nil
 }
@@ -122,6 +220,16 @@ func newManagerStub(pub, out, country string) *Manager {
 	}
 }
 
+type incomingFirewallMock struct {
+	firewall.IncomingTrafficFirewall
+	blocked []net.IPNet
+}
+
+func (f *incomingFirewallMock) BlockIncomingTraffic(network net.IPNet) (firewall.IncomingRuleRemove, error) {
+	f.blocked = append(f.blocked, network)
+	return func() error { return nil }, nil
+}
+
 type serviceFake struct{}
 
 func (service *serviceFake) Setup(nat.Options) (rules []interface{}, err error) {
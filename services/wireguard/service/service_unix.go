@@ -1,4 +1,5 @@
-//+build !windows
+//go:build !windows
+// +build !windows
 
 /*
  * Copyright (C) 2019 The "MysteriumNetwork/node" Authors.
@@ -33,6 +34,7 @@ import (
 	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/firewall"
+	"github.com/mysteriumnetwork/node/firewall/iptables"
 	"github.com/mysteriumnetwork/node/nat"
 	natevent "github.com/mysteriumnetwork/node/nat/event"
 	wg "github.com/mysteriumnetwork/node/services/wireguard"
@@ -61,12 +63,15 @@ func NewManager(
 	portSupplier port.ServicePortSupplier,
 	trafficFirewall firewall.IncomingTrafficFirewall,
 ) *Manager {
-	resourcesAllocator := resources.NewAllocator(portSupplier, options.Subnet)
+	resourcesAllocator := resources.NewAllocator(portSupplier, options.Subnet, options.Subnet6)
 
 	return &Manager{
 		done:               make(chan struct{}),
 		resourcesAllocator: resourcesAllocator,
 		ipResolver:         ipResolver,
+		subnet6:            options.Subnet6,
+		dnsUpstreams:       options.DNSUpstreams,
+		ip6NATSupported:    iptables.IPv6NATSupported,
 		natService:         natService,
 		natEventGetter:     natEventGetter,
 		eventBus:           eventBus,
@@ -77,6 +82,7 @@ func NewManager(
 		},
 		country:        country,
 		sessionCleanup: map[string]func(){},
+		shaper:         shaper.New(eventBus, options.Shaper),
 	}
 }
 
@@ -92,14 +98,20 @@ type Manager struct {
 	eventBus        eventbus.EventBus
 	trafficFirewall firewall.IncomingTrafficFirewall
 
-	dnsOK    bool
-	dnsPort  int
-	dnsProxy *dns.Proxy
+	dnsOK        bool
+	dnsPort      int
+	dnsProxy     *dns.Proxy
+	dnsUpstreams []string
 
 	connEndpointFactory func() (wg.ConnectionEndpoint, error)
+	shaper              shaper.Shaper
 
 	ipResolver ip.Resolver
 
+	subnet6         net.IPNet
+	ip6NATSupported func() bool
+	ipv6            bool
+
 	serviceInstance  *service.Instance
 	sessionCleanup   map[string]func()
 	sessionCleanupMu sync.Mutex
@@ -142,19 +154,32 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 	var dnsIP net.IP
 	var releaseTrafficFirewall firewall.IncomingRuleRemove
 	if m.dnsOK {
-		if m.serviceInstance.Policies().HasDNSRules() {
-			releaseTrafficFirewall, err = m.trafficFirewall.BlockIncomingTraffic(providerConfig.Subnet)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to enable traffic blocking")
-			}
+		releaseTrafficFirewall, err = m.blockSessionTraffic(providerConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to enable traffic blocking")
 		}
 
 		dnsIP = netutil.FirstIP(config.Consumer.IPAddress)
 		config.Consumer.DNSIPs = dnsIP.String()
 	}
 
+	var releaseTrafficRestriction firewall.IncomingRuleRemove
+	if m.serviceInstance.Policies().HasTrafficRules() {
+		trafficRules := m.serviceInstance.Policies().TrafficRules()
+		releaseTrafficRestriction, err = applyFirewallRule(sessionNetworks(providerConfig), func(network net.IPNet) (firewall.IncomingRuleRemove, error) {
+			return m.trafficFirewall.RestrictIncomingTraffic(network, trafficRules)
+		})
+		if err != nil {
+			if releaseTrafficFirewall != nil {
+				releaseTrafficFirewall()
+			}
+			return nil, errors.Wrap(err, "failed to enable traffic restriction")
+		}
+	}
+
 	natRules, err := m.natService.Setup(nat.Options{
 		VPNNetwork:        config.Consumer.IPAddress,
+		VPNNetwork6:       providerConfig.Subnet6,
 		DNSIP:             dnsIP,
 		ProviderExtIP:     net.ParseIP(m.outboundIP),
 		EnableDNSRedirect: m.dnsOK,
@@ -168,8 +193,7 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 	go statsPublisher.start(sessionID, conn)
 
 	ifaceName := conn.InterfaceName()
-	s := shaper.New(m.eventBus)
-	err = s.Start(ifaceName)
+	err = m.shaper.Start(ifaceName)
 	if err != nil {
 		log.Error().Err(err).Msg("Could not start traffic shaper")
 	}
@@ -182,7 +206,7 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 
 		statsPublisher.stop()
 
-		s.Clear(ifaceName)
+		m.shaper.Clear(ifaceName)
 
 		if releaseTrafficFirewall != nil {
 			if err := releaseTrafficFirewall(); err != nil {
@@ -190,6 +214,12 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 			}
 		}
 
+		if releaseTrafficRestriction != nil {
+			if err := releaseTrafficRestriction(); err != nil {
+				log.Warn().Err(err).Msg("failed to disable traffic restriction")
+			}
+		}
+
 		log.Trace().Msg("Deleting nat rules")
 		if err := m.natService.Del(natRules); err != nil {
 			log.Error().Err(err).Msg("Failed to delete NAT rules")
@@ -209,7 +239,12 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 	m.sessionCleanup[sessionID] = destroy
 	m.sessionCleanupMu.Unlock()
 
-	return &service.ConfigParams{SessionServiceConfig: config, SessionDestroyCallback: destroy}, nil
+	return &service.ConfigParams{
+		SessionServiceConfig:   config,
+		SessionDestroyCallback: destroy,
+		SessionInterface:       ifaceName,
+		ConsumerTunnelIP:       config.Consumer.IPAddress.IP,
+	}, nil
 }
 
 func (m *Manager) createProviderConfig(listenPort int, peerPublicKey string) (wgcfg.DeviceConfig, error) {
@@ -223,9 +258,15 @@ func (m *Manager) createProviderConfig(listenPort int, peerPublicKey string) (wg
 		return wgcfg.DeviceConfig{}, fmt.Errorf("could not generate private key: %w", err)
 	}
 
+	var network6 net.IPNet
+	if m.ipv6 {
+		network6 = m.resourcesAllocator.IPNet6(network)
+	}
+
 	return wgcfg.DeviceConfig{
 		IfaceName:  "", // Interface name will be generated by connection endpoint.
 		Subnet:     network,
+		Subnet6:    network6,
 		PrivateKey: privateKey,
 		ListenPort: listenPort,
 		DNS:        nil,
@@ -239,6 +280,51 @@ func (m *Manager) createProviderConfig(listenPort int, peerPublicKey string) (wg
 	}, nil
 }
 
+// sessionNetworks returns IPv4 and, if enabled, IPv6 networks of the session.
+// blockSessionTraffic blocks traffic of the session when DNS allow rules are applied, so that only IPs of the allowed
+// hosts are reachable. Deny rules don't need it, denied hosts are just not resolved.
+func (m *Manager) blockSessionTraffic(config wgcfg.DeviceConfig) (firewall.IncomingRuleRemove, error) {
+	if !m.serviceInstance.Policies().HasDNSAllowRules() {
+		return nil, nil
+	}
+	return applyFirewallRule(sessionNetworks(config), m.trafficFirewall.BlockIncomingTraffic)
+}
+
+func sessionNetworks(config wgcfg.DeviceConfig) []net.IPNet {
+	networks := []net.IPNet{config.Subnet}
+	if config.Subnet6.IP != nil {
+		networks = append(networks, config.Subnet6)
+	}
+	return networks
+}
+
+// applyFirewallRule applies firewall rule to every given network.
+// Rules which were already applied are removed if any of them fails.
+func applyFirewallRule(networks []net.IPNet, apply func(network net.IPNet) (firewall.IncomingRuleRemove, error)) (firewall.IncomingRuleRemove, error) {
+	var removals []firewall.IncomingRuleRemove
+	remove := func() error {
+		var lastErr error
+		for _, removal := range removals {
+			if err := removal(); err != nil {
+				lastErr = err
+			}
+		}
+		return lastErr
+	}
+
+	for _, network := range networks {
+		removal, err := apply(network)
+		if err != nil {
+			if err := remove(); err != nil {
+				log.Warn().Err(err).Msg("Failed to remove firewall rules")
+			}
+			return nil, err
+		}
+		removals = append(removals, removal)
+	}
+	return remove, nil
+}
+
 func (m *Manager) startNewConnection(publicIP string, config wgcfg.DeviceConfig) (wg.ConnectionEndpoint, error) {
 	connEndpoint, err := m.connEndpointFactory()
 	if err != nil {
@@ -262,11 +348,12 @@ func (m *Manager) Serve(instance *service.Instance) error {
 	if err != nil {
 		return errors.Wrap(err, "could not get outbound IP")
 	}
+	m.ipv6 = m.ipv6Available()
 
 	// Start DNS proxy.
 	m.dnsPort = 11253
 	m.dnsOK = false
-	dnsHandler, err := dns.ResolveViaSystem()
+	dnsHandler, err := dns.ResolveViaUpstreams(m.dnsUpstreams)
 	if err == nil {
 		if m.serviceInstance.Policies().HasDNSRules() {
 			dnsHandler = dns.WhitelistAnswers(dnsHandler, m.trafficFirewall, instance.Policies())
@@ -289,6 +376,23 @@ func (m *Manager) Serve(instance *service.Instance) error {
 	return nil
 }
 
+// ipv6Available checks whether IPv6 traffic of the sessions can be routed to the internet,
+// otherwise consumers would route their IPv6 traffic into the tunnel and lose it.
+func (m *Manager) ipv6Available() bool {
+	if m.subnet6.IP == nil {
+		return false
+	}
+	if ipv6, err := m.ipResolver.GetPublicIPv6(); err != nil || ipv6 == "" {
+		log.Warn().Err(err).Msg("Public IPv6 address not found, IPv6 will not be provided to sessions")
+		return false
+	}
+	if !m.ip6NATSupported() {
+		log.Warn().Msg("ip6tables NAT is not usable, IPv6 will not be provided to sessions")
+		return false
+	}
+	return true
+}
+
 // Stop stops service.
 func (m *Manager) Stop() error {
 	log.Info().Msg("Wireguard: stopping")
@@ -54,8 +54,9 @@ type ServiceConfig struct {
 		Endpoint  net.UDPAddr
 	}
 	Consumer struct {
-		IPAddress net.IPNet
-		DNSIPs    string
+		IPAddress  net.IPNet
+		IPAddress6 net.IPNet
+		DNSIPs     string
 	}
 }
 
@@ -74,8 +75,14 @@ func (s ServiceConfig) MarshalJSON() ([]byte, error) {
 		Endpoint  string `json:"endpoint"`
 	}
 	type consumer struct {
-		IPAddress string `json:"ip_address"`
-		DNSIPs    string `json:"dns_ips"`
+		IPAddress  string `json:"ip_address"`
+		IPAddress6 string `json:"ip_address6,omitempty"`
+		DNSIPs     string `json:"dns_ips"`
+	}
+
+	var ipAddress6 string
+	if s.Consumer.IPAddress6.IP != nil {
+		ipAddress6 = s.Consumer.IPAddress6.String()
 	}
 
 	return json.Marshal(&struct {
@@ -93,8 +100,9 @@ func (s ServiceConfig) MarshalJSON() ([]byte, error) {
 			Endpoint:  s.Provider.Endpoint.String(),
 		},
 		Consumer: consumer{
-			IPAddress: s.Consumer.IPAddress.String(),
-			DNSIPs:    s.Consumer.DNSIPs,
+			IPAddress:  s.Consumer.IPAddress.String(),
+			IPAddress6: ipAddress6,
+			DNSIPs:     s.Consumer.DNSIPs,
 		},
 	})
 }
@@ -106,8 +114,9 @@ func (s *ServiceConfig) UnmarshalJSON(data []byte) error {
 		Endpoint  string `json:"endpoint"`
 	}
 	type consumer struct {
-		IPAddress string `json:"ip_address"`
-		DNSIPs    string `json:"dns_ips"`
+		IPAddress  string `json:"ip_address"`
+		IPAddress6 string `json:"ip_address6,omitempty"`
+		DNSIPs     string `json:"dns_ips"`
 	}
 	var config struct {
 		LocalPort  int      `json:"local_port"`
@@ -130,6 +139,16 @@ func (s *ServiceConfig) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// IPv6 address is absent if provider has IPv6 disabled.
+	if config.Consumer.IPAddress6 != "" {
+		ip6, ipnet6, err := net.ParseCIDR(config.Consumer.IPAddress6)
+		if err != nil {
+			return err
+		}
+		s.Consumer.IPAddress6 = *ipnet6
+		s.Consumer.IPAddress6.IP = ip6
+	}
+
 	s.Ports = config.Ports
 	s.LocalPort = config.LocalPort
 	s.RemotePort = config.RemotePort
@@ -130,8 +130,9 @@ func TestServiceConfig_MarshalJSON(t *testing.T) {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			IPAddress6 net.IPNet
+			DNSIPs     string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -164,8 +165,9 @@ func TestServiceConfig_UnmarshalJSON(t *testing.T) {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			IPAddress6 net.IPNet
+			DNSIPs     string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -181,3 +183,20 @@ func TestServiceConfig_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expecteConfig, actualConfig)
 }
+
+func TestServiceConfig_IPv6RoundTrip(t *testing.T) {
+	config := ServiceConfig{}
+	config.Provider.Endpoint = net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 51001}
+	config.Consumer.IPAddress = net.IPNet{IP: net.IPv4(10, 182, 0, 2).To4(), Mask: net.IPv4Mask(255, 255, 255, 0)}
+	config.Consumer.IPAddress6 = net.IPNet{IP: net.ParseIP("fd6d:7973:7400::2"), Mask: net.CIDRMask(64, 128)}
+
+	configBytes, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.Contains(t, string(configBytes), `"ip_address6":"fd6d:7973:7400::2/64"`)
+
+	var actualConfig ServiceConfig
+	err = json.Unmarshal(configBytes, &actualConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, config.Consumer.IPAddress6, actualConfig.Consumer.IPAddress6)
+}
@@ -40,11 +40,18 @@ type Stats struct {
 type DeviceConfig struct {
 	IfaceName  string    `json:"iface_name"`
 	Subnet     net.IPNet `json:"subnet"`
+	Subnet6    net.IPNet `json:"subnet6"`
 	PrivateKey string    `json:"private_key"`
 	ListenPort int       `json:"listen_port"`
 	DNS        []string  `json:"dns"`
 	// Used only for unix.
 	DNSScriptDir string `json:"dns_script_dir"`
+	// Chained marks a consumer tunnel which is carried by another tunnel in front of it.
+	Chained bool `json:"chained"`
+	// ExcludedIPs are consumer networks routed outside of the tunnel.
+	ExcludedIPs []string `json:"excluded_ips"`
+	// Detached marks a consumer tunnel which leaves host routes untouched.
+	Detached bool `json:"detached"`
 
 	Peer Peer `json:"peer"`
 }
@@ -61,10 +68,14 @@ func (dc DeviceConfig) MarshalJSON() ([]byte, error) {
 	type deviceConfig struct {
 		IfaceName    string   `json:"iface_name"`
 		Subnet       string   `json:"subnet"`
+		Subnet6      string   `json:"subnet6,omitempty"`
 		PrivateKey   string   `json:"private_key"`
 		ListenPort   int      `json:"listen_port"`
 		DNS          []string `json:"dns"`
 		DNSScriptDir string   `json:"dns_script_dir"`
+		Chained      bool     `json:"chained,omitempty"`
+		ExcludedIPs  []string `json:"excluded_ips,omitempty"`
+		Detached     bool     `json:"detached,omitempty"`
 		Peer         peer     `json:"peer"`
 	}
 
@@ -73,13 +84,22 @@ func (dc DeviceConfig) MarshalJSON() ([]byte, error) {
 		peerEndpoint = dc.Peer.Endpoint.String()
 	}
 
+	var subnet6 string
+	if dc.Subnet6.IP != nil {
+		subnet6 = dc.Subnet6.String()
+	}
+
 	return json.Marshal(&deviceConfig{
 		IfaceName:    dc.IfaceName,
 		Subnet:       dc.Subnet.String(),
+		Subnet6:      subnet6,
 		PrivateKey:   dc.PrivateKey,
 		ListenPort:   dc.ListenPort,
 		DNS:          dc.DNS,
 		DNSScriptDir: dc.DNSScriptDir,
+		Chained:      dc.Chained,
+		ExcludedIPs:  dc.ExcludedIPs,
+		Detached:     dc.Detached,
 		Peer: peer{
 			PublicKey:              dc.Peer.PublicKey,
 			Endpoint:               peerEndpoint,
@@ -101,10 +121,14 @@ func (dc *DeviceConfig) UnmarshalJSON(data []byte) error {
 	type deviceConfig struct {
 		IfaceName    string   `json:"iface_name"`
 		Subnet       string   `json:"subnet"`
+		Subnet6      string   `json:"subnet6,omitempty"`
 		PrivateKey   string   `json:"private_key"`
 		ListenPort   int      `json:"listen_port"`
 		DNS          []string `json:"dns"`
 		DNSScriptDir string   `json:"dns_script_dir"`
+		Chained      bool     `json:"chained,omitempty"`
+		ExcludedIPs  []string `json:"excluded_ips,omitempty"`
+		Detached     bool     `json:"detached,omitempty"`
 		Peer         peer     `json:"peer"`
 	}
 
@@ -119,6 +143,16 @@ func (dc *DeviceConfig) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("could not parse subnet: %w", err)
 	}
 
+	var subnet6 net.IPNet
+	if cfg.Subnet6 != "" {
+		ip6, ipnet6, err := net.ParseCIDR(cfg.Subnet6)
+		if err != nil {
+			return fmt.Errorf("could not parse IPv6 subnet: %w", err)
+		}
+		subnet6 = *ipnet6
+		subnet6.IP = ip6
+	}
+
 	var peerEndpoint *net.UDPAddr
 	if cfg.Peer.Endpoint != "" {
 		peerEndpoint, err = net.ResolveUDPAddr("udp", cfg.Peer.Endpoint)
@@ -130,10 +164,14 @@ func (dc *DeviceConfig) UnmarshalJSON(data []byte) error {
 	dc.IfaceName = cfg.IfaceName
 	dc.Subnet = *ipnet
 	dc.Subnet.IP = ip
+	dc.Subnet6 = subnet6
 	dc.PrivateKey = cfg.PrivateKey
 	dc.ListenPort = cfg.ListenPort
 	dc.DNS = cfg.DNS
 	dc.DNSScriptDir = cfg.DNSScriptDir
+	dc.Chained = cfg.Chained
+	dc.ExcludedIPs = cfg.ExcludedIPs
+	dc.Detached = cfg.Detached
 	dc.Peer = Peer{
 		PublicKey:              cfg.Peer.PublicKey,
 		Endpoint:               peerEndpoint,
@@ -161,6 +199,17 @@ func (dc *DeviceConfig) Encode() string {
 	return res.String()
 }
 
+// FullTunnel returns true if peer allowed IPs cover whole IPv4 address space,
+// meaning that all consumer traffic goes through the tunnel.
+func (dc *DeviceConfig) FullTunnel() bool {
+	for _, ip := range dc.Peer.AllowedIPs {
+		if ip == "0.0.0.0/0" {
+			return true
+		}
+	}
+	return false
+}
+
 // Peer represents wireguard peer.
 type Peer struct {
 	PublicKey              string       `json:"public_key"`
@@ -190,3 +239,21 @@ func (p *Peer) Encode() string {
 	}
 	return res.String()
 }
+
+// EncodeAllowedIPs encodes an update which appends given allowed IPs to the existing device peer.
+func (p *Peer) EncodeAllowedIPs(ips []string) string {
+	var res strings.Builder
+
+	keyBytes, err := base64.StdEncoding.DecodeString(p.PublicKey)
+	if err != nil {
+		log.Err(err).Msg("Could not decode device public key. Will use empty config.")
+		return ""
+	}
+	hexKey := hex.EncodeToString(keyBytes)
+	res.WriteString(fmt.Sprintf("public_key=%s\n", hexKey))
+	res.WriteString("update_only=true\n")
+	for _, ip := range ips {
+		res.WriteString(fmt.Sprintf("allowed_ip=%s\n", ip))
+	}
+	return res.String()
+}
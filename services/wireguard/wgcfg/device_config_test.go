@@ -73,6 +73,23 @@ persistent_keepalive_interval=0
 	}
 }
 
+func TestPeer_EncodeAllowedIPs(t *testing.T) {
+	peer := Peer{PublicKey: "DyxwLJ++jVO+azusu7rPEnzdgfm+0fiOBQ1GTbkk3QQ="}
+
+	assert.Equal(t, `public_key=0f2c702c9fbe8d53be6b3bacbbbacf127cdd81f9bed1f88e050d464db924dd04
+update_only=true
+allowed_ip=1.1.1.1/32
+`, peer.EncodeAllowedIPs([]string{"1.1.1.1/32"}))
+}
+
+func TestDeviceConfig_FullTunnel(t *testing.T) {
+	config := DeviceConfig{Peer: Peer{AllowedIPs: []string{"0.0.0.0/0", "::/0"}}}
+	assert.True(t, config.FullTunnel())
+
+	config.Peer.AllowedIPs = []string{"10.0.0.0/8"}
+	assert.False(t, config.FullTunnel())
+}
+
 func TestDeviceConfig_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -151,6 +168,15 @@ func TestDeviceConfig_UnmarshalJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "Test unmarshal IPv6 subnet",
+			config: `{"iface_name":"myst0","subnet":"10.0.182.2/24","subnet6":"fd6d:7973:7400:b6::2/64","private_key":"","listen_port":0,"peer":{"public_key":"","endpoint":"","allowed_i_ps":null,"keep_alive_period_seconds":0}}`,
+			expected: DeviceConfig{
+				IfaceName: "myst0",
+				Subnet:    net.IPNet{IP: net.ParseIP("10.0.182.2"), Mask: net.IPv4Mask(255, 255, 255, 0)},
+				Subnet6:   net.IPNet{IP: net.ParseIP("fd6d:7973:7400:b6::2"), Mask: net.CIDRMask(64, 128)},
+			},
+		},
 	}
 
 	for _, test := range tests {
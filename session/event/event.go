@@ -19,6 +19,7 @@ package event
 
 import (
 	"math/big"
+	"net"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -33,6 +34,8 @@ const (
 	AppTopicDataTransferred = "Session data transferred"
 	// AppTopicTokensEarned is a topic for publish events about tokens earned as a provider.
 	AppTopicTokensEarned = "SessionTokensEarned"
+	// AppTopicSessionRejected is a topic for publish events about sessions rejected by provider admission control.
+	AppTopicSessionRejected = "Session rejected"
 )
 
 // AppEventDataTransferred represents the data transfer event
@@ -48,6 +51,14 @@ type AppEventTokensEarned struct {
 	Total      *big.Int
 }
 
+// AppEventSessionRejected describes session which was not admitted by the provider
+type AppEventSessionRejected struct {
+	ServiceID  string
+	ConsumerID identity.Identity
+	Reason     string
+	Message    string
+}
+
 // Status represents the different actions that might happen on a session
 type Status string
 
@@ -58,6 +69,8 @@ const (
 	RemovedStatus Status = "RemovedStatus"
 	// AcknowledgedStatus indicates a session has been reported as a success from consumer side
 	AcknowledgedStatus Status = "AcknowledgedStatus"
+	// ConfiguredStatus indicates a service has set up the transport of a session
+	ConfiguredStatus Status = "ConfiguredStatus"
 )
 
 // AppEventSession represents the session change payload
@@ -80,4 +93,8 @@ type SessionContext struct {
 	ConsumerLocation market.Location
 	HermesID         common.Address
 	Proposal         market.ServiceProposal
+	// Interface and ConsumerTunnelIP identify session traffic on provider side,
+	// they are known once session is configured by a service with a tunnel per consumer.
+	Interface        string
+	ConsumerTunnelIP net.IP
 }
@@ -36,8 +36,18 @@ const (
 	AppTopicInvoicePaid = "invoice_paid"
 	// AppTopicSettlementRequest forces the settlement of promises for given provider/hermes.
 	AppTopicSettlementRequest = "settlement_request"
+	// AppTopicSettlementComplete represents the outcome of promise settlement for given provider/hermes.
+	AppTopicSettlementComplete = "settlement_complete"
 )
 
+// AppEventSettlementComplete represents the payload that is sent on the AppTopicSettlementComplete topic.
+type AppEventSettlementComplete struct {
+	HermesID   common.Address
+	ProviderID identity.Identity
+	Amount     *big.Int
+	Error      error
+}
+
 // AppEventSettlementRequest represents the payload that is sent on the AppTopicSettlementRequest topic.
 type AppEventSettlementRequest struct {
 	HermesID   common.Address
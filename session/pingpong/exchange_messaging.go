@@ -19,12 +19,10 @@ package pingpong
 
 import (
 	"context"
-	"time"
 
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/payments/crypto"
-	"github.com/rs/zerolog/log"
 )
 
 const bigIntBase int = 10
@@ -36,13 +34,13 @@ type ExchangeRequest struct {
 
 // ExchangeSender is responsible for sending the exchange messages.
 type ExchangeSender struct {
-	ch p2p.ChannelSender
+	client *p2p.PaymentClient
 }
 
 // NewExchangeSender returns a new instance of exchange message sender.
 func NewExchangeSender(ch p2p.ChannelSender) *ExchangeSender {
 	return &ExchangeSender{
-		ch: ch,
+		client: p2p.NewPaymentClient(ch),
 	}
 }
 
@@ -63,10 +61,5 @@ func (es *ExchangeSender) Send(em crypto.ExchangeMessage) error {
 		Signature:      em.Signature,
 		HermesID:       em.HermesID,
 	}
-	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicPaymentMessage, pMessage.String())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-	_, err := es.ch.Send(ctx, p2p.TopicPaymentMessage, p2p.ProtoMessage(pMessage))
-	return err
+	return es.client.ExchangeMessage(context.Background(), pMessage)
 }
@@ -18,7 +18,7 @@
 package pingpong
 
 import (
-	"fmt"
+	"context"
 	"math/big"
 	"time"
 
@@ -35,7 +35,6 @@ import (
 	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/mbtime"
 	"github.com/mysteriumnetwork/payments/crypto"
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -186,24 +185,18 @@ func ExchangeFactoryFunc(
 func invoiceReceiver(channel p2p.ChannelHandler) (chan crypto.Invoice, error) {
 	invoices := make(chan crypto.Invoice)
 
-	channel.Handle(p2p.TopicPaymentInvoice, func(c p2p.Context) error {
-		var msg pb.Invoice
-		if err := c.Request().UnmarshalProto(&msg); err != nil {
-			return err
-		}
-		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicPaymentInvoice, msg.String())
-
+	p2p.HandlePaymentInvoice(channel, func(_ context.Context, msg *pb.Invoice) error {
 		agreementID, ok := new(big.Int).SetString(msg.GetAgreementID(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementID of value %v", agreementID)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field agreementID of value %v", agreementID)
 		}
 		agreementTotal, ok := new(big.Int).SetString(msg.GetAgreementTotal(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementTotal of value %v", agreementTotal)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field agreementTotal of value %v", agreementTotal)
 		}
 		transactorFee, ok := new(big.Int).SetString(msg.GetTransactorFee(), bigIntBase)
 		if !ok {
-			return fmt.Errorf("could not unmarshal field transactorFee of value %v", transactorFee)
+			return p2p.Errorf(pb.ErrorCode_INVALID_ARGUMENT, "could not unmarshal field transactorFee of value %v", transactorFee)
 		}
 
 		invoices <- crypto.Invoice{
@@ -501,6 +501,7 @@ func (aps *hermesPromiseSettler) settle(
 			}
 
 			log.Info().Msgf("Settling complete for provider %v", provider)
+			aps.publishSettlement(provider, hermesID, info.Amount, nil)
 
 			channelID, err := crypto.GenerateProviderChannelID(provider.Address, hermesID.Hex())
 			if err != nil {
@@ -535,6 +536,7 @@ func (aps *hermesPromiseSettler) settle(
 			return
 		case <-time.After(aps.config.MaxWaitForSettlement):
 			log.Info().Msgf("Settle timeout for %v", provider)
+			aps.publishSettlement(provider, hermesID, nil, ErrSettleTimeout)
 
 			// send a signal to waiter that the settlement has timed out
 			errCh <- ErrSettleTimeout
@@ -546,12 +548,22 @@ func (aps *hermesPromiseSettler) settle(
 	if err != nil {
 		cancel()
 		log.Error().Err(err).Msgf("Could not settle promise for %v", provider)
+		aps.publishSettlement(provider, hermesID, nil, err)
 		return err
 	}
 
 	return <-errCh
 }
 
+func (aps *hermesPromiseSettler) publishSettlement(provider identity.Identity, hermesID common.Address, amount *big.Int, err error) {
+	aps.eventBus.Publish(event.AppTopicSettlementComplete, event.AppEventSettlementComplete{
+		HermesID:   hermesID,
+		ProviderID: provider,
+		Amount:     amount,
+		Error:      err,
+	})
+}
+
 func (aps *hermesPromiseSettler) isSettling(id identity.Identity) bool {
 	aps.lock.RLock()
 	defer aps.lock.RUnlock()
@@ -19,12 +19,10 @@ package pingpong
 
 import (
 	"context"
-	"time"
 
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/payments/crypto"
-	"github.com/rs/zerolog/log"
 )
 
 // InvoiceRequest structure represents the invoice message that the provider sends to the consumer.
@@ -34,13 +32,13 @@ type InvoiceRequest struct {
 
 // InvoiceSender is responsible for sending the invoice messages.
 type InvoiceSender struct {
-	ch p2p.ChannelSender
+	client *p2p.PaymentClient
 }
 
 // NewInvoiceSender returns a new instance of the invoice sender.
 func NewInvoiceSender(ch p2p.ChannelSender) *InvoiceSender {
 	return &InvoiceSender{
-		ch: ch,
+		client: p2p.NewPaymentClient(ch),
 	}
 }
 
@@ -53,9 +51,5 @@ func (is *InvoiceSender) Send(invoice crypto.Invoice) error {
 		Hashlock:       invoice.Hashlock,
 		Provider:       invoice.Provider,
 	}
-	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicPaymentInvoice, pInvoice.String())
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_, err := is.ch.Send(ctx, p2p.TopicPaymentInvoice, p2p.ProtoMessage(pInvoice))
-	return err
+	return is.client.Invoice(context.Background(), pInvoice)
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -81,6 +82,8 @@ type InvoicePayer struct {
 
 	dataTransferred     DataTransferred
 	dataTransferredLock sync.Mutex
+
+	sessionIDLock sync.RWMutex
 }
 
 type hashSigner interface {
@@ -262,7 +265,7 @@ func (ip *InvoicePayer) issueExchangeMessage(invoice crypto.Invoice) error {
 
 	ip.deps.EventBus.Publish(event.AppTopicInvoicePaid, event.AppEventInvoicePaid{
 		ConsumerID: ip.deps.Identity,
-		SessionID:  ip.deps.SessionID,
+		SessionID:  ip.getSessionID(),
 		Invoice:    invoice,
 	})
 
@@ -281,10 +284,19 @@ func (ip *InvoicePayer) Stop() {
 }
 
 func (ip *InvoicePayer) consumeDataTransferredEvent(e connectionstate.AppEventConnectionStatistics) {
+	stats := e.Stats
+	// Consumer may hold several connections at once, only statistics of our session are counted.
+	if sessionID := ip.getSessionID(); sessionID != "" {
+		var ok bool
+		if stats, ok = e.SessionStatistics(session.ID(sessionID)); !ok {
+			return
+		}
+	}
+
 	// From a server perspective, bytes up are the actual bytes the client downloaded(aka the bytes we pushed to the consumer)
 	// To lessen the confusion, I suggest having the bytes reversed on the session instance.
 	// This way, the session will show that it downloaded the bytes in a manner that is easier to comprehend.
-	ip.updateDataTransfer(e.Stats.BytesSent, e.Stats.BytesReceived)
+	ip.updateDataTransfer(stats.BytesSent, stats.BytesReceived)
 }
 
 func (ip *InvoicePayer) updateDataTransfer(up, down uint64) {
@@ -316,5 +328,15 @@ func (ip *InvoicePayer) getDataTransferred() DataTransferred {
 
 // SetSessionID updates invoice payer dependencies to set session ID once session established.
 func (ip *InvoicePayer) SetSessionID(sessionID string) {
+	ip.sessionIDLock.Lock()
+	defer ip.sessionIDLock.Unlock()
+
 	ip.deps.SessionID = sessionID
 }
+
+func (ip *InvoicePayer) getSessionID() string {
+	ip.sessionIDLock.RLock()
+	defer ip.sessionIDLock.RUnlock()
+
+	return ip.deps.SessionID
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
@@ -964,3 +965,26 @@ func Test_estimateInvoiceTolerance(t *testing.T) {
 		})
 	}
 }
+
+func TestInvoicePayer_consumeDataTransferredEvent(t *testing.T) {
+	ip := NewInvoicePayer(InvoicePayerDeps{})
+	ip.SetSessionID("hop-session")
+
+	ip.consumeDataTransferredEvent(connectionstate.AppEventConnectionStatistics{
+		Stats:       connectionstate.Statistics{BytesSent: 100, BytesReceived: 200},
+		SessionInfo: connectionstate.Status{SessionID: "other-session"},
+	})
+	assert.Equal(t, DataTransferred{}, ip.getDataTransferred())
+
+	ip.consumeDataTransferredEvent(connectionstate.AppEventConnectionStatistics{
+		Stats: connectionstate.Statistics{BytesSent: 100, BytesReceived: 200},
+		SessionInfo: connectionstate.Status{
+			SessionID: "entry-session",
+			Hops: []connectionstate.HopStatus{
+				{SessionID: "entry-session"},
+				{SessionID: "hop-session", Statistics: connectionstate.Statistics{BytesSent: 10, BytesReceived: 20}},
+			},
+		},
+	})
+	assert.Equal(t, DataTransferred{Up: 10, Down: 20}, ip.getDataTransferred())
+}
@@ -25,4 +25,5 @@ const (
 	commandWgUp    = "wg-up"
 	commandWgDown  = "wg-down"
 	commandWgStats = "wg-stats"
+	commandWgRoute = "wg-route"
 )
@@ -102,6 +102,14 @@ func (d *Daemon) dialog(conn io.ReadWriter) {
 			} else {
 				answer.ok(stats)
 			}
+		case commandWgRoute:
+			err := d.wgRoute(cmd...)
+			if err != nil {
+				log.Err(err).Msgf("%s failed", commandWgRoute)
+				answer.err(err)
+			} else {
+				answer.ok()
+			}
 		case commandKill:
 			if err := d.killMyst(); err != nil {
 				log.Err(err).Msgf("%s failed", commandKill)
@@ -162,6 +170,32 @@ func (d *Daemon) wgDown(args ...string) (err error) {
 	return nil
 }
 
+func (d *Daemon) wgRoute(args ...string) error {
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	interfaceName := flags.String("iface", "", "")
+	include := flags.String("include", "", "Comma separated networks routed through the tunnel")
+	exclude := flags.String("exclude", "", "Comma separated networks routed outside of the tunnel")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *interfaceName == "" {
+		return errors.New("-iface is required")
+	}
+
+	err := d.monitor.AddRoutes(*interfaceName, splitList(*include), splitList(*exclude))
+	if err != nil {
+		return fmt.Errorf("could not add routes for %s interface: %w", *interfaceName, err)
+	}
+	return nil
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
 func (d *Daemon) wgStats(args ...string) (string, error) {
 	flags := flag.NewFlagSet("", flag.ContinueOnError)
 	interfaceName := flags.String("iface", "", "")
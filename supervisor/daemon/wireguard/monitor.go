@@ -70,6 +70,19 @@ func (m *Monitor) Down(interfaceName string) error {
 	return nil
 }
 
+// AddRoutes requests interface routes update.
+func (m *Monitor) AddRoutes(interfaceName string, include, exclude []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	iface, ok := m.interfaces[interfaceName]
+	if !ok {
+		return fmt.Errorf("interface %s not found", interfaceName)
+	}
+
+	return iface.AddRoutes(include, exclude)
+}
+
 // Stats requests interface statistics.
 func (m *Monitor) Stats(interfaceName string) (*wgcfg.Stats, error) {
 	m.mu.Lock()
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/mysteriumnetwork/node/services/wireguard/connection/dns"
+	"github.com/mysteriumnetwork/node/services/wireguard/connection/routes"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 	"github.com/mysteriumnetwork/node/utils/netutil"
 	"github.com/rs/zerolog/log"
@@ -34,6 +35,7 @@ import (
 type WgInterface struct {
 	Name       string
 	Device     *device.Device
+	peer       wgcfg.Peer
 	uapi       net.Listener
 	dnsManager dns.Manager
 }
@@ -81,6 +83,7 @@ func New(cfg wgcfg.DeviceConfig, uid string) (*WgInterface, error) {
 	wgInterface := &WgInterface{
 		Name:       interfaceName,
 		Device:     wgDevice,
+		peer:       cfg.Peer,
 		uapi:       uapi,
 		dnsManager: dnsManager,
 	}
@@ -118,6 +121,17 @@ func down(uapi net.Listener, d *device.Device, dnsManager dns.Manager) {
 	}
 }
 
+// AddRoutes routes included networks through the tunnel, adding them to peer allowed IPs,
+// and excluded networks outside of it.
+func (a *WgInterface) AddRoutes(include, exclude []string) error {
+	if len(include) > 0 {
+		if err := a.Device.IpcSetOperation(bufio.NewReader(strings.NewReader(a.peer.EncodeAllowedIPs(include)))); err != nil {
+			return fmt.Errorf("could not add allowed IPs: %w", err)
+		}
+	}
+	return routes.Add(a.Name, include, exclude)
+}
+
 // Down closes device and user space api socket.
 func (a *WgInterface) Down() {
 	down(a.uapi, a.Device, a.dnsManager)
@@ -128,13 +142,8 @@ func configureNetwork(cfg wgcfg.DeviceConfig, dnsManager dns.Manager) error {
 		return fmt.Errorf("failed to assign IP address: %w", err)
 	}
 
-	if cfg.Peer.Endpoint != nil {
-		if err := netutil.ExcludeRoute(cfg.Peer.Endpoint.IP); err != nil {
-			return fmt.Errorf("could not exclude route %s: %w", cfg.Peer.Endpoint.IP.String(), err)
-		}
-		if err := netutil.AddDefaultRoute(cfg.IfaceName); err != nil {
-			return fmt.Errorf("could not add default route for %s: %w", cfg.IfaceName, err)
-		}
+	if err := routes.Configure(cfg); err != nil {
+		return err
 	}
 
 	if err := dnsManager.Set(dns.Config{
@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -190,6 +191,18 @@ func (client *Client) ConnectionStatistics() (statistics contract.ConnectionStat
 	return statistics, err
 }
 
+// ConnectionProbe measures quality of current connection, throughput is measured only when asked
+func (client *Client) ConnectionProbe(throughput bool) (probe contract.ConnectionProbeDTO, err error) {
+	response, err := client.http.Post("connection/probe", contract.ConnectionProbeRequest{Throughput: throughput})
+	if err != nil {
+		return probe, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &probe)
+	return probe, err
+}
+
 // ConnectionStatus returns connection status
 func (client *Client) ConnectionStatus() (status contract.ConnectionStatusDTO, err error) {
 	response, err := client.http.Get("connection", url.Values{})
@@ -262,6 +275,22 @@ func (client *Client) Proposals() ([]contract.ProposalDTO, error) {
 	return client.proposals(url.Values{})
 }
 
+// ProposalsByQuery returns proposals matching the query expression within the price bounds, ordered by sort fields.
+func (client *Client) ProposalsByQuery(query ProposalsQuery) ([]contract.ProposalDTO, error) {
+	values := priceBoundValues(query.LowerTimePriceBound, query.UpperTimePriceBound, query.LowerGBPriceBound, query.UpperGBPriceBound)
+	values.Add("query", query.Query)
+	if query.Sort != "" {
+		values.Add("sort", query.Sort)
+	}
+	if query.Offset > 0 {
+		values.Add("offset", strconv.Itoa(query.Offset))
+	}
+	if query.Limit > 0 {
+		values.Add("limit", strconv.Itoa(query.Limit))
+	}
+	return client.proposals(values)
+}
+
 func (client *Client) proposals(query url.Values) ([]contract.ProposalDTO, error) {
 	response, err := client.http.Get("proposals", query)
 	if err != nil {
@@ -276,12 +305,16 @@ func (client *Client) proposals(query url.Values) ([]contract.ProposalDTO, error
 
 // ProposalsByPrice returns all available proposals within the given price range
 func (client *Client) ProposalsByPrice(lowerTime, upperTime, lowerGB, upperGB *big.Int) ([]contract.ProposalDTO, error) {
+	return client.proposals(priceBoundValues(lowerTime, upperTime, lowerGB, upperGB))
+}
+
+func priceBoundValues(lowerTime, upperTime, lowerGB, upperGB *big.Int) url.Values {
 	values := url.Values{}
 	values.Add("upper_time_price_bound", fmt.Sprintf("%v", upperTime))
 	values.Add("lower_time_price_bound", fmt.Sprintf("%v", lowerTime))
 	values.Add("upper_gb_price_bound", fmt.Sprintf("%v", upperGB))
 	values.Add("lower_gb_price_bound", fmt.Sprintf("%v", lowerGB))
-	return client.proposals(values)
+	return values
 }
 
 // Unlock allows using identity in following commands
@@ -413,6 +446,18 @@ func (client *Client) NATStatus() (status contract.NATStatusDTO, err error) {
 	return status, err
 }
 
+// NATType returns type of NAT the node is behind
+func (client *Client) NATType() (natType contract.NATTypeDTO, err error) {
+	response, err := client.http.Get("nat/type", nil)
+	if err != nil {
+		return natType, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &natType)
+	return natType, err
+}
+
 // filterSessionsByType removes all sessions of irrelevant types
 func filterSessionsByType(serviceType string, sessions contract.ListSessionsResponse) contract.ListSessionsResponse {
 	matches := 0
@@ -45,6 +45,19 @@ type SettleWithBeneficiaryRequest struct {
 	Beneficiary string `json:"beneficiary"`
 }
 
+// ProposalsQuery represents the request of proposals matching the query expression within the price bounds.
+// Zero offset and limit return proposals from the start without limit.
+type ProposalsQuery struct {
+	Query               string
+	Sort                string
+	Offset              int
+	Limit               int
+	LowerTimePriceBound *big.Int
+	UpperTimePriceBound *big.Int
+	LowerGBPriceBound   *big.Int
+	UpperGBPriceBound   *big.Int
+}
+
 // DecreaseStakeRequest represents the decrease stake request.
 type DecreaseStakeRequest struct {
 	ID            string   `json:"id,omitempty"`
@@ -19,6 +19,7 @@ package contract
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/consumer/bandwidth"
@@ -34,9 +35,11 @@ var emptyAddress = common.Address{}
 // NewConnectionStatusDTO maps to API connection status.
 func NewConnectionStatusDTO(session connectionstate.Status) ConnectionStatusDTO {
 	response := ConnectionStatusDTO{
-		Status:     string(session.State),
-		ConsumerID: session.ConsumerID.Address,
-		SessionID:  string(session.SessionID),
+		ConnectionID: session.ConnectionID,
+		Status:       string(session.State),
+		ConsumerID:   session.ConsumerID.Address,
+		SessionID:    string(session.SessionID),
+		Interface:    session.Interface,
 	}
 	if session.HermesID != emptyAddress {
 		response.HermesID = session.HermesID.Hex()
@@ -46,12 +49,23 @@ func NewConnectionStatusDTO(session connectionstate.Status) ConnectionStatusDTO
 		proposalRes := NewProposalDTO(session.Proposal)
 		response.Proposal = &proposalRes
 	}
+	for _, hop := range session.Hops {
+		response.Hops = append(response.Hops, ConnectionHopStatusDTO{
+			Proposal:      NewProposalDTO(hop.Proposal),
+			SessionID:     string(hop.SessionID),
+			BytesSent:     hop.Statistics.BytesSent,
+			BytesReceived: hop.Statistics.BytesReceived,
+		})
+	}
 	return response
 }
 
 // ConnectionStatusDTO holds partial consumer connection details.
 // swagger:model ConnectionStatusDTO
 type ConnectionStatusDTO struct {
+	// example: default
+	ConnectionID string `json:"connection_id,omitempty"`
+
 	// example: Connected
 	Status string `json:"status"`
 
@@ -66,15 +80,52 @@ type ConnectionStatusDTO struct {
 
 	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
 	SessionID string `json:"session_id,omitempty"`
+
+	// hops of a multi-hop connection, starting with the entry one
+	Hops []ConnectionHopStatusDTO `json:"hops,omitempty"`
+
+	// network interface carrying the connection traffic
+	// example: myst1
+	Interface string `json:"interface,omitempty"`
+}
+
+// ListConnectionsResponse holds statuses of all consumer connections.
+// swagger:model ListConnectionsResponse
+type ListConnectionsResponse struct {
+	Connections []ConnectionStatusDTO `json:"connections"`
+}
+
+// NewListConnectionsResponse maps to API connection list.
+func NewListConnectionsResponse(statuses []connectionstate.Status) ListConnectionsResponse {
+	response := ListConnectionsResponse{Connections: []ConnectionStatusDTO{}}
+	for _, status := range statuses {
+		response.Connections = append(response.Connections, NewConnectionStatusDTO(status))
+	}
+	return response
+}
+
+// ConnectionHopStatusDTO holds details of a single multi-hop connection hop.
+// swagger:model ConnectionHopStatusDTO
+type ConnectionHopStatusDTO struct {
+	Proposal ProposalDTO `json:"proposal"`
+
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id,omitempty"`
+
+	// example: 1024
+	BytesSent uint64 `json:"bytes_sent"`
+
+	// example: 1024
+	BytesReceived uint64 `json:"bytes_received"`
 }
 
 // NewConnectionDTO maps to API connection.
-func NewConnectionDTO(session connectionstate.Status, statistics connectionstate.Statistics, throughput bandwidth.Throughput, invoice crypto.Invoice) ConnectionDTO {
+func NewConnectionDTO(session connectionstate.Status, statistics connectionstate.Statistics, throughput bandwidth.Throughput, invoice crypto.Invoice, probe connectionstate.ProbeResult) ConnectionDTO {
 	dto := ConnectionDTO{
 		ConnectionStatusDTO: NewConnectionStatusDTO(session),
 	}
 	if !statistics.At.IsZero() {
-		statsDto := NewConnectionStatisticsDTO(session, statistics, throughput, invoice)
+		statsDto := NewConnectionStatisticsDTO(session, statistics, throughput, invoice, probe)
 		dto.Statistics = &statsDto
 	}
 	return dto
@@ -88,12 +139,12 @@ type ConnectionDTO struct {
 }
 
 // NewConnectionStatisticsDTO maps to API connection stats.
-func NewConnectionStatisticsDTO(session connectionstate.Status, statistics connectionstate.Statistics, throughput bandwidth.Throughput, invoice crypto.Invoice) ConnectionStatisticsDTO {
+func NewConnectionStatisticsDTO(session connectionstate.Status, statistics connectionstate.Statistics, throughput bandwidth.Throughput, invoice crypto.Invoice, probe connectionstate.ProbeResult) ConnectionStatisticsDTO {
 	agreementTotal := new(big.Int)
 	if invoice.AgreementTotal != nil {
 		agreementTotal = invoice.AgreementTotal
 	}
-	return ConnectionStatisticsDTO{
+	dto := ConnectionStatisticsDTO{
 		Duration:           int(session.Duration().Seconds()),
 		BytesSent:          statistics.BytesSent,
 		BytesReceived:      statistics.BytesReceived,
@@ -101,6 +152,131 @@ func NewConnectionStatisticsDTO(session connectionstate.Status, statistics conne
 		ThroughputReceived: datasize.BitSize(throughput.Down).Bits(),
 		TokensSpent:        agreementTotal,
 	}
+	if session.Quota != nil {
+		quota := NewConnectionQuotaDTO(*session.Quota)
+		dto.Quota = &quota
+	}
+	if !probe.At.IsZero() {
+		probeDto := NewConnectionProbeDTO(probe)
+		dto.Probe = &probeDto
+	}
+	return dto
+}
+
+// NewConnectionProbeDTO maps to API connection probe result.
+func NewConnectionProbeDTO(probe connectionstate.ProbeResult) ConnectionProbeDTO {
+	return ConnectionProbeDTO{
+		MeasuredAt:         probe.At.Format(time.RFC3339),
+		Latency:            probe.Latency.Milliseconds(),
+		Jitter:             probe.Jitter.Milliseconds(),
+		PacketLoss:         probe.PacketLoss,
+		ThroughputDownload: datasize.BitSize(probe.Download).Bits(),
+		ThroughputUpload:   datasize.BitSize(probe.Upload).Bits(),
+	}
+}
+
+// ConnectionProbeDTO holds connection quality measured by the latest probe.
+// swagger:model ConnectionProbeDTO
+type ConnectionProbeDTO struct {
+	// example: 2020-11-04T16:03:21Z
+	MeasuredAt string `json:"measured_at"`
+
+	// average round trip time in milliseconds
+	// example: 42
+	Latency int64 `json:"latency"`
+
+	// average round trip time variation in milliseconds
+	// example: 3
+	Jitter int64 `json:"jitter"`
+
+	// fraction of probe pings left without a response
+	// example: 0.1
+	PacketLoss float64 `json:"packet_loss"`
+
+	// download speed in bits per second, set only by throughput test
+	// example: 10485760
+	ThroughputDownload uint64 `json:"throughput_download,omitempty"`
+
+	// upload speed in bits per second, set only by throughput test
+	// example: 10485760
+	ThroughputUpload uint64 `json:"throughput_upload,omitempty"`
+}
+
+// ConnectionProbeRequest request used to probe the established connection.
+// swagger:model ConnectionProbeRequestDTO
+type ConnectionProbeRequest struct {
+	// measure throughput in addition to latency, it transfers data for several seconds
+	// required: false
+	// example: true
+	Throughput bool `json:"throughput"`
+}
+
+// NewConnectionQuotaDTO maps to API remaining connection quota, only limited quotas are set.
+func NewConnectionQuotaDTO(quota connectionstate.QuotaStatus) ConnectionQuotaDTO {
+	var dto ConnectionQuotaDTO
+	if quota.BytesLimit > 0 {
+		remaining := quota.RemainingBytes()
+		dto.BytesRemaining = &remaining
+	}
+	if quota.DurationLimit > 0 {
+		remaining := int(quota.RemainingDuration().Seconds())
+		dto.DurationRemaining = &remaining
+	}
+	if quota.TokensLimit != nil && quota.TokensLimit.Sign() > 0 {
+		dto.TokensRemaining = quota.RemainingTokens()
+	}
+	return dto
+}
+
+// ConnectionQuotaDTO holds quota left until the connection is disconnected.
+// swagger:model ConnectionQuotaDTO
+type ConnectionQuotaDTO struct {
+	// bytes left to send and receive
+	// example: 1073741824
+	BytesRemaining *uint64 `json:"bytes_remaining,omitempty"`
+
+	// connection time left in seconds
+	// example: 600
+	DurationRemaining *int `json:"duration_remaining,omitempty"`
+
+	// tokens left to spend
+	// example: 500000
+	TokensRemaining *big.Int `json:"tokens_remaining,omitempty"`
+}
+
+// NewConnectionQuotaEventDTO maps to API connection quota event.
+func NewConnectionQuotaEventDTO(event connectionstate.AppEventConnectionQuota) ConnectionQuotaEventDTO {
+	return ConnectionQuotaEventDTO{
+		ConnectionID: event.SessionInfo.ConnectionID,
+		SessionID:    string(event.SessionInfo.SessionID),
+		Kind:         string(event.Kind),
+		Threshold:    event.Threshold,
+		Exhausted:    event.Exhausted,
+		Quota:        NewConnectionQuotaDTO(event.Quota),
+	}
+}
+
+// ConnectionQuotaEventDTO describes a crossed quota warning threshold, or an exhausted quota.
+// swagger:model ConnectionQuotaEventDTO
+type ConnectionQuotaEventDTO struct {
+	// example: default
+	ConnectionID string `json:"connection_id,omitempty"`
+
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id,omitempty"`
+
+	// quota kind, one of "bytes", "duration" or "tokens"
+	// example: bytes
+	Kind string `json:"kind"`
+
+	// used fraction of quota
+	// example: 0.8
+	Threshold float64 `json:"threshold"`
+
+	// set once quota is exhausted and connection is being disconnected
+	Exhausted bool `json:"exhausted"`
+
+	Quota ConnectionQuotaDTO `json:"quota"`
 }
 
 // ConnectionStatisticsDTO holds consumer connection statistics.
@@ -126,6 +302,12 @@ type ConnectionStatisticsDTO struct {
 
 	// example: 500000
 	TokensSpent *big.Int `json:"tokens_spent"`
+
+	// quota left, set only when connection has quota limits
+	Quota *ConnectionQuotaDTO `json:"quota,omitempty"`
+
+	// latest connection probe result, set only once connection was probed
+	Probe *ConnectionProbeDTO `json:"probe,omitempty"`
 }
 
 // ConnectionCreateRequest request used to start a connection.
@@ -154,6 +336,57 @@ type ConnectionCreateRequest struct {
 	// connect options
 	// required: false
 	ConnectOptions ConnectOptions `json:"connect_options,omitempty"`
+
+	// ordered list of hops for a multi-hop connection, the first one being the entry and the last one being the exit hop.
+	// When given, provider_id and service_type fields are ignored.
+	// required: false
+	Hops []ConnectionHopRequest `json:"hops,omitempty"`
+
+	// lets node pick the best scored proposal matching the filter itself.
+	// When set, provider_id and service_type fields are ignored.
+	// required: false
+	// example: true
+	Auto bool `json:"auto,omitempty"`
+
+	// filter and preferences of automatically picked proposals
+	// required: false
+	Filter *AutoConnectFilter `json:"filter,omitempty"`
+}
+
+// AutoConnectFilter holds filter and preferences of automatically picked proposals
+// swagger:model AutoConnectFilterDTO
+type AutoConnectFilter struct {
+	// example: wireguard
+	ServiceType string `json:"service_type,omitempty"`
+	// example: residential
+	LocationType string `json:"location_type,omitempty"`
+	// example: mysterium
+	AccessPolicyID string `json:"access_policy_id,omitempty"`
+	// example: mysterium
+	AccessPolicySource string `json:"access_policy_source,omitempty"`
+	// preferred provider countries, the first one being the most preferred
+	// example: ["DE", "NL"]
+	Countries []string `json:"countries,omitempty"`
+	// maximum price per GiB
+	// example: 100000000000000000
+	PriceGiBMax *big.Int `json:"price_gib_max,omitempty"`
+	// maximum price per minute
+	// example: 1000000000000000
+	PriceMinuteMax *big.Int `json:"price_minute_max,omitempty"`
+}
+
+// ConnectionHopRequest describes a single hop of multi-hop connection.
+// swagger:model ConnectionHopRequestDTO
+type ConnectionHopRequest struct {
+	// provider identity
+	// required: true
+	// example: 0x0000000000000000000000000000000000000002
+	ProviderID string `json:"provider_id"`
+
+	// service type, only "wireguard" connections can be used as non-entry hops
+	// required: false
+	// example: wireguard
+	ServiceType string `json:"service_type"`
 }
 
 // Validate validates fields in request
@@ -162,9 +395,41 @@ func (cr ConnectionCreateRequest) Validate() *validation.FieldErrorMap {
 	if len(cr.ConsumerID) == 0 {
 		errs.ForField("consumer_id").AddError("required", "Field is required")
 	}
-	if len(cr.ProviderID) == 0 {
+	if !cr.Auto && len(cr.Hops) == 0 && len(cr.ProviderID) == 0 {
 		errs.ForField("provider_id").AddError("required", "Field is required")
 	}
+	if cr.Auto && len(cr.Hops) > 0 {
+		errs.ForField("hops").AddError("invalid", "Automatic proposal selection does not support multi-hop connections")
+	}
+	if !cr.Auto && cr.Filter != nil {
+		errs.ForField("filter").AddError("invalid", "Filter is used only with automatic proposal selection")
+	}
+	if len(cr.Hops) == 1 {
+		errs.ForField("hops").AddError("invalid", "Multi-hop connection requires at least 2 hops")
+	}
+	for _, hop := range cr.Hops {
+		if len(hop.ProviderID) == 0 {
+			errs.ForField("hops").AddError("required", "Hop provider_id is required")
+			break
+		}
+	}
+	splitTunnel := connection.SplitTunnel{
+		Include: cr.ConnectOptions.SplitTunnelInclude,
+		Exclude: cr.ConnectOptions.SplitTunnelExclude,
+	}
+	if err := splitTunnel.Validate(); err != nil {
+		errs.ForField("connect_options").AddError("invalid", err.Error())
+	} else if len(cr.Hops) > 1 && splitTunnel.Enabled() {
+		errs.ForField("connect_options").AddError("invalid", connection.ErrSplitTunnelMultiHop.Error())
+	}
+	if len(cr.Hops) > 1 && cr.ConnectOptions.Failover != nil {
+		errs.ForField("connect_options").AddError("invalid", connection.ErrFailoverMultiHop.Error())
+	}
+	if cr.ConnectOptions.Quota != nil {
+		if err := cr.ConnectOptions.Quota.ToQuota().Validate(); err != nil {
+			errs.ForField("connect_options").AddError("invalid", err.Error())
+		}
+	}
 	return errs
 }
 
@@ -180,4 +445,93 @@ type ConnectOptions struct {
 	// default: auto
 	// example: auto, provider, system, "1.1.1.1,8.8.8.8"
 	DNS connection.DNSOption `json:"dns"`
+	// where to reconnect when the active session dies, not supported for multi-hop connections
+	// required: false
+	Failover *FailoverOptions `json:"failover,omitempty"`
+	// CIDRs, IPs or DNS names to route through the tunnel, everything else is routed directly
+	// required: false
+	// example: ["10.0.0.0/8", "example.com"]
+	SplitTunnelInclude []string `json:"split_tunnel_include,omitempty"`
+	// CIDRs, IPs or DNS names to route outside of the tunnel
+	// required: false
+	// example: ["192.168.0.0/16", "1.1.1.1", "example.org"]
+	SplitTunnelExclude []string `json:"split_tunnel_exclude,omitempty"`
+	// usage limits, connection is disconnected once any of them is reached
+	// required: false
+	Quota *QuotaOptions `json:"quota,omitempty"`
+}
+
+// QuotaOptions holds tequilapi connection quota options, zero values mean unlimited
+// swagger:model QuotaOptionsDTO
+type QuotaOptions struct {
+	// bytes sent and received
+	// example: 2147483648
+	Bytes uint64 `json:"bytes,omitempty"`
+	// connection time in seconds
+	// example: 1800
+	Duration uint64 `json:"duration,omitempty"`
+	// tokens spent
+	// example: 50000000000000000
+	Tokens *big.Int `json:"tokens,omitempty"`
+	// used fractions of quota at which warnings are sent, 0.8 and 0.9 by default
+	// example: [0.5, 0.9]
+	WarningThresholds []float64 `json:"warning_thresholds,omitempty"`
+}
+
+// ToQuota maps to connection quota.
+func (qo QuotaOptions) ToQuota() connection.Quota {
+	return connection.Quota{
+		Bytes:             qo.Bytes,
+		Duration:          time.Duration(qo.Duration) * time.Second,
+		Tokens:            qo.Tokens,
+		WarningThresholds: qo.WarningThresholds,
+	}
+}
+
+// FailoverOptions holds tequilapi failover options
+// swagger:model FailoverOptionsDTO
+type FailoverOptions struct {
+	// proposals to fail over to, in order of preference
+	// required: false
+	Proposals []ConnectionHopRequest `json:"proposals,omitempty"`
+	// filter used to pick failover proposals from discovery once proposals are exhausted
+	// required: false
+	Filter *FailoverFilter `json:"filter,omitempty"`
+}
+
+// FailoverFilter holds filter of failover proposals picked from discovery
+// swagger:model FailoverFilterDTO
+type FailoverFilter struct {
+	// example: wireguard
+	ServiceType string `json:"service_type,omitempty"`
+	// example: residential
+	LocationType string `json:"location_type,omitempty"`
+	// example: mysterium
+	AccessPolicyID string `json:"access_policy_id,omitempty"`
+	// example: mysterium
+	AccessPolicySource string `json:"access_policy_source,omitempty"`
+}
+
+// NewConnectionFailoverDTO maps to API connection failover event.
+func NewConnectionFailoverDTO(event connectionstate.AppEventConnectionFailover) ConnectionFailoverDTO {
+	return ConnectionFailoverDTO{
+		From:   NewProposalDTO(event.From),
+		To:     NewProposalDTO(event.To),
+		Reason: event.Reason,
+		Error:  event.Error,
+	}
+}
+
+// ConnectionFailoverDTO describes a switch from a dead session to a failover proposal.
+// swagger:model ConnectionFailoverDTO
+type ConnectionFailoverDTO struct {
+	From ProposalDTO `json:"from"`
+	To   ProposalDTO `json:"to"`
+
+	// example: connection exited
+	Reason string `json:"reason"`
+
+	// set if connection to the failover proposal failed
+	// example: connection has failed
+	Error string `json:"error,omitempty"`
 }
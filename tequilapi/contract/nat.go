@@ -22,4 +22,15 @@ package contract
 type NATStatusDTO struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
+	// port mapping protocol used if port mapping succeeded
+	// example: natpmp
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// NATTypeDTO gives information about type of NAT the node is behind
+// swagger:model NATTypeDTO
+type NATTypeDTO struct {
+	// example: port-restricted
+	Type  string `json:"type"`
+	Error string `json:"error,omitempty"`
 }
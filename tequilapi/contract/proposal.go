@@ -34,6 +34,8 @@ func NewProposalDTO(p market.ServiceProposal) ProposalDTO {
 		ServiceDefinition: NewServiceDefinitionDTO(p.ServiceDefinition),
 		AccessPolicies:    p.AccessPolicies,
 		PaymentMethod:     NewPaymentMethodDTO(p.PaymentMethod),
+		Verified:          p.Verified,
+		Stale:             p.Stale,
 	}
 }
 
@@ -101,6 +103,14 @@ type ProposalDTO struct {
 
 	// PaymentMethod
 	PaymentMethod PaymentMethodDTO `json:"payment_method"`
+
+	// signature of the provider identity was verified by discovery
+	// example: true
+	Verified bool `json:"verified"`
+
+	// proposal is served from the local cache and was not confirmed by discovery since the node started
+	// example: false
+	Stale bool `json:"stale,omitempty"`
 }
 
 func (p ProposalDTO) String() string {
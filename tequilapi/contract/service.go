@@ -89,8 +89,9 @@ type ServiceInfoDTO struct {
 	ConnectionStatistics ServiceStatisticsDTO `json:"connection_statistics"`
 }
 
-// ServiceStatisticsDTO shows the successful and attempted connection count
+// ServiceStatisticsDTO shows the successful, attempted and rejected connection count
 type ServiceStatisticsDTO struct {
 	Attempted  int `json:"attempted"`
 	Successful int `json:"successful"`
+	Rejected   int `json:"rejected"`
 }
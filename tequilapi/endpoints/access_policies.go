@@ -18,11 +18,16 @@
 package endpoints
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/mysteriumnetwork/node/core/policy"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
+	"github.com/mysteriumnetwork/node/tequilapi/validation"
 )
 
 // swagger:model AccessPolicies
@@ -30,35 +35,61 @@ type accessPolicyCollection struct {
 	Entries []accessPolicy `json:"entries"`
 }
 
+// swagger:model AccessPolicy
 type accessPolicy struct {
 	ID          string       `json:"id"`
 	Title       string       `json:"title"`
 	Description string       `json:"description"`
 	Allow       []accessRule `json:"allow"`
+	Deny        []accessRule `json:"deny,omitempty"`
+	// Source tells whether policy is fetched from the policy Oracle or defined locally
+	// example: local
+	Source string `json:"source,omitempty"`
+}
+
+// swagger:model AccessPolicyRequest
+type accessPolicyRequest struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Allow       []accessRule `json:"allow"`
+	Deny        []accessRule `json:"deny,omitempty"`
 }
 
 type accessRule struct {
-	Type  string `json:"type"`
+	// example: ip_range
+	Type string `json:"type"`
+	// example: 10.0.0.0/8
 	Value string `json:"value"`
+	// Error describes why the rule is invalid, invalid rules are not enforced
+	Error string `json:"error,omitempty"`
+}
+
+type localPolicyStore interface {
+	List() []market.AccessPolicyRuleSet
+	Get(policyID string) (market.AccessPolicyRuleSet, error)
+	Save(rules market.AccessPolicyRuleSet) error
+	Delete(policyID string) error
 }
 
 type accessPoliciesEndpoint struct {
 	httpClient              *requests.HTTPClient
 	accessPolicyEndpointURL string
+	localPolicies           localPolicyStore
 }
 
 // NewAccessPoliciesEndpoint creates and returns access policies endpoint
-func NewAccessPoliciesEndpoint(httpClient *requests.HTTPClient, accessPolicyEndpointURL string) *accessPoliciesEndpoint {
+func NewAccessPoliciesEndpoint(httpClient *requests.HTTPClient, accessPolicyEndpointURL string, localPolicies localPolicyStore) *accessPoliciesEndpoint {
 	return &accessPoliciesEndpoint{
 		httpClient:              httpClient,
 		accessPolicyEndpointURL: accessPolicyEndpointURL,
+		localPolicies:           localPolicies,
 	}
 }
 
 // swagger:operation GET /access-policies AccessPolicies
 // ---
 // summary: Returns access policies
-// description: Returns list of access policies
+// description: Returns list of remote and local access policies, rules are validated and invalid ones are marked with an error
 // responses:
 //   200:
 //     description: List of access policies
@@ -81,11 +112,242 @@ func (ape *accessPoliciesEndpoint) List(resp http.ResponseWriter, req *http.Requ
 		return
 	}
 
+	for i := range r.Entries {
+		r.Entries[i].Source = policy.SourceRemote
+		validateAccessRules(r.Entries[i].Allow)
+		validateAccessRules(r.Entries[i].Deny)
+	}
+	if ape.localPolicies != nil {
+		for _, rules := range ape.localPolicies.List() {
+			r.Entries = append(r.Entries, toAccessPolicy(rules))
+		}
+	}
+
+	utils.WriteAsJSON(r, resp)
+}
+
+// swagger:operation GET /access-policies/local AccessPolicies ListLocalAccessPolicies
+// ---
+// summary: Returns local access policies
+// description: Returns list of access policies defined in the local policy files
+// responses:
+//   200:
+//     description: List of local access policies
+//     schema:
+//       "$ref": "#/definitions/AccessPolicies"
+func (ape *accessPoliciesEndpoint) ListLocal(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	r := accessPolicyCollection{Entries: []accessPolicy{}}
+	for _, rules := range ape.localPolicies.List() {
+		r.Entries = append(r.Entries, toAccessPolicy(rules))
+	}
+
 	utils.WriteAsJSON(r, resp)
 }
 
-// AddRoutesForAccessPolicies attaches access policies endpoints to router
-func AddRoutesForAccessPolicies(httpClient *requests.HTTPClient, router *httprouter.Router, accessPolicyEndpointURL string) {
-	ape := NewAccessPoliciesEndpoint(httpClient, accessPolicyEndpointURL)
+// swagger:operation GET /access-policies/local/{id} AccessPolicies GetLocalAccessPolicy
+// ---
+// summary: Returns local access policy
+// description: Returns access policy defined in the local policy file
+// parameters:
+// - name: id
+//   in: path
+//   description: access policy id
+//   type: string
+//   required: true
+// responses:
+//   200:
+//     description: Local access policy
+//     schema:
+//       "$ref": "#/definitions/AccessPolicy"
+//   404:
+//     description: Access policy not found
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ape *accessPoliciesEndpoint) GetLocal(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	rules, err := ape.localPolicies.Get(params.ByName("id"))
+	if err == policy.ErrLocalPolicyNotFound {
+		utils.SendError(resp, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.SendError(resp, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.WriteAsJSON(toAccessPolicy(rules), resp)
+}
+
+// swagger:operation PUT /access-policies/local/{id} AccessPolicies SaveLocalAccessPolicy
+// ---
+// summary: Creates or updates local access policy
+// description: Writes access policy to the local policy file, services using the policy pick up changes immediately
+// parameters:
+// - name: id
+//   in: path
+//   description: access policy id
+//   type: string
+//   required: true
+// - in: body
+//   name: body
+//   description: access policy rules
+//   schema:
+//     $ref: "#/definitions/AccessPolicyRequest"
+// responses:
+//   200:
+//     description: Saved access policy
+//     schema:
+//       "$ref": "#/definitions/AccessPolicy"
+//   400:
+//     description: Bad request
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   422:
+//     description: Parameters validation error
+//     schema:
+//       "$ref": "#/definitions/ValidationErrorDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ape *accessPoliciesEndpoint) SaveLocal(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	var policyReq accessPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&policyReq); err != nil {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+
+	rules := market.AccessPolicyRuleSet{
+		ID:          params.ByName("id"),
+		Title:       policyReq.Title,
+		Description: policyReq.Description,
+		Allow:       toMarketAccessRules(policyReq.Allow),
+		Deny:        toMarketAccessRules(policyReq.Deny),
+	}
+	if errorMap := validateLocalPolicy(rules); errorMap.HasErrors() {
+		utils.SendValidationErrorMessage(resp, errorMap)
+		return
+	}
+
+	if err := ape.localPolicies.Save(rules); err != nil {
+		utils.SendError(resp, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.WriteAsJSON(toAccessPolicy(rules), resp)
+}
+
+// swagger:operation DELETE /access-policies/local/{id} AccessPolicies DeleteLocalAccessPolicy
+// ---
+// summary: Deletes local access policy
+// description: Removes the local policy file, policies used by running services can not be deleted
+// parameters:
+// - name: id
+//   in: path
+//   description: access policy id
+//   type: string
+//   required: true
+// responses:
+//   202:
+//     description: Access policy deleted
+//   404:
+//     description: Access policy not found
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   409:
+//     description: Access policy is used by running services
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ape *accessPoliciesEndpoint) DeleteLocal(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	err := ape.localPolicies.Delete(params.ByName("id"))
+	switch err {
+	case nil:
+		resp.WriteHeader(http.StatusAccepted)
+	case policy.ErrLocalPolicyNotFound:
+		utils.SendError(resp, err, http.StatusNotFound)
+	case policy.ErrLocalPolicyInUse:
+		utils.SendError(resp, err, http.StatusConflict)
+	default:
+		utils.SendError(resp, err, http.StatusInternalServerError)
+	}
+}
+
+func validateLocalPolicy(rules market.AccessPolicyRuleSet) *validation.FieldErrorMap {
+	errorMap := validation.NewErrorMap()
+	if !policy.IsValidLocalPolicyID(rules.ID) {
+		errorMap.ForField("id").AddError("invalid", policy.ErrInvalidLocalPolicyID.Error())
+	}
+	validateRules := func(field string, list []market.AccessRule) {
+		for i, rule := range list {
+			if err := rule.Validate(); err != nil {
+				errorMap.ForField(fmt.Sprintf("%s[%d]", field, i)).AddError("invalid", err.Error())
+			}
+		}
+	}
+	validateRules("allow", rules.Allow)
+	validateRules("deny", rules.Deny)
+	return errorMap
+}
+
+func toAccessPolicy(rules market.AccessPolicyRuleSet) accessPolicy {
+	p := accessPolicy{
+		ID:          rules.ID,
+		Title:       rules.Title,
+		Description: rules.Description,
+		Allow:       toAccessRules(rules.Allow),
+		Deny:        toAccessRules(rules.Deny),
+		Source:      policy.SourceLocal,
+	}
+	validateAccessRules(p.Allow)
+	validateAccessRules(p.Deny)
+	return p
+}
+
+func toAccessRules(rules []market.AccessRule) []accessRule {
+	if rules == nil {
+		return nil
+	}
+	result := make([]accessRule, len(rules))
+	for i, rule := range rules {
+		result[i] = accessRule{Type: rule.Type, Value: rule.Value}
+	}
+	return result
+}
+
+func toMarketAccessRules(rules []accessRule) []market.AccessRule {
+	if rules == nil {
+		return nil
+	}
+	result := make([]market.AccessRule, len(rules))
+	for i, rule := range rules {
+		result[i] = market.AccessRule{Type: rule.Type, Value: rule.Value}
+	}
+	return result
+}
+
+func validateAccessRules(rules []accessRule) {
+	for i, rule := range rules {
+		if err := (market.AccessRule{Type: rule.Type, Value: rule.Value}).Validate(); err != nil {
+			rules[i].Error = err.Error()
+		}
+	}
+}
+
+// AddRoutesForAccessPolicies attaches access policies endpoints to router, local policy routes are added only when local policies are available
+func AddRoutesForAccessPolicies(httpClient *requests.HTTPClient, router *httprouter.Router, accessPolicyEndpointURL string, localPolicies *policy.LocalPolicies) {
+	var store localPolicyStore
+	if localPolicies != nil {
+		store = localPolicies
+	}
+	ape := NewAccessPoliciesEndpoint(httpClient, accessPolicyEndpointURL, store)
 	router.GET("/access-policies", ape.List)
+	if store != nil {
+		router.GET("/access-policies/local", ape.ListLocal)
+		router.GET("/access-policies/local/:id", ape.GetLocal)
+		router.PUT("/access-policies/local/:id", ape.SaveLocal)
+		router.DELETE("/access-policies/local/:id", ape.DeleteLocal)
+	}
 }
@@ -18,11 +18,17 @@
 package endpoints
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/mysteriumnetwork/node/core/policy"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,7 +55,7 @@ func Test_Get_AccessPolicies_ReturnsAccessPolicies(t *testing.T) {
 	server := newTestServer(http.StatusOK, mockResponse)
 
 	router := httprouter.New()
-	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL)
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL, nil)
 
 	req, err := http.NewRequest(
 		http.MethodGet,
@@ -62,14 +68,30 @@ func Test_Get_AccessPolicies_ReturnsAccessPolicies(t *testing.T) {
 	router.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusOK, resp.Code)
-	assert.JSONEq(t, mockResponse, resp.Body.String())
+	assert.JSONEq(t, `
+	{
+		"entries": [
+			{
+				"id": "mysterium",
+				"title": "Mysterium verified traffic",
+				"description": "Mysterium Network approved identities",
+				"allow": [
+					{
+						"type": "identity",
+						"value": "0xf4d6ffba09d460ebe10d24667770437981ce3de9"
+					}
+				],
+				"source": "remote"
+			}
+		]
+	}`, resp.Body.String())
 }
 
 func Test_Get_AccessPolicies_WhenRequestFails_ReturnsError(t *testing.T) {
 	server := newTestServer(http.StatusInternalServerError, `{"error": "something bad"}`)
 
 	router := httprouter.New()
-	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL)
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL, nil)
 
 	req, err := http.NewRequest(
 		http.MethodGet,
@@ -84,6 +106,178 @@ func Test_Get_AccessPolicies_WhenRequestFails_ReturnsError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.Code)
 }
 
+func Test_Get_AccessPolicies_ValidatesTrafficRules(t *testing.T) {
+	mockResponse := `
+	{
+		"entries": [
+			{
+				"id": "b2b",
+				"title": "B2B traffic",
+				"description": "HTTPS to internal network only",
+				"allow": [
+					{"type": "ip_range", "value": "10.0.0.0/8"},
+					{"type": "port", "value": "tcp/443"},
+					{"type": "port", "value": "smtp"}
+				],
+				"deny": [
+					{"type": "port", "value": "25"}
+				]
+			}
+		]
+	}`
+	server := newTestServer(http.StatusOK, mockResponse)
+
+	router := httprouter.New()
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL, nil)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/access-policies", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `
+	{
+		"entries": [
+			{
+				"id": "b2b",
+				"title": "B2B traffic",
+				"description": "HTTPS to internal network only",
+				"allow": [
+					{"type": "ip_range", "value": "10.0.0.0/8"},
+					{"type": "port", "value": "tcp/443"},
+					{"type": "port", "value": "smtp", "error": "invalid port rule \"smtp\": strconv.Atoi: parsing \"smtp\": invalid syntax"}
+				],
+				"deny": [
+					{"type": "port", "value": "25"}
+				],
+				"source": "remote"
+			}
+		]
+	}`, resp.Body.String())
+}
+
+func Test_Get_AccessPolicies_IncludesLocalPolicies(t *testing.T) {
+	server := newTestServer(http.StatusOK, `{"entries": []}`)
+	localPolicies, cleanup := newTestLocalPolicies(t)
+	defer cleanup()
+	assert.NoError(t, localPolicies.Save(market.AccessPolicyRuleSet{
+		ID:    "office",
+		Title: "Office",
+		Allow: []market.AccessRule{{Type: market.AccessPolicyTypeIPRange, Value: "10.0.0.0/8"}},
+	}))
+
+	router := httprouter.New()
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, server.URL, localPolicies)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/access-policies", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `
+	{
+		"entries": [
+			{
+				"id": "office",
+				"title": "Office",
+				"description": "",
+				"allow": [
+					{"type": "ip_range", "value": "10.0.0.0/8"}
+				],
+				"source": "local"
+			}
+		]
+	}`, resp.Body.String())
+}
+
+func Test_LocalAccessPolicies_CRUD(t *testing.T) {
+	localPolicies, cleanup := newTestLocalPolicies(t)
+	defer cleanup()
+	router := httprouter.New()
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, "", localPolicies)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(
+		http.MethodPut,
+		"/access-policies/local/office",
+		strings.NewReader(`{"title": "Office", "allow": [{"type": "port", "value": "tcp/443"}]}`),
+	))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/access-policies/local/office", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `
+	{
+		"id": "office",
+		"title": "Office",
+		"description": "",
+		"allow": [
+			{"type": "port", "value": "tcp/443"}
+		],
+		"source": "local"
+	}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/access-policies/local", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"id":"office"`)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/access-policies/local/office", nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/access-policies/local/office", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/access-policies/local/office", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func Test_LocalAccessPolicies_SaveValidatesRules(t *testing.T) {
+	localPolicies, cleanup := newTestLocalPolicies(t)
+	defer cleanup()
+	router := httprouter.New()
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, "", localPolicies)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(
+		http.MethodPut,
+		"/access-policies/local/office",
+		strings.NewReader(`{"allow": [{"type": "port", "value": "smtp"}], "deny": [{"type": "ip_range", "value": "10.0.0"}]}`),
+	))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.JSONEq(t, `
+	{
+		"message": "validation_error",
+		"errors": {
+			"allow[0]": [{"code": "invalid", "message": "invalid port rule \"smtp\": strconv.Atoi: parsing \"smtp\": invalid syntax"}],
+			"deny[0]": [{"code": "invalid", "message": "invalid IP \"10.0.0\""}]
+		}
+	}`, resp.Body.String())
+}
+
+func Test_LocalAccessPolicies_DeleteInUse(t *testing.T) {
+	localPolicies, cleanup := newTestLocalPolicies(t)
+	defer cleanup()
+	assert.NoError(t, localPolicies.Save(market.AccessPolicyRuleSet{ID: "office"}))
+	assert.NoError(t, localPolicies.SubscribePolicies([]market.AccessPolicy{localPolicies.Policy("office")}, policy.NewRepository()))
+
+	router := httprouter.New()
+	AddRoutesForAccessPolicies(requests.NewHTTPClient(bindAllAddress, requests.DefaultTimeout), router, "", localPolicies)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/access-policies/local/office", nil))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func newTestLocalPolicies(t *testing.T) (*policy.LocalPolicies, func()) {
+	dir, err := ioutil.TempDir("", "accessPoliciesTest")
+	assert.NoError(t, err)
+	return policy.NewLocalPolicies(dir, time.Second), func() { os.RemoveAll(dir) }
+}
+
 func newTestServer(mockStatus int, mockResponse string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(mockStatus)
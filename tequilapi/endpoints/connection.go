@@ -20,6 +20,8 @@ package endpoints
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"net/http"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -27,6 +29,7 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/discovery/scoring"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/market"
@@ -36,6 +39,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// autoConnectAttempts is the number of best scored proposals tried when proposal is picked automatically.
+const autoConnectAttempts = 3
+
 // statusConnectCancelled indicates that connect request was cancelled by user. Since there is no such concept in REST
 // operations, custom client error code is defined. Maybe in later times a better idea will come how to handle these situations
 const statusConnectCancelled = 499
@@ -49,6 +55,10 @@ type identityRegistry interface {
 	GetRegistrationStatus(identity.Identity) (registry.RegistrationStatus, error)
 }
 
+type proposalScorer interface {
+	Rank(proposals []market.ServiceProposal, preferences scoring.Preferences) []scoring.Score
+}
+
 // ConnectionEndpoint struct represents /connection resource and it's subresources
 type ConnectionEndpoint struct {
 	manager       connection.Manager
@@ -56,15 +66,17 @@ type ConnectionEndpoint struct {
 	//TODO connection should use concrete proposal from connection params and avoid going to marketplace
 	proposalRepository proposal.Repository
 	identityRegistry   identityRegistry
+	proposalScorer     proposalScorer
 }
 
 // NewConnectionEndpoint creates and returns connection endpoint
-func NewConnectionEndpoint(manager connection.Manager, stateProvider stateProvider, proposalRepository proposal.Repository, identityRegistry identityRegistry) *ConnectionEndpoint {
+func NewConnectionEndpoint(manager connection.Manager, stateProvider stateProvider, proposalRepository proposal.Repository, identityRegistry identityRegistry, proposalScorer proposalScorer) *ConnectionEndpoint {
 	return &ConnectionEndpoint{
 		manager:            manager,
 		stateProvider:      stateProvider,
 		proposalRepository: proposalRepository,
 		identityRegistry:   identityRegistry,
+		proposalScorer:     proposalScorer,
 	}
 }
 
@@ -92,11 +104,12 @@ func (ce *ConnectionEndpoint) Status(resp http.ResponseWriter, _ *http.Request,
 // swagger:operation PUT /connection Connection connectionCreate
 // ---
 // summary: Starts new connection
-// description: Consumer opens connection to provider
+// description: Consumer opens connection to provider, or a multi-hop connection chained through the given hops.
+//   When auto is set, node picks the best scored proposal matching the filter and tries next ones if connection fails.
 // parameters:
 //   - in: body
 //     name: body
-//     description: Parameters in body (consumer_id, provider_id, service_type) required for creating new connection
+//     description: Parameters in body (consumer_id, provider_id, service_type or auto) required for creating new connection
 //     schema:
 //       $ref: "#/definitions/ConnectionCreateRequestDTO"
 // responses:
@@ -105,7 +118,7 @@ func (ce *ConnectionEndpoint) Status(resp http.ResponseWriter, _ *http.Request,
 //     schema:
 //       "$ref": "#/definitions/ConnectionStatusDTO"
 //   400:
-//     description: Bad request
+//     description: Bad request, or no proposals match the filter of automatic proposal selection
 //     schema:
 //       "$ref": "#/definitions/ErrorMessageDTO"
 //   409:
@@ -124,6 +137,10 @@ func (ce *ConnectionEndpoint) Status(resp http.ResponseWriter, _ *http.Request,
 //     description: Internal server error
 //     schema:
 //       "$ref": "#/definitions/ErrorMessageDTO"
+//   503:
+//     description: Session was rejected by provider, another proposal should be picked
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
 func (ce *ConnectionEndpoint) Create(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
 	cr, err := toConnectionRequest(req)
 	if err != nil {
@@ -138,49 +155,63 @@ func (ce *ConnectionEndpoint) Create(resp http.ResponseWriter, req *http.Request
 
 	// TODO Validate for account existence
 	consumerID := identity.FromAddress(cr.ConsumerID)
-	status, err := ce.identityRegistry.GetRegistrationStatus(consumerID)
-	if err != nil {
-		log.Error().Err(err).Stack().Msg("could not check registration status")
-		utils.SendError(resp, err, http.StatusInternalServerError)
+	if !checkRegistration(resp, ce.identityRegistry, consumerID) {
 		return
 	}
-	switch status {
-	case registry.Unregistered, registry.RegistrationError:
-		log.Warn().Msgf("identity %q is not registered, aborting...", cr.ConsumerID)
-		utils.SendError(resp, fmt.Errorf("identity %q is not registered. Please register the identity first", cr.ConsumerID), http.StatusExpectationFailed)
-		return
-	case registry.InProgress:
-		log.Info().Msgf("identity %q registration is in progress, continuing...", cr.ConsumerID)
-	default:
-		log.Info().Msgf("identity %q is registered, continuing...", cr.ConsumerID)
+
+	var proposals []market.ServiceProposal
+	if cr.Auto {
+		proposals, err = ce.rankedProposals(cr.Filter)
+		if err != nil {
+			utils.SendError(resp, err, http.StatusInternalServerError)
+			return
+		}
+		if len(proposals) == 0 {
+			utils.SendError(resp, errors.New("no proposals match the filter"), http.StatusBadRequest)
+			return
+		}
+	} else {
+		hops := cr.Hops
+		if len(hops) == 0 {
+			hops = []contract.ConnectionHopRequest{{ProviderID: cr.ProviderID, ServiceType: cr.ServiceType}}
+		}
+
+		for _, hop := range hops {
+			// TODO Pass proposal ID directly in request
+			proposal, err := ce.proposalRepository.Proposal(market.ProposalID{
+				ProviderID:  hop.ProviderID,
+				ServiceType: hop.ServiceType,
+			})
+			if err != nil {
+				utils.SendError(resp, err, http.StatusInternalServerError)
+				return
+			}
+			if proposal == nil {
+				utils.SendError(resp, errors.New("provider has no service proposals"), http.StatusBadRequest)
+				return
+			}
+			proposals = append(proposals, *proposal)
+		}
 	}
 
-	// TODO Pass proposal ID directly in request
-	proposal, err := ce.proposalRepository.Proposal(market.ProposalID{
-		ProviderID:  cr.ProviderID,
-		ServiceType: cr.ServiceType,
-	})
+	connectOptions := getConnectOptions(cr)
+	connectOptions.Failover, err = failoverStrategy(ce.proposalRepository, cr.ConnectOptions.Failover)
 	if err != nil {
-		utils.SendError(resp, err, http.StatusInternalServerError)
-		return
-	}
-	if proposal == nil {
-		utils.SendError(resp, errors.New("provider has no service proposals"), http.StatusBadRequest)
+		utils.SendError(resp, err, http.StatusBadRequest)
 		return
 	}
 
-	err = ce.manager.Connect(consumerID, common.HexToAddress(cr.HermesID), *proposal, getConnectOptions(cr))
+	switch {
+	case cr.Auto:
+		err = ce.connectBest(consumerID, common.HexToAddress(cr.HermesID), proposals, connectOptions)
+	case len(proposals) > 1:
+		err = ce.manager.ConnectMultiHop(consumerID, common.HexToAddress(cr.HermesID), proposals, connectOptions)
+	default:
+		err = ce.manager.Connect(consumerID, common.HexToAddress(cr.HermesID), proposals[0], connectOptions)
+	}
 
 	if err != nil {
-		switch err {
-		case connection.ErrAlreadyExists:
-			utils.SendError(resp, err, http.StatusConflict)
-		case connection.ErrConnectionCancelled:
-			utils.SendError(resp, err, statusConnectCancelled)
-		default:
-			log.Error().Err(err).Msg("")
-			utils.SendError(resp, err, http.StatusInternalServerError)
-		}
+		sendConnectError(resp, err)
 		return
 	}
 	resp.WriteHeader(http.StatusCreated)
@@ -233,19 +264,202 @@ func (ce *ConnectionEndpoint) Kill(resp http.ResponseWriter, req *http.Request,
 //       "$ref": "#/definitions/ErrorMessageDTO"
 func (ce *ConnectionEndpoint) GetStatistics(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	connection := ce.stateProvider.GetState().Connection
-	response := contract.NewConnectionStatisticsDTO(connection.Session, connection.Statistics, connection.Throughput, connection.Invoice)
+	response := contract.NewConnectionStatisticsDTO(connection.Session, connection.Statistics, connection.Throughput, connection.Invoice, connection.Probe)
 
 	utils.WriteAsJSON(response, writer)
 }
 
+// Probe measures quality of current connection
+// swagger:operation POST /connection/probe Connection connectionProbe
+// ---
+// summary: Probes connection quality
+// description: Measures latency, jitter and optionally throughput of current connection against its provider
+// parameters:
+//   - in: body
+//     name: body
+//     description: Parameters of the probe
+//     schema:
+//       $ref: "#/definitions/ConnectionProbeRequestDTO"
+// responses:
+//   200:
+//     description: Probe result
+//     schema:
+//       "$ref": "#/definitions/ConnectionProbeDTO"
+//   400:
+//     description: Bad request
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   409:
+//     description: Conflict. No connection exists
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   501:
+//     description: Provider does not support connection probes
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ce *ConnectionEndpoint) Probe(resp http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	var pr contract.ConnectionProbeRequest
+	if err := json.NewDecoder(req.Body).Decode(&pr); err != nil && err != io.EOF {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := ce.manager.Probe(req.Context(), pr.Throughput)
+	if err != nil {
+		switch err {
+		case connection.ErrNoConnection:
+			utils.SendError(resp, err, http.StatusConflict)
+		case connection.ErrProbeNotSupported:
+			utils.SendError(resp, err, http.StatusNotImplemented)
+		default:
+			utils.SendError(resp, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionProbeDTO(result), resp)
+}
+
+// rankedProposals returns proposals matching the filter ordered from the best to the worst scored.
+func (ce *ConnectionEndpoint) rankedProposals(filter *contract.AutoConnectFilter) ([]market.ServiceProposal, error) {
+	if ce.proposalScorer == nil {
+		return nil, errors.New("automatic proposal selection is not available")
+	}
+	if filter == nil {
+		filter = &contract.AutoConnectFilter{}
+	}
+
+	candidates, err := ce.proposalRepository.Proposals(&proposal.Filter{
+		ServiceType:         filter.ServiceType,
+		LocationType:        filter.LocationType,
+		AccessPolicyID:      filter.AccessPolicyID,
+		AccessPolicySource:  filter.AccessPolicySource,
+		UpperGBPriceBound:   filter.PriceGiBMax,
+		LowerGBPriceBound:   lowerPriceBound(filter.PriceGiBMax),
+		UpperTimePriceBound: filter.PriceMinuteMax,
+		LowerTimePriceBound: lowerPriceBound(filter.PriceMinuteMax),
+		ExcludeUnsupported:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := ce.proposalScorer.Rank(candidates, scoring.Preferences{Countries: filter.Countries})
+	proposals := make([]market.ServiceProposal, len(scores))
+	for i, score := range scores {
+		proposals[i] = score.Proposal
+	}
+	return proposals, nil
+}
+
+// connectBest connects to the best of given ranked proposals, it moves to the next proposal if connection fails.
+func (ce *ConnectionEndpoint) connectBest(consumerID identity.Identity, hermesID common.Address, proposals []market.ServiceProposal, options connection.ConnectParams) (err error) {
+	for i, proposal := range proposals {
+		if i == autoConnectAttempts {
+			break
+		}
+
+		err = ce.manager.Connect(consumerID, hermesID, proposal, options)
+		switch err {
+		case nil, connection.ErrAlreadyExists, connection.ErrConnectionCancelled, connection.ErrInvalidQuota:
+			return err
+		}
+		log.Warn().Err(err).Msgf("Automatic connection to %s failed, trying next proposal", proposal.ProviderID)
+	}
+	return err
+}
+
+func lowerPriceBound(upper *big.Int) *big.Int {
+	if upper == nil {
+		return nil
+	}
+	return new(big.Int)
+}
+
+func checkRegistration(resp http.ResponseWriter, identityRegistry identityRegistry, consumerID identity.Identity) bool {
+	status, err := identityRegistry.GetRegistrationStatus(consumerID)
+	if err != nil {
+		log.Error().Err(err).Stack().Msg("could not check registration status")
+		utils.SendError(resp, err, http.StatusInternalServerError)
+		return false
+	}
+	switch status {
+	case registry.Unregistered, registry.RegistrationError:
+		log.Warn().Msgf("identity %q is not registered, aborting...", consumerID.Address)
+		utils.SendError(resp, fmt.Errorf("identity %q is not registered. Please register the identity first", consumerID.Address), http.StatusExpectationFailed)
+		return false
+	case registry.InProgress:
+		log.Info().Msgf("identity %q registration is in progress, continuing...", consumerID.Address)
+	default:
+		log.Info().Msgf("identity %q is registered, continuing...", consumerID.Address)
+	}
+	return true
+}
+
+func sendConnectError(resp http.ResponseWriter, err error) {
+	if _, ok := err.(*connection.SessionRejectedError); ok {
+		utils.SendError(resp, err, http.StatusServiceUnavailable)
+		return
+	}
+
+	switch err {
+	case connection.ErrAlreadyExists:
+		utils.SendError(resp, err, http.StatusConflict)
+	case connection.ErrSplitTunnelMultiHop, connection.ErrFailoverMultiHop, connection.ErrSplitTunnelNotSupported, connection.ErrDetachingNotSupported, connection.ErrInvalidQuota:
+		utils.SendError(resp, err, http.StatusBadRequest)
+	case connection.ErrConnectionCancelled:
+		utils.SendError(resp, err, statusConnectCancelled)
+	default:
+		log.Error().Err(err).Msg("")
+		utils.SendError(resp, err, http.StatusInternalServerError)
+	}
+}
+
+func failoverStrategy(proposalRepository proposal.Repository, options *contract.FailoverOptions) (connection.FailoverStrategy, error) {
+	var strategy connection.FailoverStrategy
+	if options == nil {
+		return strategy, nil
+	}
+
+	for _, candidate := range options.Proposals {
+		proposal, err := proposalRepository.Proposal(market.ProposalID{
+			ProviderID:  candidate.ProviderID,
+			ServiceType: candidate.ServiceType,
+		})
+		if err != nil {
+			return strategy, err
+		}
+		if proposal == nil {
+			return strategy, fmt.Errorf("failover provider %q has no service proposals", candidate.ProviderID)
+		}
+		strategy.Proposals = append(strategy.Proposals, *proposal)
+	}
+
+	if options.Filter != nil {
+		strategy.Filter = &proposal.Filter{
+			ServiceType:        options.Filter.ServiceType,
+			LocationType:       options.Filter.LocationType,
+			AccessPolicyID:     options.Filter.AccessPolicyID,
+			AccessPolicySource: options.Filter.AccessPolicySource,
+			ExcludeUnsupported: true,
+		}
+	}
+
+	return strategy, nil
+}
+
 // AddRoutesForConnection adds connections routes to given router
 func AddRoutesForConnection(router *httprouter.Router, manager connection.Manager,
-	stateProvider stateProvider, proposalRepository proposal.Repository, identityRegistry identityRegistry) {
-	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry)
+	stateProvider stateProvider, proposalRepository proposal.Repository, identityRegistry identityRegistry, proposalScorer proposalScorer) {
+	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, proposalScorer)
 	router.GET("/connection", connectionEndpoint.Status)
 	router.PUT("/connection", connectionEndpoint.Create)
 	router.DELETE("/connection", connectionEndpoint.Kill)
 	router.GET("/connection/statistics", connectionEndpoint.GetStatistics)
+	router.POST("/connection/probe", connectionEndpoint.Probe)
 }
 
 func toConnectionRequest(req *http.Request) (*contract.ConnectionCreateRequest, error) {
@@ -269,8 +483,16 @@ func getConnectOptions(cr *contract.ConnectionCreateRequest) connection.ConnectP
 		dns = cr.ConnectOptions.DNS
 	}
 
-	return connection.ConnectParams{
+	params := connection.ConnectParams{
 		DisableKillSwitch: cr.ConnectOptions.DisableKillSwitch,
 		DNS:               dns,
+		SplitTunnel: connection.SplitTunnel{
+			Include: cr.ConnectOptions.SplitTunnelInclude,
+			Exclude: cr.ConnectOptions.SplitTunnelExclude,
+		},
+	}
+	if cr.ConnectOptions.Quota != nil {
+		params.Quota = cr.ConnectOptions.Quota.ToQuota()
 	}
+	return params
 }
@@ -19,6 +19,7 @@ package endpoints
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
@@ -31,10 +32,13 @@ import (
 	"github.com/mysteriumnetwork/node/consumer/bandwidth"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/scoring"
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/payments/crypto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -44,12 +48,17 @@ type mockConnectionManager struct {
 	onConnectReturn      error
 	onDisconnectReturn   error
 	onCheckChannelReturn error
+	onProbeReturn        connectionstate.ProbeResult
+	onProbeErr           error
+	requestedThroughput  bool
 	onStatusReturn       connectionstate.Status
 	disconnectCount      int
 	requestedConsumerID  identity.Identity
 	requestedProvider    identity.Identity
 	requestedHermesID    common.Address
 	requestedServiceType string
+	requestedProposals   []market.ServiceProposal
+	requestedParams      connection.ConnectParams
 }
 
 func (cm *mockConnectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, options connection.ConnectParams) error {
@@ -57,6 +66,15 @@ func (cm *mockConnectionManager) Connect(consumerID identity.Identity, hermesID
 	cm.requestedHermesID = hermesID
 	cm.requestedProvider = identity.FromAddress(proposal.ProviderID)
 	cm.requestedServiceType = proposal.ServiceType
+	cm.requestedParams = options
+	return cm.onConnectReturn
+}
+
+func (cm *mockConnectionManager) ConnectMultiHop(consumerID identity.Identity, hermesID common.Address, proposals []market.ServiceProposal, options connection.ConnectParams) error {
+	cm.requestedConsumerID = consumerID
+	cm.requestedHermesID = hermesID
+	cm.requestedProposals = proposals
+	cm.requestedParams = options
 	return cm.onConnectReturn
 }
 
@@ -73,6 +91,11 @@ func (cm *mockConnectionManager) CheckChannel(context.Context) error {
 	return cm.onCheckChannelReturn
 }
 
+func (cm *mockConnectionManager) Probe(_ context.Context, throughput bool) (connectionstate.ProbeResult, error) {
+	cm.requestedThroughput = throughput
+	return cm.onProbeReturn, cm.onProbeErr
+}
+
 func (cm *mockConnectionManager) Reconnect() {
 	return
 }
@@ -105,7 +128,7 @@ func TestAddRoutesForConnectionAddsRoutes(t *testing.T) {
 	fakeState.stateToReturn.Connection.Statistics = connectionstate.Statistics{BytesSent: 1, BytesReceived: 2}
 
 	mockedProposalProvider := mockRepositoryWithProposal("node1", "noop")
-	AddRoutesForConnection(router, fakeManager, fakeState, mockedProposalProvider, mockIdentityRegistryInstance)
+	AddRoutesForConnection(router, fakeManager, fakeState, mockedProposalProvider, mockIdentityRegistryInstance, nil)
 
 	tests := []struct {
 		method         string
@@ -164,7 +187,7 @@ func TestStateIsReturnedFromStore(t *testing.T) {
 		},
 	}
 
-	connEndpoint := NewConnectionEndpoint(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(http.MethodGet, "/irrelevant", nil)
 	resp := httptest.NewRecorder()
 
@@ -184,7 +207,7 @@ func TestStateIsReturnedFromStore(t *testing.T) {
 func TestPutReturns400ErrorIfRequestBodyIsNotJSON(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
-	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(http.MethodPut, "/irrelevant", strings.NewReader("a"))
 	resp := httptest.NewRecorder()
 
@@ -203,7 +226,7 @@ func TestPutReturns400ErrorIfRequestBodyIsNotJSON(t *testing.T) {
 func TestPutReturns422ErrorIfRequestBodyIsMissingFieldValues(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
-	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(http.MethodPut, "/irrelevant", strings.NewReader("{}"))
 	resp := httptest.NewRecorder()
 
@@ -232,7 +255,7 @@ func TestPutWithValidBodyCreatesConnection(t *testing.T) {
 	fakeState.stateToReturn.Connection.Session = state
 
 	proposalProvider := mockRepositoryWithProposal("required-node", "openvpn")
-	connEndpoint := NewConnectionEndpoint(&fakeManager, fakeState, proposalProvider, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, fakeState, proposalProvider, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -262,6 +285,159 @@ func TestPutWithValidBodyCreatesConnection(t *testing.T) {
 	)
 }
 
+func TestPutWithFailoverPassesFailoverStrategy(t *testing.T) {
+	fakeManager := mockConnectionManager{}
+	proposalProvider := mockRepositoryWithProposal("required-node", "wireguard")
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, mockIdentityRegistryInstance, nil)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(
+			`{
+				"consumer_id" : "my-identity",
+				"provider_id" : "required-node",
+				"hermes_id" : "hermes",
+				"connect_options": {
+					"failover": {
+						"proposals": [{"provider_id": "backup-node", "service_type": "wireguard"}],
+						"filter": {"service_type": "wireguard", "location_type": "residential"}
+					}
+				}
+			}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	failover := fakeManager.requestedParams.Failover
+	assert.Len(t, failover.Proposals, 1)
+	assert.Equal(t, "wireguard", failover.Filter.ServiceType)
+	assert.Equal(t, "residential", failover.Filter.LocationType)
+	assert.True(t, failover.Filter.ExcludeUnsupported)
+}
+
+func TestPutWithSplitTunnelPassesSplitTunnel(t *testing.T) {
+	fakeManager := mockConnectionManager{}
+	proposalProvider := mockRepositoryWithProposal("required-node", "wireguard")
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, mockIdentityRegistryInstance, nil)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(
+			`{
+				"consumer_id" : "my-identity",
+				"provider_id" : "required-node",
+				"hermes_id" : "hermes",
+				"connect_options": {
+					"split_tunnel_include": ["10.0.0.0/8", "example.com"],
+					"split_tunnel_exclude": ["1.1.1.1"]
+				}
+			}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, []string{"10.0.0.0/8", "example.com"}, fakeManager.requestedParams.SplitTunnel.Include)
+	assert.Equal(t, []string{"1.1.1.1"}, fakeManager.requestedParams.SplitTunnel.Exclude)
+}
+
+func TestPutWithInvalidSplitTunnelReturnsValidationError(t *testing.T) {
+	proposalProvider := mockRepositoryWithProposal("required-node", "wireguard")
+	connEndpoint := NewConnectionEndpoint(&mockConnectionManager{}, &mockStateProvider{}, proposalProvider, mockIdentityRegistryInstance, nil)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(
+			`{
+				"consumer_id" : "my-identity",
+				"provider_id" : "required-node",
+				"connect_options": {
+					"split_tunnel_exclude": ["10.0.0.0/33"]
+				}
+			}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestPutWithHopsCreatesMultiHopConnection(t *testing.T) {
+	state := connectionstate.Status{
+		State:     connectionstate.Connected,
+		SessionID: "1",
+		Proposal:  market.ServiceProposal{ProviderID: "entry-node", ServiceType: "wireguard"},
+		Hops: []connectionstate.HopStatus{
+			{
+				SessionID:  "1",
+				Proposal:   market.ServiceProposal{ProviderID: "entry-node", ServiceType: "wireguard"},
+				Statistics: connectionstate.Statistics{BytesSent: 1, BytesReceived: 2},
+			},
+			{
+				SessionID:  "2",
+				Proposal:   market.ServiceProposal{ProviderID: "exit-node", ServiceType: "wireguard"},
+				Statistics: connectionstate.Statistics{BytesSent: 3, BytesReceived: 4},
+			},
+		},
+	}
+	fakeManager := mockConnectionManager{onStatusReturn: state}
+
+	proposalProvider := mockRepositoryWithProposal("exit-node", "wireguard")
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, mockIdentityRegistryInstance, nil)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(
+			`{
+				"consumer_id" : "my-identity",
+				"hermes_id" : "hermes",
+				"hops" : [
+					{"provider_id" : "entry-node", "service_type" : "wireguard"},
+					{"provider_id" : "exit-node", "service_type" : "wireguard"}
+				]
+			}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, identity.FromAddress("my-identity"), fakeManager.requestedConsumerID)
+	assert.Equal(t, common.HexToAddress("hermes"), fakeManager.requestedHermesID)
+	assert.Len(t, fakeManager.requestedProposals, 2)
+
+	var status contract.ConnectionStatusDTO
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	assert.Len(t, status.Hops, 2)
+	assert.Equal(t, "entry-node", status.Hops[0].Proposal.ProviderID)
+	assert.Equal(t, "2", status.Hops[1].SessionID)
+	assert.Equal(t, uint64(3), status.Hops[1].BytesSent)
+	assert.Equal(t, uint64(4), status.Hops[1].BytesReceived)
+}
+
+func TestPutWithSingleHopReturnsValidationError(t *testing.T) {
+	fakeManager := mockConnectionManager{}
+
+	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(`{"consumer_id" : "my-identity", "hops" : [{"provider_id" : "exit-node"}]}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"message" : "validation_error",
+			"errors" : {
+				"hops" : [ { "code" : "invalid" , "message" : "Multi-hop connection requires at least 2 hops" } ]
+			}
+		}`, resp.Body.String())
+}
+
 func TestPutUnregisteredIdentityReturnsError(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
@@ -269,7 +445,7 @@ func TestPutUnregisteredIdentityReturnsError(t *testing.T) {
 	mir := *mockIdentityRegistryInstance
 	mir.RegistrationStatus = registry.Unregistered
 
-	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, &mir)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -298,7 +474,7 @@ func TestPutFailedRegistrationCheckReturnsError(t *testing.T) {
 	mir := *mockIdentityRegistryInstance
 	mir.RegistrationCheckError = errors.New("explosions everywhere")
 
-	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, &mir)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -324,7 +500,7 @@ func TestPutWithServiceTypeOverridesDefault(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
 	mystAPI := mockRepositoryWithProposal("required-node", "noop")
-	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, mystAPI, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, &mockStateProvider{}, mystAPI, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -350,7 +526,7 @@ func TestPutWithServiceTypeOverridesDefault(t *testing.T) {
 func TestDeleteCallsDisconnect(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
-	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(http.MethodDelete, "/irrelevant", nil)
 	resp := httptest.NewRecorder()
 
@@ -368,7 +544,7 @@ func TestGetStatisticsEndpointReturnsStatistics(t *testing.T) {
 	fakeState.stateToReturn.Connection.Invoice = crypto.Invoice{AgreementTotal: big.NewInt(10001)}
 
 	manager := mockConnectionManager{}
-	connEndpoint := NewConnectionEndpoint(&manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connEndpoint := NewConnectionEndpoint(&manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 
 	resp := httptest.NewRecorder()
 	connEndpoint.GetStatistics(resp, nil, nil)
@@ -386,12 +562,115 @@ func TestGetStatisticsEndpointReturnsStatistics(t *testing.T) {
 	)
 }
 
+func TestGetStatisticsEndpointReturnsProbeResult(t *testing.T) {
+	fakeState := &mockStateProvider{}
+	fakeState.stateToReturn.Connection.Probe = connectionstate.ProbeResult{
+		At:         time.Date(2020, 11, 4, 16, 3, 21, 0, time.UTC),
+		Latency:    42 * time.Millisecond,
+		Jitter:     3 * time.Millisecond,
+		PacketLoss: 0.1,
+	}
+
+	manager := mockConnectionManager{}
+	connEndpoint := NewConnectionEndpoint(&manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
+
+	resp := httptest.NewRecorder()
+	connEndpoint.GetStatistics(resp, nil, nil)
+	assert.JSONEq(
+		t,
+		`{
+			"bytes_sent": 0,
+			"bytes_received": 0,
+			"throughput_sent": 0,
+			"throughput_received": 0,
+			"duration": 0,
+			"tokens_spent": 0,
+			"probe": {
+				"measured_at": "2020-11-04T16:03:21Z",
+				"latency": 42,
+				"jitter": 3,
+				"packet_loss": 0.1
+			}
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestProbeEndpointReturnsProbeResult(t *testing.T) {
+	manager := mockConnectionManager{
+		onProbeReturn: connectionstate.ProbeResult{
+			At:         time.Date(2020, 11, 4, 16, 3, 21, 0, time.UTC),
+			Latency:    42 * time.Millisecond,
+			Jitter:     3 * time.Millisecond,
+			PacketLoss: 0,
+			Download:   datasize.BitSpeed(2000),
+			Upload:     datasize.BitSpeed(1000),
+		},
+	}
+	connEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/connection/probe", strings.NewReader(`{"throughput": true}`))
+	resp := httptest.NewRecorder()
+	connEndpoint.Probe(resp, req, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, manager.requestedThroughput)
+	assert.JSONEq(
+		t,
+		`{
+			"measured_at": "2020-11-04T16:03:21Z",
+			"latency": 42,
+			"jitter": 3,
+			"packet_loss": 0,
+			"throughput_download": 2000,
+			"throughput_upload": 1000
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestProbeEndpointAcceptsEmptyBody(t *testing.T) {
+	manager := mockConnectionManager{}
+	connEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/connection/probe", strings.NewReader(""))
+	resp := httptest.NewRecorder()
+	connEndpoint.Probe(resp, req, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.False(t, manager.requestedThroughput)
+}
+
+func TestProbeEndpointReturnsErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{connection.ErrNoConnection, http.StatusConflict},
+		{connection.ErrProbeNotSupported, http.StatusNotImplemented},
+		{errors.New("no probe pings were answered"), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			manager := mockConnectionManager{onProbeErr: test.err}
+			connEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/connection/probe", strings.NewReader(`{}`))
+			resp := httptest.NewRecorder()
+			connEndpoint.Probe(resp, req, nil)
+
+			assert.Equal(t, test.code, resp.Code)
+			assert.Contains(t, resp.Body.String(), test.err.Error())
+		})
+	}
+}
+
 func TestEndpointReturnsConflictStatusIfConnectionAlreadyExists(t *testing.T) {
 	manager := mockConnectionManager{}
 	manager.onConnectReturn = connection.ErrAlreadyExists
 
 	mystAPI := mockRepositoryWithProposal("required-node", "openvpn")
-	connectionEndpoint := NewConnectionEndpoint(&manager, nil, mystAPI, mockIdentityRegistryInstance)
+	connectionEndpoint := NewConnectionEndpoint(&manager, nil, mystAPI, mockIdentityRegistryInstance, nil)
 
 	req := httptest.NewRequest(
 		http.MethodPut,
@@ -420,7 +699,7 @@ func TestDisconnectReturnsConflictStatusIfConnectionDoesNotExist(t *testing.T) {
 	manager := mockConnectionManager{}
 	manager.onDisconnectReturn = connection.ErrNoConnection
 
-	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 
 	req := httptest.NewRequest(
 		http.MethodDelete,
@@ -446,7 +725,7 @@ func TestConnectReturnsConnectCancelledStatusWhenErrConnectionCancelledIsEncount
 	manager.onConnectReturn = connection.ErrConnectionCancelled
 
 	mockProposalProvider := mockRepositoryWithProposal("required-node", "openvpn")
-	connectionEndpoint := NewConnectionEndpoint(&manager, nil, mockProposalProvider, mockIdentityRegistryInstance)
+	connectionEndpoint := NewConnectionEndpoint(&manager, nil, mockProposalProvider, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -474,7 +753,7 @@ func TestConnectReturnsErrorIfNoProposals(t *testing.T) {
 	manager := mockConnectionManager{}
 	manager.onConnectReturn = connection.ErrConnectionCancelled
 
-	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance)
+	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, nil)
 	req := httptest.NewRequest(
 		http.MethodPut,
 		"/irrelevant",
@@ -498,4 +777,95 @@ func TestConnectReturnsErrorIfNoProposals(t *testing.T) {
 	)
 }
 
+type reversingProposalScorer struct {
+	preferences scoring.Preferences
+}
+
+func (s *reversingProposalScorer) Rank(proposals []market.ServiceProposal, preferences scoring.Preferences) []scoring.Score {
+	s.preferences = preferences
+	scores := make([]scoring.Score, len(proposals))
+	for i, proposal := range proposals {
+		scores[len(proposals)-1-i] = scoring.Score{Proposal: proposal}
+	}
+	return scores
+}
+
+type autoConnectionManager struct {
+	mockConnectionManager
+	failing   map[string]error
+	attempted []string
+}
+
+func (cm *autoConnectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, options connection.ConnectParams) error {
+	cm.attempted = append(cm.attempted, proposal.ProviderID)
+	return cm.failing[proposal.ProviderID]
+}
+
+func TestConnectAutoPicksBestProposalAndTriesNext(t *testing.T) {
+	manager := &autoConnectionManager{
+		failing: map[string]error{"node3": &connection.SessionRejectedError{Reason: pb.RejectionReason_MAX_SESSIONS}},
+	}
+	repository := &mockProposalRepository{proposals: []market.ServiceProposal{
+		{ProviderID: "node1", ServiceType: "wireguard"},
+		{ProviderID: "node2", ServiceType: "wireguard"},
+		{ProviderID: "node3", ServiceType: "wireguard"},
+	}}
+	scorer := &reversingProposalScorer{}
+
+	connEndpoint := NewConnectionEndpoint(manager, nil, repository, mockIdentityRegistryInstance, scorer)
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/irrelevant",
+		strings.NewReader(
+			`{
+				"consumer_id" : "my-identity",
+				"auto" : true,
+				"filter" : {"service_type" : "wireguard", "countries" : ["DE"], "price_gib_max" : 100}
+			}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, []string{"node3", "node2"}, manager.attempted)
+	assert.Equal(t, []string{"DE"}, scorer.preferences.Countries)
+	assert.Equal(t, "wireguard", repository.recordedFilter.ServiceType)
+	assert.Equal(t, big.NewInt(100), repository.recordedFilter.UpperGBPriceBound)
+	assert.Equal(t, new(big.Int), repository.recordedFilter.LowerGBPriceBound)
+	assert.True(t, repository.recordedFilter.ExcludeUnsupported)
+}
+
+func TestConnectAutoStopsAfterAttemptsLimit(t *testing.T) {
+	connectErr := errors.New("connect failed")
+	manager := &autoConnectionManager{
+		failing: map[string]error{"node1": connectErr, "node2": connectErr, "node3": connectErr, "node4": connectErr},
+	}
+	repository := &mockProposalRepository{proposals: []market.ServiceProposal{
+		{ProviderID: "node1"}, {ProviderID: "node2"}, {ProviderID: "node3"}, {ProviderID: "node4"},
+	}}
+
+	connEndpoint := NewConnectionEndpoint(manager, nil, repository, mockIdentityRegistryInstance, &reversingProposalScorer{})
+	req := httptest.NewRequest(http.MethodPut, "/irrelevant", strings.NewReader(`{"consumer_id" : "my-identity", "auto" : true}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, []string{"node4", "node3", "node2"}, manager.attempted)
+}
+
+func TestConnectAutoReturnsErrorIfNoProposalsMatch(t *testing.T) {
+	manager := &autoConnectionManager{}
+
+	connEndpoint := NewConnectionEndpoint(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, &reversingProposalScorer{})
+	req := httptest.NewRequest(http.MethodPut, "/irrelevant", strings.NewReader(`{"consumer_id" : "my-identity", "auto" : true}`))
+	resp := httptest.NewRecorder()
+
+	connEndpoint.Create(resp, req, httprouter.Params{})
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"message" : "no proposals match the filter"}`, resp.Body.String())
+	assert.Empty(t, manager.attempted)
+}
+
 var mockIdentityRegistryInstance = &registry.FakeRegistry{RegistrationStatus: registry.Registered}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type connectionPool interface {
+	Get(id string) (connection.Manager, bool)
+	Connect(consumerID identity.Identity, hermesID common.Address, proposal market.ServiceProposal, params connection.ConnectParams) (string, error)
+	List() []connectionstate.Status
+	Disconnect(id string) error
+}
+
+// ConnectionsEndpoint struct represents /connections resource and it's subresources
+type ConnectionsEndpoint struct {
+	pool               connectionPool
+	proposalRepository proposal.Repository
+	identityRegistry   identityRegistry
+}
+
+// NewConnectionsEndpoint creates and returns connections endpoint
+func NewConnectionsEndpoint(pool connectionPool, proposalRepository proposal.Repository, identityRegistry identityRegistry) *ConnectionsEndpoint {
+	return &ConnectionsEndpoint{
+		pool:               pool,
+		proposalRepository: proposalRepository,
+		identityRegistry:   identityRegistry,
+	}
+}
+
+// List returns statuses of all connections
+// swagger:operation GET /connections Connection connectionList
+// ---
+// summary: Returns all connections
+// description: Returns statuses of the default connection and every additional one
+// responses:
+//   200:
+//     description: List of connections
+//     schema:
+//       "$ref": "#/definitions/ListConnectionsResponse"
+func (ce *ConnectionsEndpoint) List(resp http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	utils.WriteAsJSON(contract.NewListConnectionsResponse(ce.pool.List()), resp)
+}
+
+// Status returns status of the connection
+// swagger:operation GET /connections/{id} Connection connectionGet
+// ---
+// summary: Returns connection status
+// description: Returns status of the connection with the given ID
+// parameters:
+// - name: id
+//   in: path
+//   description: connection ID
+//   type: string
+//   required: true
+// responses:
+//   200:
+//     description: Status
+//     schema:
+//       "$ref": "#/definitions/ConnectionStatusDTO"
+//   404:
+//     description: Connection not found
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ce *ConnectionsEndpoint) Status(resp http.ResponseWriter, _ *http.Request, params httprouter.Params) {
+	manager, ok := ce.pool.Get(params.ByName("id"))
+	if !ok {
+		utils.SendErrorMessage(resp, "Connection not found", http.StatusNotFound)
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionStatusDTO(manager.Status()), resp)
+}
+
+// Create starts an additional connection
+// swagger:operation PUT /connections Connection connectionsCreate
+// ---
+// summary: Starts an additional connection
+// description: Consumer opens a connection to provider alongside the default one. Additional connection doesn't route host traffic, it is reachable through its own network interface only.
+// parameters:
+//   - in: body
+//     name: body
+//     description: Parameters in body (consumer_id, provider_id, service_type) required for creating new connection
+//     schema:
+//       $ref: "#/definitions/ConnectionCreateRequestDTO"
+// responses:
+//   201:
+//     description: Connection started
+//     schema:
+//       "$ref": "#/definitions/ConnectionStatusDTO"
+//   400:
+//     description: Bad request
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   422:
+//     description: Parameters validation error
+//     schema:
+//       "$ref": "#/definitions/ValidationErrorDTO"
+//   499:
+//     description: Connection was cancelled
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ce *ConnectionsEndpoint) Create(resp http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	cr, err := toConnectionRequest(req)
+	if err != nil {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+
+	errorMap := cr.Validate()
+	if len(cr.Hops) > 0 {
+		errorMap.ForField("hops").AddError("invalid", "Additional connections can not be multi-hop")
+	}
+	if errorMap.HasErrors() {
+		utils.SendValidationErrorMessage(resp, errorMap)
+		return
+	}
+
+	consumerID := identity.FromAddress(cr.ConsumerID)
+	if !checkRegistration(resp, ce.identityRegistry, consumerID) {
+		return
+	}
+
+	proposal, err := ce.proposalRepository.Proposal(market.ProposalID{
+		ProviderID:  cr.ProviderID,
+		ServiceType: cr.ServiceType,
+	})
+	if err != nil {
+		utils.SendError(resp, err, http.StatusInternalServerError)
+		return
+	}
+	if proposal == nil {
+		utils.SendError(resp, errors.New("provider has no service proposals"), http.StatusBadRequest)
+		return
+	}
+
+	connectOptions := getConnectOptions(cr)
+	connectOptions.Failover, err = failoverStrategy(ce.proposalRepository, cr.ConnectOptions.Failover)
+	if err != nil {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := ce.pool.Connect(consumerID, common.HexToAddress(cr.HermesID), *proposal, connectOptions)
+	if err != nil {
+		sendConnectError(resp, err)
+		return
+	}
+
+	manager, ok := ce.pool.Get(id)
+	if !ok {
+		utils.SendErrorMessage(resp, "Connection closed", http.StatusInternalServerError)
+		return
+	}
+	resp.WriteHeader(http.StatusCreated)
+	utils.WriteAsJSON(contract.NewConnectionStatusDTO(manager.Status()), resp)
+}
+
+// Kill stops the connection
+// swagger:operation DELETE /connections/{id} Connection connectionsCancel
+// ---
+// summary: Stops connection
+// description: Stops the connection with the given ID
+// parameters:
+// - name: id
+//   in: path
+//   description: connection ID
+//   type: string
+//   required: true
+// responses:
+//   202:
+//     description: Connection Stopped
+//   404:
+//     description: Connection not found
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   409:
+//     description: Conflict. No connection exists
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
+func (ce *ConnectionsEndpoint) Kill(resp http.ResponseWriter, _ *http.Request, params httprouter.Params) {
+	id := params.ByName("id")
+	if _, ok := ce.pool.Get(id); !ok {
+		utils.SendErrorMessage(resp, "Connection not found", http.StatusNotFound)
+		return
+	}
+
+	err := ce.pool.Disconnect(id)
+	if err != nil {
+		switch err {
+		case connection.ErrNoConnection:
+			utils.SendError(resp, err, http.StatusConflict)
+		default:
+			utils.SendError(resp, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	resp.WriteHeader(http.StatusAccepted)
+}
+
+// AddRoutesForConnections adds routes of simultaneous connections to given router
+func AddRoutesForConnections(router *httprouter.Router, pool connectionPool, proposalRepository proposal.Repository, identityRegistry identityRegistry) {
+	connectionsEndpoint := NewConnectionsEndpoint(pool, proposalRepository, identityRegistry)
+	router.GET("/connections", connectionsEndpoint.List)
+	router.PUT("/connections", connectionsEndpoint.Create)
+	router.GET("/connections/:id", connectionsEndpoint.Status)
+	router.DELETE("/connections/:id", connectionsEndpoint.Kill)
+}
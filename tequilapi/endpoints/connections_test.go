@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func newTestConnectionPool() (*connection.Pool, map[string]*mockConnectionManager) {
+	managers := make(map[string]*mockConnectionManager)
+	pool := connection.NewPool(func(id string) connection.Manager {
+		manager := &mockConnectionManager{
+			onStatusReturn: connectionstate.Status{ConnectionID: id, State: connectionstate.Connected, SessionID: session.ID("session-" + id)},
+		}
+		managers[id] = manager
+		return manager
+	})
+	return pool, managers
+}
+
+func TestConnectionsCreateListAndKill(t *testing.T) {
+	pool, managers := newTestConnectionPool()
+	router := httprouter.New()
+	AddRoutesForConnections(router, pool, mockRepositoryWithProposal("node1", "wireguard"), mockIdentityRegistryInstance)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/connections", strings.NewReader(
+		`{"consumer_id": "me", "provider_id": "node1", "hermes_id": "hermes", "service_type": "wireguard"}`,
+	))
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var created contract.ConnectionStatusDTO
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	assert.NotEqual(t, connection.DefaultConnectionID, created.ConnectionID)
+	assert.Equal(t, "session-"+created.ConnectionID, created.SessionID)
+
+	manager := managers[created.ConnectionID]
+	assert.Equal(t, identity.FromAddress("me"), manager.requestedConsumerID)
+	assert.True(t, manager.requestedParams.Detached)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/connections", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var list contract.ListConnectionsResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Len(t, list.Connections, 2)
+	assert.Equal(t, connection.DefaultConnectionID, list.Connections[0].ConnectionID)
+	assert.Equal(t, created.ConnectionID, list.Connections[1].ConnectionID)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/connections/"+created.ConnectionID, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/connections/"+created.ConnectionID, nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, 1, manager.disconnectCount)
+
+	_, ok := pool.Get(created.ConnectionID)
+	assert.False(t, ok)
+}
+
+func TestConnectionsUnknownConnectionReturnsNotFound(t *testing.T) {
+	pool, _ := newTestConnectionPool()
+	router := httprouter.New()
+	AddRoutesForConnections(router, pool, mockRepositoryWithProposal("node1", "wireguard"), mockIdentityRegistryInstance)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(method, "/connections/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	}
+}
+
+func TestConnectionsCreateWithHopsReturnsValidationError(t *testing.T) {
+	pool, _ := newTestConnectionPool()
+	router := httprouter.New()
+	AddRoutesForConnections(router, pool, mockRepositoryWithProposal("node1", "wireguard"), mockIdentityRegistryInstance)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/connections", strings.NewReader(
+		`{"consumer_id": "me", "hops": [{"provider_id": "node1", "service_type": "wireguard"}, {"provider_id": "node1", "service_type": "wireguard"}]}`,
+	))
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Len(t, pool.List(), 1)
+}
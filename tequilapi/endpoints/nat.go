@@ -21,18 +21,26 @@ import (
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/mysteriumnetwork/node/nat/stun"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
 
+type natTypeProvider interface {
+	Status() (stun.NATType, error)
+}
+
 // NATEndpoint struct represents endpoints about NAT traversal
 type NATEndpoint struct {
-	stateProvider stateProvider
+	stateProvider   stateProvider
+	natTypeProvider natTypeProvider
 }
 
 // NewNATEndpoint creates and returns nat endpoint
-func NewNATEndpoint(stateProvider stateProvider) *NATEndpoint {
+func NewNATEndpoint(stateProvider stateProvider, natTypeProvider natTypeProvider) *NATEndpoint {
 	return &NATEndpoint{
-		stateProvider: stateProvider,
+		stateProvider:   stateProvider,
+		natTypeProvider: natTypeProvider,
 	}
 }
 
@@ -50,9 +58,29 @@ func (ne *NATEndpoint) NATStatus(resp http.ResponseWriter, _ *http.Request, _ ht
 	utils.WriteAsJSON(ne.stateProvider.GetState().NATStatus, resp)
 }
 
+// NATType provides type of NAT the node is behind
+// swagger:operation GET /nat/type NAT NATTypeDTO
+// ---
+// summary: Shows NAT type
+// description: NAT type returns the last NAT type detected using STUN servers
+// responses:
+//   200:
+//     description: NAT type ("unknown"/"blocked"/"open"/"full-cone"/"restricted"/"port-restricted"/"symmetric") and optionally error if detection failed
+//     schema:
+//       "$ref": "#/definitions/NATTypeDTO"
+func (ne *NATEndpoint) NATType(resp http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	natType, err := ne.natTypeProvider.Status()
+	dto := contract.NATTypeDTO{Type: string(natType)}
+	if err != nil {
+		dto.Error = err.Error()
+	}
+	utils.WriteAsJSON(dto, resp)
+}
+
 // AddRoutesForNAT adds nat routes to given router
-func AddRoutesForNAT(router *httprouter.Router, stateProvider stateProvider) {
-	natEndpoint := NewNATEndpoint(stateProvider)
+func AddRoutesForNAT(router *httprouter.Router, stateProvider stateProvider, natTypeProvider natTypeProvider) {
+	natEndpoint := NewNATEndpoint(stateProvider, natTypeProvider)
 
 	router.GET("/nat/status", natEndpoint.NATStatus)
+	router.GET("/nat/type", natEndpoint.NATType)
 }
@@ -19,12 +19,14 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/julienschmidt/httprouter"
 	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
+	"github.com/mysteriumnetwork/node/nat/stun"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,10 +46,53 @@ func Test_NATStatus_ReturnsStatusSuccessful_WithSuccessfulEvent(t *testing.T) {
 	assert.Nil(t, err)
 	resp := httptest.NewRecorder()
 	router := httprouter.New()
-	AddRoutesForNAT(router, provider)
+	AddRoutesForNAT(router, provider, &mockNATTypeProvider{})
 
 	router.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.JSONEq(t, string(expectedJSON), resp.Body.String())
 }
+
+func Test_NATType_ReturnsDetectedType(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     *mockNATTypeProvider
+		expectedJSON string
+	}{
+		{
+			name:         "detected",
+			provider:     &mockNATTypeProvider{natType: stun.NATTypeSymmetric},
+			expectedJSON: `{"type": "symmetric"}`,
+		},
+		{
+			name:         "detection failed",
+			provider:     &mockNATTypeProvider{natType: stun.NATTypeUnknown, err: errors.New("no response")},
+			expectedJSON: `{"type": "unknown", "error": "no response"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/nat/type", nil)
+			assert.Nil(t, err)
+			resp := httptest.NewRecorder()
+			router := httprouter.New()
+			AddRoutesForNAT(router, &mockStateProvider{}, test.provider)
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.JSONEq(t, test.expectedJSON, resp.Body.String())
+		})
+	}
+}
+
+type mockNATTypeProvider struct {
+	natType stun.NATType
+	err     error
+}
+
+func (m *mockNATTypeProvider) Status() (stun.NATType, error) {
+	return m.natType, m.err
+}
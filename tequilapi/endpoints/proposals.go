@@ -20,10 +20,13 @@ package endpoints
 import (
 	"math/big"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/discovery/query"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 	"github.com/pkg/errors"
@@ -72,11 +75,31 @@ func NewProposalsEndpoint(proposalRepository proposal.Repository, qualityProvide
 //     name: fetch_metrics
 //     description: if set to true, fetches the connection success metrics for nodes. False by default.
 //     type: boolean
+//   - in: query
+//     name: query
+//     description: 'filter expression over provider, service_type, country, node_type, price_minute, price_gib (MYST), verified and quality (0-3) fields, e.g. country in ("DE", "NL") and price_gib < 0.1 and quality > 2'
+//     type: string
+//   - in: query
+//     name: sort
+//     description: comma separated list of query fields to sort by, prefix field with "-" for descending order, e.g. "-quality,price_gib", ties are ordered by provider ID and service type
+//     type: string
+//   - in: query
+//     name: offset
+//     description: number of proposals to skip
+//     type: integer
+//   - in: query
+//     name: limit
+//     description: maximum number of proposals to return
+//     type: integer
 // responses:
 //   200:
 //     description: List of proposals
 //     schema:
 //       "$ref": "#/definitions/ListProposalsResponse"
+//   400:
+//     description: Bad request
+//     schema:
+//       "$ref": "#/definitions/ErrorMessageDTO"
 //   500:
 //     description: Internal server error
 //     schema:
@@ -104,7 +127,33 @@ func (pe *proposalsEndpoint) List(resp http.ResponseWriter, req *http.Request, p
 		return
 	}
 
-	proposals, err := pe.proposalRepository.Proposals(&proposal.Filter{
+	offset, err := parseCount(req, "offset")
+	if err != nil {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+	limit, err := parseCount(req, "limit")
+	if err != nil {
+		utils.SendError(resp, err, http.StatusBadRequest)
+		return
+	}
+
+	fields := query.NewFields(pe.qualityProvider.ProposalsMetrics)
+	var filterQuery *query.Query
+	if text := req.URL.Query().Get("query"); text != "" {
+		filterQuery, err = query.Parse(text, fields)
+		if err != nil {
+			utils.SendError(resp, errors.Wrap(err, "could not parse query"), http.StatusBadRequest)
+			return
+		}
+	}
+	order, err := query.ParseOrder(req.URL.Query().Get("sort"), fields)
+	if err != nil {
+		utils.SendError(resp, errors.Wrap(err, "could not parse sort"), http.StatusBadRequest)
+		return
+	}
+
+	filter := &proposal.Filter{
 		ProviderID:          req.URL.Query().Get("provider_id"),
 		ServiceType:         req.URL.Query().Get("service_type"),
 		AccessPolicyID:      req.URL.Query().Get("access_policy_id"),
@@ -115,13 +164,20 @@ func (pe *proposalsEndpoint) List(resp http.ResponseWriter, req *http.Request, p
 		UpperTimePriceBound: upperTimePriceBound,
 		ExcludeUnsupported:  true,
 		IncludeFailed:       req.URL.Query().Get("monitoring_failed") == "true",
-	})
+	}
+	if filterQuery != nil {
+		filterQuery.Apply(filter)
+	}
 
+	proposals, err := pe.proposalRepository.Proposals(filter)
 	if err != nil {
 		utils.SendError(resp, err, http.StatusInternalServerError)
 		return
 	}
 
+	order.Sort(proposals)
+	proposals = paginate(proposals, offset, limit)
+
 	proposalsRes := contract.ListProposalsResponse{Proposals: []contract.ProposalDTO{}}
 	for _, p := range proposals {
 		proposalsRes.Proposals = append(proposalsRes.Proposals, contract.NewProposalDTO(p))
@@ -162,6 +218,30 @@ func parsePriceBound(req *http.Request, key string) (*big.Int, error) {
 	return upperPriceBound, nil
 }
 
+func parseCount(req *http.Request, key string) (int, error) {
+	value := req.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, errors.Errorf("%s must be a non-negative integer", key)
+	}
+	return count, nil
+}
+
+// paginate returns proposals page, zero limit means no limit.
+func paginate(proposals []market.ServiceProposal, offset, limit int) []market.ServiceProposal {
+	if offset >= len(proposals) {
+		return nil
+	}
+	proposals = proposals[offset:]
+	if limit > 0 && limit < len(proposals) {
+		proposals = proposals[:limit]
+	}
+	return proposals
+}
+
 // AddRoutesForProposals attaches proposals endpoints to router
 func AddRoutesForProposals(router *httprouter.Router, proposalRepository proposal.Repository, qualityProvider QualityFinder) {
 	pe := NewProposalsEndpoint(proposalRepository, qualityProvider)
@@ -18,6 +18,7 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -92,6 +93,7 @@ func TestProposalsEndpointListByNodeId(t *testing.T) {
                     "id": 1,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
+                    "verified": false,
                     "service_definition": {
                         "location_originate": {
                             "asn": 123,
@@ -160,6 +162,7 @@ func TestProposalsEndpointAcceptsAccessPolicyParams(t *testing.T) {
                     "id": 1,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
+                    "verified": false,
                     "service_definition": {
                         "location_originate": {
                             "asn": 123,
@@ -217,6 +220,7 @@ func TestProposalsEndpointList(t *testing.T) {
                     "id": 1,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
+                    "verified": false,
                     "service_definition": {
                         "location_originate": {
                             "asn": 123,
@@ -240,6 +244,7 @@ func TestProposalsEndpointList(t *testing.T) {
                     "id": 1,
                     "provider_id": "other_provider",
                     "service_type": "testprotocol",
+                    "verified": false,
                     "service_definition": {
                         "location_originate": {
                             "asn": 123,
@@ -289,6 +294,7 @@ func TestProposalsEndpointListFetchConnectCounts(t *testing.T) {
 					"id": 1,
 					"provider_id": "0xProviderId",
 					"service_type": "testprotocol",
+					"verified": false,
 					"service_definition": {
 						"location_originate": {
 							"asn": 123,
@@ -320,6 +326,7 @@ func TestProposalsEndpointListFetchConnectCounts(t *testing.T) {
 					"id": 1,
 					"provider_id": "other_provider",
 					"service_type": "testprotocol",
+					"verified": false,
 					"service_definition": {
 						"location_originate": {
 							"asn": 123,
@@ -345,6 +352,70 @@ func TestProposalsEndpointListFetchConnectCounts(t *testing.T) {
 	)
 }
 
+func TestProposalsEndpointListByQuery(t *testing.T) {
+	repository := &mockProposalRepository{
+		proposals: []market.ServiceProposal{
+			{ProviderID: "0x1", ServiceType: "wireguard"},
+			{ProviderID: "0x3", ServiceType: "wireguard"},
+			{ProviderID: "0x2", ServiceType: "wireguard"},
+			{ProviderID: "0x4", ServiceType: "wireguard"},
+		},
+	}
+	query := url.Values{}
+	query.Set("query", `service_type = wireguard and country in ("LT", "DE")`)
+	query.Set("sort", "-provider")
+	query.Set("offset", "1")
+	query.Set("limit", "2")
+	req, err := http.NewRequest(http.MethodGet, "/irrelevant?"+query.Encode(), nil)
+	assert.Nil(t, err)
+
+	resp := httptest.NewRecorder()
+	NewProposalsEndpoint(repository, &mockQualityProvider{}).List(resp, req, nil)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var res struct {
+		Proposals []struct {
+			ProviderID string `json:"provider_id"`
+		} `json:"proposals"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+	assert.Len(t, res.Proposals, 2)
+	assert.Equal(t, "0x3", res.Proposals[0].ProviderID)
+	assert.Equal(t, "0x2", res.Proposals[1].ProviderID)
+
+	assert.Equal(t, "wireguard", repository.recordedFilter.ServiceType)
+	assert.NotNil(t, repository.recordedFilter.Condition)
+	assert.False(t, repository.recordedFilter.Condition(market.ServiceProposal{ServiceType: "wireguard"}))
+}
+
+func TestProposalsEndpointListRejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		param, value, err string
+	}{
+		{param: "query", value: "city = Vilnius", err: `could not parse query: unknown field \"city\" at position 1`},
+		{param: "sort", value: "-city", err: `could not parse sort: unknown order field \"city\"`},
+		{param: "offset", value: "-1", err: "offset must be a non-negative integer"},
+		{param: "limit", value: "ten", err: "limit must be a non-negative integer"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.param, func(t *testing.T) {
+			query := url.Values{}
+			query.Set(test.param, test.value)
+			req, err := http.NewRequest(http.MethodGet, "/irrelevant?"+query.Encode(), nil)
+			assert.Nil(t, err)
+
+			resp := httptest.NewRecorder()
+			repository := &mockProposalRepository{proposals: serviceProposals}
+			NewProposalsEndpoint(repository, &mockQualityProvider{}).List(resp, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Contains(t, resp.Body.String(), test.err)
+			assert.Nil(t, repository.recordedFilter)
+		})
+	}
+}
+
 type mockQualityProvider struct{}
 
 func (m *mockQualityProvider) ProposalsMetrics() []quality.ConnectMetric {
@@ -153,6 +153,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 					"id": 1,
 					"provider_id": "0xproviderid",
 					"service_type": "testprotocol",
+					"verified": false,
 					"service_definition": {
 						"location_originate": {"asn": 123, "country": "Lithuania", "city": "Vilnius"}
 					},
@@ -168,7 +169,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 						}
 					}
 				},
-				"connection_statistics": {"attempted":0, "successful":0}
+				"connection_statistics": {"attempted":0, "successful":0, "rejected":0}
 			}]`,
 		},
 		{
@@ -186,6 +187,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 					"id": 1,
 					"provider_id": "0xproviderid",
 					"service_type": "testprotocol",
+					"verified": false,
 					"service_definition": {
 						"location_originate": {"asn": 123, "country": "Lithuania", "city": "Vilnius"}
 					},
@@ -201,7 +203,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 						}
 					}
 				},
-				"connection_statistics": {"attempted":0, "successful":0}
+				"connection_statistics": {"attempted":0, "successful":0, "rejected":0}
 			}`,
 		},
 		{
@@ -219,6 +221,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 					"id": 1,
 					"provider_id": "0xproviderid",
 					"service_type": "testprotocol",
+					"verified": false,
 					"service_definition": {
 						"location_originate": {"asn": 123, "country": "Lithuania", "city": "Vilnius"}
 					},
@@ -234,7 +237,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 						}
 					}
 				},
-				"connection_statistics": {"attempted":0, "successful":0}
+				"connection_statistics": {"attempted":0, "successful":0, "rejected":0}
 			}`,
 		},
 		{
@@ -404,6 +407,7 @@ func Test_ServiceGetReturnsServiceInfo(t *testing.T) {
 				"id": 1,
 				"provider_id": "0xproviderid",
 				"service_type": "testprotocol",
+				"verified": false,
 				"service_definition": {
 					"location_originate": {
 						"asn": 123,
@@ -423,7 +427,7 @@ func Test_ServiceGetReturnsServiceInfo(t *testing.T) {
 					}
 				}
 			},
-			"connection_statistics": {"attempted":0, "successful":0}
+			"connection_statistics": {"attempted":0, "successful":0, "rejected":0}
 		}`,
 		resp.Body.String(),
 	)
@@ -498,6 +502,7 @@ func Test_ServiceStart_WithAccessPolicy(t *testing.T) {
 				"id": 1,
 				"provider_id": "0xproviderid",
 				"service_type": "mockAccessPolicyService",
+				"verified": false,
 				"service_definition": {
 					"location_originate": {"asn": 123, "country": "Lithuania", "city": "Vilnius"}
 				},
@@ -531,7 +536,7 @@ func Test_ServiceStart_WithAccessPolicy(t *testing.T) {
 					}
 				]
 			},
-			"connection_statistics": {"attempted":0, "successful":0}
+			"connection_statistics": {"attempted":0, "successful":0, "rejected":0}
 		}`,
 		resp.Body.String(),
 	)
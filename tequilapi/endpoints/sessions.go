@@ -60,7 +60,7 @@ func NewSessionsEndpoint(sessionStorage sessionStorage) *sessionsEndpoint {
 //     type: string
 //   - in: query
 //     name: direction
-//     description: Direction to filter the sessions by. Possible values are "Provider", "Consumed".
+//     description: Direction to filter the sessions by. Possible values are "Provided", "Consumed", matched case insensitively.
 //     type: string
 //   - in: query
 //     name: service_type
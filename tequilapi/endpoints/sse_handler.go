@@ -26,6 +26,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	nodeEvent "github.com/mysteriumnetwork/node/core/node/event"
 	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
 	"github.com/mysteriumnetwork/node/eventbus"
@@ -50,6 +51,12 @@ const (
 	ServiceStatusEvent EventType = "service-status"
 	// StateChangeEvent represents the state change
 	StateChangeEvent EventType = "state-change"
+	// ConnectionFailoverEvent represents a switch of a dead session to a failover proposal
+	ConnectionFailoverEvent EventType = "connection-failover"
+	// ConnectionStateEvent represents a state change of any consumer connection, tagged with its connection ID
+	ConnectionStateEvent EventType = "connection-state"
+	// ConnectionQuotaEvent represents a crossed connection quota warning threshold or an exhausted quota
+	ConnectionQuotaEvent EventType = "connection-quota"
 )
 
 // Handler represents an sse handler
@@ -86,6 +93,18 @@ func (h *Handler) Subscribe(bus eventbus.Subscriber) error {
 		return err
 	}
 	err = bus.Subscribe(stateEvent.AppTopicState, h.ConsumeStateEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(connectionstate.AppTopicConnectionFailover, h.ConsumeConnectionFailoverEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(connectionstate.AppTopicConnectionState, h.ConsumeConnectionStateEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(connectionstate.AppTopicConnectionQuota, h.ConsumeConnectionQuotaEvent)
 	return err
 }
 
@@ -246,7 +265,7 @@ func mapState(event stateEvent.State) stateRes {
 		Sessions:      sessionsRes,
 		SessionsStats: contract.NewSessionStatsDTO(sessionsStats),
 		Consumer: consumerStateRes{
-			Connection: contract.NewConnectionDTO(event.Connection.Session, event.Connection.Statistics, event.Connection.Throughput, event.Connection.Invoice),
+			Connection: contract.NewConnectionDTO(event.Connection.Session, event.Connection.Statistics, event.Connection.Throughput, event.Connection.Invoice, event.Connection.Probe),
 		},
 		Identities: identitiesRes,
 	}
@@ -260,3 +279,27 @@ func (h *Handler) ConsumeStateEvent(event stateEvent.State) {
 		Payload: mapState(event),
 	})
 }
+
+// ConsumeConnectionFailoverEvent consumes the connection failover event
+func (h *Handler) ConsumeConnectionFailoverEvent(event connectionstate.AppEventConnectionFailover) {
+	h.send(Event{
+		Type:    ConnectionFailoverEvent,
+		Payload: contract.NewConnectionFailoverDTO(event),
+	})
+}
+
+// ConsumeConnectionStateEvent consumes the connection state change event
+func (h *Handler) ConsumeConnectionStateEvent(event connectionstate.AppEventConnectionState) {
+	h.send(Event{
+		Type:    ConnectionStateEvent,
+		Payload: contract.NewConnectionStatusDTO(event.SessionInfo),
+	})
+}
+
+// ConsumeConnectionQuotaEvent consumes the connection quota event
+func (h *Handler) ConsumeConnectionQuotaEvent(event connectionstate.AppEventConnectionQuota) {
+	h.send(Event{
+		Type:    ConnectionQuotaEvent,
+		Payload: contract.NewConnectionQuotaEventDTO(event),
+	})
+}
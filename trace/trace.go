@@ -18,6 +18,8 @@
 package trace
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"sync"
@@ -34,8 +36,21 @@ const (
 
 // NewTracer returns new tracer instance.
 func NewTracer(name string) *Tracer {
+	return NewChildTracer(name, SpanContext{})
+}
+
+// NewChildTracer returns new tracer instance which continues the trace of a remote parent,
+// e.g. provider tracer continues the trace started by consumer.
+// New trace is started if the parent is not valid.
+func NewChildTracer(name string, parent SpanContext) *Tracer {
 	tracer := &Tracer{
 		stages: make([]*stage, 0),
+		parent: parent,
+	}
+	if parent.IsValid() {
+		tracer.traceID = parent.TraceID
+	} else {
+		tracer.traceID = newTraceID()
 	}
 	tracer.name = tracer.StartStage(name)
 	return tracer
@@ -45,6 +60,8 @@ func NewTracer(name string) *Tracer {
 // to record stages times for tracing how long it took time.
 type Tracer struct {
 	name     string
+	traceID  TraceID
+	parent   SpanContext
 	mu       sync.Mutex
 	stages   []*stage
 	finished bool
@@ -64,13 +81,33 @@ func (t *Tracer) StartStage(key string) string {
 		return ""
 	}
 
+	parentID := t.parent.SpanID
+	if active := t.activeStage(); active != nil {
+		parentID = active.spanID
+	}
+
 	t.stages = append(t.stages, &stage{
-		key:   key,
-		start: time.Now(),
+		key:      key,
+		spanID:   newSpanID(),
+		parentID: parentID,
+		start:    time.Now(),
 	})
 	return key
 }
 
+// SpanContext returns context of the innermost running stage,
+// it should be passed to remote peers to continue the same trace.
+func (t *Tracer) SpanContext() SpanContext {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sc := SpanContext{TraceID: t.traceID}
+	if active := t.activeStage(); active != nil {
+		sc.SpanID = active.spanID
+	}
+	return sc
+}
+
 // EndStage ends tracing stage for given key.
 func (t *Tracer) EndStage(key string) {
 	t.mu.Lock()
@@ -83,6 +120,7 @@ func (t *Tracer) EndStage(key string) {
 	s, ok := t.findStage(key)
 	if !ok {
 		log.Error().Msgf("Stage %s was not started", key)
+		return
 	}
 
 	s.end = time.Now()
@@ -109,6 +147,16 @@ func (t *Tracer) Finish(eventPublisher eventbus.Publisher, id string) string {
 	return strings.Join(strs, ", ")
 }
 
+// activeStage returns the latest started stage which is not ended yet.
+func (t *Tracer) activeStage() *stage {
+	for i := len(t.stages) - 1; i >= 0; i-- {
+		if t.stages[i].end.IsZero() {
+			return t.stages[i]
+		}
+	}
+	return nil
+}
+
 func (t *Tracer) findStage(key string) (*stage, bool) {
 	for _, s := range t.stages {
 		if s.key == key {
@@ -125,16 +173,21 @@ func (t *Tracer) publishStageEvent(eventPublisher eventbus.Publisher, id string,
 
 	eventPublisher.Publish(AppTopicTraceEvent,
 		Event{
-			ID:       id,
-			Key:      stage.key,
-			Duration: stage.end.Sub(stage.start),
+			ID:           id,
+			Key:          stage.key,
+			Duration:     stage.end.Sub(stage.start),
+			TraceID:      t.traceID,
+			SpanID:       stage.spanID,
+			ParentSpanID: stage.parentID,
+			Start:        stage.start,
 		},
 	)
 }
 
 type stage struct {
-	key        string
-	start, end time.Time
+	key              string
+	spanID, parentID SpanID
+	start, end       time.Time
 }
 
 // Event represents a published Trace event.
@@ -142,4 +195,97 @@ type Event struct {
 	ID       string
 	Key      string
 	Duration time.Duration
+
+	// TraceID, SpanID and ParentSpanID relate stages of consumer and provider into a single trace.
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Start        time.Time
+}
+
+// TraceID identifies a trace spanning both peers of the connection.
+type TraceID [16]byte
+
+// IsValid checks if trace ID is set.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// String returns hex representation of the trace ID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a stage of a trace.
+type SpanID [8]byte
+
+// IsValid checks if span ID is set.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// String returns hex representation of the span ID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies a stage of a trace across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid checks if span context identifies a stage.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats span context as W3C Trace Context traceparent value.
+func (sc SpanContext) Traceparent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// ParseTraceparent parses W3C Trace Context traceparent value.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return SpanContext{}, fmt.Errorf("unsupported traceparent: %q", value)
+	}
+	if err := decodeID(sc.TraceID[:], parts[1]); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace ID: %w", err)
+	}
+	if err := decodeID(sc.SpanID[:], parts[2]); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid span ID: %w", err)
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("empty traceparent IDs: %q", value)
+	}
+	return sc, nil
+}
+
+func decodeID(dst []byte, value string) error {
+	if hex.DecodedLen(len(value)) != len(dst) {
+		return fmt.Errorf("expected %d bytes, got %q", len(dst), value)
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err
+}
+
+func newTraceID() (id TraceID) {
+	if _, err := rand.Read(id[:]); err != nil {
+		log.Error().Err(err).Msg("Failed to generate trace ID")
+	}
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	if _, err := rand.Read(id[:]); err != nil {
+		log.Error().Err(err).Msg("Failed to generate span ID")
+	}
+	return id
 }
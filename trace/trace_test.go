@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracer_StagesAreChildrenOfRunningStage(t *testing.T) {
+	bus := eventbus.New()
+	var events []Event
+	assert.NoError(t, bus.Subscribe(AppTopicTraceEvent, func(e Event) {
+		events = append(events, e)
+	}))
+
+	tracer := NewTracer("whole")
+	outer := tracer.StartStage("outer")
+	inner := tracer.StartStage("inner")
+	tracer.EndStage(inner)
+	tracer.EndStage(outer)
+	tracer.Finish(bus, "session-1")
+
+	assert.Len(t, events, 3)
+	whole, outerEvent, innerEvent := events[0], events[1], events[2]
+
+	assert.True(t, whole.TraceID.IsValid())
+	assert.False(t, whole.ParentSpanID.IsValid())
+	assert.Equal(t, whole.TraceID, outerEvent.TraceID)
+	assert.Equal(t, whole.SpanID, outerEvent.ParentSpanID)
+	assert.Equal(t, whole.TraceID, innerEvent.TraceID)
+	assert.Equal(t, outerEvent.SpanID, innerEvent.ParentSpanID)
+}
+
+func TestTracer_ContinuesRemoteTrace(t *testing.T) {
+	consumer := NewTracer("Consumer whole Connect")
+	consumer.StartStage("Consumer P2P exchange")
+	consumerContext := consumer.SpanContext()
+
+	parent, err := ParseTraceparent(consumerContext.Traceparent())
+	assert.NoError(t, err)
+	assert.Equal(t, consumerContext, parent)
+
+	provider := NewChildTracer("Provider whole Connect", parent)
+	providerContext := provider.SpanContext()
+
+	assert.Equal(t, consumerContext.TraceID, providerContext.TraceID)
+	assert.NotEqual(t, consumerContext.SpanID, providerContext.SpanID)
+	assert.Equal(t, consumerContext.SpanID, provider.stages[0].parentID)
+}
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+
+	for _, value := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+	} {
+		sc, err := ParseTraceparent(value)
+		assert.Error(t, err, value)
+		assert.False(t, sc.IsValid(), value)
+	}
+}
@@ -44,15 +44,17 @@ type route struct {
 }
 
 type routeManager struct {
-	db          *boltdb.Bolt
-	deleteRoute func(ip, wg string) error
+	db                *boltdb.Bolt
+	deleteRoute       func(ip, wg string) error
+	deletePinnedRoute func(ip, via, dev string) error
 }
 
 // SetRouteManagerStorage initiate defaultRouteManager with a provided storage.
 func SetRouteManagerStorage(db *boltdb.Bolt) {
 	defaultRouteManager = &routeManager{
-		db:          db,
-		deleteRoute: deleteRoute,
+		db:                db,
+		deleteRoute:       deleteRoute,
+		deletePinnedRoute: deletePinnedRoute,
 	}
 }
 
@@ -73,13 +75,19 @@ func ClearStaleRoutes() {
 	for _, r := range records {
 		args := strings.Split(r.Record, routeRecordDelimeter)
 
-		if len(args) != 2 {
-			log.Error().Err(err).Msgf("Failed to parse %s record", r.Record)
-		} else {
+		switch len(args) {
+		case 2:
 			log.Info().Msgf("Cleaning stale route: %s %s", args[0], args[1])
 			if err := defaultRouteManager.deleteRoute(args[0], args[1]); err != nil {
 				log.Error().Err(err).Msgf("Failed to delete route: %s %s", args[0], args[1])
 			}
+		case 3:
+			log.Info().Msgf("Cleaning stale pinned route: %s %s %s", args[0], args[1], args[2])
+			if err := defaultRouteManager.deletePinnedRoute(args[0], args[1], args[2]); err != nil {
+				log.Error().Err(err).Msgf("Failed to delete pinned route: %s %s %s", args[0], args[1], args[2])
+			}
+		default:
+			log.Error().Err(err).Msgf("Failed to parse %s record", r.Record)
 		}
 
 		err := defaultRouteManager.db.Delete(routeRecordBucket, &r)
@@ -108,11 +116,65 @@ func ExcludeRoute(ip net.IP) error {
 	return excludeRoute(ip, gw)
 }
 
+// ExcludeNetwork excludes given network from VPN tunnel.
+func ExcludeNetwork(network net.IPNet) error {
+	if network.IP.To4() == nil {
+		return fmt.Errorf("could not exclude %s: only IPv4 networks can be routed via default gateway", network.String())
+	}
+
+	gw, err := gateway.DiscoverGateway()
+	if err != nil {
+		return fmt.Errorf("failed to get default gateway: %w", err)
+	}
+
+	if defaultRouteManager != nil {
+		err := defaultRouteManager.db.Store(routeRecordBucket, &route{
+			Record: strings.Join([]string{network.String(), gw.String()}, routeRecordDelimeter),
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to save %s record", routeRecordBucket)
+		}
+	}
+
+	return excludeNetwork(network, gw)
+}
+
+// RouteNetwork routes given network through VPN tunnel interface.
+func RouteNetwork(network net.IPNet, iface string) error {
+	return routeNetwork(network, iface)
+}
+
 // AddDefaultRoute adds default VPN tunnel route.
 func AddDefaultRoute(iface string) error {
 	return addDefaultRoute(iface)
 }
 
+// PinRoute keeps given IP on the interface it is currently routed through.
+// It is used by chained tunnels, which must reach their peer through the tunnel in front of them.
+// Pinned route is recorded along with excluded ones, so that it is removed with them.
+func PinRoute(ip net.IP) error {
+	via, dev, err := currentRoute(ip)
+	if err != nil {
+		return err
+	}
+
+	if defaultRouteManager != nil {
+		err := defaultRouteManager.db.Store(routeRecordBucket, &route{
+			Record: strings.Join([]string{ip.String(), via, dev}, routeRecordDelimeter),
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to save %s record", routeRecordBucket)
+		}
+	}
+
+	return pinRoute(ip.String(), via, dev)
+}
+
+// ReplaceDefaultRoute moves default VPN tunnel route to the given interface.
+func ReplaceDefaultRoute(iface string) error {
+	return replaceDefaultRoute(iface)
+}
+
 // AssignIP assigns subnet to given interface.
 func AssignIP(iface string, subnet net.IPNet) error {
 	return assignIP(iface, subnet)
@@ -18,13 +18,20 @@
 package netutil
 
 import (
+	"fmt"
 	"net"
 	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/mysteriumnetwork/node/utils/cmdutil"
 )
 
 func assignIP(iface string, subnet net.IPNet) error {
+	if subnet.IP.To4() == nil {
+		ones, _ := subnet.Mask.Size()
+		return cmdutil.SudoExec("ifconfig", iface, "inet6", subnet.IP.String(), "prefixlen", strconv.Itoa(ones), "alias")
+	}
 	return cmdutil.SudoExec("ifconfig", iface, subnet.String(), peerIP(subnet).String())
 }
 
@@ -32,6 +39,17 @@ func excludeRoute(ip, gw net.IP) error {
 	return cmdutil.SudoExec("route", "add", "-host", ip.String(), gw.String())
 }
 
+func excludeNetwork(network net.IPNet, gw net.IP) error {
+	return cmdutil.SudoExec("route", "add", "-net", network.String(), gw.String())
+}
+
+func routeNetwork(network net.IPNet, iface string) error {
+	if network.IP.To4() == nil {
+		return cmdutil.SudoExec("route", "add", "-inet6", "-net", network.String(), "-interface", iface)
+	}
+	return cmdutil.SudoExec("route", "add", "-net", network.String(), "-interface", iface)
+}
+
 func deleteRoute(ip, gw string) error {
 	return cmdutil.SudoExec("route", "delete", ip, gw)
 }
@@ -44,6 +62,38 @@ func addDefaultRoute(iface string) error {
 	return cmdutil.SudoExec("route", "add", "-net", "128.0.0.0/1", "-interface", iface)
 }
 
+func currentRoute(ip net.IP) (via, dev string, err error) {
+	out, err := cmdutil.ExecOutput("route", "-n", "get", ip.String())
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "interface:" {
+			return "", fields[1], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("could not find route interface for %s", ip)
+}
+
+func pinRoute(ip, _, dev string) error {
+	return cmdutil.SudoExec("route", "add", "-host", ip, "-interface", dev)
+}
+
+func deletePinnedRoute(ip, _, dev string) error {
+	return cmdutil.SudoExec("route", "delete", "-host", ip, "-interface", dev)
+}
+
+func replaceDefaultRoute(iface string) error {
+	if err := cmdutil.SudoExec("route", "change", "-net", "0.0.0.0/1", "-interface", iface); err != nil {
+		return err
+	}
+
+	return cmdutil.SudoExec("route", "change", "-net", "128.0.0.0/1", "-interface", iface)
+}
+
 func peerIP(subnet net.IPNet) net.IP {
 	lastOctetID := len(subnet.IP) - 1
 	if subnet.IP[lastOctetID] == byte(1) {
@@ -18,8 +18,10 @@
 package netutil
 
 import (
+	"fmt"
 	"net"
 	"os/exec"
+	"strings"
 
 	"github.com/mysteriumnetwork/node/utils/cmdutil"
 )
@@ -35,6 +37,14 @@ func excludeRoute(ip, gw net.IP) error {
 	return cmdutil.SudoExec("ip", "route", "add", ip.String(), "via", gw.String())
 }
 
+func excludeNetwork(network net.IPNet, gw net.IP) error {
+	return cmdutil.SudoExec("ip", "route", "add", network.String(), "via", gw.String())
+}
+
+func routeNetwork(network net.IPNet, iface string) error {
+	return cmdutil.SudoExec("ip", "route", "add", network.String(), "dev", iface)
+}
+
 func deleteRoute(ip, gw string) error {
 	return cmdutil.SudoExec("ip", "route", "delete", ip, "via", gw)
 }
@@ -47,6 +57,53 @@ func addDefaultRoute(iface string) error {
 	return cmdutil.SudoExec("ip", "route", "add", "128.0.0.0/1", "dev", iface)
 }
 
+func currentRoute(ip net.IP) (via, dev string, err error) {
+	out, err := cmdutil.ExecOutput("ip", "route", "get", ip.String())
+	if err != nil {
+		return "", "", err
+	}
+
+	// Gateway is kept as well, IPv6 routes usually go via link-local address of the router.
+	fields := strings.Fields(out)
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "via":
+			via = fields[i+1]
+		case "dev":
+			dev = fields[i+1]
+		}
+	}
+
+	if dev == "" {
+		return "", "", fmt.Errorf("could not find route interface for %s", ip)
+	}
+	return via, dev, nil
+}
+
+func pinRoute(ip, via, dev string) error {
+	return cmdutil.SudoExec(pinnedRouteArgs("add", ip, via, dev)...)
+}
+
+func deletePinnedRoute(ip, via, dev string) error {
+	return cmdutil.SudoExec(pinnedRouteArgs("delete", ip, via, dev)...)
+}
+
+func pinnedRouteArgs(action, ip, via, dev string) []string {
+	args := []string{"ip", "route", action, ip}
+	if via != "" {
+		args = append(args, "via", via)
+	}
+	return append(args, "dev", dev)
+}
+
+func replaceDefaultRoute(iface string) error {
+	if err := cmdutil.SudoExec("ip", "route", "replace", "0.0.0.0/1", "dev", iface); err != nil {
+		return err
+	}
+
+	return cmdutil.SudoExec("ip", "route", "replace", "128.0.0.0/1", "dev", iface)
+}
+
 func logNetworkStats() {
 	for _, args := range [][]string{{"iptables", "-L", "-n"}, {"iptables", "-L", "-n", "-t", "nat"}, {"ip", "route", "list"}, {"ip", "address", "list"}} {
 		out, err := exec.Command("sudo", args...).CombinedOutput()
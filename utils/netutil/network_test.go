@@ -41,6 +41,11 @@ func TestClearStaleRoutes(t *testing.T) {
 	SetRouteManagerStorage(db)
 
 	defaultRouteManager.deleteRoute = noopDeleteRoute
+	var deletedPinnedRoutes [][]string
+	defaultRouteManager.deletePinnedRoute = func(ip, via, dev string) error {
+		deletedPinnedRoutes = append(deletedPinnedRoutes, []string{ip, via, dev})
+		return nil
+	}
 
 	t.Run("record deleted", func(t *testing.T) {
 		err := db.Store(routeRecordBucket, &route{Record: "8.9.7.6|1.2.3.4"})
@@ -49,17 +54,22 @@ func TestClearStaleRoutes(t *testing.T) {
 		assert.NoError(t, err)
 		err = db.Store(routeRecordBucket, &route{Record: "1.2.7.6|3.6.5.4"})
 		assert.NoError(t, err)
+		err = db.Store(routeRecordBucket, &route{Record: "2001:db8::1|fe80::1|eth0"})
+		assert.NoError(t, err)
+		err = db.Store(routeRecordBucket, &route{Record: "5.6.7.8||wg0"})
+		assert.NoError(t, err)
 
 		var records []route
 		err = defaultRouteManager.db.GetAllFrom(routeRecordBucket, &records)
 		assert.NoError(t, err)
-		assert.Len(t, records, 3)
+		assert.Len(t, records, 5)
 
 		ClearStaleRoutes()
 
 		err = defaultRouteManager.db.GetAllFrom(routeRecordBucket, &records)
 		assert.NoError(t, err)
 		assert.Len(t, records, 0)
+		assert.ElementsMatch(t, [][]string{{"2001:db8::1", "fe80::1", "eth0"}, {"5.6.7.8", "", "wg0"}}, deletedPinnedRoutes)
 	})
 }
 
@@ -22,12 +22,17 @@ import (
 	"net"
 	"os/exec"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
 func assignIP(iface string, subnet net.IPNet) error {
+	if subnet.IP.To4() == nil {
+		out, err := exec.Command("powershell", "-Command", "netsh interface ipv6 add address interface=\""+iface+"\" address="+subnet.String()).CombinedOutput()
+		return errors.Wrap(err, string(out))
+	}
 	out, err := exec.Command("powershell", "-Command", "netsh interface ip set address name=\""+iface+"\" source=static "+subnet.String()).CombinedOutput()
 	return errors.Wrap(err, string(out))
 }
@@ -37,8 +42,27 @@ func excludeRoute(ip, gw net.IP) error {
 	return errors.Wrap(err, string(out))
 }
 
+func excludeNetwork(network net.IPNet, gw net.IP) error {
+	out, err := exec.Command("powershell", "-Command", "route add "+network.String()+" "+gw.String()).CombinedOutput()
+	return errors.Wrap(err, string(out))
+}
+
+func routeNetwork(network net.IPNet, iface string) error {
+	id, gw, err := interfaceInfo(iface)
+	if err != nil {
+		return errors.Wrap(err, "failed to get info of interface: "+iface)
+	}
+
+	out, err := exec.Command("powershell", "-Command", "route add "+network.String()+" "+gw+" if "+id).CombinedOutput()
+	return errors.Wrap(err, string(out))
+}
+
 func deleteRoute(ip, gw string) error {
-	out, err := exec.Command("powershell", "-Command", "route delete "+ip+"/32").CombinedOutput()
+	if !strings.Contains(ip, "/") {
+		ip += "/32"
+	}
+
+	out, err := exec.Command("powershell", "-Command", "route delete "+ip).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to delete route: %w, %s", err, string(out))
 	}
@@ -60,6 +84,22 @@ func addDefaultRoute(name string) error {
 	return errors.Wrap(err, string(out))
 }
 
+func currentRoute(ip net.IP) (via, dev string, err error) {
+	return "", "", errors.New("chained routes are not supported on windows")
+}
+
+func pinRoute(ip, via, dev string) error {
+	return errors.New("chained routes are not supported on windows")
+}
+
+func deletePinnedRoute(ip, via, dev string) error {
+	return deleteRoute(ip, via)
+}
+
+func replaceDefaultRoute(iface string) error {
+	return errors.New("chained routes are not supported on windows")
+}
+
 func interfaceInfo(name string) (id, gw string, err error) {
 	iface, err := net.InterfaceByName(name)
 	if err != nil {